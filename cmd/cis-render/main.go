@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2017-2023 F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// cis-render prints the AS3 declarations which CIS would post to Central Manager for the given
+// DeployConfig and manifests, without connecting to a cluster or Central Manager.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	cisscheme "github.com/F5Networks/k8s-bigip-ctlr/v3/config/client/clientset/versioned/scheme"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/controller"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	routeapi "github.com/openshift/api/route/v1"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+var (
+	flags *pflag.FlagSet
+
+	deployConfig    *string
+	manifestsDir    *string
+	outputDir       *string
+	userAgent       *string
+	useNodeInternal *bool
+	logLevel        *string
+)
+
+func _init() {
+	flags = pflag.NewFlagSet("cis-render", pflag.ContinueOnError)
+	deployConfig = flags.String("deploy-config", "",
+		"Required, path to the DeployConfig CR manifest")
+	manifestsDir = flags.String("manifests", "",
+		"Required, directory of the VirtualServer, TransportServer, TLSProfile, Policy, Route, Service, "+
+			"Endpoints, EndpointSlice, Secret and Node manifests")
+	outputDir = flags.String("output-dir", "",
+		"Optional, directory to write the declaration of each BIG-IP as <bigip-label>_<bigip-address>.json, "+
+			"the declarations are printed otherwise")
+	userAgent = flags.String("user-agent", "cis-render",
+		"Optional, user agent set in the controls of the declaration")
+	useNodeInternal = flags.Bool("use-node-internal", true,
+		"Optional, provide kubernetes InternalIP addresses to pool")
+	logLevel = flags.String("log-level", "WARNING",
+		"Optional, logging level, the logs below WARNING are printed along with the declarations")
+}
+
+func init() {
+	_init()
+}
+
+func main() {
+	if err := flags.Parse(os.Args); err != nil {
+		os.Exit(1)
+	}
+	if err := run(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(out io.Writer) error {
+	if *deployConfig == "" || *manifestsDir == "" {
+		return fmt.Errorf("--deploy-config and --manifests are required")
+	}
+	ll := log.NewLogLevel(strings.ToUpper(*logLevel))
+	if ll == nil {
+		return fmt.Errorf("Unknown log level requested: %s", *logLevel)
+	}
+	log.RegisterLogger(log.LL_MIN_LEVEL, log.LL_MAX_LEVEL, log.NewConsoleLogger())
+	log.SetLogLevel(*ll)
+
+	decoder := newDecoder()
+	configObjs, err := readManifestFile(decoder, *deployConfig)
+	if err != nil {
+		return err
+	}
+	if len(configObjs) != 1 {
+		return fmt.Errorf("%v should contain exactly one DeployConfig", *deployConfig)
+	}
+	configCR, ok := configObjs[0].(*cisapiv1.DeployConfig)
+	if !ok {
+		return fmt.Errorf("%v is not a DeployConfig", *deployConfig)
+	}
+	objs, err := readManifests(decoder, *manifestsDir)
+	if err != nil {
+		return err
+	}
+
+	declarations, err := controller.RenderDeclarations(controller.RenderParams{
+		DeployConfig:    configCR,
+		Objects:         objs,
+		UserAgent:       *userAgent,
+		UseNodeInternal: *useNodeInternal,
+	})
+	if err != nil {
+		return err
+	}
+	return writeDeclarations(out, declarations)
+}
+
+// writeDeclarations writes the declarations ordered by the BIG-IP label and address
+func writeDeclarations(out io.Writer, declarations map[controller.BigIpKey]string) error {
+	var keys []controller.BigIpKey
+	for key := range declarations {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].BigIpLabel != keys[j].BigIpLabel {
+			return keys[i].BigIpLabel < keys[j].BigIpLabel
+		}
+		return keys[i].BigIpAddress < keys[j].BigIpAddress
+	})
+	for _, key := range keys {
+		decl, err := controller.FormatDeclaration(declarations[key])
+		if err != nil {
+			return err
+		}
+		if *outputDir != "" {
+			fileName := filepath.Join(*outputDir, fmt.Sprintf("%s_%s.json", key.BigIpLabel, key.BigIpAddress))
+			if err = os.WriteFile(fileName, []byte(decl+"\n"), 0644); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(keys) > 1 {
+			fmt.Fprintf(out, "# BIG-IP %s %s\n", key.BigIpLabel, key.BigIpAddress)
+		}
+		fmt.Fprintln(out, decl)
+	}
+	return nil
+}
+
+func newDecoder() runtime.Decoder {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		cisscheme.AddToScheme,
+		routeapi.Install,
+	} {
+		_ = addToScheme(scheme)
+	}
+	return serializer.NewCodecFactory(scheme).UniversalDeserializer()
+}
+
+// readManifests reads the YAML and JSON manifests in the directory and its sub directories
+func readManifests(decoder runtime.Decoder, dir string) ([]runtime.Object, error) {
+	var objs []runtime.Object
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		fileObjs, err := readManifestFile(decoder, path)
+		if err != nil {
+			return err
+		}
+		objs = append(objs, fileObjs...)
+		return nil
+	})
+	return objs, err
+}
+
+// readManifestFile decodes all the documents in the manifest
+func readManifestFile(decoder runtime.Decoder, path string) ([]runtime.Object, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var objs []runtime.Object
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %v: %v", path, err)
+		}
+		jsonDoc, err := yaml.ToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %v: %v", path, err)
+		}
+		// skip the empty documents
+		if len(bytes.TrimSpace(jsonDoc)) == 0 || string(jsonDoc) == "null" {
+			continue
+		}
+		obj, _, err := decoder.Decode(jsonDoc, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %v: %v", path, err)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
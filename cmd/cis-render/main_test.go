@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const testDeployConfig = `
+apiVersion: cis.f5.com/v1
+kind: DeployConfig
+metadata:
+  name: global-cm
+  namespace: kube-system
+spec:
+  baseConfig:
+    controllerIdentifier: cluster-1
+  networkConfig:
+    orchestrationCNI: ovn-k8s
+    metaData:
+      poolMemberType: cluster
+      staticRoutingMode: true
+  bigIpConfig:
+    - bigIpAddress: 10.8.3.11
+      bigIpLabel: bigip1
+      defaultPartition: test
+`
+
+const testVirtualServer = `
+# virtual server of the sample app
+apiVersion: cis.f5.com/v1
+kind: VirtualServer
+metadata:
+  name: sample-vs
+  namespace: default
+spec:
+  host: test.com
+  virtualServerAddress: 1.2.3.4
+  pools:
+    - path: /path
+      service: svc1
+      servicePort: 80
+`
+
+const testApp = `
+apiVersion: v1
+kind: Node
+metadata:
+  name: worker1
+status:
+  addresses:
+    - type: InternalIP
+      address: 10.10.10.1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc1
+  namespace: default
+spec:
+  ports:
+    - name: port0
+      port: 80
+      targetPort: 8080
+---
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: svc1
+  namespace: default
+subsets:
+  - addresses:
+      - ip: 10.244.1.5
+        nodeName: worker1
+    ports:
+      - name: port0
+        port: 8080
+        protocol: TCP
+`
+
+var _ = Describe("CIS Render", func() {
+	var dir string
+
+	BeforeEach(func() {
+		_init()
+		var err error
+		dir, err = os.MkdirTemp("", "cis-render")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Mkdir(filepath.Join(dir, "manifests"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "deployconfig.yaml"), []byte(testDeployConfig), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "manifests", "vs.yaml"), []byte(testVirtualServer), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "manifests", "app.yml"), []byte(testApp), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "manifests", "README.md"), []byte("docs"), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("reads the manifests", func() {
+		objs, err := readManifests(newDecoder(), filepath.Join(dir, "manifests"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objs).To(HaveLen(4))
+	})
+
+	It("prints the declaration", func() {
+		Expect(flags.Parse([]string{
+			"--deploy-config=" + filepath.Join(dir, "deployconfig.yaml"),
+			"--manifests=" + filepath.Join(dir, "manifests"),
+		})).To(Succeed())
+		var out bytes.Buffer
+		Expect(run(&out)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(`"address": "10.8.3.11"`))
+		Expect(out.String()).To(ContainSubstring(`"1.2.3.4"`))
+		Expect(out.String()).To(ContainSubstring(`"10.244.1.5"`))
+		Expect(out.String()).To(ContainSubstring(`"userAgent": "cis-render"`))
+	})
+
+	It("writes the declaration of each BIG-IP", func() {
+		Expect(flags.Parse([]string{
+			"--deploy-config=" + filepath.Join(dir, "deployconfig.yaml"),
+			"--manifests=" + filepath.Join(dir, "manifests"),
+			"--output-dir=" + dir,
+		})).To(Succeed())
+		var out bytes.Buffer
+		Expect(run(&out)).To(Succeed())
+		Expect(out.Len()).To(Equal(0))
+		decl, err := os.ReadFile(filepath.Join(dir, "bigip1_10.8.3.11.json"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(decl)).To(ContainSubstring(`"10.244.1.5"`))
+	})
+
+	It("fails on invalid input", func() {
+		var out bytes.Buffer
+		Expect(run(&out)).NotTo(Succeed())
+		Expect(flags.Parse([]string{
+			"--deploy-config=" + filepath.Join(dir, "manifests", "vs.yaml"),
+			"--manifests=" + filepath.Join(dir, "manifests"),
+		})).To(Succeed())
+		Expect(run(&out)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2017-2023 F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"time"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+)
+
+// cmConfigReloadInterval is the interval at which the credentials directory and trusted-certs-cfgmap are checked
+const cmConfigReloadInterval = 10 * time.Second
+
+// cmConfigWatcher reloads the Central Manager credentials, url and trusted certs into the token manager
+type cmConfigWatcher struct {
+	tokenManager *tokenmanager.TokenManager
+	interval     time.Duration
+}
+
+func newCMConfigWatcher(tm *tokenmanager.TokenManager) *cmConfigWatcher {
+	return &cmConfigWatcher{
+		tokenManager: tm,
+		interval:     cmConfigReloadInterval,
+	}
+}
+
+func (w *cmConfigWatcher) run(stopCh <-chan struct{}) {
+	if w.tokenManager == nil || (len(*credsDir) == 0 && *trustedCertsCfgmap == "") {
+		return
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			w.reload()
+		}
+	}
+}
+
+// reload swaps the config in the token manager, the current config is retained when it can not be read
+func (w *cmConfigWatcher) reload() bool {
+	credentials, serverURL, err := readCredentials(*cmUsername, *cmPassword, *cmURL)
+	if err != nil {
+		log.Errorf("[Reload] Unable to read Central Manager credentials, retaining the current credentials: %v", err)
+		credentials = tokenmanager.Credentials{Username: *cmUsername, Password: *cmPassword}
+		serverURL = w.tokenManager.GetServerURL()
+	}
+	certs, err := readBIGIPTrustedCerts()
+	if err != nil {
+		log.Errorf("[Reload] Unable to read trusted certs, retaining the current certs: %v", err)
+		certs = w.tokenManager.GetTrustedCerts()
+	}
+	if !w.tokenManager.UpdateConfig(serverURL, credentials, certs) {
+		return false
+	}
+	*cmUsername = credentials.Username
+	*cmPassword = credentials.Password
+	*cmURL = serverURL
+	log.Infof("[Reload] Central Manager credentials and trusted certs reloaded")
+	return true
+}
@@ -22,11 +22,13 @@ import (
 	"fmt"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/controller"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/teem"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/url"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
@@ -78,6 +80,20 @@ var (
 
 	kubeConfig            *string
 	manageCustomResources *bool
+	manageGatewayAPI      *bool
+	gatewayControllerName *string
+	manageIngress         *bool
+	ingressControllerName *string
+
+	loadBalancerClass           *string
+	manageLoadBalancerClassOnly *bool
+
+	leaderElection            *bool
+	leaderElectionLeaseName   *string
+	leaderElectionNamespace   *string
+	leaderElectionLeaseDur    *time.Duration
+	leaderElectionRenewDur    *time.Duration
+	leaderElectionRetryPeriod *time.Duration
 
 	cmURL       *string
 	cmUsername  *string
@@ -87,8 +103,10 @@ var (
 
 	trustedCertsCfgmap *string
 
-	CISConfigCR *string
-	httpAddress *string
+	CISConfigCR           *string
+	httpAddress           *string
+	declarationHistoryAPI *bool
+	as3SchemaPath         *string
 
 	// package variables
 	kubeClient    kubernetes.Interface
@@ -127,6 +145,11 @@ func _init() {
 		"Required, specify a CRD that holds additional spec for controller.")
 	httpAddress = globalFlags.String("http-listen-address", "0.0.0.0:8080",
 		"Optional, address to serve http based informations (/metrics and /health).")
+	declarationHistoryAPI = globalFlags.Bool("declaration-history-api", false,
+		"Optional, serve the declaration history (/declarations) and the rollback of the tenants (/declarations/rollback) "+
+			"on http-listen-address, the endpoints are not authenticated.")
+	as3SchemaPath = globalFlags.String("as3-schema-path", controller.DefaultAS3SchemaPath,
+		"Optional, path to the AS3 schema to validate the tenant declarations before posting, validation is disabled when empty.")
 	globalFlags.Usage = func() {
 		fmt.Fprintf(os.Stderr, "  Global:\n%s\n", globalFlags.FlagUsagesWrapped(width))
 	}
@@ -157,6 +180,33 @@ func _init() {
 	}
 	manageCustomResources = kubeFlags.Bool("manage-custom-resources", true,
 		"Optional, specify whether or not to manage custom resources i.e. transportserver")
+	manageGatewayAPI = kubeFlags.Bool("manage-gateway-api", false,
+		"Optional, specify whether or not to manage Kubernetes Gateway API resources i.e. gateway, httproute, "+
+			"tlsroute, tcproute and udproute")
+	gatewayControllerName = kubeFlags.String("gateway-controller-name", controller.DefaultGatewayControllerName,
+		"Optional, controllerName of the GatewayClasses managed by CIS")
+	manageIngress = kubeFlags.Bool("manage-ingress", false,
+		"Optional, specify whether or not to manage Kubernetes Ingress resources")
+	ingressControllerName = kubeFlags.String("ingress-controller-name", controller.DefaultIngressControllerName,
+		"Optional, controller of the IngressClasses managed by CIS")
+	loadBalancerClass = kubeFlags.String("load-balancer-class", "",
+		"Optional, loadBalancerClass of the Services of type LoadBalancer managed by CIS, "+
+			"Services with a different loadBalancerClass are ignored")
+	manageLoadBalancerClassOnly = kubeFlags.Bool("manage-load-balancer-class-only", false,
+		"Optional, manage only the Services of type LoadBalancer whose loadBalancerClass matches load-balancer-class")
+	leaderElection = kubeFlags.Bool("leader-election", false,
+		"Optional, enable Lease based leader election to run multiple CIS replicas, "+
+			"only the leader posts the declarations to CentralManager")
+	leaderElectionLeaseName = kubeFlags.String("leader-election-lease-name", controller.DefaultLeaseName,
+		"Optional, name of the Lease used for leader election")
+	leaderElectionNamespace = kubeFlags.String("leader-election-namespace", "",
+		"Optional, namespace of the Lease used for leader election, defaults to the namespace of deploy-config-cr")
+	leaderElectionLeaseDur = kubeFlags.Duration("leader-election-lease-duration", controller.DefaultLeaseDuration,
+		"Optional, duration that followers wait before taking over the lease from an unresponsive leader")
+	leaderElectionRenewDur = kubeFlags.Duration("leader-election-renew-deadline", controller.DefaultRenewDeadline,
+		"Optional, duration that the leader retries renewing the lease before giving up the leadership")
+	leaderElectionRetryPeriod = kubeFlags.Duration("leader-election-retry-period", controller.DefaultRetryPeriod,
+		"Optional, duration between the attempts to acquire or renew the lease")
 
 	flags.AddFlagSet(globalFlags)
 	flags.AddFlagSet(cmIPFlags)
@@ -214,10 +264,29 @@ func verifyArgs() error {
 		}
 	}
 
+	if *leaderElection && (*leaderElectionLeaseDur <= *leaderElectionRenewDur ||
+		*leaderElectionRenewDur <= *leaderElectionRetryPeriod) {
+		return fmt.Errorf("invalid leader election durations, --leader-election-lease-duration must be greater " +
+			"than --leader-election-renew-deadline, which must be greater than --leader-election-retry-period")
+	}
+
 	return nil
 }
 
 func getCredentials() error {
+	credentials, serverURL, err := readCredentials(*cmUsername, *cmPassword, *cmURL)
+	if err != nil {
+		return err
+	}
+	*cmUsername = credentials.Username
+	*cmPassword = credentials.Password
+	*cmURL = serverURL
+	return nil
+}
+
+// readCredentials reads the credentials and url from the credentials directory, the given values are used
+// for the files which are not present
+func readCredentials(username, password, cmURL string) (tokenmanager.Credentials, string, error) {
 	if len(*credsDir) > 0 {
 		var usr, pass, cmCredURL string
 		var err error
@@ -245,32 +314,32 @@ func getCredentials() error {
 			return nil
 		}
 
-		err = setField(cmUsername, usr, "username")
+		err = setField(&username, usr, "username")
 		if err != nil {
-			return err
+			return tokenmanager.Credentials{}, "", err
 		}
-		err = setField(cmPassword, pass, "password")
+		err = setField(&password, pass, "password")
 		if err != nil {
-			return err
+			return tokenmanager.Credentials{}, "", err
 		}
-		err = setField(cmURL, cmCredURL, "url")
+		err = setField(&cmURL, cmCredURL, "url")
 		if err != nil {
-			return err
+			return tokenmanager.Credentials{}, "", err
 		}
 	}
 	// Verify URL is valid
-	if !strings.HasPrefix(*cmURL, "https://") {
-		*cmURL = "https://" + *cmURL
+	if !strings.HasPrefix(cmURL, "https://") {
+		cmURL = "https://" + cmURL
 	}
-	u, err := url.Parse(*cmURL)
+	u, err := url.Parse(cmURL)
 	if nil != err {
-		return fmt.Errorf("Error parsing url: %s", err)
+		return tokenmanager.Credentials{}, "", fmt.Errorf("Error parsing url: %s", err)
 	}
 	if len(u.Path) > 0 && u.Path != "/" {
-		return fmt.Errorf("CM-URL path must be empty or '/'; check URL formatting and/or remove %s from path",
-			u.Path)
+		return tokenmanager.Credentials{}, "", fmt.Errorf(
+			"CM-URL path must be empty or '/'; check URL formatting and/or remove %s from path", u.Path)
 	}
-	return nil
+	return tokenmanager.Credentials{Username: username, Password: password}, cmURL, nil
 }
 
 func main() {
@@ -319,9 +388,13 @@ func main() {
 
 	//TODO initialize and add support for teems data
 	initTeems(ctlr)
+	// reload the rotated credentials and trusted certs without restarting CIS
+	watcherStopCh := make(chan struct{})
+	go newCMConfigWatcher(ctlr.CMTokenManager).run(watcherStopCh)
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 	sig := <-sigs
+	close(watcherStopCh)
 	ctlr.Stop()
 	log.Infof("Exiting - signal %v\n", sig)
 }
@@ -339,18 +412,40 @@ func initController(
 				UserName: *cmUsername,
 				Password: *cmPassword,
 			},
-			CMTrustedCerts:        getBIGIPTrustedCerts(),
-			CMSSLInsecure:         *sslInsecure,
-			CISConfigCRKey:        *CISConfigCR,
-			HttpAddress:           *httpAddress,
-			ManageCustomResources: *manageCustomResources,
-			UseNodeInternal:       *useNodeInternal,
+			CMTrustedCerts:              getBIGIPTrustedCerts(),
+			CMSSLInsecure:               *sslInsecure,
+			CISConfigCRKey:              *CISConfigCR,
+			HttpAddress:                 *httpAddress,
+			DeclarationHistoryAPI:       *declarationHistoryAPI,
+			ManageCustomResources:       *manageCustomResources,
+			ManageGatewayAPI:            *manageGatewayAPI,
+			GatewayControllerName:       *gatewayControllerName,
+			ManageIngress:               *manageIngress,
+			IngressControllerName:       *ingressControllerName,
+			UseNodeInternal:             *useNodeInternal,
+			LeaderElectionConfig:        getLeaderElectionConfig(),
+			AS3SchemaPath:               *as3SchemaPath,
+			LoadBalancerClass:           *loadBalancerClass,
+			ManageLoadBalancerClassOnly: *manageLoadBalancerClassOnly,
 		},
 	)
 
 	return ctlr
 }
 
+func getLeaderElectionConfig() *controller.LeaderElectionConfig {
+	if !*leaderElection {
+		return nil
+	}
+	return &controller.LeaderElectionConfig{
+		LeaseName:      *leaderElectionLeaseName,
+		LeaseNamespace: *leaderElectionNamespace,
+		LeaseDuration:  *leaderElectionLeaseDur,
+		RenewDeadline:  *leaderElectionRenewDur,
+		RetryPeriod:    *leaderElectionRetryPeriod,
+	}
+}
+
 func initTeems(ctlr *controller.Controller) {
 	td := &teem.TeemsData{
 		CisVersion:      version,
@@ -441,28 +536,43 @@ func getUserAgentInfo() string {
 
 // Read certificate from configmap
 func getBIGIPTrustedCerts() string {
+	certs, err := readBIGIPTrustedCerts()
+	if err != nil {
+		log.Errorf("[INIT] %v", err)
+		os.Exit(1)
+	}
+	return certs
+}
+
+// readBIGIPTrustedCerts returns the certificates in the trusted-certs-cfgmap ordered by the key
+func readBIGIPTrustedCerts() (string, error) {
 	if *trustedCertsCfgmap == "" {
-		return ""
+		return "", nil
 	}
 	namespaceCfgmapSlice := strings.Split(*trustedCertsCfgmap, "/")
 	if len(namespaceCfgmapSlice) != 2 {
 		log.Debugf("[INIT] either trusted-certs-cfgmap is not provided or provided trusted-certs-cfgmap is invalid.")
-		return ""
+		return "", nil
 	}
 
 	cm, err := getConfigMapUsingNamespaceAndName(namespaceCfgmapSlice[0], namespaceCfgmapSlice[1])
 	if err != nil {
-		log.Errorf("[INIT] ConfigMap with name %v not found in namespace: %v, error: %v",
+		return "", fmt.Errorf("ConfigMap with name %v not found in namespace: %v, error: %v",
 			namespaceCfgmapSlice[1], namespaceCfgmapSlice[0], err)
-		os.Exit(1)
 	}
 
+	// keys are sorted so that the certs are compared reliably on reload
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
 	var certs string
 	// Fetch all certificates from configmap
-	for _, v := range cm.Data {
-		certs += v + "\n"
+	for _, k := range keys {
+		certs += cm.Data[k] + "\n"
 	}
-	return certs
+	return certs, nil
 }
 
 // getConfigMapUsingNamespaceAndName fetches and returns the configMap
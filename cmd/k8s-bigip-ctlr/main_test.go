@@ -25,6 +25,10 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	"os"
 	"strings"
+	"time"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/controller"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -120,6 +124,34 @@ var _ = Describe("Main Tests", func() {
 			argError := verifyArgs()
 			Expect(argError).ToNot(BeNil())
 		})
+		It("verifies leader election CLI parameters", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--cm-password=admin",
+				"--cm-url=cm.example.com",
+				"--cm-username=admin",
+				"--deploy-config-cr=default/testcr",
+				"--leader-election=true",
+				"--leader-election-lease-duration=5s",
+				"--leader-election-renew-deadline=10s",
+			}
+			flags.Parse(os.Args)
+			argError := verifyArgs()
+			Expect(argError).ToNot(BeNil())
+
+			*leaderElectionRenewDur = 3 * time.Second
+			argError = verifyArgs()
+			Expect(argError).To(BeNil())
+			leConfig := getLeaderElectionConfig()
+			Expect(leConfig).NotTo(BeNil())
+			Expect(leConfig.LeaseName).To(Equal(controller.DefaultLeaseName))
+			Expect(leConfig.LeaseDuration).To(Equal(5 * time.Second))
+			Expect(leConfig.RetryPeriod).To(Equal(controller.DefaultRetryPeriod))
+
+			*leaderElection = false
+			Expect(getLeaderElectionConfig()).To(BeNil())
+		})
 		It("verifies with missing --deploy-config-cr required CLI parameter", func() {
 			defer _init()
 			os.Args = []string{
@@ -281,5 +313,55 @@ var _ = Describe("Main Tests", func() {
 			out = getBIGIPTrustedCerts()
 			Expect(out).To(Equal(""))
 		})
+
+		It("reloads the rotated credentials and trusted certs", func() {
+			defer _init()
+			defer os.RemoveAll("/tmp/k8s-test-creds")
+
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--credentials-directory=/tmp/k8s-test-creds",
+				"--cm-url=cm.example.com",
+				"--deploy-config-cr=default/testcr",
+				"--trusted-certs-cfgmap=default/foomap",
+			}
+			flags.Parse(os.Args)
+			os.Mkdir("/tmp/k8s-test-creds", 0755)
+			Expect(os.WriteFile("/tmp/k8s-test-creds/username", []byte("user"), 0755)).To(Succeed())
+			Expect(os.WriteFile("/tmp/k8s-test-creds/password", []byte("pass"), 0755)).To(Succeed())
+			Expect(getCredentials()).To(Succeed())
+			kubeClient = fake.NewSimpleClientset()
+			cfgFoo := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foomap", Namespace: "default"},
+				Data: map[string]string{"b": "bar", "a": "foo"}}
+			_, err := kubeClient.CoreV1().ConfigMaps("default").Create(context.TODO(), cfgFoo, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			certs, err := readBIGIPTrustedCerts()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(certs).To(Equal("foo\nbar\n"))
+
+			tm := tokenmanager.NewTokenManager(*cmURL, tokenmanager.Credentials{Username: "user", Password: "pass"},
+				certs, true)
+			watcher := newCMConfigWatcher(tm)
+			Expect(watcher.reload()).To(BeFalse())
+
+			// rotate the secret
+			Expect(os.WriteFile("/tmp/k8s-test-creds/password", []byte("newpass"), 0755)).To(Succeed())
+			Expect(os.WriteFile("/tmp/k8s-test-creds/url", []byte("cm2.example.com"), 0755)).To(Succeed())
+			Expect(watcher.reload()).To(BeTrue())
+			Expect(tm.GetServerURL()).To(Equal("https://cm2.example.com"))
+			Expect(*cmPassword).To(Equal("newpass"))
+
+			// update the trusted certs
+			cfgFoo.Data = map[string]string{"a": "foo"}
+			_, err = kubeClient.CoreV1().ConfigMaps("default").Update(context.TODO(), cfgFoo, metav1.UpdateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(watcher.reload()).To(BeTrue())
+			Expect(tm.GetTrustedCerts()).To(Equal("foo\n"))
+
+			// invalid url retains the current config
+			Expect(os.WriteFile("/tmp/k8s-test-creds/url", []byte("https://cm3.example.com/path"), 0755)).To(Succeed())
+			Expect(watcher.reload()).To(BeFalse())
+			Expect(tm.GetServerURL()).To(Equal("https://cm2.example.com"))
+		})
 	})
 })
@@ -21,8 +21,12 @@ type VirtualServer struct {
 
 // VirtualServerStatus is the status of the VirtualServer resource.
 type VirtualServerStatus struct {
-	VSAddress string `json:"vsAddress,omitempty"`
-	StatusOk  string `json:"status,omitempty"`
+	VSAddress          string             `json:"vsAddress,omitempty"`
+	StatusOk           string             `json:"status,omitempty"`
+	Error              string             `json:"error,omitempty"`
+	LastUpdated        metav1.Time        `json:"lastUpdated,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // VirtualServerSpec is the spec of the VirtualServer resource.
@@ -55,6 +59,7 @@ type VirtualServerSpec struct {
 	AllowSourceRange                 []string         `json:"allowSourceRange,omitempty"`
 	HttpMrfRoutingEnabled            *bool            `json:"httpMrfRoutingEnabled,omitempty"`
 	Partition                        string           `json:"partition,omitempty"`
+	BigIpLabel                       string           `json:"bigIpLabel,omitempty"`
 }
 
 // ServiceAddress Service IP address definition (BIG-IP virtual-address).
@@ -221,6 +226,7 @@ type IngressLinkSpec struct {
 	IRules               []string              `json:"iRules,omitempty"`
 	IPAMLabel            string                `json:"ipamLabel"`
 	Partition            string                `json:"partition,omitempty"`
+	BigIpLabel           string                `json:"bigIpLabel,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -276,6 +282,7 @@ type TransportServerSpec struct {
 	BotDefense           string           `json:"botDefense,omitempty"`
 	Profiles             ProfileTSSpec    `json:"profiles,omitempty"`
 	Partition            string           `json:"partition,omitempty"`
+	BigIpLabel           string           `json:"bigIpLabel,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -466,6 +473,20 @@ type DeployConfigSpec struct {
 	AS3Config     AS3Config     `json:"as3Config,omitempty"`
 	BigIpConfig   []BigIpConfig `json:"bigIpConfig,omitempty"`
 	ExtendedSpec  ExtendedSpec  `json:"extendedSpec,omitempty"`
+	IPAMConfig    IPAMConfig    `json:"ipamConfig,omitempty"`
+}
+
+// IPAMConfig defines the IP address ranges of the embedded IPAM, which allocates the virtual server addresses of the
+// resources with an ipamLabel when CIS is not deployed with F5 IPAM Controller
+type IPAMConfig struct {
+	Ranges []IPAMRange `json:"ranges,omitempty"`
+}
+
+// IPAMRange defines an IPv4 or IPv6 address range of an IPAM label
+type IPAMRange struct {
+	IPAMLabel string `json:"ipamLabel"`
+	// Range is either a CIDR or the first and the last IP address separated by a hyphen, e.g. 10.1.1.10-10.1.1.20
+	Range string `json:"range"`
 }
 
 type BaseConfig struct {
@@ -476,8 +497,47 @@ type BaseConfig struct {
 }
 
 type NetworkConfig struct {
-	OrchestrationCNI string        `json:"orchestrationCNI,omitempty"`
-	MetaData         CNIConfigMeta `json:"metaData,omitempty"`
+	OrchestrationCNI string            `json:"orchestrationCNI,omitempty"`
+	MetaData         CNIConfigMeta     `json:"metaData,omitempty"`
+	L3Config         []L3NetworkConfig `json:"l3Config,omitempty"`
+}
+
+// L3NetworkConfig defines the network objects of a BIG-IP instance which are managed by CIS
+type L3NetworkConfig struct {
+	BigIpAddress string        `json:"bigIpAddress"`
+	RouteDomains []RouteDomain `json:"routeDomains,omitempty"`
+	VLANs        []VLAN        `json:"vlans,omitempty"`
+	SelfIPs      []SelfIP      `json:"selfIPs,omitempty"`
+	Tunnels      []Tunnel      `json:"tunnels,omitempty"`
+}
+
+type RouteDomain struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+type VLAN struct {
+	Name        string   `json:"name"`
+	Tag         int      `json:"tag,omitempty"`
+	MTU         int      `json:"mtu,omitempty"`
+	Interfaces  []string `json:"interfaces,omitempty"`
+	RouteDomain string   `json:"routeDomain,omitempty"`
+}
+
+type SelfIP struct {
+	Name string `json:"name"`
+	// Address in CIDR notation
+	Address string `json:"address"`
+	// VLAN or tunnel of the self IP
+	VLAN string `json:"vlan"`
+}
+
+type Tunnel struct {
+	Name         string `json:"name"`
+	Profile      string `json:"profile,omitempty"`
+	LocalAddress string `json:"localAddress"`
+	// Key is the VXLAN network identifier
+	Key int `json:"key,omitempty"`
 }
 
 type CNIConfigMeta struct {
@@ -489,9 +549,43 @@ type CNIConfigMeta struct {
 }
 
 type AS3Config struct {
-	DebugAS3     bool `json:"debugAS3,omitempty"`
-	PostDelayAS3 int  `json:"postDelayAS3,omitempty"`
-	DocumentAPI  bool `json:"documentAPI,omitempty"`
+	DebugAS3       bool                 `json:"debugAS3,omitempty"`
+	PostDelayAS3   int                  `json:"postDelayAS3,omitempty"`
+	DocumentAPI    bool                 `json:"documentAPI,omitempty"`
+	DriftDetection DriftDetectionConfig `json:"driftDetection,omitempty"`
+	Rollout        RolloutConfig        `json:"rollout,omitempty"`
+	History        HistoryConfig        `json:"history,omitempty"`
+}
+
+// DriftDetectionConfig defines the periodic comparison of the tenants deployed on Central Manager with the
+// tenants posted by CIS
+type DriftDetectionConfig struct {
+	// Interval in seconds, drift detection is disabled when not set
+	Interval int `json:"interval,omitempty"`
+	// Policy is either report-only or auto-heal
+	Policy string `json:"policy,omitempty"`
+}
+
+// RolloutConfig defines the staged rollout of the declarations to the configured BIG-IPs, the declarations are
+// posted to the canary BIG-IP first and promoted to the other BIG-IPs after they are deployed on the canary BIG-IP
+type RolloutConfig struct {
+	// CanaryBigIpAddress of the BIG-IP which receives the declarations first, staged rollout is disabled when not set
+	CanaryBigIpAddress string `json:"canaryBigIpAddress,omitempty"`
+	// BakeTime in seconds to wait after the deployment on the canary BIG-IP before the promotion
+	BakeTime int `json:"bakeTime,omitempty"`
+	// HealthCheck verifies the virtual addresses deployed on the canary BIG-IP accept connections before the promotion
+	HealthCheck bool `json:"healthCheck,omitempty"`
+}
+
+// HistoryConfig defines the history of the tenant declarations deployed on the BIG-IPs
+type HistoryConfig struct {
+	// Size is the number of revisions kept for each tenant, defaults to 5
+	Size int `json:"size,omitempty"`
+	// ConfigMap persists the history in a ConfigMap of each BIG-IP, so that it's available after a restart
+	ConfigMap bool `json:"configMap,omitempty"`
+	// AutoRollbackFailures is the number of consecutive failed deployments of a tenant after which the tenant is
+	// rolled back to the last deployed revision, automatic rollback is disabled when not set
+	AutoRollbackFailures int `json:"autoRollbackFailures,omitempty"`
 }
 
 type BigIpConfig struct {
@@ -524,6 +618,7 @@ type ExtendedRouteGroupSpec struct {
 	AllowOverride      string `json:"allowOverride"`
 	Policy             string `json:"policyCR,omitempty"`
 	HTTPServerPolicyCR string `json:"httpServerPolicyCR,omitempty"`
+	BigIpLabel         string `json:"bigIpLabel,omitempty"`
 	Meta               Meta   `json:",inline"`
 }
 
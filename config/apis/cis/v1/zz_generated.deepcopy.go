@@ -29,6 +29,9 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AS3Config) DeepCopyInto(out *AS3Config) {
 	*out = *in
+	out.DriftDetection = in.DriftDetection
+	out.Rollout = in.Rollout
+	out.History = in.History
 	return
 }
 
@@ -308,7 +311,7 @@ func (in *DeployConfigList) DeepCopyObject() runtime.Object {
 func (in *DeployConfigSpec) DeepCopyInto(out *DeployConfigSpec) {
 	*out = *in
 	out.BaseConfig = in.BaseConfig
-	out.NetworkConfig = in.NetworkConfig
+	in.NetworkConfig.DeepCopyInto(&out.NetworkConfig)
 	out.AS3Config = in.AS3Config
 	if in.BigIpConfig != nil {
 		in, out := &in.BigIpConfig, &out.BigIpConfig
@@ -316,6 +319,7 @@ func (in *DeployConfigSpec) DeepCopyInto(out *DeployConfigSpec) {
 		copy(*out, *in)
 	}
 	in.ExtendedSpec.DeepCopyInto(&out.ExtendedSpec)
+	in.IPAMConfig.DeepCopyInto(&out.IPAMConfig)
 	return
 }
 
@@ -329,6 +333,22 @@ func (in *DeployConfigSpec) DeepCopy() *DeployConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetectionConfig) DeepCopyInto(out *DriftDetectionConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetectionConfig.
+func (in *DriftDetectionConfig) DeepCopy() *DriftDetectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExtendedRouteGroupConfig) DeepCopyInto(out *ExtendedRouteGroupConfig) {
 	*out = *in
@@ -541,6 +561,59 @@ func (in *HAMode) DeepCopy() *HAMode {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HistoryConfig) DeepCopyInto(out *HistoryConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryConfig.
+func (in *HistoryConfig) DeepCopy() *HistoryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HistoryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAMConfig) DeepCopyInto(out *IPAMConfig) {
+	*out = *in
+	if in.Ranges != nil {
+		in, out := &in.Ranges, &out.Ranges
+		*out = make([]IPAMRange, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMConfig.
+func (in *IPAMConfig) DeepCopy() *IPAMConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAMConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAMRange) DeepCopyInto(out *IPAMRange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMRange.
+func (in *IPAMRange) DeepCopy() *IPAMRange {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAMRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressLink) DeepCopyInto(out *IngressLink) {
 	*out = *in
@@ -644,6 +717,44 @@ func (in *IngressLinkStatus) DeepCopy() *IngressLinkStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L3NetworkConfig) DeepCopyInto(out *L3NetworkConfig) {
+	*out = *in
+	if in.RouteDomains != nil {
+		in, out := &in.RouteDomains, &out.RouteDomains
+		*out = make([]RouteDomain, len(*in))
+		copy(*out, *in)
+	}
+	if in.VLANs != nil {
+		in, out := &in.VLANs, &out.VLANs
+		*out = make([]VLAN, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SelfIPs != nil {
+		in, out := &in.SelfIPs, &out.SelfIPs
+		*out = make([]SelfIP, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tunnels != nil {
+		in, out := &in.Tunnels, &out.Tunnels
+		*out = make([]Tunnel, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L3NetworkConfig.
+func (in *L3NetworkConfig) DeepCopy() *L3NetworkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(L3NetworkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *L3PolicySpec) DeepCopyInto(out *L3PolicySpec) {
 	*out = *in
@@ -776,6 +887,13 @@ func (in *MultiPoolPersistence) DeepCopy() *MultiPoolPersistence {
 func (in *NetworkConfig) DeepCopyInto(out *NetworkConfig) {
 	*out = *in
 	out.MetaData = in.MetaData
+	if in.L3Config != nil {
+		in, out := &in.L3Config, &out.L3Config
+		*out = make([]L3NetworkConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -990,6 +1108,38 @@ func (in *ProfileVSSpec) DeepCopy() *ProfileVSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutConfig) DeepCopyInto(out *RolloutConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutConfig.
+func (in *RolloutConfig) DeepCopy() *RolloutConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteDomain) DeepCopyInto(out *RouteDomain) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteDomain.
+func (in *RouteDomain) DeepCopy() *RouteDomain {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteDomain)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSLProfiles) DeepCopyInto(out *SSLProfiles) {
 	*out = *in
@@ -1016,6 +1166,22 @@ func (in *SSLProfiles) DeepCopy() *SSLProfiles {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfIP) DeepCopyInto(out *SelfIP) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfIP.
+func (in *SelfIP) DeepCopy() *SelfIP {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAddress) DeepCopyInto(out *ServiceAddress) {
 	*out = *in
@@ -1301,6 +1467,43 @@ func (in *TransportServerStatus) DeepCopy() *TransportServerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tunnel) DeepCopyInto(out *Tunnel) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tunnel.
+func (in *Tunnel) DeepCopy() *Tunnel {
+	if in == nil {
+		return nil
+	}
+	out := new(Tunnel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VLAN) DeepCopyInto(out *VLAN) {
+	*out = *in
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VLAN.
+func (in *VLAN) DeepCopy() *VLAN {
+	if in == nil {
+		return nil
+	}
+	out := new(VLAN)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSPool) DeepCopyInto(out *VSPool) {
 	*out = *in
@@ -1350,7 +1553,7 @@ func (in *VirtualServer) DeepCopyInto(out *VirtualServer) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1463,6 +1666,14 @@ func (in *VirtualServerSpec) DeepCopy() *VirtualServerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualServerStatus) DeepCopyInto(out *VirtualServerStatus) {
 	*out = *in
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -0,0 +1,474 @@
+/*
+ * Copyright (c) 2017-2023 F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package as3schema validates AS3 declarations against the AS3 JSON schema bundled with CIS.
+// It implements the subset of JSON schema draft-07 used by the AS3 schema, the formats and
+// the AS3 specific keywords like f5PostProcess are not validated.
+package as3schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// TenantDefinition is the schema definition of an AS3 tenant
+	TenantDefinition = "Tenant"
+
+	definitionsRef = "#/definitions/"
+	// maxErrors is the number of errors reported for a document
+	maxErrors = 5
+)
+
+// Validator validates the documents against the definitions of a JSON schema, it is safe for concurrent use
+type Validator struct {
+	definitions map[string]interface{}
+	patterns    map[string]*regexp.Regexp
+}
+
+// ValidationError holds the violations of the schema found in a document
+type ValidationError struct {
+	Errors []string
+}
+
+func (err *ValidationError) Error() string {
+	if len(err.Errors) > maxErrors {
+		return fmt.Sprintf("%s and %d more errors", strings.Join(err.Errors[:maxErrors], "; "), len(err.Errors)-maxErrors)
+	}
+	return strings.Join(err.Errors, "; ")
+}
+
+// NewValidator reads the JSON schema from the file
+func NewValidator(path string) (*Validator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(data)
+}
+
+// New parses the JSON schema and compiles its patterns
+func New(data []byte) (*Validator, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %v", err)
+	}
+	definitions, ok := schema["definitions"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid JSON schema: definitions not found")
+	}
+	v := &Validator{
+		definitions: definitions,
+		patterns:    make(map[string]*regexp.Regexp),
+	}
+	v.compilePatterns(definitions)
+	return v, nil
+}
+
+// compilePatterns compiles all the patterns upfront so that the validator is read only afterwards,
+// the ECMA patterns which are not supported by RE2 are skipped
+func (v *Validator) compilePatterns(schema interface{}) {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		for key, val := range s {
+			if pattern, ok := val.(string); ok && key == "pattern" {
+				v.patterns[pattern], _ = regexp.Compile(pattern)
+				continue
+			}
+			if patternProperties, ok := val.(map[string]interface{}); ok && key == "patternProperties" {
+				for pattern := range patternProperties {
+					v.patterns[pattern], _ = regexp.Compile(pattern)
+				}
+			}
+			v.compilePatterns(val)
+		}
+	case []interface{}:
+		for _, val := range s {
+			v.compilePatterns(val)
+		}
+	}
+}
+
+// HasDefinition checks whether the schema contains the definition
+func (v *Validator) HasDefinition(definition string) bool {
+	_, ok := v.definitions[definition]
+	return ok
+}
+
+// Validate validates the document against the definition of the schema, the document is converted to
+// its JSON representation before validation
+func (v *Validator) Validate(definition string, doc interface{}) error {
+	schema, ok := v.definitions[definition]
+	if !ok {
+		return fmt.Errorf("definition %v not found in the schema", definition)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var instance interface{}
+	if err = json.Unmarshal(data, &instance); err != nil {
+		return err
+	}
+	if errs := v.validate(schema, instance, ""); len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// ValidateTenant validates the tenant declaration
+func (v *Validator) ValidateTenant(tenant interface{}) error {
+	return v.Validate(TenantDefinition, tenant)
+}
+
+func (v *Validator) validate(schema interface{}, instance interface{}, path string) []string {
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			return []string{fmt.Sprintf("%s: is not allowed", pathOf(path))}
+		}
+		return nil
+	case map[string]interface{}:
+		// $ref overrides the sibling keywords in draft-07
+		if ref, ok := s["$ref"].(string); ok {
+			refSchema, err := v.resolve(ref)
+			if err != nil {
+				return []string{fmt.Sprintf("%s: %v", pathOf(path), err)}
+			}
+			return v.validate(refSchema, instance, path)
+		}
+		return v.validateKeywords(s, instance, path)
+	}
+	return nil
+}
+
+func (v *Validator) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, definitionsRef) {
+		return nil, fmt.Errorf("unsupported reference %v", ref)
+	}
+	schema, ok := v.definitions[strings.TrimPrefix(ref, definitionsRef)]
+	if !ok {
+		return nil, fmt.Errorf("unresolved reference %v", ref)
+	}
+	return schema, nil
+}
+
+func (v *Validator) validateKeywords(s map[string]interface{}, instance interface{}, path string) []string {
+	if t, ok := s["type"]; ok && !matchesType(t, instance) {
+		return []string{fmt.Sprintf("%s: should be of type %v", pathOf(path), typeNames(t))}
+	}
+	var errs []string
+	if c, ok := s["const"]; ok && !reflect.DeepEqual(c, instance) {
+		errs = append(errs, fmt.Sprintf("%s: should be equal to %v", pathOf(path), toJSON(c)))
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && !contains(enum, instance) {
+		errs = append(errs, fmt.Sprintf("%s: should be one of %v", pathOf(path), toJSON(enum)))
+	}
+
+	switch val := instance.(type) {
+	case string:
+		errs = append(errs, v.validateString(s, val, path)...)
+	case float64:
+		errs = append(errs, validateNumber(s, val, path)...)
+	case map[string]interface{}:
+		errs = append(errs, v.validateObject(s, val, path)...)
+	case []interface{}:
+		errs = append(errs, v.validateArray(s, val, path)...)
+	}
+
+	if allOf, ok := s["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			errs = append(errs, v.validate(sub, instance, path)...)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]interface{}); ok && v.countMatches(anyOf, instance, path, 1) == 0 {
+		errs = append(errs, fmt.Sprintf("%s: should match at least one of the schemas in anyOf", pathOf(path)))
+	}
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		if matches := v.countMatches(oneOf, instance, path, 2); matches != 1 {
+			errs = append(errs, fmt.Sprintf("%s: should match exactly one of the schemas in oneOf, matched %d",
+				pathOf(path), matches))
+		}
+	}
+	if not, ok := s["not"]; ok && len(v.validate(not, instance, path)) == 0 {
+		errs = append(errs, fmt.Sprintf("%s: should not match the schema in not", pathOf(path)))
+	}
+	if cond, ok := s["if"]; ok {
+		if len(v.validate(cond, instance, path)) == 0 {
+			if then, ok := s["then"]; ok {
+				errs = append(errs, v.validate(then, instance, path)...)
+			}
+		} else if els, ok := s["else"]; ok {
+			errs = append(errs, v.validate(els, instance, path)...)
+		}
+	}
+	return errs
+}
+
+// countMatches counts the schemas matching the instance up to the limit
+func (v *Validator) countMatches(schemas []interface{}, instance interface{}, path string, limit int) int {
+	matches := 0
+	for _, sub := range schemas {
+		if len(v.validate(sub, instance, path)) == 0 {
+			matches++
+			if matches == limit {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func (v *Validator) validateString(s map[string]interface{}, val string, path string) []string {
+	var errs []string
+	length := float64(utf8.RuneCountInString(val))
+	if min, ok := s["minLength"].(float64); ok && length < min {
+		errs = append(errs, fmt.Sprintf("%s: should not be shorter than %v characters", pathOf(path), min))
+	}
+	if max, ok := s["maxLength"].(float64); ok && length > max {
+		errs = append(errs, fmt.Sprintf("%s: should not be longer than %v characters", pathOf(path), max))
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		if re := v.patterns[pattern]; re != nil && !re.MatchString(val) {
+			errs = append(errs, fmt.Sprintf("%s: should match pattern %q", pathOf(path), pattern))
+		}
+	}
+	return errs
+}
+
+func validateNumber(s map[string]interface{}, val float64, path string) []string {
+	var errs []string
+	if min, ok := s["minimum"].(float64); ok && val < min {
+		errs = append(errs, fmt.Sprintf("%s: should be >= %v", pathOf(path), min))
+	}
+	if max, ok := s["maximum"].(float64); ok && val > max {
+		errs = append(errs, fmt.Sprintf("%s: should be <= %v", pathOf(path), max))
+	}
+	if min, ok := s["exclusiveMinimum"].(float64); ok && val <= min {
+		errs = append(errs, fmt.Sprintf("%s: should be > %v", pathOf(path), min))
+	}
+	if max, ok := s["exclusiveMaximum"].(float64); ok && val >= max {
+		errs = append(errs, fmt.Sprintf("%s: should be < %v", pathOf(path), max))
+	}
+	if multipleOf, ok := s["multipleOf"].(float64); ok && multipleOf > 0 {
+		if q := val / multipleOf; q != math.Trunc(q) {
+			errs = append(errs, fmt.Sprintf("%s: should be a multiple of %v", pathOf(path), multipleOf))
+		}
+	}
+	return errs
+}
+
+func (v *Validator) validateObject(s map[string]interface{}, val map[string]interface{}, path string) []string {
+	var errs []string
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, found := val[name]; !found {
+					errs = append(errs, fmt.Sprintf("%s: missing required property %q", pathOf(path), name))
+				}
+			}
+		}
+	}
+	if min, ok := s["minProperties"].(float64); ok && float64(len(val)) < min {
+		errs = append(errs, fmt.Sprintf("%s: should not have fewer than %v properties", pathOf(path), min))
+	}
+	if max, ok := s["maxProperties"].(float64); ok && float64(len(val)) > max {
+		errs = append(errs, fmt.Sprintf("%s: should not have more than %v properties", pathOf(path), max))
+	}
+
+	properties, _ := s["properties"].(map[string]interface{})
+	patternProperties, _ := s["patternProperties"].(map[string]interface{})
+	additionalProperties, hasAdditional := s["additionalProperties"]
+	propertyNames, hasPropertyNames := s["propertyNames"]
+	dependencies, _ := s["dependencies"].(map[string]interface{})
+
+	// iterate in order, so that the errors are reported consistently
+	names := make([]string, 0, len(val))
+	for name := range val {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propPath := path + "/" + name
+		if hasPropertyNames {
+			if len(v.validate(propertyNames, name, propPath)) > 0 {
+				errs = append(errs, fmt.Sprintf("%s: property name is not valid", pathOf(propPath)))
+			}
+		}
+		matched := false
+		if propSchema, ok := properties[name]; ok {
+			matched = true
+			errs = append(errs, v.validate(propSchema, val[name], propPath)...)
+		}
+		for pattern, propSchema := range patternProperties {
+			if re := v.patterns[pattern]; re != nil && re.MatchString(name) {
+				matched = true
+				errs = append(errs, v.validate(propSchema, val[name], propPath)...)
+			}
+		}
+		if !matched && hasAdditional {
+			if allowed, ok := additionalProperties.(bool); ok && !allowed {
+				errs = append(errs, fmt.Sprintf("%s: additional property is not allowed", pathOf(propPath)))
+			} else {
+				errs = append(errs, v.validate(additionalProperties, val[name], propPath)...)
+			}
+		}
+		if dependency, ok := dependencies[name]; ok {
+			if required, ok := dependency.([]interface{}); ok {
+				for _, r := range required {
+					if dep, ok := r.(string); ok {
+						if _, found := val[dep]; !found {
+							errs = append(errs, fmt.Sprintf("%s: property %q is required along with %q",
+								pathOf(path), dep, name))
+						}
+					}
+				}
+			} else {
+				errs = append(errs, v.validate(dependency, val, path)...)
+			}
+		}
+	}
+	return errs
+}
+
+func (v *Validator) validateArray(s map[string]interface{}, val []interface{}, path string) []string {
+	var errs []string
+	if min, ok := s["minItems"].(float64); ok && float64(len(val)) < min {
+		errs = append(errs, fmt.Sprintf("%s: should not have fewer than %v items", pathOf(path), min))
+	}
+	if max, ok := s["maxItems"].(float64); ok && float64(len(val)) > max {
+		errs = append(errs, fmt.Sprintf("%s: should not have more than %v items", pathOf(path), max))
+	}
+	if unique, ok := s["uniqueItems"].(bool); ok && unique {
+	duplicates:
+		for i := range val {
+			for j := i + 1; j < len(val); j++ {
+				if reflect.DeepEqual(val[i], val[j]) {
+					errs = append(errs, fmt.Sprintf("%s: should not have duplicate items", pathOf(path)))
+					break duplicates
+				}
+			}
+		}
+	}
+	switch items := s["items"].(type) {
+	case []interface{}:
+		for i, item := range val {
+			itemPath := fmt.Sprintf("%s/%d", path, i)
+			if i < len(items) {
+				errs = append(errs, v.validate(items[i], item, itemPath)...)
+			} else if additionalItems, ok := s["additionalItems"]; ok {
+				errs = append(errs, v.validate(additionalItems, item, itemPath)...)
+			}
+		}
+	case map[string]interface{}, bool:
+		for i, item := range val {
+			errs = append(errs, v.validate(items, item, fmt.Sprintf("%s/%d", path, i))...)
+		}
+	}
+	if contained, ok := s["contains"]; ok {
+		found := false
+		for i, item := range val {
+			if len(v.validate(contained, item, fmt.Sprintf("%s/%d", path, i))) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Sprintf("%s: should contain a valid item", pathOf(path)))
+		}
+	}
+	return errs
+}
+
+func matchesType(t interface{}, instance interface{}) bool {
+	switch types := t.(type) {
+	case string:
+		return isType(types, instance)
+	case []interface{}:
+		for _, name := range types {
+			if n, ok := name.(string); ok && isType(n, instance) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func isType(name string, instance interface{}) bool {
+	switch name {
+	case "null":
+		return instance == nil
+	case "boolean":
+		_, ok := instance.(bool)
+		return ok
+	case "string":
+		_, ok := instance.(string)
+		return ok
+	case "number":
+		_, ok := instance.(float64)
+		return ok
+	case "integer":
+		val, ok := instance.(float64)
+		return ok && val == math.Trunc(val)
+	case "object":
+		_, ok := instance.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := instance.([]interface{})
+		return ok
+	}
+	return false
+}
+
+func typeNames(t interface{}) string {
+	if types, ok := t.([]interface{}); ok {
+		names := make([]string, 0, len(types))
+		for _, name := range types {
+			names = append(names, fmt.Sprintf("%v", name))
+		}
+		return strings.Join(names, ",")
+	}
+	return fmt.Sprintf("%v", t)
+}
+
+func contains(values []interface{}, instance interface{}) bool {
+	for _, val := range values {
+		if reflect.DeepEqual(val, instance) {
+			return true
+		}
+	}
+	return false
+}
+
+func toJSON(val interface{}) string {
+	data, _ := json.Marshal(val)
+	return string(data)
+}
+
+func pathOf(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
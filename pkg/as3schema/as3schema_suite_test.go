@@ -0,0 +1,13 @@
+package as3schema_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestAS3Schema(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "AS3 Schema Suite")
+}
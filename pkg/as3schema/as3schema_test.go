@@ -0,0 +1,173 @@
+package as3schema
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const testSchema = `{
+  "definitions": {
+    "Tenant": {
+      "type": "object",
+      "properties": {
+        "class": {"type": "string", "const": "Tenant"},
+        "label": {"type": "string", "maxLength": 8, "pattern": "^[a-z]+$"}
+      },
+      "required": ["class"],
+      "propertyNames": {"pattern": "^[A-Za-z][0-9A-Za-z_.-]*$"},
+      "additionalProperties": {"$ref": "#/definitions/Application"}
+    },
+    "Application": {
+      "type": "object",
+      "properties": {
+        "class": {"const": "Application"},
+        "template": {"enum": ["shared", "generic"]}
+      },
+      "additionalProperties": {
+        "type": "object",
+        "if": {"properties": {"class": {"const": "Pool"}}, "required": ["class"]},
+        "then": {"$ref": "#/definitions/Pool"},
+        "else": {"required": ["class"]}
+      }
+    },
+    "Pool": {
+      "type": "object",
+      "properties": {
+        "class": {"type": "string"},
+        "minimumMonitors": {"type": ["integer", "string"], "minimum": 1},
+        "members": {
+          "type": "array",
+          "maxItems": 2,
+          "uniqueItems": true,
+          "items": {
+            "type": "object",
+            "properties": {"servicePort": {"type": "integer", "minimum": 0, "maximum": 65535}},
+            "dependencies": {"adminState": ["servicePort"]}
+          }
+        },
+        "loadBalancingMode": {"oneOf": [{"enum": ["round-robin"]}, {"type": "string", "pattern": "^least-"}]}
+      },
+      "additionalProperties": false
+    }
+  }
+}`
+
+var _ = Describe("AS3 Schema Validator", func() {
+	var validator *Validator
+
+	BeforeEach(func() {
+		var err error
+		validator, err = New([]byte(testSchema))
+		Expect(err).To(BeNil())
+	})
+
+	It("rejects the invalid schema", func() {
+		_, err := New([]byte(`{"definitions": `))
+		Expect(err).NotTo(BeNil())
+		_, err = New([]byte(`{}`))
+		Expect(err).NotTo(BeNil())
+		_, err = NewValidator("/nonexistent/as3-schema.json")
+		Expect(err).NotTo(BeNil())
+		Expect(validator.Validate("Unknown", map[string]interface{}{})).NotTo(Succeed())
+	})
+
+	It("validates a valid tenant", func() {
+		tenant := map[string]interface{}{
+			"class": "Tenant",
+			"label": "test",
+			"Shared": map[string]interface{}{
+				"class":    "Application",
+				"template": "shared",
+				"pool1": map[string]interface{}{
+					"class":             "Pool",
+					"minimumMonitors":   1,
+					"loadBalancingMode": "least-connections-member",
+					"members": []interface{}{
+						map[string]interface{}{"servicePort": 80, "adminState": "enable"},
+						map[string]interface{}{"servicePort": 8080},
+					},
+				},
+				"monitor1": map[string]interface{}{"class": "Monitor"},
+			},
+		}
+		Expect(validator.ValidateTenant(tenant)).To(Succeed())
+	})
+
+	It("reports the violations of the schema", func() {
+		tenant := map[string]interface{}{
+			"label": "Invalid label",
+			"_app":  map[string]interface{}{"class": "Application"},
+			"Shared": map[string]interface{}{
+				"class":    "Application",
+				"template": "http",
+				"pool1": map[string]interface{}{
+					"class":             "Pool",
+					"minimumMonitors":   1.5,
+					"loadBalancingMode": "ratio",
+					"monitors":          []interface{}{},
+					"members": []interface{}{
+						map[string]interface{}{"servicePort": 70000},
+						map[string]interface{}{"adminState": "enable"},
+						map[string]interface{}{"adminState": "enable"},
+					},
+				},
+				"monitor1": map[string]interface{}{},
+			},
+		}
+		err := validator.ValidateTenant(tenant)
+		Expect(err).NotTo(BeNil())
+		Expect(err.(*ValidationError).Errors).To(ConsistOf(
+			`/: missing required property "class"`,
+			`/_app: property name is not valid`,
+			`/label: should not be longer than 8 characters`,
+			`/label: should match pattern "^[a-z]+$"`,
+			`/Shared/monitor1: missing required property "class"`,
+			`/Shared/pool1/loadBalancingMode: should match exactly one of the schemas in oneOf, matched 0`,
+			`/Shared/pool1/members: should not have more than 2 items`,
+			`/Shared/pool1/members: should not have duplicate items`,
+			`/Shared/pool1/members/0/servicePort: should be <= 65535`,
+			`/Shared/pool1/members/1: property "servicePort" is required along with "adminState"`,
+			`/Shared/pool1/members/2: property "servicePort" is required along with "adminState"`,
+			`/Shared/pool1/minimumMonitors: should be of type integer,string`,
+			`/Shared/pool1/monitors: additional property is not allowed`,
+			`/Shared/template: should be one of ["shared","generic"]`,
+		))
+		Expect(err.Error()).To(HaveSuffix("and 9 more errors"))
+	})
+
+	It("validates the tenants against the bundled AS3 schema", func() {
+		validator, err := NewValidator("../../schemas/as3-schema-3.48.0-10-cis.json")
+		Expect(err).To(BeNil())
+		Expect(validator.HasDefinition(TenantDefinition)).To(BeTrue())
+		tenant := map[string]interface{}{
+			"class": "Tenant",
+			"label": "test",
+			"Shared": map[string]interface{}{
+				"class":    "Application",
+				"template": "shared",
+				"vs1": map[string]interface{}{
+					"class":            "Service_HTTP",
+					"virtualAddresses": []interface{}{"10.1.1.1"},
+					"virtualPort":      80,
+					"snat":             "auto",
+					"pool":             "pool1",
+				},
+				"pool1": map[string]interface{}{
+					"class": "Pool",
+					"members": []interface{}{map[string]interface{}{
+						"addressDiscovery": "static",
+						"serverAddresses":  []interface{}{"10.244.1.5"},
+						"servicePort":      8080,
+					}},
+				},
+			},
+		}
+		Expect(validator.ValidateTenant(tenant)).To(Succeed())
+		Expect(validator.ValidateTenant(map[string]interface{}{"class": "Tenant"})).To(Succeed())
+
+		tenant["Shared"].(map[string]interface{})["vs1"].(map[string]interface{})["virtualPort"] = "http"
+		err = validator.ValidateTenant(tenant)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(HavePrefix("/Shared/vs1/virtualPort: "))
+	})
+})
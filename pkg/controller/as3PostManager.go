@@ -3,6 +3,7 @@ package controller
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	"reflect"
 	"sort"
@@ -1069,13 +1070,30 @@ func (svc *as3Service) addPersistenceMethod(persistenceProfile string) {
 func (req *RequestHandler) createAS3Config(rsConfig ResourceConfigRequest, pm *PostManager) as3Config {
 	as3cfg := as3Config{
 		id:                    rsConfig.reqMeta.id,
+		userAgent:             req.userAgent,
 		tenantResponseMap:     make(map[string]tenantResponse),
+		tenantTaskIdMap:       make(map[string]string),
 		failedTenants:         make(map[string]struct{}),
+		invalidTenants:        make(map[string]string),
 		incomingTenantDeclMap: make(map[string]as3Tenant),
 	}
 	for tenant, cfg := range pm.AS3PostManager.createAS3BIGIPConfig(rsConfig.bigIpResourceConfig, pm.defaultPartition, pm.cachedTenantDeclMap) {
 		if !reflect.DeepEqual(cfg, pm.cachedTenantDeclMap[tenant]) ||
 			(req.PrimaryClusterHealthProbeParams.EndPoint != "" && req.PrimaryClusterHealthProbeParams.statusChanged) {
+			// Rolled back tenants are not posted until their resources change
+			if pm.declarationHistory != nil && pm.declarationHistory.isPinned(tenant, cfg) {
+				log.Debugf("%v[AS3]%v Tenant %v is rolled back, it's not posted until its resources change",
+					getRequestPrefix(as3cfg.id), pm.postManagerPrefix, tenant)
+				continue
+			}
+			// Invalid tenants are not posted, they are reported as failed along with the valid tenants
+			if err := pm.validateTenant(cfg); err != nil {
+				log.Errorf("%v[AS3]%v Tenant %v failed the AS3 schema validation: %v", getRequestPrefix(as3cfg.id),
+					pm.postManagerPrefix, tenant, err)
+				prometheus.AS3ValidationFailures.WithLabelValues(tenant).Inc()
+				as3cfg.invalidTenants[tenant] = fmt.Sprintf("AS3 schema validation failed: %v", err)
+				continue
+			}
 			as3cfg.incomingTenantDeclMap[tenant] = cfg.(as3Tenant)
 			as3cfg.tenantResponseMap[tenant] = tenantResponse{}
 		} else {
@@ -1089,8 +1107,17 @@ func (req *RequestHandler) createAS3Config(rsConfig ResourceConfigRequest, pm *P
 	return as3cfg
 }
 
+// validateTenant validates the tenant declaration against the AS3 schema, if the schema is loaded
+func (postMgr *PostManager) validateTenant(tenantDecl interface{}) error {
+	if postMgr.as3Validator == nil {
+		return nil
+	}
+	return postMgr.as3Validator.ValidateTenant(tenantDecl)
+}
+
 func (as3PM *AS3PostManager) createAS3BIGIPConfig(config BigIpResourceConfig, partition string, cachedTenantDeclMap map[string]as3Tenant) as3ADC {
 	adc := as3PM.createAS3LTMConfigADC(config, partition, cachedTenantDeclMap)
+	adc = as3PM.createAS3GTMConfigADC(config, adc, partition, cachedTenantDeclMap)
 	return adc
 }
 
@@ -1099,6 +1126,10 @@ func (postMgr *AS3PostManager) createAS3LTMConfigADC(config BigIpResourceConfig,
 	cisLabel := partition
 
 	for tenant := range cachedTenantDeclMap {
+		// GTM tenant is handled by createAS3GTMConfigADC
+		if tenant == getGTMTenantName(partition) {
+			continue
+		}
 		if _, ok := config.ltmConfig[tenant]; !ok {
 			// Remove partition
 			adc[tenant] = getDeletedTenantDeclaration(partition, tenant, cisLabel)
@@ -1140,12 +1171,115 @@ func (postMgr *AS3PostManager) createAS3LTMConfigADC(config BigIpResourceConfig,
 	return adc
 }
 
+// getGTMTenantName returns the name of the AS3 tenant holding the GTM config of a BIG-IP
+func getGTMTenantName(defaultPartition string) string {
+	return defaultPartition + "_gtm"
+}
+
+// hasWideIPs checks whether the GTM config contains at least one WideIP
+func (gtmConfig GTMConfig) hasWideIPs() bool {
+	for _, gtmPartitionConfig := range gtmConfig {
+		if len(gtmPartitionConfig.WideIPs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// createAS3GTMConfigADC creates the GTM tenant with GSLB domains, pools and monitors for the WideIPs.
+// All the GTM partitions are combined into a single GTM tenant of the BIG-IP, the tenant is flushed once
+// the last WideIP is removed.
+func (postMgr *AS3PostManager) createAS3GTMConfigADC(config BigIpResourceConfig, adc as3ADC, partition string, cachedTenantDeclMap map[string]as3Tenant) as3ADC {
+	cisLabel := partition
+	gtmTenant := getGTMTenantName(partition)
+
+	if !config.gtmConfig.hasWideIPs() {
+		if _, ok := cachedTenantDeclMap[gtmTenant]; ok {
+			// Remove GTM partition
+			adc[gtmTenant] = getDeletedTenantDeclaration(partition, gtmTenant, cisLabel)
+		}
+		return adc
+	}
+
+	// Create Shared as3Application object
+	sharedApp := as3Application{}
+	sharedApp["class"] = "Application"
+	sharedApp["template"] = "shared"
+
+	for _, gtmPartitionConfig := range config.gtmConfig {
+		for domainName, wideIP := range gtmPartitionConfig.WideIPs {
+			gslbDomain := as3GLSBDomain{
+				Class:              "GSLB_Domain",
+				DomainName:         wideIP.DomainName,
+				RecordType:         wideIP.RecordType,
+				LBMode:             wideIP.LBMethod,
+				PersistenceEnabled: wideIP.PersistenceEnabled,
+				PersistCidrIPv4:    wideIP.PersistCidrIPv4,
+				PersistCidrIPv6:    wideIP.PersistCidrIPv6,
+				TTLPersistence:     wideIP.TTLPersistence,
+				Pools:              make([]as3GSLBDomainPool, 0, len(wideIP.Pools)),
+			}
+			if wideIP.ClientSubnetPreferred != nil {
+				gslbDomain.ClientSubnetPreferred = wideIP.ClientSubnetPreferred
+			}
+			for _, pool := range wideIP.Pools {
+				gslbPool := as3GSLBPool{
+					Class:          "GSLB_Pool",
+					RecordType:     pool.RecordType,
+					LBMode:         pool.LBMethod,
+					LBModeFallback: pool.LBModeFallBack,
+					Members:        make([]as3GSLBPoolMemberA, 0, len(pool.Members)),
+					Monitors:       make([]as3ResourcePointer, 0, len(pool.Monitors)),
+				}
+				// sort the members so that the tenant declaration doesn't change with the order of processing
+				members := make([]string, len(pool.Members))
+				copy(members, pool.Members)
+				sort.Strings(members)
+				for _, mem := range members {
+					gslbPool.Members = append(gslbPool.Members, as3GSLBPoolMemberA{
+						Enabled: true,
+						Server: as3ResourcePointer{
+							BigIP: pool.DataServer,
+						},
+						VirtualServer: mem,
+					})
+				}
+				for _, mon := range pool.Monitors {
+					gslbMon := as3GSLBMonitor{
+						Class:    "GSLB_Monitor",
+						Interval: mon.Interval,
+						Type:     mon.Type,
+						Send:     mon.Send,
+						Receive:  mon.Recv,
+						Timeout:  mon.Timeout,
+					}
+					gslbPool.Monitors = append(gslbPool.Monitors, as3ResourcePointer{
+						Use: mon.Name,
+					})
+					sharedApp[mon.Name] = gslbMon
+				}
+				gslbDomain.Pools = append(gslbDomain.Pools, as3GSLBDomainPool{Use: pool.Name, Ratio: pool.Ratio})
+				sharedApp[pool.Name] = gslbPool
+			}
+			sharedApp[strings.Replace(domainName, "*", "wildcard", -1)] = gslbDomain
+		}
+	}
+
+	// Create AS3 Tenant
+	adc[gtmTenant] = as3Tenant{
+		"class":              "Tenant",
+		as3SharedApplication: sharedApp,
+		"label":              cisLabel,
+	}
+	return adc
+}
+
 // removeDeletedTenantsForBigIP will check the tenant exists on bigip or not
 // if tenant exists and rsConfig does not have tenant, update the tenant with empty PartitionConfig
 func removeDeletedTenantsForBigIP(rsConfig *BigIpResourceConfig, cisLabel string, as3Config map[string]interface{}, partition string) {
 	for k, v := range as3Config {
 		if decl, ok := v.(map[string]interface{}); ok {
-			if label, found := decl["label"]; found && label == cisLabel && k != partition+"_gtm" {
+			if label, found := decl["label"]; found && label == cisLabel && k != getGTMTenantName(partition) {
 				if _, ok := rsConfig.ltmConfig[k]; !ok {
 					// adding an empty tenant to delete the tenant from BIGIP
 					priority := 1
@@ -0,0 +1,461 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ficIPAMProvider allocates the IP addresses with F5 IPAM Controller through the IPAM CR
+type ficIPAMProvider struct {
+	ctlr *Controller
+}
+
+func (fic *ficIPAMProvider) RequestIP(ipamLabel string, host string, key string) (string, int) {
+	return fic.ctlr.requestIP(ipamLabel, host, key)
+}
+
+func (fic *ficIPAMProvider) ReleaseIP(ipamLabel string, host string, key string) string {
+	return fic.ctlr.releaseIP(ipamLabel, host, key)
+}
+
+// getIPAMProvider returns the provider which allocates the virtual server addresses, nil when IPAM is not enabled
+func (ctlr *Controller) getIPAMProvider() IPAMProvider {
+	if ctlr.ipamCli != nil {
+		return &ficIPAMProvider{ctlr: ctlr}
+	}
+	if ctlr.builtinIPAM != nil {
+		return ctlr.builtinIPAM
+	}
+	return nil
+}
+
+// processIPAMConfig sets up the embedded IPAM with the IP address ranges of the DeployConfig CR
+func (ctlr *Controller) processIPAMConfig(ipamConfig cisapiv1.IPAMConfig, namespace string) {
+	if ctlr.builtinIPAM == nil && len(ipamConfig.Ranges) == 0 {
+		return
+	}
+	if ctlr.ipamCli != nil {
+		log.Warningf("[IPAM] IP address ranges of the DeployConfig CR are ignored, as CIS is deployed with F5 IPAM Controller")
+		return
+	}
+	if ctlr.builtinIPAM == nil {
+		var kubeClient kubernetes.Interface
+		if ctlr.clientsets != nil {
+			kubeClient = ctlr.clientsets.kubeClient
+		}
+		ctlr.builtinIPAM = NewBuiltinIPAM(kubeClient, namespace, ctlr.getStaticVirtualAddresses)
+		ctlr.builtinIPAM.loadAllocations()
+	}
+	ctlr.builtinIPAM.updateRanges(ipamConfig)
+}
+
+// getStaticVirtualAddresses returns the virtual server addresses specified in the custom resources and the Services of
+// type LoadBalancer
+func (ctlr *Controller) getStaticVirtualAddresses() map[string]struct{} {
+	addresses := make(map[string]struct{})
+	for _, vs := range ctlr.getAllVSFromMonitoredNamespaces() {
+		if vs.Spec.VirtualServerAddress != "" {
+			addresses[vs.Spec.VirtualServerAddress] = struct{}{}
+		}
+		for _, address := range vs.Spec.AdditionalVirtualServerAddresses {
+			addresses[address] = struct{}{}
+		}
+	}
+	for _, ts := range ctlr.getAllTSFromMonitoredNamespaces() {
+		if ts.Spec.VirtualServerAddress != "" {
+			addresses[ts.Spec.VirtualServerAddress] = struct{}{}
+		}
+	}
+	for _, il := range ctlr.getAllIngLinkFromMonitoredNamespaces() {
+		if il.Spec.VirtualServerAddress != "" {
+			addresses[il.Spec.VirtualServerAddress] = struct{}{}
+		}
+	}
+	namespaces := []string{""}
+	if !ctlr.watchingAllNamespaces() {
+		namespaces = namespaces[:0]
+		for ns := range ctlr.namespaces {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	for _, ns := range namespaces {
+		for _, svc := range ctlr.getAllLBServices(ns) {
+			lbAddresses, _ := getLBServiceAddresses(svc)
+			for _, address := range lbAddresses {
+				addresses[address] = struct{}{}
+			}
+		}
+	}
+	return addresses
+}
+
+// hasIPAMAddressConflict checks if the virtual server address of the resource is allocated by the embedded IPAM to
+// another host or resource
+func (ctlr *Controller) hasIPAMAddressConflict(obj runtime.Object, address string, host string, key string) bool {
+	if ctlr.builtinIPAM == nil {
+		return false
+	}
+	owner, found := ctlr.builtinIPAM.getAllocation(address)
+	if !found || (host != "" && owner == host) || owner == key {
+		return false
+	}
+	log.Errorf("[IPAM] VirtualServerAddress %v of %v is allocated by IPAM to %v", address, key, owner)
+	ctlr.recordEventf(obj, v1.EventTypeWarning, AddressConflict, "VirtualServerAddress %v is allocated by IPAM to %v",
+		address, owner)
+	return true
+}
+
+func NewBuiltinIPAM(kubeClient kubernetes.Interface, namespace string, staticAddresses func() map[string]struct{}) *BuiltinIPAM {
+	return &BuiltinIPAM{
+		ranges:          make(map[string][]ipRange),
+		allocations:     make(map[string]ipAllocation),
+		requested:       make(map[string]struct{}),
+		staticAddresses: staticAddresses,
+		kubeClient:      kubeClient,
+		namespace:       namespace,
+	}
+}
+
+// updateRanges replaces the IP address ranges, the IP addresses allocated earlier are retained
+func (ipam *BuiltinIPAM) updateRanges(ipamConfig cisapiv1.IPAMConfig) {
+	ranges := make(map[string][]ipRange)
+	for _, ipamRange := range ipamConfig.Ranges {
+		r, err := parseIPRange(ipamRange.Range)
+		if err != nil {
+			log.Errorf("[IPAM] Invalid IP address range %v of IPAM label %v: %v", ipamRange.Range, ipamRange.IPAMLabel, err)
+			continue
+		}
+		ranges[ipamRange.IPAMLabel] = append(ranges[ipamRange.IPAMLabel], r)
+	}
+	ipam.Lock()
+	ipam.ranges = ranges
+	ipam.Unlock()
+}
+
+// RequestIP allocates the first free IP address of the ranges of the IPAM label, the IP address is shared by the
+// resources with the same host
+func (ipam *BuiltinIPAM) RequestIP(ipamLabel string, host string, key string) (string, int) {
+	return ipam.requestIP(ipamLabel, host, key, "")
+}
+
+// RequestFamilyIP allocates the first free IP address of the IP family from the ranges of the IPAM label
+func (ipam *BuiltinIPAM) RequestFamilyIP(ipamLabel string, family v1.IPFamily, key string) (string, int) {
+	return ipam.requestIP(ipamLabel, "", key, family)
+}
+
+// requestIP allocates the IP address from the ranges of the IP family, or from all the ranges when family is empty
+func (ipam *BuiltinIPAM) requestIP(ipamLabel string, host string, key string, family v1.IPFamily) (string, int) {
+	id := host
+	if id == "" {
+		id = key
+	}
+	if ipamLabel == "" || id == "" {
+		return "", InvalidInput
+	}
+	ipam.Lock()
+	defer ipam.Unlock()
+	ranges, found := ipam.ranges[ipamLabel]
+	if !found {
+		log.Errorf("[IPAM] IP address range of IPAM label %v is not found in the DeployConfig CR", ipamLabel)
+		return "", InvalidInput
+	}
+	if family != "" {
+		var familyRanges []ipRange
+		for _, r := range ranges {
+			if getIPFamily(r.first) == family {
+				familyRanges = append(familyRanges, r)
+			}
+		}
+		if len(familyRanges) == 0 {
+			log.Debugf("[IPAM] %v address range of IPAM label %v is not found in the DeployConfig CR", family, ipamLabel)
+			return "", InvalidInput
+		}
+		ranges = familyRanges
+	}
+	ipam.requested[id] = struct{}{}
+	if allocation, ok := ipam.allocations[id]; ok {
+		if allocation.IPAMLabel == ipamLabel && (family == "" || getIPFamily(net.ParseIP(allocation.IP)) == family) {
+			return allocation.IP, Allocated
+		}
+		// IPAM label or IP family is updated, so the IP address is allocated from the ranges of the new label
+		log.Debugf("[IPAM] Releasing IP address %v of %v allocated with IPAM label %v", allocation.IP, id, allocation.IPAMLabel)
+		delete(ipam.allocations, id)
+	}
+	ip := ipam.getFreeIP(ranges)
+	if ip == "" {
+		log.Errorf("[IPAM] No IP address is available in the ranges of IPAM label %v for %v", ipamLabel, id)
+		return "", NotRequested
+	}
+	ipam.allocations[id] = ipAllocation{IPAMLabel: ipamLabel, Host: host, Key: key, IP: ip}
+	if err := ipam.saveAllocations(); err != nil {
+		log.Errorf("[IPAM] Could not persist the IP address allocations: %v", err)
+		delete(ipam.allocations, id)
+		return "", NotRequested
+	}
+	log.Debugf("[IPAM] Allocated IP address %v with IPAM label %v to %v", ip, ipamLabel, id)
+	return ip, Allocated
+}
+
+// ReleaseIP releases the IP address allocated with the IPAM label
+func (ipam *BuiltinIPAM) ReleaseIP(ipamLabel string, host string, key string) string {
+	id := host
+	if id == "" {
+		id = key
+	}
+	ipam.Lock()
+	defer ipam.Unlock()
+	allocation, found := ipam.allocations[id]
+	if !found || ipamLabel == "" || allocation.IPAMLabel != ipamLabel {
+		return ""
+	}
+	delete(ipam.allocations, id)
+	if err := ipam.saveAllocations(); err != nil {
+		log.Errorf("[IPAM] Could not persist the IP address allocations: %v", err)
+	}
+	log.Debugf("[IPAM] Released IP address %v of %v", allocation.IP, id)
+	return allocation.IP
+}
+
+// getAllocation returns the host or key the IP address is allocated to
+func (ipam *BuiltinIPAM) getAllocation(ip string) (string, bool) {
+	ipam.Lock()
+	defer ipam.Unlock()
+	for id, allocation := range ipam.allocations {
+		if net.ParseIP(allocation.IP).Equal(net.ParseIP(ip)) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// pruneAllocations releases the IP addresses of the resources which are deleted while CIS was down
+func (ipam *BuiltinIPAM) pruneAllocations() {
+	ipam.Lock()
+	defer ipam.Unlock()
+	if ipam.pruned {
+		return
+	}
+	ipam.pruned = true
+	pruned := false
+	for id, allocation := range ipam.allocations {
+		if _, found := ipam.requested[id]; !found {
+			log.Debugf("[IPAM] Releasing unused IP address %v of %v", allocation.IP, id)
+			delete(ipam.allocations, id)
+			pruned = true
+		}
+	}
+	if pruned {
+		if err := ipam.saveAllocations(); err != nil {
+			log.Errorf("[IPAM] Could not persist the IP address allocations: %v", err)
+		}
+	}
+}
+
+// getFreeIP returns the first IP address of the ranges which is neither allocated nor specified in a resource.
+// Only the used IP addresses are walked, so the scan is bounded by their number rather than by the size of the
+// ranges. The caller must hold the lock.
+func (ipam *BuiltinIPAM) getFreeIP(ranges []ipRange) string {
+	var used []net.IP
+	if ipam.staticAddresses != nil {
+		for address := range ipam.staticAddresses() {
+			if ip := net.ParseIP(address); ip != nil {
+				used = append(used, ip)
+			}
+		}
+	}
+	for _, allocation := range ipam.allocations {
+		if ip := net.ParseIP(allocation.IP); ip != nil {
+			used = append(used, ip)
+		}
+	}
+	for _, r := range ranges {
+		var inRange []net.IP
+		for _, ip := range used {
+			if len(r.first) == net.IPv4len {
+				ip = ip.To4()
+			} else if getIPFamily(ip) == getIPFamily(r.first) {
+				ip = ip.To16()
+			} else {
+				ip = nil
+			}
+			if ip != nil && bytes.Compare(ip, r.first) >= 0 && bytes.Compare(ip, r.last) <= 0 {
+				inRange = append(inRange, ip)
+			}
+		}
+		sort.Slice(inRange, func(i, j int) bool {
+			return bytes.Compare(inRange[i], inRange[j]) < 0
+		})
+		candidate := r.first
+		for _, ip := range inRange {
+			if bytes.Compare(ip, candidate) > 0 {
+				break
+			}
+			if ip.Equal(candidate) {
+				if candidate.Equal(r.last) {
+					candidate = nil
+					break
+				}
+				candidate = nextIP(candidate)
+			}
+		}
+		if candidate != nil {
+			return candidate.String()
+		}
+	}
+	return ""
+}
+
+// saveAllocations persists the allocations in the ConfigMap. The caller must hold the lock.
+func (ipam *BuiltinIPAM) saveAllocations() error {
+	if ipam.kubeClient == nil {
+		return nil
+	}
+	allocations := make([]ipAllocation, 0, len(ipam.allocations))
+	for _, allocation := range ipam.allocations {
+		allocations = append(allocations, allocation)
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].Host+allocations[i].Key < allocations[j].Host+allocations[j].Key
+	})
+	data, err := json.Marshal(allocations)
+	if err != nil {
+		return err
+	}
+	configMaps := ipam.kubeClient.CoreV1().ConfigMaps(ipam.namespace)
+	cm, err := configMaps.Get(context.TODO(), BuiltinIPAMConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      BuiltinIPAMConfigMapName,
+				Namespace: ipam.namespace,
+			},
+			Data: map[string]string{BuiltinIPAMAllocationsKey: string(data)},
+		}
+		_, err = configMaps.Create(context.TODO(), cm, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[BuiltinIPAMAllocationsKey] = string(data)
+	_, err = configMaps.Update(context.TODO(), cm, metav1.UpdateOptions{})
+	return err
+}
+
+// loadAllocations recovers the allocations persisted before the controller restart
+func (ipam *BuiltinIPAM) loadAllocations() {
+	if ipam.kubeClient == nil {
+		return
+	}
+	cm, err := ipam.kubeClient.CoreV1().ConfigMaps(ipam.namespace).Get(context.TODO(), BuiltinIPAMConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Errorf("[IPAM] Could not recover the IP address allocations: %v", err)
+		}
+		return
+	}
+	var allocations []ipAllocation
+	if err := json.Unmarshal([]byte(cm.Data[BuiltinIPAMAllocationsKey]), &allocations); err != nil {
+		log.Errorf("[IPAM] Could not recover the IP address allocations: %v", err)
+		return
+	}
+	ipam.Lock()
+	defer ipam.Unlock()
+	for _, allocation := range allocations {
+		id := allocation.Host
+		if id == "" {
+			id = allocation.Key
+		}
+		ipam.allocations[id] = allocation
+	}
+	log.Debugf("[IPAM] Recovered %v IP address allocations", len(allocations))
+}
+
+// getIPFamily returns the IP family of the IP address
+func getIPFamily(ip net.IP) v1.IPFamily {
+	if ip.To4() != nil {
+		return v1.IPv4Protocol
+	}
+	return v1.IPv6Protocol
+}
+
+// parseIPRange parses a CIDR or the first and the last IP address separated by a hyphen
+func parseIPRange(ipamRange string) (ipRange, error) {
+	ipamRange = strings.TrimSpace(ipamRange)
+	if strings.Contains(ipamRange, "/") {
+		ip, ipNet, err := net.ParseCIDR(ipamRange)
+		if err != nil {
+			return ipRange{}, err
+		}
+		first := ipNet.IP
+		last := make(net.IP, len(first))
+		for i := range first {
+			last[i] = first[i] | ^ipNet.Mask[i]
+		}
+		// the network and broadcast addresses of IPv4 and the subnet-router anycast address of IPv6 are not allocated
+		if ones, bits := ipNet.Mask.Size(); bits-ones >= 2 {
+			first = nextIP(first)
+			if ip.To4() != nil {
+				last = prevIP(last)
+			}
+		}
+		return ipRange{first: first, last: last}, nil
+	}
+	bounds := strings.Split(ipamRange, "-")
+	if len(bounds) != 2 {
+		return ipRange{}, fmt.Errorf("range is neither a CIDR nor the first and the last IP address separated by a hyphen")
+	}
+	first := net.ParseIP(strings.TrimSpace(bounds[0]))
+	last := net.ParseIP(strings.TrimSpace(bounds[1]))
+	if first == nil || last == nil {
+		return ipRange{}, fmt.Errorf("invalid IP address")
+	}
+	if first.To4() != nil && last.To4() != nil {
+		first, last = first.To4(), last.To4()
+	} else if first.To4() != nil || last.To4() != nil {
+		return ipRange{}, fmt.Errorf("range mixes IPv4 and IPv6 addresses")
+	}
+	if bytes.Compare(first, last) > 0 {
+		return ipRange{}, fmt.Errorf("first IP address is greater than the last IP address")
+	}
+	return ipRange{first: first, last: last}, nil
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func prevIP(ip net.IP) net.IP {
+	prev := make(net.IP, len(ip))
+	copy(prev, ip)
+	for i := len(prev) - 1; i >= 0; i-- {
+		prev[i]--
+		if prev[i] != 0xff {
+			break
+		}
+	}
+	return prev
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"context"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("Builtin IPAM", func() {
+	var ipam *BuiltinIPAM
+	var staticAddresses map[string]struct{}
+	BeforeEach(func() {
+		staticAddresses = map[string]struct{}{"10.8.3.10": {}}
+		ipam = NewBuiltinIPAM(fake.NewSimpleClientset(), "kube-system", func() map[string]struct{} {
+			return staticAddresses
+		})
+		ipam.updateRanges(cisapiv1.IPAMConfig{Ranges: []cisapiv1.IPAMRange{
+			{IPAMLabel: "Dev", Range: "10.8.3.10-10.8.3.12"},
+			{IPAMLabel: "Test", Range: "10.8.4.0/30"},
+			{IPAMLabel: "Dev6", Range: "2001:db8::/126"},
+		}})
+	})
+
+	It("Parses the IP address ranges", func() {
+		r, err := parseIPRange("10.8.3.0/24")
+		Expect(err).To(BeNil())
+		Expect(r.first.String()).To(Equal("10.8.3.1"))
+		Expect(r.last.String()).To(Equal("10.8.3.254"))
+		r, err = parseIPRange("10.8.3.5/32")
+		Expect(err).To(BeNil())
+		Expect(r.first.String()).To(Equal("10.8.3.5"))
+		Expect(r.last.String()).To(Equal("10.8.3.5"))
+		r, err = parseIPRange("2001:db8::/120")
+		Expect(err).To(BeNil())
+		Expect(r.first.String()).To(Equal("2001:db8::1"))
+		Expect(r.last.String()).To(Equal("2001:db8::ff"))
+		r, err = parseIPRange(" 2001:db8::10 - 2001:db8::20 ")
+		Expect(err).To(BeNil())
+		Expect(r.last.String()).To(Equal("2001:db8::20"))
+		_, err = parseIPRange("10.8.3.20-10.8.3.10")
+		Expect(err).NotTo(BeNil())
+		_, err = parseIPRange("10.8.3.10-2001:db8::20")
+		Expect(err).NotTo(BeNil())
+		_, err = parseIPRange("10.8.3.10")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("Allocates and releases the IP addresses", func() {
+		// static virtual server addresses are not allocated
+		ip, status := ipam.RequestIP("Dev", "foo.com", "default/foo.com_host")
+		Expect(status).To(Equal(Allocated))
+		Expect(ip).To(Equal("10.8.3.11"))
+		ip, _ = ipam.RequestIP("Dev", "foo.com", "default/foo.com_host")
+		Expect(ip).To(Equal("10.8.3.11"))
+		ip, _ = ipam.RequestIP("Dev", "", "default/ts_ts")
+		Expect(ip).To(Equal("10.8.3.12"))
+		_, status = ipam.RequestIP("Dev", "", "default/svc_svc")
+		Expect(status).To(Equal(NotRequested))
+		_, status = ipam.RequestIP("Prod", "", "default/svc_svc")
+		Expect(status).To(Equal(InvalidInput))
+		ip, _ = ipam.RequestIP("Dev6", "", "default/svc_svc")
+		Expect(ip).To(Equal("2001:db8::1"))
+
+		// updated IPAM label allocates from the ranges of the new label
+		ip, _ = ipam.RequestIP("Test", "", "default/ts_ts")
+		Expect(ip).To(Equal("10.8.4.1"))
+		ip, _ = ipam.RequestIP("Dev", "", "default/svc_svc")
+		Expect(ip).To(Equal("10.8.3.12"))
+
+		Expect(ipam.ReleaseIP("Dev", "foo.com", "default/foo.com_host")).To(Equal("10.8.3.11"))
+		Expect(ipam.ReleaseIP("Dev", "foo.com", "default/foo.com_host")).To(BeEmpty())
+		owner, found := ipam.getAllocation("10.8.4.1")
+		Expect(found).To(BeTrue())
+		Expect(owner).To(Equal("default/ts_ts"))
+	})
+
+	It("Allocates the IP addresses of each IP family", func() {
+		ipam.updateRanges(cisapiv1.IPAMConfig{Ranges: []cisapiv1.IPAMRange{
+			{IPAMLabel: "Dev", Range: "10.8.3.10-10.8.3.12"},
+			{IPAMLabel: "Dev", Range: "2001:db8::/126"},
+		}})
+		ip, status := ipam.RequestFamilyIP("Dev", v1.IPv6Protocol, "default/svc_svc_ipv6")
+		Expect(status).To(Equal(Allocated))
+		Expect(ip).To(Equal("2001:db8::1"))
+		ip, _ = ipam.RequestFamilyIP("Dev", v1.IPv4Protocol, "default/svc_svc_ipv4")
+		Expect(ip).To(Equal("10.8.3.11"))
+		_, status = ipam.RequestFamilyIP("Test", v1.IPv6Protocol, "default/ts_ts")
+		Expect(status).To(Equal(InvalidInput))
+		Expect(ipam.ReleaseIP("Dev", "", "default/svc_svc_ipv6")).To(Equal("2001:db8::1"))
+	})
+
+	It("Allocates the IP addresses of large IPv6 ranges", func() {
+		staticAddresses = map[string]struct{}{"2001:db8::3": {}, "2001:db8:1::1": {}, "10.8.3.10": {}}
+		ipam.updateRanges(cisapiv1.IPAMConfig{Ranges: []cisapiv1.IPAMRange{
+			{IPAMLabel: "Dev6", Range: "2001:db8::1-2001:db8::3"},
+			{IPAMLabel: "Dev6", Range: "2001:db8:1::/48"},
+		}})
+		ip, _ := ipam.RequestIP("Dev6", "", "default/svc1_svc1")
+		Expect(ip).To(Equal("2001:db8::1"))
+		ip, _ = ipam.RequestIP("Dev6", "", "default/svc2_svc2")
+		Expect(ip).To(Equal("2001:db8::2"))
+		// the first range is exhausted
+		ip, _ = ipam.RequestIP("Dev6", "", "default/svc3_svc3")
+		Expect(ip).To(Equal("2001:db8:1::2"))
+		Expect(ipam.ReleaseIP("Dev6", "", "default/svc1_svc1")).To(Equal("2001:db8::1"))
+		ip, _ = ipam.RequestIP("Dev6", "", "default/svc4_svc4")
+		Expect(ip).To(Equal("2001:db8::1"))
+	})
+
+	It("Persists the allocations in a ConfigMap", func() {
+		ipam.RequestIP("Dev", "foo.com", "default/foo.com_host")
+		ipam.RequestIP("Test", "", "default/ts_ts")
+		cm, err := ipam.kubeClient.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), BuiltinIPAMConfigMapName, metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		Expect(cm.Data[BuiltinIPAMAllocationsKey]).To(ContainSubstring("10.8.4.1"))
+
+		recovered := NewBuiltinIPAM(ipam.kubeClient, "kube-system", nil)
+		recovered.loadAllocations()
+		Expect(len(recovered.allocations)).To(Equal(2))
+		Expect(recovered.allocations["foo.com"].IP).To(Equal("10.8.3.11"))
+
+		// allocations which are not requested after the restart are released
+		recovered.updateRanges(cisapiv1.IPAMConfig{Ranges: []cisapiv1.IPAMRange{{IPAMLabel: "Test", Range: "10.8.4.0/30"}}})
+		ip, _ := recovered.RequestIP("Test", "", "default/ts_ts")
+		Expect(ip).To(Equal("10.8.4.1"))
+		recovered.pruneAllocations()
+		Expect(recovered.allocations).NotTo(HaveKey("foo.com"))
+		Expect(recovered.allocations).To(HaveKey("default/ts_ts"))
+	})
+
+	It("Detects the static virtual server addresses allocated by IPAM", func() {
+		mockCtlr := newMockController()
+		mockCtlr.builtinIPAM = ipam
+		ip, _ := ipam.RequestIP("Dev", "foo.com", "default/foo.com_host")
+		vs := test.NewVirtualServer("vs", "default", cisapiv1.VirtualServerSpec{Host: "bar.com"})
+		Expect(mockCtlr.hasIPAMAddressConflict(vs, ip, "bar.com", "default/bar.com_host")).To(BeTrue())
+		Expect(mockCtlr.hasIPAMAddressConflict(vs, ip, "foo.com", "default/foo.com_host")).To(BeFalse())
+		Expect(mockCtlr.hasIPAMAddressConflict(vs, "10.8.3.10", "bar.com", "default/bar.com_host")).To(BeFalse())
+		Expect(mockCtlr.getIPAMProvider()).To(Equal(ipam))
+	})
+})
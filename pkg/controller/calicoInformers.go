@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// calicoBlockAffinityGVR is the Calico BlockAffinity resource which assigns the IPAM blocks to the nodes
+var calicoBlockAffinityGVR = schema.GroupVersionResource{
+	Group:    "crd.projectcalico.org",
+	Version:  "v1",
+	Resource: "blockaffinities",
+}
+
+func (calicoInfr *CalicoInformer) start() {
+	log.Debugf("Starting calico blockAffinity informer")
+	go calicoInfr.blockAffinityInformer.Run(calicoInfr.stopCh)
+	cache.WaitForNamedCacheSync(
+		"F5 CIS Ingress Controller",
+		calicoInfr.stopCh,
+		calicoInfr.blockAffinityInformer.HasSynced,
+	)
+}
+
+func (calicoInfr *CalicoInformer) stop() {
+	log.Debugf("Stopping calico blockAffinity informer")
+	close(calicoInfr.stopCh)
+}
+
+func (ctlr *Controller) newCalicoInformer() *CalicoInformer {
+	log.Debugf("Creating calico blockAffinity informer")
+	return &CalicoInformer{
+		stopCh: make(chan struct{}),
+		blockAffinityInformer: dynamicinformer.NewFilteredDynamicInformer(
+			ctlr.clientsets.dynamicClient,
+			calicoBlockAffinityGVR,
+			"",
+			0*time.Second,
+			cache.Indexers{},
+			nil,
+		).Informer(),
+	}
+}
+
+func (ctlr *Controller) addCalicoEventHandlers(calicoInf *CalicoInformer) {
+	ctlr.addEventHandler(
+		calicoInf.blockAffinityInformer,
+		&cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { ctlr.processBlockAffinityUpdate() },
+			UpdateFunc: func(old, cur interface{}) {
+				oldBA, okOld := old.(*unstructured.Unstructured)
+				curBA, okCur := cur.(*unstructured.Unstructured)
+				// process the block affinity only when the block is moved or its state is changed
+				if okOld && okCur && reflect.DeepEqual(oldBA.Object["spec"], curBA.Object["spec"]) {
+					return
+				}
+				ctlr.processBlockAffinityUpdate()
+			},
+			DeleteFunc: func(obj interface{}) { ctlr.processBlockAffinityUpdate() },
+		},
+	)
+	calicoInf.blockAffinityInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(BlockAffinity, Local))
+}
+
+// processBlockAffinityUpdate updates the static routes when the Calico IPAM blocks are allocated or moved between the nodes
+func (ctlr *Controller) processBlockAffinityUpdate() {
+	// static routes are processed once the controller is initialised
+	if ctlr.initState {
+		return
+	}
+	log.Debugf("Processing Calico block affinity updates for static routes")
+	ctlr.processStaticRouteUpdate()
+}
+
+// getCalicoNodeBlocks returns the cidrs of the Calico IPAM blocks confirmed for each node
+func (ctlr *Controller) getCalicoNodeBlocks() map[string][]string {
+	nodeBlocks := make(map[string][]string)
+	if ctlr.calicoInformer == nil {
+		return nodeBlocks
+	}
+	for _, obj := range ctlr.calicoInformer.blockAffinityInformer.GetIndexer().List() {
+		blockAffinity, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		node, _, _ := unstructured.NestedString(blockAffinity.Object, "spec", "node")
+		cidr, _, _ := unstructured.NestedString(blockAffinity.Object, "spec", "cidr")
+		state, _, _ := unstructured.NestedString(blockAffinity.Object, "spec", "state")
+		deleted, _, _ := unstructured.NestedString(blockAffinity.Object, "spec", "deleted")
+		if node == "" || cidr == "" || state != CalicoBlockAffinityConfirmed || deleted == "true" {
+			continue
+		}
+		nodeBlocks[node] = append(nodeBlocks[node], cidr)
+	}
+	for node := range nodeBlocks {
+		sort.Strings(nodeBlocks[node])
+	}
+	return nodeBlocks
+}
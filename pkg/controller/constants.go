@@ -29,8 +29,8 @@ const (
 	Pod = "Pod"
 	//Secret  is a k8s native object
 	K8sSecret = "Secret"
-	// Endpoints is a k8s native Endpoint Resource.
-	Endpoints = "Endpoints"
+	// EndpointSlice is a k8s native EndpointSlice Resource.
+	EndpointSlice = "EndpointSlice"
 	// Namespace is k8s namespace
 	Namespace = "Namespace"
 	// ConfigCR is k8s native ConfigCR resource
@@ -39,6 +39,24 @@ const (
 	Route = "Route"
 	// Node update
 	NodeUpdate = "Node"
+	// GatewayClass is a Gateway API Resource Kind
+	GatewayClass = "GatewayClass"
+	// Gateway is a Gateway API Resource Kind
+	Gateway = "Gateway"
+	// HTTPRoute is a Gateway API Resource Kind
+	HTTPRoute = "HTTPRoute"
+	// TLSRoute is a Gateway API Resource Kind
+	TLSRoute = "TLSRoute"
+	// TCPRoute is a Gateway API Resource Kind
+	TCPRoute = "TCPRoute"
+	// UDPRoute is a Gateway API Resource Kind
+	UDPRoute = "UDPRoute"
+	// Ingress is a k8s native Ingress Resource
+	Ingress = "Ingress"
+	// IngressClass is a k8s native IngressClass Resource
+	IngressClass = "IngressClass"
+	// BlockAffinity is a Calico IPAM Resource Kind
+	BlockAffinity = "BlockAffinity"
 
 	NodePort = "nodeport"
 	Cluster  = "cluster"
@@ -56,6 +74,9 @@ const (
 	PolicyControlForward = "forwarding"
 	// Namespace for IPAM CRD
 	IPAMNamespace = "kube-system"
+	// BuiltinIPAMConfigMapName is the ConfigMap which persists the allocations of the embedded IPAM
+	BuiltinIPAMConfigMapName  = "k8s-bigip-ctlr-ipam"
+	BuiltinIPAMAllocationsKey = "allocations"
 	//Name for ipam CR
 	ipamCRName = "ipam"
 
@@ -73,6 +94,8 @@ const (
 	LBServiceHostAnnotation       = "cis.f5.com/host"
 	HealthMonitorAnnotation       = "cis.f5.com/health"
 	LBServicePolicyNameAnnotation = "cis.f5.com/policyName"
+	LBServiceBigIpLabelAnnotation = "cis.f5.com/bigIpLabel"
+	LBServiceIPAnnotation         = "cis.f5.com/ip"
 
 	//Antrea NodePortLocal support
 	NPLPodAnnotation = "nodeportlocal.antrea.io"
@@ -87,6 +110,26 @@ const (
 	defaultAS3Version = "3.48.0"
 	defaultAS3Build   = "10"
 	clusterHealthPath = "/readyz"
+	// DefaultAS3SchemaPath is the path of the bundled AS3 schema in the CIS image
+	DefaultAS3SchemaPath = "/app/vendor/src/f5/schemas/as3-schema-3.48.0-10-cis.json"
+
+	// Drift detection policies
+	DriftPolicyReportOnly = "report-only"
+	DriftPolicyAutoHeal   = "auto-heal"
+
+	// Results of the staged rollouts
+	RolloutPromoted = "promoted"
+	RolloutHalted   = "halted"
+
+	// DefaultDeclarationHistorySize is the number of revisions kept for each tenant by default
+	DefaultDeclarationHistorySize = 5
+	// DeclarationHistoryConfigMapPrefix is the prefix of the ConfigMaps which persist the declaration history
+	DeclarationHistoryConfigMapPrefix = "k8s-bigip-ctlr-declaration-history-"
+	// RedactedValue replaces the certificates and keys of the revisions which are served or persisted
+	RedactedValue = "<redacted>"
+	// Triggers of the tenant rollbacks
+	RollbackManual = "manual"
+	RollbackAuto   = "auto"
 
 	Create = "Create"
 	Update = "Update"
@@ -115,9 +158,24 @@ const (
 	OvnK8sNodeIPAnnotation3 = "k8s.ovn.org/host-cidrs"
 
 	//Cilium CNI
-	CILIUM                          = "cilium"
-	CiliumK8sNodeSubnetAnnotation12 = "io.cilium.network.ipv4-pod-cidr"
-	CiliumK8sNodeSubnetAnnotation13 = "network.cilium.io/ipv4-pod-cidr"
+	CILIUM                            = "cilium"
+	CiliumK8sNodeSubnetAnnotation12   = "io.cilium.network.ipv4-pod-cidr"
+	CiliumK8sNodeSubnetAnnotation13   = "network.cilium.io/ipv4-pod-cidr"
+	CiliumK8sNodeSubnetV6Annotation12 = "io.cilium.network.ipv6-pod-cidr"
+	CiliumK8sNodeSubnetV6Annotation13 = "network.cilium.io/ipv6-pod-cidr"
+
+	//Calico CNI
+	CALICO                       = "calico"
+	CalicoBlockAffinityConfirmed = "confirmed"
+
+	// queues of the post pipeline reported in the metrics
+	ResourceQueueName  = "resource_queue"
+	RequestChannelName = "request_channel"
+	PostChannelName    = "post_channel"
+
+	//Flannel CNI
+	FlannelBackendDataAnnotation = "flannel.alpha.coreos.com/backend-data"
+	FlannelPublicIPAnnotation    = "flannel.alpha.coreos.com/public-ip"
 
 	//CNI plugin
 	FLANNEL      = "flannel"
@@ -157,6 +215,9 @@ const (
 	timeoutSmall         = 5 * time.Second
 	timeoutMedium        = 30 * time.Second
 	timeoutLarge         = 180 * time.Second
+
+	// documentIDRecoveryAttempts is the number of attempts to recover the document IDs before failing the post
+	documentIDRecoveryAttempts = 3
 )
 
 const (
@@ -236,3 +297,63 @@ const AbDeploymentDgName = "ab_deployment_dg"
 const BigIPLabel = ""
 
 const CM_DECLARE_API = "/api/v1/spaces/default/appsvcs/documents/"
+
+// constants for resource status
+const (
+	StatusOk     = "Ok"
+	StatusFailed = "Failed"
+
+	// Condition type set on the resources deployed on BIG-IP
+	DeployedCondition = "Deployed"
+	// Condition reasons
+	DeploySucceeded = "DeploySucceeded"
+	DeployFailed    = "DeployFailed"
+	DeployRetrying  = "DeployRetrying"
+)
+
+// constants for kubernetes events
+const (
+	EventSourceComponent = "k8s-bigip-ctlr"
+
+	// Event reasons
+	InvalidSpec          = "InvalidSpec"
+	AddressConflict      = "AddressConflict"
+	InvalidAddress       = "InvalidAddress"
+	MissingTLSProfile    = "MissingTLSProfile"
+	MissingSecret        = "MissingSecret"
+	IPAMAllocationFailed = "IPAMAllocationFailed"
+	BIGIPNotFound        = "BIGIPNotFound"
+)
+
+// constants for leader election
+const (
+	DefaultLeaseName      = "k8s-bigip-ctlr"
+	DefaultLeaseNamespace = "kube-system"
+	// lease timings for failover in about 15 seconds if the leader goes down without releasing the lease
+	DefaultLeaseDuration = 15 * time.Second
+	DefaultRenewDeadline = 10 * time.Second
+	DefaultRetryPeriod   = 2 * time.Second
+
+	LeaderRole   = "leader"
+	FollowerRole = "follower"
+)
+
+// constants for Gateway API
+const (
+	// DefaultGatewayControllerName is the controllerName of the GatewayClasses managed by CIS
+	DefaultGatewayControllerName = "f5.com/cis-gateway-controller"
+)
+
+// constants for Ingress
+const (
+	// DefaultIngressControllerName is the controller of the IngressClasses managed by CIS
+	DefaultIngressControllerName = "f5.com/cntr-ingress-svcs"
+
+	F5VsBindAddrAnnotation       = "virtual-server.f5.com/ip"
+	IngressClassAnnotation       = "kubernetes.io/ingress.class"
+	IngressSslRedirectAnnotation = "ingress.kubernetes.io/ssl-redirect"
+	IngressAllowHttpAnnotation   = "ingress.kubernetes.io/allow-http"
+)
+
+// EndpointSliceServiceIndex is the indexer of the EndpointSlices by namespace/name of the owning Service
+const EndpointSliceServiceIndex = "service"
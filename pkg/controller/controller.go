@@ -17,8 +17,10 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/as3schema"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
 	"os"
@@ -39,9 +41,11 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/workqueue"
+	gatewayclient "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 )
 
 // NewController creates a new Controller Instance.
@@ -66,22 +70,36 @@ func NewController(params Params) *Controller {
 		managedResources: ManagedResources{
 			ManageCustomResources: true,
 			ManageTransportServer: true,
+			ManageGatewayAPI:      params.ManageGatewayAPI,
+			ManageIngress:         params.ManageIngress,
+			// secrets are required for the certificateRefs of the Gateway listeners and the Ingress TLS
+			ManageSecrets: params.ManageGatewayAPI || params.ManageIngress,
 		},
-		bigIpMap:   make(BigIpMap),
-		PostParams: PostParams{},
+		gatewayControllerName:       params.GatewayControllerName,
+		ingressControllerName:       params.IngressControllerName,
+		loadBalancerClass:           params.LoadBalancerClass,
+		manageLoadBalancerClassOnly: params.ManageLoadBalancerClassOnly,
+		declarationHistoryAPI:       params.DeclarationHistoryAPI,
+		bigIpMap:                    make(BigIpMap),
+		PostParams:                  PostParams{},
+	}
+	if ctlr.gatewayControllerName == "" {
+		ctlr.gatewayControllerName = DefaultGatewayControllerName
+	}
+	if ctlr.ingressControllerName == "" {
+		ctlr.ingressControllerName = DefaultIngressControllerName
 	}
 
 	log.Debug("Controller Created")
-	// fetch the CM token
+	// fetch the CM token, token sync retries with backoff when the fetch fails
 	err := ctlr.CMTokenManager.FetchToken()
 	if err != nil {
 		log.Errorf("Failed to Fetch Token: %v", err)
-		os.Exit(1)
 	}
 	// Sync CM token
 	go ctlr.CMTokenManager.SyncToken(make(chan struct{}))
-	ctlr.resourceQueue = workqueue.NewNamedRateLimitingQueue(
-		workqueue.DefaultControllerRateLimiter(), "nextgen-resource-controller")
+	ctlr.resourceQueue = newResourceEventQueue(workqueue.NewNamedRateLimitingQueue(
+		workqueue.DefaultControllerRateLimiter(), "nextgen-resource-controller"))
 
 	// set extended spec configCR for all
 	ctlr.CISConfigCRKey = params.CISConfigCRKey
@@ -90,6 +108,9 @@ func NewController(params Params) *Controller {
 		log.Errorf("Failed to Setup Clients: %v", err)
 	}
 
+	// Setup the recorder to emit events on the processed resources
+	ctlr.setupEventRecorder()
+
 	// Initialize the controller with base resources in CIS config CR
 	ctlr.initController()
 
@@ -98,8 +119,69 @@ func NewController(params Params) *Controller {
 		log.Error("Failed to Setup Informers")
 	}
 
-	// start request handler
+	// load the AS3 schema to validate the tenant declarations before posting
+	ctlr.PostParams.as3Validator = loadAS3Validator(params.AS3SchemaPath)
+
+	// create request handler
 	ctlr.NewRequestHandler(params.UserAgent, params.httpClientMetrics)
+
+	// setup leader election, so that only the leader among the CIS replicas posts to Central Manager
+	if params.LeaderElectionConfig != nil {
+		ctlr.setupLeaderElection(params.LeaderElectionConfig)
+	}
+
+	// enable http endpoint
+	go ctlr.enableHttpEndpoint(params.HttpAddress)
+
+	// setup ipam
+	ctlr.setupIPAM(params)
+
+	go ctlr.Start()
+
+	return ctlr
+}
+
+// setupLeaderElection sets the defaults for leader election config which is started along with the controller
+func (ctlr *Controller) setupLeaderElection(config *LeaderElectionConfig) {
+	if config.LeaseName == "" {
+		config.LeaseName = DefaultLeaseName
+	}
+	if config.LeaseDuration <= 0 {
+		config.LeaseDuration = DefaultLeaseDuration
+	}
+	if config.RenewDeadline <= 0 {
+		config.RenewDeadline = DefaultRenewDeadline
+	}
+	if config.RetryPeriod <= 0 {
+		config.RetryPeriod = DefaultRetryPeriod
+	}
+	ctlr.leaderElectionConfig = config
+	ctlr.leaderElectionCtx, ctlr.leaderElectionCancel = context.WithCancel(context.Background())
+	ctlr.leaderElectionDone = make(chan struct{})
+}
+
+// loadAS3Validator loads the AS3 schema, the declarations are posted without validation when the schema is not available
+func loadAS3Validator(schemaPath string) *as3schema.Validator {
+	if schemaPath == "" {
+		log.Debugf("[AS3] Schema validation of the declarations is disabled")
+		return nil
+	}
+	validator, err := as3schema.NewValidator(schemaPath)
+	if err != nil {
+		log.Warningf("[AS3] Unable to load the AS3 schema, declarations are posted without validation: %v", err)
+		return nil
+	}
+	if !validator.HasDefinition(as3schema.TenantDefinition) {
+		log.Warningf("[AS3] Tenant definition not found in the AS3 schema %v, declarations are posted without validation", schemaPath)
+		return nil
+	}
+	log.Debugf("[AS3] Loaded the AS3 schema %v", schemaPath)
+	return validator
+}
+
+// startProcessing starts the handlers which process the resources and post the declarations to Central Manager
+func (ctlr *Controller) startProcessing(stopChan chan struct{}) {
+	// start request handler
 	ctlr.RequestHandler.startRequestHandler()
 
 	// start response handler
@@ -114,15 +196,7 @@ func NewController(params Params) *Controller {
 		ctlr.RequestHandler.startPostManager(bigip)
 	}
 
-	// enable http endpoint
-	go ctlr.enableHttpEndpoint(params.HttpAddress)
-
-	// setup ipam
-	ctlr.setupIPAM(params)
-
-	go ctlr.Start()
-
-	return ctlr
+	go wait.Until(ctlr.nextGenResourceWorker, time.Second, stopChan)
 }
 
 func (ctlr *Controller) NewRequestHandler(userAgent string, httpClientMetrics bool) {
@@ -265,12 +339,29 @@ func (ctlr *Controller) setupClients(config *rest.Config) error {
 		}
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("Failed to create dynamic Client: %v", err)
+	}
+
+	var gwClient *gatewayclient.Clientset
+	if ctlr.managedResources.ManageGatewayAPI {
+		gwClient, err = gatewayclient.NewForConfig(config)
+		if err != nil {
+			return fmt.Errorf("Failed to create Gateway API Client: %v", err)
+		}
+	}
+
 	log.Debug("Client Created")
 	ctlr.clientsets = &ClientSets{
 		kubeClient:    kubeClient,
 		kubeCRClient:  kubeCRClient,
 		kubeAPIClient: kubeIPAMClient,
 		routeClientV1: rclient,
+		dynamicClient: dynamicClient,
+	}
+	if gwClient != nil {
+		ctlr.clientsets.gatewayClient = gwClient
 	}
 	return nil
 }
@@ -290,7 +381,14 @@ func (ctlr *Controller) Start() {
 
 	stopChan := make(chan struct{})
 
-	go wait.Until(ctlr.nextGenResourceWorker, time.Second, stopChan)
+	// informers warm up the caches on all the replicas, but only the leader processes the resources
+	if ctlr.leaderElectionConfig != nil {
+		go ctlr.runLeaderElection(ctlr.leaderElectionConfig, func() {
+			ctlr.startProcessing(stopChan)
+		})
+	} else {
+		ctlr.startProcessing(stopChan)
+	}
 
 	<-stopChan
 	ctlr.Stop()
@@ -298,6 +396,8 @@ func (ctlr *Controller) Start() {
 
 // Stop the Controller
 func (ctlr *Controller) Stop() {
+	// release the lease for a faster failover
+	ctlr.stopLeaderElection()
 	// stop the informers
 	ctlr.stopInformers()
 	if ctlr.ipamCli != nil {
@@ -11,6 +11,7 @@ import (
 	routeapi "github.com/openshift/api/route/v1"
 	"io/ioutil"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"net/http"
 	"sync"
 	"testing"
@@ -75,6 +76,7 @@ func newMockPostManger() *mockPostManager {
 		RespIndex: 0,
 	}
 	mockPM.AS3PostManager = &AS3PostManager{}
+	mockPM.L3PostManager = &L3PostManager{}
 	mockPM.AS3PostManager.firstPost = true
 	mockPM.tokenManager = tokenmanager.NewTokenManager(
 		"0.0.0.0",
@@ -115,6 +117,23 @@ func (mockPM *mockPostManager) setResponses(responces []responceCtx, method stri
 	mockPM.PostParams.httpClient = client
 }
 
+// setMethodResponses mocks the responses of multiple HTTP methods on the same client
+func (mockPM *mockPostManager) setMethodResponses(methodResponces map[string][]responceCtx) {
+	responseMap := make(mockhc.ResponseConfigMap)
+	for method, responces := range methodResponces {
+		responseMap[method] = &mockhc.ResponseConfig{}
+		for _, resp := range responces {
+			responseMap[method].Responses = append(responseMap[method].Responses, &http.Response{
+				StatusCode: int(resp.status),
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(resp.body))),
+			})
+		}
+	}
+	client, _ := mockhc.NewMockHTTPClient(responseMap)
+	mockPM.PostParams.httpClient = client
+}
+
 func newMockAgent(userAgent string) *RequestHandler {
 	return &RequestHandler{
 		PostManagers: PostManagers{sync.RWMutex{}, make(map[BigIpKey]*PostManager)},
@@ -179,33 +198,33 @@ func (m *mockController) deleteService(svc *v1.Service) {
 	}
 }
 
-func (m *mockController) addEndpoints(ep *v1.Endpoints) {
-	comInf, _ := m.getNamespacedCommonInformer(ep.ObjectMeta.Namespace)
-	comInf.epsInformer.GetStore().Add(ep)
+func (m *mockController) addEndpointSlice(slice *discoveryv1.EndpointSlice) {
+	comInf, _ := m.getNamespacedCommonInformer(slice.ObjectMeta.Namespace)
+	comInf.epsInformer.GetStore().Add(slice)
 
 	if m.resourceQueue != nil {
-		m.enqueueEndpoints(ep, Create, "")
+		m.enqueueEndpointSlice(slice, Create, "")
 	}
 }
 
-func (m *mockController) updateEndpoints(ep *v1.Endpoints) {
-	comInf, _ := m.getNamespacedCommonInformer(ep.ObjectMeta.Namespace)
-	comInf.epsInformer.GetStore().Update(ep)
+func (m *mockController) updateEndpointSlice(slice *discoveryv1.EndpointSlice) {
+	comInf, _ := m.getNamespacedCommonInformer(slice.ObjectMeta.Namespace)
+	comInf.epsInformer.GetStore().Update(slice)
 }
 
-func (m *mockController) deleteEndpoints(ep *v1.Endpoints) {
-	comInf, _ := m.getNamespacedCommonInformer(ep.ObjectMeta.Namespace)
-	comInf.epsInformer.GetStore().Delete(ep)
+func (m *mockController) deleteEndpointSlice(slice *discoveryv1.EndpointSlice) {
+	comInf, _ := m.getNamespacedCommonInformer(slice.ObjectMeta.Namespace)
+	comInf.epsInformer.GetStore().Delete(slice)
 	if m.resourceQueue != nil {
-		m.enqueueEndpoints(ep, Delete, "")
+		m.enqueueEndpointSlice(slice, Delete, "")
 	}
 }
 
-func convertSvcPortsToEndpointPorts(svcPorts []v1.ServicePort) []v1.EndpointPort {
-	eps := make([]v1.EndpointPort, len(svcPorts))
-	for i, v := range svcPorts {
-		eps[i].Name = v.Name
-		eps[i].Port = v.Port
+func convertSvcPortsToEndpointPorts(svcPorts []v1.ServicePort) []discoveryv1.EndpointPort {
+	eps := make([]discoveryv1.EndpointPort, len(svcPorts))
+	for i := range svcPorts {
+		eps[i].Name = &svcPorts[i].Name
+		eps[i].Port = &svcPorts[i].Port
 	}
 	return eps
 }
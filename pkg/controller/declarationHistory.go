@@ -0,0 +1,394 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newDeclarationHistory(size int) *DeclarationHistory {
+	if size <= 0 {
+		size = DefaultDeclarationHistorySize
+	}
+	return &DeclarationHistory{
+		size:      size,
+		revisions: make(map[string][]declarationRevision),
+		pinned:    make(map[string]as3Tenant),
+	}
+}
+
+// pin keeps the rolled back tenant until the declaration of its resources changes
+func (history *DeclarationHistory) pin(tenant string, decl as3Tenant) {
+	history.Lock()
+	defer history.Unlock()
+	history.pinned[tenant] = decl
+}
+
+// isPinned checks if the tenant is rolled back and the declaration of its resources is not changed since,
+// the tenant is unpinned once the declaration changes
+func (history *DeclarationHistory) isPinned(tenant string, decl interface{}) bool {
+	history.Lock()
+	defer history.Unlock()
+	pinned, found := history.pinned[tenant]
+	if !found {
+		return false
+	}
+	if reflect.DeepEqual(decl, pinned) {
+		return true
+	}
+	delete(history.pinned, tenant)
+	return false
+}
+
+// record adds the tenant declaration as the latest revision, unless it's same as the latest revision
+func (history *DeclarationHistory) record(tenant string, decl as3Tenant) bool {
+	history.Lock()
+	defer history.Unlock()
+	revisions := history.revisions[tenant]
+	revision := 1
+	if len(revisions) > 0 {
+		latest := revisions[len(revisions)-1]
+		if tenantDeclarationsEqual(latest.Declaration, decl) {
+			return false
+		}
+		revision = latest.Revision + 1
+	}
+	revisions = append(revisions, declarationRevision{Revision: revision, Timestamp: time.Now(), Declaration: decl})
+	if len(revisions) > history.size {
+		revisions = revisions[len(revisions)-history.size:]
+	}
+	history.revisions[tenant] = revisions
+	return true
+}
+
+// getRevisions returns the revisions of the tenant, latest revision last
+func (history *DeclarationHistory) getRevisions(tenant string) []declarationRevision {
+	history.RLock()
+	defer history.RUnlock()
+	return append([]declarationRevision(nil), history.revisions[tenant]...)
+}
+
+// getTenants returns the tenants with revisions in sorted order
+func (history *DeclarationHistory) getTenants() []string {
+	history.RLock()
+	defer history.RUnlock()
+	tenants := make([]string, 0, len(history.revisions))
+	for tenant := range history.revisions {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// getRevision returns the revision of the tenant, the revision before the latest one when revision is 0
+func (history *DeclarationHistory) getRevision(tenant string, revision int) (declarationRevision, error) {
+	revisions := history.getRevisions(tenant)
+	if revision == 0 {
+		if len(revisions) < 2 {
+			return declarationRevision{}, fmt.Errorf("no previous revision of tenant %v", tenant)
+		}
+		return revisions[len(revisions)-2], nil
+	}
+	for _, rev := range revisions {
+		if rev.Revision == revision {
+			return rev, nil
+		}
+	}
+	return declarationRevision{}, fmt.Errorf("revision %v of tenant %v is not found", revision, tenant)
+}
+
+// redactRevisions returns the revisions with the certificates and keys of the declarations replaced
+func redactRevisions(revisions []declarationRevision) []declarationRevision {
+	redacted := make([]declarationRevision, 0, len(revisions))
+	for _, rev := range revisions {
+		if decl, found := redactDeclaration(rev.Declaration); found {
+			rev.Declaration = decl
+			rev.Redacted = true
+		}
+		redacted = append(redacted, rev)
+	}
+	return redacted
+}
+
+// redactDeclaration returns a copy of the tenant declaration with the certificates and keys replaced,
+// and whether any of them is found
+func redactDeclaration(decl as3Tenant) (as3Tenant, bool) {
+	data, err := json.Marshal(decl)
+	if err != nil {
+		return nil, true
+	}
+	var redacted as3Tenant
+	if err = json.Unmarshal(data, &redacted); err != nil {
+		return nil, true
+	}
+	return redacted, redactValues(map[string]interface{}(redacted))
+}
+
+// redactValues replaces the private keys and passphrases, and the certificates of the Certificate objects
+func redactValues(value interface{}) bool {
+	found := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			switch {
+			case key == "privateKey" || key == "passphrase",
+				v["class"] == "Certificate" && (key == "certificate" || key == "chainCA"):
+				v[key] = RedactedValue
+				found = true
+			default:
+				found = redactValues(val) || found
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			found = redactValues(val) || found
+		}
+	}
+	return found
+}
+
+// recordDeclaration adds the deployed tenant declaration to the history, and persists the history if required
+func (postMgr *PostManager) recordDeclaration(tenant string, decl as3Tenant) {
+	if postMgr.declarationHistory == nil || !postMgr.declarationHistory.record(tenant, decl) {
+		return
+	}
+	if postMgr.AS3Config.History.ConfigMap {
+		postMgr.saveDeclarationHistory(tenant)
+	}
+}
+
+// getHistoryConfigMapName returns the name of the ConfigMap holding the declaration history of the BIG-IP
+func (postMgr *PostManager) getHistoryConfigMapName() string {
+	return DeclarationHistoryConfigMapPrefix + strings.NewReplacer(".", "-", ":", "-").Replace(postMgr.bigIpKey.BigIpAddress)
+}
+
+// saveDeclarationHistory updates the revisions of the tenant in the ConfigMap of the BIG-IP
+func (postMgr *PostManager) saveDeclarationHistory(tenant string) {
+	if postMgr.kubeClient == nil {
+		return
+	}
+	// the certificates and keys are not persisted in the ConfigMap
+	data, err := json.Marshal(redactRevisions(postMgr.declarationHistory.getRevisions(tenant)))
+	if err != nil {
+		log.Errorf("[AS3][History]%v Could not marshal the history of tenant %v: %v", postMgr.postManagerPrefix, tenant, err)
+		return
+	}
+	configMaps := postMgr.kubeClient.CoreV1().ConfigMaps(postMgr.historyNamespace)
+	cm, err := configMaps.Get(context.TODO(), postMgr.getHistoryConfigMapName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      postMgr.getHistoryConfigMapName(),
+				Namespace: postMgr.historyNamespace,
+			},
+			Data: map[string]string{tenant: string(data)},
+		}
+		_, err = configMaps.Create(context.TODO(), cm, metav1.CreateOptions{})
+	} else if err == nil {
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[tenant] = string(data)
+		_, err = configMaps.Update(context.TODO(), cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		log.Errorf("[AS3][History]%v Could not persist the history of tenant %v: %v", postMgr.postManagerPrefix, tenant, err)
+	}
+}
+
+// loadDeclarationHistory recovers the declaration history persisted before the controller restart
+func (postMgr *PostManager) loadDeclarationHistory() {
+	if postMgr.kubeClient == nil || postMgr.declarationHistory == nil {
+		return
+	}
+	cm, err := postMgr.kubeClient.CoreV1().ConfigMaps(postMgr.historyNamespace).Get(context.TODO(),
+		postMgr.getHistoryConfigMapName(), metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Errorf("[AS3][History]%v Could not recover the declaration history: %v", postMgr.postManagerPrefix, err)
+		}
+		return
+	}
+	postMgr.declarationHistory.Lock()
+	defer postMgr.declarationHistory.Unlock()
+	for tenant, data := range cm.Data {
+		var revisions []declarationRevision
+		if err := json.Unmarshal([]byte(data), &revisions); err != nil {
+			log.Errorf("[AS3][History]%v Could not recover the history of tenant %v: %v", postMgr.postManagerPrefix, tenant, err)
+			continue
+		}
+		if len(revisions) > postMgr.declarationHistory.size {
+			revisions = revisions[len(revisions)-postMgr.declarationHistory.size:]
+		}
+		postMgr.declarationHistory.revisions[tenant] = revisions
+	}
+}
+
+// handleRollbackRequest rolls back the tenant to the requested revision
+func (postMgr *PostManager) handleRollbackRequest(request rollbackRequest) error {
+	if postMgr.declarationHistory == nil {
+		return fmt.Errorf("declaration history is not available")
+	}
+	revision, err := postMgr.declarationHistory.getRevision(request.tenant, request.revision)
+	if err != nil {
+		return err
+	}
+	// the declaration of the resources is deployed again once the resources of the tenant change
+	return postMgr.rollbackTenant(request.tenant, revision, RollbackManual, postMgr.cachedTenantDeclMap[request.tenant])
+}
+
+// rollbackTenant posts the revision of the tenant declaration, the tenant is pinned to the revision until
+// the declaration of its resources differs from resourceDecl
+func (postMgr *PostManager) rollbackTenant(tenant string, revision declarationRevision, trigger string, resourceDecl as3Tenant) error {
+	if revision.Redacted {
+		prometheus.TenantRollbacks.WithLabelValues(postMgr.bigIpKey.BigIpAddress, tenant, trigger, "failure").Inc()
+		return fmt.Errorf("revision %v of tenant %v is recovered without its certificates and keys, it can't be rolled back",
+			revision.Revision, tenant)
+	}
+	log.Infof("[AS3][History]%v Rolling back tenant %v to revision %v", postMgr.postManagerPrefix, tenant, revision.Revision)
+	cfg := postMgr.postTenantDeclarations(map[string]as3Tenant{tenant: revision.Declaration})
+	if _, failed := cfg.failedTenants[tenant]; failed {
+		prometheus.TenantRollbacks.WithLabelValues(postMgr.bigIpKey.BigIpAddress, tenant, trigger, "failure").Inc()
+		return fmt.Errorf("failed to roll back tenant %v to revision %v: %v", tenant, revision.Revision,
+			getTenantErrorMessage(cfg.tenantResponseMap[tenant]))
+	}
+	if resourceDecl != nil {
+		postMgr.declarationHistory.pin(tenant, resourceDecl)
+	}
+	prometheus.TenantRollbacks.WithLabelValues(postMgr.bigIpKey.BigIpAddress, tenant, trigger, "success").Inc()
+	return nil
+}
+
+// rollbackFailedTenants rolls back the tenants whose new declaration failed repeatedly to the last deployed revision.
+// The rolled back tenants are reported as failed, but they are not retried.
+func (postMgr *PostManager) rollbackFailedTenants(cfg *as3Config) {
+	threshold := postMgr.AS3Config.History.AutoRollbackFailures
+	if threshold <= 0 || postMgr.declarationHistory == nil {
+		return
+	}
+	for tenant := range cfg.incomingTenantDeclMap {
+		if _, failed := cfg.failedTenants[tenant]; !failed {
+			delete(postMgr.tenantFailures, tenant)
+			continue
+		}
+		// rolled back tenants are not retried
+		if _, invalid := cfg.invalidTenants[tenant]; invalid {
+			continue
+		}
+		postMgr.tenantFailures[tenant]++
+		if postMgr.tenantFailures[tenant] < threshold {
+			continue
+		}
+		revisions := postMgr.declarationHistory.getRevisions(tenant)
+		if len(revisions) == 0 {
+			continue
+		}
+		failures := postMgr.tenantFailures[tenant]
+		delete(postMgr.tenantFailures, tenant)
+		latest := revisions[len(revisions)-1]
+		message := getTenantErrorMessage(cfg.tenantResponseMap[tenant])
+		if err := postMgr.rollbackTenant(tenant, latest, RollbackAuto, cfg.incomingTenantDeclMap[tenant]); err != nil {
+			log.Errorf("%v[AS3][History]%v %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, err)
+			continue
+		}
+		log.Warningf("%v[AS3][History]%v Rolled back tenant %v to revision %v after %v failed deployments",
+			getRequestPrefix(cfg.id), postMgr.postManagerPrefix, tenant, latest.Revision, failures)
+		message = fmt.Sprintf("rolled back to revision %v after %v failed deployments: %v", latest.Revision, failures, message)
+		cfg.invalidTenants[tenant] = message
+		cfg.tenantResponseMap[tenant] = tenantResponse{agentResponseCode: http.StatusUnprocessableEntity, message: message}
+	}
+}
+
+// getPostManager returns the post manager of the BIG-IP address
+func (ctlr *Controller) getPostManager(bigIpAddress string) *PostManager {
+	ctlr.RequestHandler.PostManagers.RLock()
+	defer ctlr.RequestHandler.PostManagers.RUnlock()
+	for bigIpKey, pm := range ctlr.RequestHandler.PostManagers.PostManagerMap {
+		if bigIpKey.BigIpAddress == bigIpAddress {
+			return pm
+		}
+	}
+	return nil
+}
+
+// DeclarationHistoryHandler serves the declaration history of the tenants deployed on a BIG-IP,
+// the certificates and keys of the declarations are redacted
+func (ctlr *Controller) DeclarationHistoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pm := ctlr.getPostManager(r.URL.Query().Get("bigip"))
+		if pm == nil || pm.declarationHistory == nil {
+			http.Error(w, "BIG-IP is not found, set the bigip query parameter to the BIG-IP address", http.StatusNotFound)
+			return
+		}
+		history := make(map[string][]declarationRevision)
+		if tenant := r.URL.Query().Get("tenant"); tenant != "" {
+			history[tenant] = redactRevisions(pm.declarationHistory.getRevisions(tenant))
+		} else {
+			for _, tenant := range pm.declarationHistory.getTenants() {
+				history[tenant] = redactRevisions(pm.declarationHistory.getRevisions(tenant))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(history); err != nil {
+			log.Errorf("[AS3][History] Could not write the declaration history: %v", err)
+		}
+	})
+}
+
+// RollbackHandler rolls back a tenant deployed on a BIG-IP to a revision of its declaration history
+func (ctlr *Controller) RollbackHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		if !ctlr.IsLeader() {
+			http.Error(w, "tenants are rolled back by the leader", http.StatusServiceUnavailable)
+			return
+		}
+		query := r.URL.Query()
+		pm := ctlr.getPostManager(query.Get("bigip"))
+		if pm == nil || pm.rollbackChan == nil {
+			http.Error(w, "BIG-IP is not found, set the bigip query parameter to the BIG-IP address", http.StatusNotFound)
+			return
+		}
+		request := rollbackRequest{tenant: query.Get("tenant"), result: make(chan error, 1)}
+		if request.tenant == "" {
+			http.Error(w, "tenant query parameter is required", http.StatusBadRequest)
+			return
+		}
+		if revision := query.Get("revision"); revision != "" {
+			var err error
+			if request.revision, err = strconv.Atoi(revision); err != nil || request.revision < 0 {
+				http.Error(w, fmt.Sprintf("invalid revision %v", revision), http.StatusBadRequest)
+				return
+			}
+		}
+		// the rollback is posted by the post manager in between the requests of the resources
+		select {
+		case pm.rollbackChan <- request:
+		case <-time.After(timeoutSmall):
+			http.Error(w, "BIG-IP is busy posting the declarations, retry the rollback later", http.StatusServiceUnavailable)
+			return
+		}
+		if err := <-request.result; err != nil {
+			log.Errorf("[AS3][History]%v %v", pm.postManagerPrefix, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Ok"))
+	})
+}
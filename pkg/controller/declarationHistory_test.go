@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("Declaration History", func() {
+	var mockPM *mockPostManager
+	var decl1, decl2, decl3 as3Tenant
+	successResponse := `{"results": [{"code": 200, "message": "success", "tenant": "test"}],
+		"declaration": {"test": {"class": "Tenant"}}}`
+
+	BeforeEach(func() {
+		mockPM = newMockPostManger()
+		mockPM.defaultPartition = "test"
+		mockPM.bigIpKey = BigIpKey{BigIpAddress: "10.10.10.1", BigIpLabel: "bigip1"}
+		mockPM.declarationHistory = newDeclarationHistory(2)
+		mockPM.tenantFailures = make(map[string]int)
+		decl1 = as3Tenant{"class": "Tenant", "label": "v1"}
+		decl2 = as3Tenant{"class": "Tenant", "label": "v2"}
+		decl3 = as3Tenant{"class": "Tenant", "label": "v3"}
+	})
+
+	It("keeps the bounded revisions of the tenants", func() {
+		Expect(mockPM.declarationHistory.record("test", decl1)).To(BeTrue())
+		Expect(mockPM.declarationHistory.record("test", as3Tenant{"label": "v1", "class": "Tenant"})).To(BeFalse())
+		Expect(mockPM.declarationHistory.record("test", decl2)).To(BeTrue())
+		Expect(mockPM.declarationHistory.record("test", decl3)).To(BeTrue())
+		revisions := mockPM.declarationHistory.getRevisions("test")
+		Expect(len(revisions)).To(Equal(2))
+		Expect(revisions[0].Revision).To(Equal(2))
+		Expect(revisions[1].Revision).To(Equal(3))
+
+		previous, err := mockPM.declarationHistory.getRevision("test", 0)
+		Expect(err).To(BeNil())
+		Expect(previous.Declaration).To(Equal(decl2))
+		_, err = mockPM.declarationHistory.getRevision("test", 1)
+		Expect(err).NotTo(BeNil())
+		_, err = mockPM.declarationHistory.getRevision("test1", 0)
+		Expect(err).NotTo(BeNil())
+		Expect(newDeclarationHistory(0).size).To(Equal(DefaultDeclarationHistorySize))
+	})
+
+	It("persists the history in a ConfigMap", func() {
+		mockPM.AS3Config = cisapiv1.AS3Config{History: cisapiv1.HistoryConfig{Size: 2, ConfigMap: true}}
+		mockPM.kubeClient = fake.NewSimpleClientset()
+		mockPM.historyNamespace = "kube-system"
+		mockPM.recordDeclaration("test", decl1)
+		mockPM.recordDeclaration("test", decl2)
+		mockPM.recordDeclaration("test1", decl1)
+		Expect(mockPM.getHistoryConfigMapName()).To(Equal("k8s-bigip-ctlr-declaration-history-10-10-10-1"))
+
+		mockPM.declarationHistory = newDeclarationHistory(2)
+		mockPM.loadDeclarationHistory()
+		Expect(mockPM.declarationHistory.getTenants()).To(Equal([]string{"test", "test1"}))
+		revisions := mockPM.declarationHistory.getRevisions("test")
+		Expect(len(revisions)).To(Equal(2))
+		Expect(revisions[1].Revision).To(Equal(2))
+		Expect(revisions[1].Declaration["label"]).To(Equal("v2"))
+	})
+
+	It("redacts the certificates and keys of the persisted revisions", func() {
+		mockPM.AS3Config = cisapiv1.AS3Config{History: cisapiv1.HistoryConfig{Size: 2, ConfigMap: true}}
+		mockPM.kubeClient = fake.NewSimpleClientset()
+		mockPM.historyNamespace = "kube-system"
+		decl := as3Tenant{"class": "Tenant", "app": as3Application{
+			"class": "Application",
+			"cert":  &as3Certificate{Class: "Certificate", Certificate: "cert-pem", PrivateKey: "key-pem"},
+		}}
+		mockPM.recordDeclaration("test", decl)
+		cm, err := mockPM.kubeClient.CoreV1().ConfigMaps("kube-system").Get(context.TODO(),
+			mockPM.getHistoryConfigMapName(), metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		Expect(cm.Data["test"]).NotTo(ContainSubstring("key-pem"))
+		Expect(cm.Data["test"]).NotTo(ContainSubstring("cert-pem"))
+		// the revision in memory keeps the keys for the rollback
+		Expect(mockPM.declarationHistory.getRevisions("test")[0].Declaration).To(Equal(decl))
+
+		mockPM.declarationHistory = newDeclarationHistory(2)
+		mockPM.loadDeclarationHistory()
+		revision, err := mockPM.declarationHistory.getRevision("test", 1)
+		Expect(err).To(BeNil())
+		Expect(revision.Redacted).To(BeTrue())
+		Expect(mockPM.handleRollbackRequest(rollbackRequest{tenant: "test", revision: 1})).NotTo(BeNil())
+
+		_, redacted := redactDeclaration(decl1)
+		Expect(redacted).To(BeFalse())
+	})
+
+	It("rolls back a tenant to the previous revision", func() {
+		mockPM.declarationHistory.record("test", decl1)
+		mockPM.declarationHistory.record("test", decl2)
+		rollbacks := testutil.ToFloat64(prometheus.TenantRollbacks.WithLabelValues("10.10.10.1", "test", RollbackManual, "success"))
+		mockPM.cachedTenantDeclMap["test"] = decl2
+		mockPM.setResponses([]responceCtx{{tenant: "test", status: http.StatusOK, body: successResponse}}, http.MethodPost)
+		Expect(mockPM.handleRollbackRequest(rollbackRequest{tenant: "test"})).To(BeNil())
+		Expect(mockPM.cachedTenantDeclMap).To(HaveKeyWithValue("test", decl1))
+		// the rolled back tenant is not posted until the declaration of its resources changes
+		Expect(mockPM.declarationHistory.isPinned("test", decl2)).To(BeTrue())
+		Expect(mockPM.declarationHistory.isPinned("test", decl3)).To(BeFalse())
+		Expect(mockPM.declarationHistory.isPinned("test", decl2)).To(BeFalse())
+		Expect(testutil.ToFloat64(prometheus.TenantRollbacks.WithLabelValues("10.10.10.1", "test", RollbackManual, "success"))).To(Equal(rollbacks + 1))
+		// the rolled back declaration is the latest revision
+		Expect(mockPM.declarationHistory.getRevisions("test")[1].Revision).To(Equal(3))
+		Expect(mockPM.handleRollbackRequest(rollbackRequest{tenant: "test", revision: 1})).NotTo(BeNil())
+	})
+
+	It("responds with service unavailable when the post manager is busy", func() {
+		mockCtlr := newMockController()
+		mockPM.rollbackChan = make(chan rollbackRequest)
+		mockCtlr.RequestHandler.PostManagers.PostManagerMap[mockPM.bigIpKey] = mockPM.PostManager
+		w := httptest.NewRecorder()
+		mockCtlr.RollbackHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/declarations/rollback?bigip=10.10.10.1&tenant=test", nil))
+		Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+	})
+
+	It("rolls back the tenants which fail repeatedly", func() {
+		mockPM.AS3Config = cisapiv1.AS3Config{History: cisapiv1.HistoryConfig{AutoRollbackFailures: 2}}
+		mockPM.declarationHistory.record("test", decl1)
+		cfg := &as3Config{
+			tenantResponseMap:     map[string]tenantResponse{"test": {agentResponseCode: http.StatusServiceUnavailable}},
+			failedTenants:         map[string]struct{}{"test": {}},
+			invalidTenants:        make(map[string]string),
+			incomingTenantDeclMap: map[string]as3Tenant{"test": decl2},
+		}
+		mockPM.rollbackFailedTenants(cfg)
+		Expect(cfg.invalidTenants).To(BeEmpty())
+		Expect(mockPM.tenantFailures["test"]).To(Equal(1))
+
+		rollbacks := testutil.ToFloat64(prometheus.TenantRollbacks.WithLabelValues("10.10.10.1", "test", RollbackAuto, "success"))
+		mockPM.setResponses([]responceCtx{{tenant: "test", status: http.StatusOK, body: successResponse}}, http.MethodPost)
+		mockPM.rollbackFailedTenants(cfg)
+		Expect(cfg.invalidTenants).To(HaveKey("test"))
+		Expect(cfg.hasRetryableTenants()).To(BeFalse())
+		Expect(mockPM.tenantFailures).To(BeEmpty())
+		Expect(mockPM.cachedTenantDeclMap).To(HaveKeyWithValue("test", decl1))
+		Expect(mockPM.declarationHistory.isPinned("test", decl2)).To(BeTrue())
+		Expect(testutil.ToFloat64(prometheus.TenantRollbacks.WithLabelValues("10.10.10.1", "test", RollbackAuto, "success"))).To(Equal(rollbacks + 1))
+	})
+})
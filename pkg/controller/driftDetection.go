@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+)
+
+// detectDrift compares the tenants deployed on Central Manager with the tenants posted by CIS.
+// Drifted tenants are reported in logs and metrics, and re-posted when the policy is auto-heal.
+func (postMgr *PostManager) detectDrift() {
+	if len(postMgr.cachedTenantDeclMap) == 0 {
+		return
+	}
+	deployedTenants, err := postMgr.getDeployedTenants()
+	if err != nil {
+		log.Errorf("[AS3][Drift]%v Could not fetch the deployed tenants from Central Manager: %v", postMgr.postManagerPrefix, err)
+		return
+	}
+	driftedTenants := make(map[string]as3Tenant)
+	for tenant, cachedDecl := range postMgr.cachedTenantDeclMap {
+		// Deleted tenants remain cached as declarations without applications, which are not deployed
+		if isDeletedTenantDeclaration(cachedDecl) {
+			prometheus.TenantDrift.WithLabelValues(postMgr.bigIpKey.BigIpAddress, tenant).Set(0)
+			continue
+		}
+		deployedDecl, found := deployedTenants[tenant]
+		if found && tenantDeclarationsEqual(cachedDecl, deployedDecl) {
+			prometheus.TenantDrift.WithLabelValues(postMgr.bigIpKey.BigIpAddress, tenant).Set(0)
+			continue
+		}
+		if !found {
+			log.Warningf("[AS3][Drift]%v Tenant %v is missing on Central Manager", postMgr.postManagerPrefix, tenant)
+		} else {
+			log.Warningf("[AS3][Drift]%v Tenant %v is modified on Central Manager", postMgr.postManagerPrefix, tenant)
+		}
+		prometheus.TenantDrift.WithLabelValues(postMgr.bigIpKey.BigIpAddress, tenant).Set(1)
+		driftedTenants[tenant] = cachedDecl
+	}
+	if len(driftedTenants) == 0 {
+		log.Debugf("[AS3][Drift]%v Deployed tenants are in sync", postMgr.postManagerPrefix)
+		return
+	}
+	if postMgr.AS3Config.DriftDetection.Policy != DriftPolicyAutoHeal {
+		return
+	}
+	postMgr.healDrift(driftedTenants)
+}
+
+// getDeployedTenants fetches the tenant declarations deployed on Central Manager
+func (postMgr *PostManager) getDeployedTenants() (map[string]interface{}, error) {
+	if !postMgr.AS3Config.DocumentAPI {
+		return postMgr.GetAS3DeclarationFromBigIP()
+	}
+	tenants, tenantDocIDs, err := postMgr.getDocumentTenantsFromCM()
+	if err != nil {
+		return nil, err
+	}
+	// Documents removed on Central Manager are re-created while healing
+	for tenant := range postMgr.tenantDeclarationIDMap {
+		if docID, found := tenantDocIDs[tenant]; found {
+			postMgr.tenantDeclarationIDMap[tenant] = docID
+		} else {
+			delete(postMgr.tenantDeclarationIDMap, tenant)
+		}
+	}
+	return tenants, nil
+}
+
+// healDrift re-posts the tenant declarations which are posted by CIS
+func (postMgr *PostManager) healDrift(driftedTenants map[string]as3Tenant) {
+	tenants := make([]string, 0, len(driftedTenants))
+	for tenant := range driftedTenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	log.Infof("[AS3][Drift]%v Re-posting the drifted tenants %v", postMgr.postManagerPrefix, tenants)
+
+	cfg := postMgr.postTenantDeclarations(driftedTenants)
+	for _, tenant := range tenants {
+		result := "success"
+		if _, failed := cfg.failedTenants[tenant]; failed {
+			result = "failure"
+			log.Errorf("[AS3][Drift]%v Failed to heal the tenant %v: %v", postMgr.postManagerPrefix, tenant,
+				getTenantErrorMessage(cfg.tenantResponseMap[tenant]))
+		} else {
+			prometheus.TenantDrift.WithLabelValues(postMgr.bigIpKey.BigIpAddress, tenant).Set(0)
+		}
+		prometheus.DriftHeals.WithLabelValues(postMgr.bigIpKey.BigIpAddress, tenant, result).Inc()
+	}
+}
+
+// postTenantDeclarations posts the tenant declarations outside the requests of the resources
+func (postMgr *PostManager) postTenantDeclarations(tenantDeclMap map[string]as3Tenant) as3Config {
+	cfg := as3Config{
+		userAgent:             postMgr.UserAgent,
+		targetAddress:         postMgr.bigIpKey.BigIpAddress,
+		tenantResponseMap:     make(map[string]tenantResponse),
+		tenantTaskIdMap:       make(map[string]string),
+		failedTenants:         make(map[string]struct{}),
+		invalidTenants:        make(map[string]string),
+		incomingTenantDeclMap: tenantDeclMap,
+	}
+	for tenant := range tenantDeclMap {
+		cfg.tenantResponseMap[tenant] = tenantResponse{}
+	}
+	cfg.data = string(postMgr.AS3PostManager.createAS3Declaration(tenantDeclMap, cfg.userAgent, cfg.targetAddress))
+	postMgr.publishConfig(&cfg)
+	postMgr.updateTenantCache(&cfg)
+	postMgr.pollTenantStatus(&cfg)
+	postMgr.recordTenantResponses(&cfg)
+	return cfg
+}
+
+// tenantDeclarationsEqual semantically compares the tenant declaration posted by CIS with the deployed one
+func tenantDeclarationsEqual(cachedDecl as3Tenant, deployedDecl interface{}) bool {
+	cached, err := json.Marshal(cachedDecl)
+	if err != nil {
+		return false
+	}
+	deployed, err := json.Marshal(deployedDecl)
+	if err != nil {
+		return false
+	}
+	return DeepEqualJSON(as3Declaration(cached), as3Declaration(deployed))
+}
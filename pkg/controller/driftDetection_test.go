@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"net/http"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Drift Detection", func() {
+	var mockPM *mockPostManager
+	var tenantDecl as3Tenant
+
+	BeforeEach(func() {
+		mockPM = newMockPostManger()
+		mockPM.defaultPartition = "test"
+		mockPM.bigIpKey = BigIpKey{BigIpAddress: "10.10.10.1", BigIpLabel: "bigip1"}
+		mockPM.tenantDeclarationIDMap = make(map[string]string)
+		tenantDecl = as3Tenant{
+			"class": "Tenant",
+			"label": "test",
+			"Shared": as3Application{
+				"class":    "Application",
+				"template": "shared",
+				"pool1":    &as3Pool{Class: "Pool", LoadBalancingMode: "round-robin"},
+			},
+		}
+		mockPM.cachedTenantDeclMap["test"] = tenantDecl
+	})
+
+	It("compares the tenant declarations semantically", func() {
+		deployed := map[string]interface{}{
+			"label": "test",
+			"class": "Tenant",
+			"Shared": map[string]interface{}{
+				"template": "shared",
+				"class":    "Application",
+				"pool1":    map[string]interface{}{"loadBalancingMode": "round-robin", "class": "Pool"},
+			},
+		}
+		Expect(tenantDeclarationsEqual(tenantDecl, deployed)).To(BeTrue())
+		deployed["Shared"].(map[string]interface{})["pool1"].(map[string]interface{})["loadBalancingMode"] = "ratio-member"
+		Expect(tenantDeclarationsEqual(tenantDecl, deployed)).To(BeFalse())
+		Expect(tenantDeclarationsEqual(tenantDecl, nil)).To(BeFalse())
+	})
+
+	It("reports the drifted tenants", func() {
+		mockPM.cachedTenantDeclMap["test1"] = as3Tenant{
+			"class": "Tenant",
+			"label": "test",
+			"app1":  as3Application{"class": "Application", "template": "generic", "pool1": &as3Pool{Class: "Pool"}},
+		}
+		mockPM.setResponses([]responceCtx{{
+			tenant: "test",
+			status: http.StatusOK,
+			body: `{"class": "ADC", "test": {"class": "Tenant", "label": "test", "Shared": {"class": "Application",
+				"template": "shared", "pool1": {"class": "Pool", "loadBalancingMode": "least-connections-member"}}}}`,
+		}}, http.MethodGet)
+		mockPM.detectDrift()
+		Expect(testutil.ToFloat64(prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test1"))).To(Equal(1.0))
+		// cache is not updated with the report-only policy
+		Expect(mockPM.cachedTenantDeclMap["test"]).To(Equal(tenantDecl))
+
+		mockPM.setResponses([]responceCtx{{
+			tenant: "test",
+			status: http.StatusOK,
+			body: `{"class": "ADC", "test": {"class": "Tenant", "label": "test", "Shared": {"class": "Application",
+				"template": "shared", "pool1": {"class": "Pool", "loadBalancingMode": "round-robin"}}},
+				"test1": {"class": "Tenant", "label": "test", "app1": {"class": "Application",
+				"template": "generic", "pool1": {"class": "Pool"}}}}`,
+		}}, http.MethodGet)
+		mockPM.detectDrift()
+		Expect(testutil.ToFloat64(prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test"))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test1"))).To(Equal(0.0))
+	})
+
+	It("ignores the deleted tenants", func() {
+		mockPM.AS3Config = cisapiv1.AS3Config{
+			DriftDetection: cisapiv1.DriftDetectionConfig{Interval: 60, Policy: DriftPolicyAutoHeal},
+		}
+		mockPM.cachedTenantDeclMap["test"] = getDeletedTenantDeclaration("test", "test", "test")
+		mockPM.cachedTenantDeclMap["test1"] = getDeletedTenantDeclaration("test", "test1", "test")
+		heals := testutil.ToFloat64(prometheus.DriftHeals.WithLabelValues("10.10.10.1", "test1", "failure"))
+		mockPM.setResponses([]responceCtx{{
+			tenant: "test",
+			status: http.StatusOK,
+			body:   `{"class": "ADC"}`,
+		}}, http.MethodGet)
+		// the deleted tenants are not re-posted
+		mockPM.detectDrift()
+		Expect(testutil.ToFloat64(prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test"))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test1"))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(prometheus.DriftHeals.WithLabelValues("10.10.10.1", "test1", "failure"))).To(Equal(heals))
+	})
+
+	It("skips the check when the deployed tenants could not be fetched", func() {
+		prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test").Set(0)
+		mockPM.setResponses([]responceCtx{{
+			tenant: "test",
+			status: http.StatusServiceUnavailable,
+			body:   `{"code": 503}`,
+		}}, http.MethodGet)
+		mockPM.detectDrift()
+		Expect(testutil.ToFloat64(prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test"))).To(Equal(0.0))
+	})
+
+	It("heals the drifted tenants", func() {
+		mockPM.AS3Config = cisapiv1.AS3Config{
+			DriftDetection: cisapiv1.DriftDetectionConfig{Interval: 60, Policy: DriftPolicyAutoHeal},
+		}
+		heals := testutil.ToFloat64(prometheus.DriftHeals.WithLabelValues("10.10.10.1", "test", "success"))
+		mockPM.setMethodResponses(map[string][]responceCtx{
+			http.MethodGet: {{status: http.StatusOK, body: `{"class": "ADC"}`}},
+			http.MethodPost: {{status: http.StatusOK, body: `{"results": [{"code": 200, "message": "success", "tenant": "test"}],
+				"declaration": {"test": {"class": "Tenant"}}}`}},
+		})
+		mockPM.detectDrift()
+		Expect(testutil.ToFloat64(prometheus.DriftHeals.WithLabelValues("10.10.10.1", "test", "success"))).To(Equal(heals + 1))
+		Expect(testutil.ToFloat64(prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test"))).To(Equal(0.0))
+		Expect(mockPM.cachedTenantDeclMap).To(HaveKeyWithValue("test", tenantDecl))
+	})
+
+	It("prunes the documents removed on Central Manager", func() {
+		mockPM.AS3Config = cisapiv1.AS3Config{
+			DocumentAPI:    true,
+			DriftDetection: cisapiv1.DriftDetectionConfig{Interval: 60, Policy: DriftPolicyReportOnly},
+		}
+		mockPM.cachedTenantDeclMap["test1"] = as3Tenant{
+			"class": "Tenant",
+			"label": "test",
+			"app1":  as3Application{"class": "Application", "template": "generic", "pool1": &as3Pool{Class: "Pool"}},
+		}
+		mockPM.tenantDeclarationIDMap["test"] = "doc1"
+		mockPM.tenantDeclarationIDMap["test1"] = "doc3"
+		mockPM.setResponses([]responceCtx{{
+			tenant: "test",
+			status: http.StatusOK,
+			body: `{"_embedded": {"appsvcs": [{"id": "doc2", "declaration": {"class": "ADC", "test": {"class": "Tenant",
+				"label": "test", "Shared": {"class": "Application", "template": "shared",
+				"pool1": {"class": "Pool", "loadBalancingMode": "round-robin"}}}}}]}}`,
+		}}, http.MethodGet)
+		mockPM.detectDrift()
+		// the removed document is re-created on the next post of the tenant
+		Expect(mockPM.tenantDeclarationIDMap).To(Equal(map[string]string{"test": "doc2"}))
+		Expect(testutil.ToFloat64(prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test"))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(prometheus.TenantDrift.WithLabelValues("10.10.10.1", "test1"))).To(Equal(1.0))
+	})
+})
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"fmt"
+
+	cisscheme "github.com/F5Networks/k8s-bigip-ctlr/v3/config/client/clientset/versioned/scheme"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	routeapi "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	gatewayscheme "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/scheme"
+)
+
+// setupEventRecorder creates the recorder to emit events on the resources processed by CIS
+func (ctlr *Controller) setupEventRecorder() {
+	if ctlr.clientsets == nil || ctlr.clientsets.kubeClient == nil {
+		log.Warningf("Kubernetes client not available, events will not be recorded")
+		return
+	}
+	// events are recorded on custom resources, routes and gateways, so register them along with the native types
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		cisscheme.AddToScheme,
+		routeapi.Install,
+		gatewayscheme.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			log.Errorf("Failed to setup scheme for event recorder: %v", err)
+			return
+		}
+	}
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: ctlr.clientsets.kubeClient.CoreV1().Events(""),
+	})
+	ctlr.eventRecorder = eventBroadcaster.NewRecorder(scheme, v1.EventSource{Component: EventSourceComponent})
+}
+
+// recordEvent emits an event on the given resource, if the event recorder is available
+func (ctlr *Controller) recordEvent(obj runtime.Object, eventType, reason, message string) {
+	if ctlr.eventRecorder == nil || obj == nil {
+		return
+	}
+	ctlr.eventRecorder.Event(obj, eventType, reason, message)
+}
+
+// recordEventf emits an event with formatted message on the given resource
+func (ctlr *Controller) recordEventf(obj runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	ctlr.recordEvent(obj, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"reflect"
+	"time"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	"k8s.io/client-go/tools/cache"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwinfv1 "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1"
+	gwinfv1alpha2 "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1alpha2"
+)
+
+// start the Gateway and Route informers
+func (gwInfr *GWInformer) start() {
+	var cacheSyncs []cache.InformerSynced
+	for _, inf := range []cache.SharedIndexInformer{gwInfr.gatewayInformer, gwInfr.httpRouteInformer,
+		gwInfr.tlsRouteInformer, gwInfr.tcpRouteInformer, gwInfr.udpRouteInformer} {
+		if inf != nil {
+			go inf.Run(gwInfr.stopCh)
+			cacheSyncs = append(cacheSyncs, inf.HasSynced)
+		}
+	}
+	log.Debugf("Starting gateway, httpRoute, tlsRoute, tcpRoute and udpRoute informers for namespace %v", gwInfr.namespace)
+	cache.WaitForNamedCacheSync(
+		"F5 CIS Ingress Controller",
+		gwInfr.stopCh,
+		cacheSyncs...,
+	)
+}
+
+func (gwInfr *GWInformer) stop() {
+	log.Debugf("Stopping gateway, httpRoute, tlsRoute, tcpRoute and udpRoute informers for namespace %v", gwInfr.namespace)
+	close(gwInfr.stopCh)
+}
+
+func (gcInfr *GatewayClassInformer) start() {
+	log.Debugf("Starting gatewayClass informer")
+	go gcInfr.gcInformer.Run(gcInfr.stopCh)
+	cache.WaitForNamedCacheSync(
+		"F5 CIS Ingress Controller",
+		gcInfr.stopCh,
+		gcInfr.gcInformer.HasSynced,
+	)
+}
+
+func (gcInfr *GatewayClassInformer) stop() {
+	log.Debugf("Stopping gatewayClass informer")
+	close(gcInfr.stopCh)
+}
+
+func (ctlr *Controller) getNamespacedGatewayInformer(
+	namespace string,
+) (*GWInformer, bool) {
+	if ctlr.watchingAllNamespaces() {
+		namespace = ""
+	}
+	gwInf, found := ctlr.gwInformers[namespace]
+	return gwInf, found
+}
+
+func (ctlr *Controller) newGatewayClassInformer() *GatewayClassInformer {
+	log.Debugf("Creating gatewayClass informer")
+	return &GatewayClassInformer{
+		stopCh: make(chan struct{}),
+		gcInformer: gwinfv1.NewGatewayClassInformer(
+			ctlr.clientsets.gatewayClient,
+			0*time.Second,
+			cache.Indexers{},
+		),
+	}
+}
+
+func (ctlr *Controller) newNamespacedGatewayInformer(
+	namespace string,
+) *GWInformer {
+	log.Debugf("Creating gateway API informers for namespace %v", namespace)
+	resyncPeriod := 0 * time.Second
+	indexers := cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}
+	client := ctlr.clientsets.gatewayClient
+	return &GWInformer{
+		namespace:         namespace,
+		stopCh:            make(chan struct{}),
+		gatewayInformer:   gwinfv1.NewGatewayInformer(client, namespace, resyncPeriod, indexers),
+		httpRouteInformer: gwinfv1.NewHTTPRouteInformer(client, namespace, resyncPeriod, indexers),
+		tlsRouteInformer:  gwinfv1alpha2.NewTLSRouteInformer(client, namespace, resyncPeriod, indexers),
+		tcpRouteInformer:  gwinfv1alpha2.NewTCPRouteInformer(client, namespace, resyncPeriod, indexers),
+		udpRouteInformer:  gwinfv1alpha2.NewUDPRouteInformer(client, namespace, resyncPeriod, indexers),
+	}
+}
+
+func (ctlr *Controller) addGatewayClassEventHandlers(gcInf *GatewayClassInformer) {
+	ctlr.addEventHandler(
+		gcInf.gcInformer,
+		&cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctlr.enqueueGatewayClass(obj, Create) },
+			UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedGatewayClass(old, cur) },
+			DeleteFunc: func(obj interface{}) { ctlr.enqueueGatewayClass(obj, Delete) },
+		},
+	)
+	gcInf.gcInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(GatewayClass, Local))
+}
+
+func (ctlr *Controller) addGatewayEventHandlers(gwInf *GWInformer) {
+	ctlr.addEventHandler(
+		gwInf.gatewayInformer,
+		&cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctlr.enqueueGateway(obj, Create) },
+			UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedGateway(old, cur) },
+			DeleteFunc: func(obj interface{}) { ctlr.enqueueGateway(obj, Delete) },
+		},
+	)
+	gwInf.gatewayInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(Gateway, Local))
+
+	for kind, inf := range map[string]cache.SharedIndexInformer{
+		HTTPRoute: gwInf.httpRouteInformer,
+		TLSRoute:  gwInf.tlsRouteInformer,
+		TCPRoute:  gwInf.tcpRouteInformer,
+		UDPRoute:  gwInf.udpRouteInformer,
+	} {
+		kind := kind
+		ctlr.addEventHandler(
+			inf,
+			&cache.ResourceEventHandlerFuncs{
+				AddFunc:    func(obj interface{}) { ctlr.enqueueGatewayRoute(obj, kind, Create) },
+				UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedGatewayRoute(old, cur, kind) },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueGatewayRoute(obj, kind, Delete) },
+			},
+		)
+		inf.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(kind, Local))
+	}
+}
+
+func (ctlr *Controller) enqueueGatewayClass(obj interface{}, event string) {
+	gc := obj.(*gatewayv1.GatewayClass)
+	log.Debugf("Enqueueing GatewayClass: %v", gc.Name)
+	key := &rqKey{
+		kind:    GatewayClass,
+		rscName: gc.Name,
+		rsc:     obj,
+		event:   event,
+	}
+	ctlr.resourceQueue.Add(key)
+}
+
+func (ctlr *Controller) enqueueUpdatedGatewayClass(oldObj, newObj interface{}) {
+	oldGC := oldObj.(*gatewayv1.GatewayClass)
+	newGC := newObj.(*gatewayv1.GatewayClass)
+	// Skip gateway classes on status updates
+	if reflect.DeepEqual(oldGC.Spec, newGC.Spec) {
+		return
+	}
+	ctlr.enqueueGatewayClass(newObj, Update)
+}
+
+func (ctlr *Controller) enqueueGateway(obj interface{}, event string) {
+	gw := obj.(*gatewayv1.Gateway)
+	log.Debugf("Enqueueing Gateway: %v/%v", gw.Namespace, gw.Name)
+	key := &rqKey{
+		namespace: gw.Namespace,
+		kind:      Gateway,
+		rscName:   gw.Name,
+		rsc:       obj,
+		event:     event,
+	}
+	ctlr.resourceQueue.Add(key)
+}
+
+func (ctlr *Controller) enqueueUpdatedGateway(oldObj, newObj interface{}) {
+	oldGW := oldObj.(*gatewayv1.Gateway)
+	newGW := newObj.(*gatewayv1.Gateway)
+	// Skip gateways on status updates
+	if reflect.DeepEqual(oldGW.Spec, newGW.Spec) && reflect.DeepEqual(oldGW.Labels, newGW.Labels) {
+		return
+	}
+	ctlr.enqueueGateway(newObj, Update)
+}
+
+func (ctlr *Controller) enqueueGatewayRoute(obj interface{}, kind string, event string) {
+	namespace, name, _ := getGatewayRouteParentRefs(obj)
+	log.Debugf("Enqueueing %v: %v/%v", kind, namespace, name)
+	key := &rqKey{
+		namespace: namespace,
+		kind:      kind,
+		rscName:   name,
+		rsc:       obj,
+		event:     event,
+	}
+	ctlr.resourceQueue.Add(key)
+}
+
+func (ctlr *Controller) enqueueUpdatedGatewayRoute(oldObj, newObj interface{}, kind string) {
+	// Skip routes on status updates
+	if reflect.DeepEqual(getGatewayRouteSpec(oldObj), getGatewayRouteSpec(newObj)) {
+		return
+	}
+	_, _, oldParentRefs := getGatewayRouteParentRefs(oldObj)
+	_, _, newParentRefs := getGatewayRouteParentRefs(newObj)
+	if !reflect.DeepEqual(oldParentRefs, newParentRefs) {
+		// gateways which are no longer referred by the route need to be processed as well
+		ctlr.enqueueGatewayRoute(oldObj, kind, Delete)
+	}
+	ctlr.enqueueGatewayRoute(newObj, kind, Update)
+}
+
+// getGatewayRouteParentRefs returns the namespace, name and the parent references of the route
+func getGatewayRouteParentRefs(obj interface{}) (string, string, []gatewayv1.ParentReference) {
+	switch rt := obj.(type) {
+	case *gatewayv1.HTTPRoute:
+		return rt.Namespace, rt.Name, rt.Spec.ParentRefs
+	case *gatewayv1alpha2.TLSRoute:
+		return rt.Namespace, rt.Name, rt.Spec.ParentRefs
+	case *gatewayv1alpha2.TCPRoute:
+		return rt.Namespace, rt.Name, rt.Spec.ParentRefs
+	case *gatewayv1alpha2.UDPRoute:
+		return rt.Namespace, rt.Name, rt.Spec.ParentRefs
+	}
+	return "", "", nil
+}
+
+func getGatewayRouteSpec(obj interface{}) interface{} {
+	switch rt := obj.(type) {
+	case *gatewayv1.HTTPRoute:
+		return rt.Spec
+	case *gatewayv1alpha2.TLSRoute:
+		return rt.Spec
+	case *gatewayv1alpha2.TCPRoute:
+		return rt.Spec
+	case *gatewayv1alpha2.UDPRoute:
+		return rt.Spec
+	}
+	return nil
+}
@@ -0,0 +1,1351 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// processGatewayClass updates the status of the gateway class and processes the gateways of the class
+func (ctlr *Controller) processGatewayClass(gc *gatewayv1.GatewayClass, isDelete bool) error {
+	if string(gc.Spec.ControllerName) != ctlr.gatewayControllerName {
+		return nil
+	}
+	if !isDelete {
+		ctlr.updateGatewayClassStatus(gc)
+	}
+	var err error
+	for _, gw := range ctlr.getAllGateways("") {
+		if string(gw.Spec.GatewayClassName) != gc.Name {
+			continue
+		}
+		if gwErr := ctlr.processGateway(gw, false); gwErr != nil {
+			err = gwErr
+		}
+	}
+	return err
+}
+
+// processGateway translates the gateway and its routes to the BIG-IP virtuals, the virtuals of the gateway are
+// recreated on every change of the gateway, its routes or the referred secrets
+func (ctlr *Controller) processGateway(gw *gatewayv1.Gateway, isDelete bool) error {
+	startTime := time.Now()
+	defer func() {
+		log.Debugf("Finished syncing gateway %v/%v (%v)", gw.Namespace, gw.Name, time.Since(startTime))
+	}()
+
+	gwKey := gw.Namespace + "/" + gw.Name
+	gwRef := resourceRef{
+		kind:      Gateway,
+		namespace: gw.Namespace,
+		name:      gw.Name,
+	}
+	bigipConfig := ctlr.getBIGIPConfig(BigIPLabel)
+	partition := ctlr.getCRPartition("", BigIPLabel)
+	rsMap := ctlr.resources.getPartitionResourceMap(partition, bigipConfig)
+	var staleVirtuals []string
+	for rsName, rsCfg := range rsMap {
+		if rsCfg.MetaData.baseResources[gwKey] == Gateway {
+			staleVirtuals = append(staleVirtuals, rsName)
+		}
+	}
+	for _, rsName := range staleVirtuals {
+		ctlr.deleteVirtualServer(partition, rsName, bigipConfig)
+	}
+	ctlr.deleteResourceExternalClusterSvcRouteReference(gwRef)
+
+	gc := ctlr.getGatewayClass(string(gw.Spec.GatewayClassName))
+	if isDelete || gc == nil || string(gc.Spec.ControllerName) != ctlr.gatewayControllerName {
+		// routes are no longer attached to the gateway
+		for _, rt := range ctlr.getGatewayRoutes(gw) {
+			ctlr.updateGatewayRouteStatus(rt, gw, nil)
+		}
+		return nil
+	}
+	ctlr.updateGatewayClassStatus(gc)
+
+	ip, addressReason := getGatewayAddress(gw)
+	listeners := ctlr.validateGatewayListeners(gw)
+	routes := ctlr.getGatewayRoutes(gw)
+	routeParents := ctlr.attachGatewayRoutes(gw, listeners, routes)
+
+	var processingErr error
+	vsMap := make(ResourceMap)
+	if ip != "" {
+		vsMap, processingErr = ctlr.prepareGatewayVirtuals(gw, gwRef, ip, partition, listeners)
+		if processingErr != nil {
+			log.Errorf("Cannot Publish Gateway %v: %v", gwKey, processingErr)
+			ctlr.recordEventf(gw, v1.EventTypeWarning, InvalidSpec, "Failed to process the Gateway: %v", processingErr)
+			vsMap = make(ResourceMap)
+		} else {
+			for rsName, rsCfg := range vsMap {
+				rsMap[rsName] = rsCfg
+			}
+		}
+	} else {
+		ctlr.recordEvent(gw, v1.EventTypeWarning, InvalidSpec, "No IPAddress type address found in the Gateway spec")
+	}
+
+	ctlr.updateGatewayStatus(gw, func(status *gatewayv1.GatewayStatus, generation int64) {
+		status.Addresses = nil
+		if ip != "" {
+			addressType := gatewayv1.IPAddressType
+			status.Addresses = []gatewayv1.GatewayStatusAddress{{Type: &addressType, Value: ip}}
+		}
+		validListeners := 0
+		var listenerStatuses []gatewayv1.ListenerStatus
+		for _, gl := range listeners {
+			if gl.valid {
+				validListeners++
+			}
+			listenerStatus := gatewayv1.ListenerStatus{
+				Name:           gl.listener.Name,
+				SupportedKinds: gl.supportedKinds,
+				AttachedRoutes: int32(len(gl.attachedRoutes)),
+			}
+			for _, old := range status.Listeners {
+				if old.Name == gl.listener.Name {
+					listenerStatus.Conditions = old.Conditions
+				}
+			}
+			setGatewayConditions(&listenerStatus.Conditions, gl.conditions, generation)
+			listenerStatuses = append(listenerStatuses, listenerStatus)
+		}
+		status.Listeners = listenerStatuses
+
+		accepted := newGatewayCondition(string(gatewayv1.GatewayConditionAccepted), metav1.ConditionTrue,
+			string(gatewayv1.GatewayReasonAccepted), "Gateway is accepted")
+		// the Programmed condition is updated once the virtuals are posted to BIG-IP
+		programmed := newGatewayCondition(string(gatewayv1.GatewayConditionProgrammed), metav1.ConditionFalse,
+			string(gatewayv1.GatewayReasonPending), "Waiting for the Gateway to be deployed on BIG-IP")
+		if existing := apimeta.FindStatusCondition(status.Conditions, programmed.Type); existing != nil &&
+			existing.Status == metav1.ConditionTrue && existing.ObservedGeneration == generation {
+			programmed = *existing
+		}
+		switch {
+		case addressReason == gatewayv1.GatewayReasonUnsupportedAddress:
+			accepted = newGatewayCondition(string(gatewayv1.GatewayConditionAccepted), metav1.ConditionFalse,
+				string(addressReason), "Only IPAddress type addresses are supported")
+			programmed = newGatewayCondition(string(gatewayv1.GatewayConditionProgrammed), metav1.ConditionFalse,
+				string(gatewayv1.GatewayReasonInvalid), "Only IPAddress type addresses are supported")
+		case validListeners == 0:
+			accepted = newGatewayCondition(string(gatewayv1.GatewayConditionAccepted), metav1.ConditionFalse,
+				string(gatewayv1.GatewayReasonListenersNotValid), "None of the listeners are valid")
+			programmed = newGatewayCondition(string(gatewayv1.GatewayConditionProgrammed), metav1.ConditionFalse,
+				string(gatewayv1.GatewayReasonInvalid), "None of the listeners are valid")
+		case addressReason == gatewayv1.GatewayReasonAddressNotAssigned:
+			programmed = newGatewayCondition(string(gatewayv1.GatewayConditionProgrammed), metav1.ConditionFalse,
+				string(addressReason), "No IPAddress type address found in the Gateway spec")
+		case processingErr != nil:
+			programmed = newGatewayCondition(string(gatewayv1.GatewayConditionProgrammed), metav1.ConditionFalse,
+				string(gatewayv1.GatewayReasonInvalid), processingErr.Error())
+		case len(vsMap) == 0:
+			programmed = newGatewayCondition(string(gatewayv1.GatewayConditionProgrammed), metav1.ConditionFalse,
+				string(gatewayv1.GatewayReasonInvalid), "No routes are attached to the listeners of the Gateway")
+		}
+		setGatewayConditions(&status.Conditions, []metav1.Condition{accepted, programmed}, generation)
+	})
+
+	for _, rt := range routes {
+		ctlr.updateGatewayRouteStatus(rt, gw, routeParents[rt])
+	}
+	return nil
+}
+
+// processGatewayRoute processes the gateways referred by the route
+func (ctlr *Controller) processGatewayRoute(kind string, obj interface{}, isDelete bool) error {
+	namespace, name, parentRefs := getGatewayRouteParentRefs(obj)
+	if !isDelete {
+		// clear the status of the gateways which are no longer referred by the route
+		if rt := ctlr.getGatewayRoute(kind, namespace, name); rt != nil {
+			ctlr.updateGatewayRouteStatus(rt, nil, nil)
+		}
+	}
+	var err error
+	processed := make(map[string]struct{})
+	for _, ref := range parentRefs {
+		if !isGatewayKindParentRef(ref) {
+			continue
+		}
+		gwNamespace := namespace
+		if ref.Namespace != nil {
+			gwNamespace = string(*ref.Namespace)
+		}
+		gwKey := gwNamespace + "/" + string(ref.Name)
+		if _, ok := processed[gwKey]; ok {
+			continue
+		}
+		processed[gwKey] = struct{}{}
+		if gw := ctlr.getGateway(gwNamespace, string(ref.Name)); gw != nil {
+			if gwErr := ctlr.processGateway(gw, false); gwErr != nil {
+				err = gwErr
+			}
+		}
+	}
+	return err
+}
+
+// getGatewayAddress returns the IP address of the gateway, CIS doesn't allocate addresses for the gateways
+func getGatewayAddress(gw *gatewayv1.Gateway) (string, gatewayv1.GatewayConditionReason) {
+	for _, address := range gw.Spec.Addresses {
+		if address.Type == nil || *address.Type == gatewayv1.IPAddressType {
+			return address.Value, ""
+		}
+	}
+	if len(gw.Spec.Addresses) > 0 {
+		return "", gatewayv1.GatewayReasonUnsupportedAddress
+	}
+	return "", gatewayv1.GatewayReasonAddressNotAssigned
+}
+
+// validateGatewayListeners validates the listeners of the gateway and prepares the listener conditions
+func (ctlr *Controller) validateGatewayListeners(gw *gatewayv1.Gateway) []*gatewayListener {
+	portProtocols := make(map[gatewayv1.PortNumber]map[gatewayv1.ProtocolType]int)
+	for _, l := range gw.Spec.Listeners {
+		if _, ok := portProtocols[l.Port]; !ok {
+			portProtocols[l.Port] = make(map[gatewayv1.ProtocolType]int)
+		}
+		portProtocols[l.Port][l.Protocol]++
+	}
+
+	var listeners []*gatewayListener
+	for _, l := range gw.Spec.Listeners {
+		gl := &gatewayListener{
+			listener:   l,
+			routeHosts: make(map[*gatewayRoute][]string),
+		}
+		accepted := newGatewayCondition(string(gatewayv1.ListenerConditionAccepted), metav1.ConditionTrue,
+			string(gatewayv1.ListenerReasonAccepted), "Listener is accepted")
+		conflicted := newGatewayCondition(string(gatewayv1.ListenerConditionConflicted), metav1.ConditionFalse,
+			string(gatewayv1.ListenerReasonNoConflicts), "Listener has no conflicts")
+		resolvedRefs := newGatewayCondition(string(gatewayv1.ListenerConditionResolvedRefs), metav1.ConditionTrue,
+			string(gatewayv1.ListenerReasonResolvedRefs), "References of the listener are resolved")
+
+		switch l.Protocol {
+		case gatewayv1.HTTPProtocolType:
+			gl.routeKind = HTTPRoute
+		case gatewayv1.HTTPSProtocolType:
+			gl.routeKind = HTTPRoute
+			if l.TLS != nil && l.TLS.Mode != nil && *l.TLS.Mode != gatewayv1.TLSModeTerminate {
+				accepted = newGatewayCondition(string(gatewayv1.ListenerConditionAccepted), metav1.ConditionFalse,
+					string(gatewayv1.ListenerReasonUnsupportedProtocol), "HTTPS listener supports only Terminate TLS mode")
+			}
+			reason, message := "", ""
+			gl.secrets, reason, message = ctlr.validateGatewayCertificateRefs(gw, l)
+			if reason != "" {
+				resolvedRefs = newGatewayCondition(string(gatewayv1.ListenerConditionResolvedRefs),
+					metav1.ConditionFalse, reason, message)
+			}
+		case gatewayv1.TLSProtocolType:
+			gl.routeKind = TLSRoute
+			if l.TLS == nil || l.TLS.Mode == nil || *l.TLS.Mode != gatewayv1.TLSModePassthrough {
+				accepted = newGatewayCondition(string(gatewayv1.ListenerConditionAccepted), metav1.ConditionFalse,
+					string(gatewayv1.ListenerReasonUnsupportedProtocol), "TLS listener supports only Passthrough TLS mode")
+			}
+		case gatewayv1.TCPProtocolType:
+			gl.routeKind = TCPRoute
+		case gatewayv1.UDPProtocolType:
+			gl.routeKind = UDPRoute
+		default:
+			accepted = newGatewayCondition(string(gatewayv1.ListenerConditionAccepted), metav1.ConditionFalse,
+				string(gatewayv1.ListenerReasonUnsupportedProtocol), fmt.Sprintf("Protocol %v is not supported", l.Protocol))
+		}
+
+		if gl.routeKind != "" {
+			// a BIG-IP virtual serves a single protocol, TCP and UDP virtuals serve a single route
+			isL4 := l.Protocol == gatewayv1.TCPProtocolType || l.Protocol == gatewayv1.UDPProtocolType
+			if len(portProtocols[l.Port]) > 1 || (isL4 && portProtocols[l.Port][l.Protocol] > 1) {
+				conflicted = newGatewayCondition(string(gatewayv1.ListenerConditionConflicted), metav1.ConditionTrue,
+					string(gatewayv1.ListenerReasonProtocolConflict), fmt.Sprintf("Port %v is used by another listener", l.Port))
+				accepted = newGatewayCondition(string(gatewayv1.ListenerConditionAccepted), metav1.ConditionFalse,
+					string(gatewayv1.ListenerReasonPortUnavailable), fmt.Sprintf("Port %v is used by another listener", l.Port))
+			}
+			supportedKind := gatewayv1.RouteGroupKind{Group: gatewayGroup(), Kind: gatewayv1.Kind(gl.routeKind)}
+			if l.AllowedRoutes != nil && len(l.AllowedRoutes.Kinds) > 0 {
+				routeKindAllowed := false
+				for _, k := range l.AllowedRoutes.Kinds {
+					if (k.Group == nil || *k.Group == gatewayv1.GroupName) && string(k.Kind) == gl.routeKind {
+						routeKindAllowed = true
+					} else if resolvedRefs.Status == metav1.ConditionTrue {
+						resolvedRefs = newGatewayCondition(string(gatewayv1.ListenerConditionResolvedRefs),
+							metav1.ConditionFalse, string(gatewayv1.ListenerReasonInvalidRouteKinds),
+							fmt.Sprintf("Route kind %v is not supported for %v listener", k.Kind, l.Protocol))
+					}
+				}
+				if !routeKindAllowed {
+					gl.routeKind = ""
+				}
+			}
+			if gl.routeKind != "" {
+				gl.supportedKinds = []gatewayv1.RouteGroupKind{supportedKind}
+			}
+		}
+
+		gl.valid = gl.routeKind != "" && accepted.Status == metav1.ConditionTrue &&
+			(resolvedRefs.Status == metav1.ConditionTrue ||
+				resolvedRefs.Reason == string(gatewayv1.ListenerReasonInvalidRouteKinds))
+		programmed := newGatewayCondition(string(gatewayv1.ListenerConditionProgrammed), metav1.ConditionTrue,
+			string(gatewayv1.ListenerReasonProgrammed), "Listener is programmed")
+		if !gl.valid {
+			programmed = newGatewayCondition(string(gatewayv1.ListenerConditionProgrammed), metav1.ConditionFalse,
+				string(gatewayv1.ListenerReasonInvalid), "Listener is invalid")
+		}
+		gl.conditions = []metav1.Condition{accepted, conflicted, resolvedRefs, programmed}
+		listeners = append(listeners, gl)
+	}
+	return listeners
+}
+
+// validateGatewayCertificateRefs returns the secrets referred by the HTTPS listener, reason is set if any of the
+// references is invalid
+func (ctlr *Controller) validateGatewayCertificateRefs(
+	gw *gatewayv1.Gateway,
+	l gatewayv1.Listener,
+) ([]string, string, string) {
+	if l.TLS == nil || len(l.TLS.CertificateRefs) == 0 {
+		return nil, string(gatewayv1.ListenerReasonInvalidCertificateRef), "No certificateRefs found for the HTTPS listener"
+	}
+	var secrets []string
+	for _, ref := range l.TLS.CertificateRefs {
+		if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != "Secret") {
+			return nil, string(gatewayv1.ListenerReasonInvalidCertificateRef), "Only Secret certificateRefs are supported"
+		}
+		if ref.Namespace != nil && string(*ref.Namespace) != gw.Namespace {
+			return nil, string(gatewayv1.ListenerReasonRefNotPermitted),
+				fmt.Sprintf("Secret %v/%v is not in the namespace of the Gateway", *ref.Namespace, ref.Name)
+		}
+		secret := ctlr.getGatewaySecret(gw.Namespace, string(ref.Name))
+		if secret == nil {
+			return nil, string(gatewayv1.ListenerReasonInvalidCertificateRef),
+				fmt.Sprintf("Secret %v/%v not found", gw.Namespace, ref.Name)
+		}
+		if len(secret.Data["tls.crt"]) == 0 || len(secret.Data["tls.key"]) == 0 {
+			return nil, string(gatewayv1.ListenerReasonInvalidCertificateRef),
+				fmt.Sprintf("Secret %v/%v doesn't have tls.crt and tls.key", gw.Namespace, ref.Name)
+		}
+		secrets = append(secrets, string(ref.Name))
+	}
+	return secrets, "", ""
+}
+
+// attachGatewayRoutes attaches the routes to the listeners of the gateway and returns the parent statuses of
+// the routes for the gateway
+func (ctlr *Controller) attachGatewayRoutes(
+	gw *gatewayv1.Gateway,
+	listeners []*gatewayListener,
+	routes []*gatewayRoute,
+) map[*gatewayRoute][]gatewayv1.RouteParentStatus {
+	// the reasons are ordered by the preference of reporting
+	reasonRank := map[gatewayv1.RouteConditionReason]int{
+		gatewayv1.RouteReasonNoMatchingParent:           0,
+		gatewayv1.RouteReasonNotAllowedByListeners:      1,
+		gatewayv1.RouteReasonNoMatchingListenerHostname: 2,
+		gatewayv1.RouteReasonAccepted:                   3,
+	}
+	namespaceLabels := make(map[string]labels.Set)
+	routeParents := make(map[*gatewayRoute][]gatewayv1.RouteParentStatus)
+	for _, rt := range routes {
+		resolvedRefs := ctlr.validateGatewayRouteBackends(rt)
+		unsupported := validateGatewayRoute(rt)
+		for _, ref := range rt.parentRefs {
+			if !isGatewayParentRef(ref, rt.namespace, gw) {
+				continue
+			}
+			reason := gatewayv1.RouteReasonNoMatchingParent
+			for _, gl := range listeners {
+				if unsupported != "" {
+					break
+				}
+				if (ref.SectionName != nil && *ref.SectionName != gl.listener.Name) ||
+					(ref.Port != nil && *ref.Port != gl.listener.Port) || !gl.valid {
+					continue
+				}
+				if gl.routeKind != rt.kind || !ctlr.isGatewayRouteNamespaceAllowed(gw, gl.listener, rt.namespace, namespaceLabels) {
+					if reasonRank[reason] < reasonRank[gatewayv1.RouteReasonNotAllowedByListeners] {
+						reason = gatewayv1.RouteReasonNotAllowedByListeners
+					}
+					continue
+				}
+				hosts := intersectGatewayHostnames(gl.listener.Hostname, rt.hostnames, rt.kind == TLSRoute)
+				if len(hosts) == 0 {
+					if reasonRank[reason] < reasonRank[gatewayv1.RouteReasonNoMatchingListenerHostname] {
+						reason = gatewayv1.RouteReasonNoMatchingListenerHostname
+					}
+					continue
+				}
+				if _, ok := gl.routeHosts[rt]; !ok {
+					gl.attachedRoutes = append(gl.attachedRoutes, rt)
+				}
+				gl.routeHosts[rt] = appendUniqueHosts(gl.routeHosts[rt], hosts)
+				reason = gatewayv1.RouteReasonAccepted
+			}
+
+			var accepted metav1.Condition
+			switch {
+			case unsupported != "":
+				accepted = newGatewayCondition(string(gatewayv1.RouteConditionAccepted), metav1.ConditionFalse,
+					string(gatewayv1.RouteReasonUnsupportedValue), unsupported)
+			case reason == gatewayv1.RouteReasonAccepted:
+				accepted = newGatewayCondition(string(gatewayv1.RouteConditionAccepted), metav1.ConditionTrue,
+					string(reason), "Route is accepted")
+			case reason == gatewayv1.RouteReasonNotAllowedByListeners:
+				accepted = newGatewayCondition(string(gatewayv1.RouteConditionAccepted), metav1.ConditionFalse,
+					string(reason), "Route is not allowed by the listeners of the Gateway")
+			case reason == gatewayv1.RouteReasonNoMatchingListenerHostname:
+				accepted = newGatewayCondition(string(gatewayv1.RouteConditionAccepted), metav1.ConditionFalse,
+					string(reason), "Hostnames of the route don't match the listeners of the Gateway")
+			default:
+				accepted = newGatewayCondition(string(gatewayv1.RouteConditionAccepted), metav1.ConditionFalse,
+					string(reason), "No valid listener matches the parentRef")
+			}
+			routeParents[rt] = append(routeParents[rt], gatewayv1.RouteParentStatus{
+				ParentRef:      ref,
+				ControllerName: gatewayv1.GatewayController(ctlr.gatewayControllerName),
+				Conditions:     []metav1.Condition{accepted, resolvedRefs},
+			})
+		}
+	}
+	return routeParents
+}
+
+// isGatewayRouteNamespaceAllowed checks the allowedRoutes namespaces of the listener, labels of the
+// namespaces are cached in nsLabels
+func (ctlr *Controller) isGatewayRouteNamespaceAllowed(
+	gw *gatewayv1.Gateway,
+	l gatewayv1.Listener,
+	namespace string,
+	nsLabels map[string]labels.Set,
+) bool {
+	from := gatewayv1.NamespacesFromSame
+	var selector *metav1.LabelSelector
+	if l.AllowedRoutes != nil && l.AllowedRoutes.Namespaces != nil {
+		if l.AllowedRoutes.Namespaces.From != nil {
+			from = *l.AllowedRoutes.Namespaces.From
+		}
+		selector = l.AllowedRoutes.Namespaces.Selector
+	}
+	switch from {
+	case gatewayv1.NamespacesFromAll:
+		return true
+	case gatewayv1.NamespacesFromSelector:
+		if selector == nil {
+			return false
+		}
+		nsSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			log.Errorf("Invalid namespace selector in listener %v of Gateway %v/%v: %v", l.Name, gw.Namespace,
+				gw.Name, err)
+			return false
+		}
+		if _, ok := nsLabels[namespace]; !ok {
+			ns, err := ctlr.clientsets.kubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+			if err != nil {
+				log.Errorf("Unable to fetch namespace %v: %v", namespace, err)
+				return false
+			}
+			nsLabels[namespace] = ns.Labels
+		}
+		return nsSelector.Matches(nsLabels[namespace])
+	default:
+		return namespace == gw.Namespace
+	}
+}
+
+// intersectGatewayHostnames returns the hostnames of the route which match the listener hostname, if the route
+// has no hostnames the listener hostname is used
+func intersectGatewayHostnames(listenerHost *gatewayv1.Hostname, routeHosts []gatewayv1.Hostname, hostRequired bool) []string {
+	var lHost string
+	if listenerHost != nil {
+		lHost = string(*listenerHost)
+	}
+	var hosts []string
+	if len(routeHosts) == 0 {
+		hosts = []string{lHost}
+	}
+	for _, host := range routeHosts {
+		rHost := string(host)
+		switch {
+		case lHost == "" || gatewayHostnameMatches(lHost, rHost):
+			hosts = append(hosts, rHost)
+		case gatewayHostnameMatches(rHost, lHost):
+			hosts = append(hosts, lHost)
+		}
+	}
+	if hostRequired {
+		var validHosts []string
+		for _, host := range hosts {
+			if host != "" {
+				validHosts = append(validHosts, host)
+			}
+		}
+		return validHosts
+	}
+	return hosts
+}
+
+// gatewayHostnameMatches checks if the hostname matches the pattern, a wildcard pattern matches one or more labels
+func gatewayHostnameMatches(pattern, hostname string) bool {
+	if pattern == hostname {
+		return true
+	}
+	if strings.HasPrefix(pattern, "*.") {
+		return len(hostname) > len(pattern)-1 && strings.HasSuffix(hostname, pattern[1:])
+	}
+	return false
+}
+
+func appendUniqueHosts(hosts []string, newHosts []string) []string {
+	for _, host := range newHosts {
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// validateGatewayRoute returns the reason if the route uses any feature which is not supported
+func validateGatewayRoute(rt *gatewayRoute) string {
+	switch route := rt.rsc.(type) {
+	case *gatewayv1.HTTPRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, match := range rule.Matches {
+				if match.Path != nil && match.Path.Type != nil && *match.Path.Type != gatewayv1.PathMatchPathPrefix {
+					return fmt.Sprintf("Path match type %v is not supported", *match.Path.Type)
+				}
+				if len(match.Headers) > 0 || len(match.QueryParams) > 0 || match.Method != nil {
+					return "Header, query param and method matches are not supported"
+				}
+			}
+			for _, filter := range rule.Filters {
+				if filter.Type != gatewayv1.HTTPRouteFilterURLRewrite {
+					return fmt.Sprintf("Filter %v is not supported", filter.Type)
+				}
+				if filter.URLRewrite != nil && filter.URLRewrite.Path != nil &&
+					filter.URLRewrite.Path.Type != gatewayv1.PrefixMatchHTTPPathModifier {
+					return fmt.Sprintf("URLRewrite path modifier %v is not supported", filter.URLRewrite.Path.Type)
+				}
+			}
+			for _, backendRef := range rule.BackendRefs {
+				if len(backendRef.Filters) > 0 {
+					return "Filters of the backendRefs are not supported"
+				}
+			}
+		}
+	case *gatewayv1alpha2.TLSRoute, *gatewayv1alpha2.TCPRoute, *gatewayv1alpha2.UDPRoute:
+		// BIG-IP virtual serves a single pool for these routes
+		if backendRefs := getGatewayRouteBackendRefs(rt); len(backendRefs) != 1 {
+			return fmt.Sprintf("%v supports exactly one backendRef", rt.kind)
+		}
+	}
+	for _, backendRef := range getGatewayRouteBackendRefs(rt) {
+		if backendRef.Port == nil {
+			return fmt.Sprintf("Port of the backendRef %v is required", backendRef.Name)
+		}
+	}
+	return ""
+}
+
+// validateGatewayRouteBackends returns the ResolvedRefs condition of the route
+func (ctlr *Controller) validateGatewayRouteBackends(rt *gatewayRoute) metav1.Condition {
+	for _, backendRef := range getGatewayRouteBackendRefs(rt) {
+		if (backendRef.Group != nil && *backendRef.Group != "") || (backendRef.Kind != nil && *backendRef.Kind != "Service") {
+			return newGatewayCondition(string(gatewayv1.RouteConditionResolvedRefs), metav1.ConditionFalse,
+				string(gatewayv1.RouteReasonInvalidKind), "Only Service backendRefs are supported")
+		}
+		if backendRef.Namespace != nil && string(*backendRef.Namespace) != rt.namespace {
+			return newGatewayCondition(string(gatewayv1.RouteConditionResolvedRefs), metav1.ConditionFalse,
+				string(gatewayv1.RouteReasonRefNotPermitted),
+				fmt.Sprintf("Service %v/%v is not in the namespace of the route", *backendRef.Namespace, backendRef.Name))
+		}
+		if ctlr.GetService(rt.namespace, string(backendRef.Name)) == nil {
+			return newGatewayCondition(string(gatewayv1.RouteConditionResolvedRefs), metav1.ConditionFalse,
+				string(gatewayv1.RouteReasonBackendNotFound),
+				fmt.Sprintf("Service %v/%v not found", rt.namespace, backendRef.Name))
+		}
+	}
+	return newGatewayCondition(string(gatewayv1.RouteConditionResolvedRefs), metav1.ConditionTrue,
+		string(gatewayv1.RouteReasonResolvedRefs), "References of the route are resolved")
+}
+
+// isValidGatewayBackendRef checks if the backendRef refers a service in the namespace of the route
+func isValidGatewayBackendRef(namespace string, backendRef gatewayv1.BackendRef) bool {
+	return (backendRef.Group == nil || *backendRef.Group == "") &&
+		(backendRef.Kind == nil || *backendRef.Kind == "Service") &&
+		(backendRef.Namespace == nil || string(*backendRef.Namespace) == namespace) &&
+		backendRef.Port != nil
+}
+
+// getGatewayRouteBackendRefs returns the backendRefs of all the rules of the route
+func getGatewayRouteBackendRefs(rt *gatewayRoute) []gatewayv1.BackendRef {
+	var backendRefs []gatewayv1.BackendRef
+	switch route := rt.rsc.(type) {
+	case *gatewayv1.HTTPRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				backendRefs = append(backendRefs, backendRef.BackendRef)
+			}
+		}
+	case *gatewayv1alpha2.TLSRoute:
+		for _, rule := range route.Spec.Rules {
+			backendRefs = append(backendRefs, rule.BackendRefs...)
+		}
+	case *gatewayv1alpha2.TCPRoute:
+		for _, rule := range route.Spec.Rules {
+			backendRefs = append(backendRefs, rule.BackendRefs...)
+		}
+	case *gatewayv1alpha2.UDPRoute:
+		for _, rule := range route.Spec.Rules {
+			backendRefs = append(backendRefs, rule.BackendRefs...)
+		}
+	}
+	return backendRefs
+}
+
+// getGatewayPool prepares the VirtualServer pool for the backendRefs, multiple backendRefs are configured as
+// alternate backends with the weights
+func getGatewayPool(namespace string, backendRefs []gatewayv1.BackendRef) (cisapiv1.VSPool, bool) {
+	var validRefs []gatewayv1.BackendRef
+	for _, backendRef := range backendRefs {
+		if isValidGatewayBackendRef(namespace, backendRef) {
+			validRefs = append(validRefs, backendRef)
+		}
+	}
+	if len(validRefs) == 0 {
+		return cisapiv1.VSPool{}, false
+	}
+	backendWeight := func(backendRef gatewayv1.BackendRef) *int32 {
+		weight := int32(1)
+		if backendRef.Weight != nil {
+			weight = *backendRef.Weight
+		}
+		return &weight
+	}
+	pool := cisapiv1.VSPool{
+		Service:          string(validRefs[0].Name),
+		ServicePort:      intstr.FromInt(int(*validRefs[0].Port)),
+		ServiceNamespace: namespace,
+	}
+	if len(validRefs) > 1 {
+		pool.Weight = backendWeight(validRefs[0])
+		for _, backendRef := range validRefs[1:] {
+			pool.AlternateBackends = append(pool.AlternateBackends, cisapiv1.AlternateBackend{
+				Service:          string(backendRef.Name),
+				ServiceNamespace: namespace,
+				Weight:           backendWeight(backendRef),
+			})
+		}
+	}
+	return pool, true
+}
+
+// prepareGatewayVirtuals prepares a virtual for every port of the valid listeners of the gateway
+func (ctlr *Controller) prepareGatewayVirtuals(
+	gw *gatewayv1.Gateway,
+	gwRef resourceRef,
+	ip string,
+	partition string,
+	listeners []*gatewayListener,
+) (ResourceMap, error) {
+	portListeners := make(map[int32][]*gatewayListener)
+	var ports []int32
+	for _, gl := range listeners {
+		if !gl.valid || len(gl.attachedRoutes) == 0 {
+			continue
+		}
+		port := int32(gl.listener.Port)
+		if _, ok := portListeners[port]; !ok {
+			ports = append(ports, port)
+		}
+		portListeners[port] = append(portListeners[port], gl)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	vsMap := make(ResourceMap)
+	for _, port := range ports {
+		gls := portListeners[port]
+		rsCfg := &ResourceConfig{}
+		rsCfg.Virtual.Partition = partition
+		rsCfg.Virtual.Enabled = true
+		rsCfg.Virtual.Name = formatCustomVirtualServerName(gw.Namespace+"_"+gw.Name, port)
+		rsCfg.MetaData.baseResources = map[string]string{gwRef.namespace + "/" + gwRef.name: Gateway}
+		rsCfg.Virtual.SetVirtualAddress(ip, port)
+		rsCfg.IntDgMap = make(InternalDataGroupMap)
+		rsCfg.IRulesMap = make(IRulesMap)
+		rsCfg.customProfiles = make(map[SecretKey]CustomProfile)
+
+		var err error
+		switch gls[0].listener.Protocol {
+		case gatewayv1.HTTPProtocolType, gatewayv1.HTTPSProtocolType:
+			err = ctlr.prepareRSConfigFromGatewayHTTPListeners(rsCfg, gw, gwRef, gls, port)
+		case gatewayv1.TLSProtocolType:
+			err = ctlr.prepareRSConfigFromGatewayTLSListeners(rsCfg, gw, gwRef, gls, port)
+		case gatewayv1.TCPProtocolType, gatewayv1.UDPProtocolType:
+			err = ctlr.prepareRSConfigFromGatewayL4Listener(rsCfg, gwRef, gls[0], port)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rsCfg.Pools) == 0 {
+			continue
+		}
+		vsMap[rsCfg.Virtual.Name] = rsCfg
+	}
+	return vsMap, nil
+}
+
+// prepareRSConfigFromGatewayHTTPListeners prepares the virtual of the HTTP or HTTPS listeners sharing the port,
+// every hostname of the HTTPRoutes is processed as a VirtualServer
+func (ctlr *Controller) prepareRSConfigFromGatewayHTTPListeners(
+	rsCfg *ResourceConfig,
+	gw *gatewayv1.Gateway,
+	gwRef resourceRef,
+	gls []*gatewayListener,
+	port int32,
+) error {
+	rsCfg.MetaData.ResourceType = VirtualServer
+	rsCfg.MetaData.Protocol = HTTP
+	var tlsTermination string
+	if gls[0].listener.Protocol == gatewayv1.HTTPSProtocolType {
+		rsCfg.MetaData.Protocol = HTTPS
+		tlsTermination = TLSEdge
+	}
+	var secrets []string
+	var poolPathRefs []poolPathRef
+	var vsHostname string
+	// paths of the hosts are served by the oldest route
+	claimedPaths := make(map[string]struct{})
+	for _, gl := range gls {
+		for _, secret := range gl.secrets {
+			if !slices.Contains(secrets, secret) {
+				secrets = append(secrets, secret)
+			}
+		}
+		for _, rt := range gl.attachedRoutes {
+			route := rt.rsc.(*gatewayv1.HTTPRoute)
+			for _, host := range gl.routeHosts[rt] {
+				vs := newGatewayVirtualServer(gw, host, port, tlsTermination != "")
+				for _, rule := range route.Spec.Rules {
+					var backendRefs []gatewayv1.BackendRef
+					for _, backendRef := range rule.BackendRefs {
+						backendRefs = append(backendRefs, backendRef.BackendRef)
+					}
+					pool, ok := getGatewayPool(route.Namespace, backendRefs)
+					if !ok {
+						continue
+					}
+					for _, filter := range rule.Filters {
+						if filter.URLRewrite == nil {
+							continue
+						}
+						if filter.URLRewrite.Hostname != nil {
+							pool.HostRewrite = string(*filter.URLRewrite.Hostname)
+						}
+						if filter.URLRewrite.Path != nil && filter.URLRewrite.Path.ReplacePrefixMatch != nil {
+							pool.Rewrite = *filter.URLRewrite.Path.ReplacePrefixMatch
+						}
+					}
+					paths := []string{"/"}
+					if len(rule.Matches) > 0 {
+						paths = nil
+						for _, match := range rule.Matches {
+							path := "/"
+							if match.Path != nil && match.Path.Value != nil {
+								path = *match.Path.Value
+							}
+							paths = append(paths, path)
+						}
+					}
+					for _, path := range paths {
+						if _, ok := claimedPaths[host+path]; ok {
+							continue
+						}
+						claimedPaths[host+path] = struct{}{}
+						pl := pool
+						pl.Path = path
+						vs.Spec.Pools = append(vs.Spec.Pools, pl)
+					}
+				}
+				if len(vs.Spec.Pools) == 0 {
+					continue
+				}
+				log.Debugf("Processing %v %v/%v with host %q for Gateway %v/%v port %v", rt.kind, rt.namespace,
+					rt.name, host, gw.Namespace, gw.Name, port)
+				err := ctlr.prepareRSConfigFromVirtualServerSpec(rsCfg, vs, gwRef, false, tlsTermination)
+				if err != nil {
+					return err
+				}
+				if vsHostname == "" {
+					vsHostname = host
+				}
+				poolPathRefs = append(poolPathRefs, ctlr.getVirtualServerPoolPathRefs(vs)...)
+			}
+		}
+	}
+	if tlsTermination == "" || len(rsCfg.Pools) == 0 {
+		return nil
+	}
+	// the certificates of all the listeners are bundled in a client ssl profile
+	processed := ctlr.handleTLS(rsCfg, TLSContext{
+		name:          gw.Name,
+		namespace:     gw.Namespace,
+		resourceType:  Gateway,
+		referenceType: Secret,
+		vsHostname:    vsHostname,
+		httpsPort:     port,
+		httpPort:      DEFAULT_HTTP_PORT,
+		ipAddress:     rsCfg.Virtual.VirtualAddress.BindAddr,
+		termination:   TLSEdge,
+		poolPathRefs:  poolPathRefs,
+		bigIPSSLProfiles: BigIPSSLProfiles{
+			clientSSLs: secrets,
+		},
+	})
+	if !processed {
+		return fmt.Errorf("failed to process the certificateRefs of the listeners on port %v", port)
+	}
+	return nil
+}
+
+// prepareRSConfigFromGatewayTLSListeners prepares the passthrough virtual of the TLS listeners sharing the port
+func (ctlr *Controller) prepareRSConfigFromGatewayTLSListeners(
+	rsCfg *ResourceConfig,
+	gw *gatewayv1.Gateway,
+	gwRef resourceRef,
+	gls []*gatewayListener,
+	port int32,
+) error {
+	rsCfg.MetaData.ResourceType = VirtualServer
+	rsCfg.MetaData.Protocol = HTTPS
+	var poolPathRefs []poolPathRef
+	var vsHostname string
+	claimedHosts := make(map[string]struct{})
+	for _, gl := range gls {
+		for _, rt := range gl.attachedRoutes {
+			pool, ok := getGatewayPool(rt.namespace, getGatewayRouteBackendRefs(rt))
+			if !ok {
+				continue
+			}
+			for _, host := range gl.routeHosts[rt] {
+				if _, ok := claimedHosts[host]; ok {
+					continue
+				}
+				claimedHosts[host] = struct{}{}
+				vs := newGatewayVirtualServer(gw, host, port, true)
+				vs.Spec.Pools = []cisapiv1.VSPool{pool}
+				log.Debugf("Processing %v %v/%v with host %q for Gateway %v/%v port %v", rt.kind, rt.namespace,
+					rt.name, host, gw.Namespace, gw.Name, port)
+				err := ctlr.prepareRSConfigFromVirtualServerSpec(rsCfg, vs, gwRef, true, TLSPassthrough)
+				if err != nil {
+					return err
+				}
+				if vsHostname == "" {
+					vsHostname = host
+				}
+				poolPathRefs = append(poolPathRefs, ctlr.getVirtualServerPoolPathRefs(vs)...)
+			}
+		}
+	}
+	if len(rsCfg.Pools) == 0 {
+		return nil
+	}
+	if !ctlr.handleTLS(rsCfg, TLSContext{
+		name:         gw.Name,
+		namespace:    gw.Namespace,
+		resourceType: Gateway,
+		vsHostname:   vsHostname,
+		httpsPort:    port,
+		httpPort:     DEFAULT_HTTP_PORT,
+		ipAddress:    rsCfg.Virtual.VirtualAddress.BindAddr,
+		termination:  TLSPassthrough,
+		poolPathRefs: poolPathRefs,
+	}) {
+		return fmt.Errorf("failed to process the TLS listeners on port %v", port)
+	}
+	return nil
+}
+
+// prepareRSConfigFromGatewayL4Listener prepares the virtual of the TCP or UDP listener, the oldest attached route
+// is served by the virtual
+func (ctlr *Controller) prepareRSConfigFromGatewayL4Listener(
+	rsCfg *ResourceConfig,
+	gwRef resourceRef,
+	gl *gatewayListener,
+	port int32,
+) error {
+	rt := gl.attachedRoutes[0]
+	pool, ok := getGatewayPool(rt.namespace, getGatewayRouteBackendRefs(rt))
+	if !ok {
+		return nil
+	}
+	ipProtocol := "tcp"
+	if gl.listener.Protocol == gatewayv1.UDPProtocolType {
+		ipProtocol = "udp"
+	}
+	rsCfg.MetaData.ResourceType = TransportServer
+	rsCfg.Virtual.IpProtocol = ipProtocol
+	// the service is tracked against the namespace of the TransportServer
+	ts := &cisapiv1.TransportServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rt.name,
+			Namespace: rt.namespace,
+		},
+		Spec: cisapiv1.TransportServerSpec{
+			VirtualServerAddress: rsCfg.Virtual.VirtualAddress.BindAddr,
+			VirtualServerPort:    port,
+			Mode:                 "standard",
+			Type:                 ipProtocol,
+			Pool: cisapiv1.TSPool{
+				Service:          pool.Service,
+				ServicePort:      pool.ServicePort,
+				ServiceNamespace: rt.namespace,
+			},
+		},
+	}
+	log.Debugf("Processing %v %v/%v for Gateway %v/%v port %v", rt.kind, rt.namespace, rt.name, gwRef.namespace,
+		gwRef.name, port)
+	return ctlr.prepareRSConfigFromTransportServerSpec(rsCfg, ts, gwRef)
+}
+
+// newGatewayVirtualServer returns the VirtualServer used to translate the routes of the gateway for the hostname
+func newGatewayVirtualServer(gw *gatewayv1.Gateway, host string, port int32, secure bool) *cisapiv1.VirtualServer {
+	vs := &cisapiv1.VirtualServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gw.Name,
+			Namespace: gw.Namespace,
+		},
+		Spec: cisapiv1.VirtualServerSpec{
+			Host: host,
+		},
+	}
+	if secure {
+		vs.Spec.VirtualServerHTTPSPort = port
+	} else {
+		vs.Spec.VirtualServerHTTPPort = port
+	}
+	return vs
+}
+
+// updateGatewayProgrammedStatus updates the Programmed condition of the gateway with the BIG-IP post result
+func (ctlr *Controller) updateGatewayProgrammedStatus(
+	namespace, name string,
+	status metav1.ConditionStatus,
+	reason, message string,
+) {
+	gw := ctlr.getGateway(namespace, name)
+	if gw == nil {
+		return
+	}
+	updated := ctlr.updateGatewayStatus(gw, func(gwStatus *gatewayv1.GatewayStatus, generation int64) {
+		setGatewayConditions(&gwStatus.Conditions, []metav1.Condition{
+			newGatewayCondition(string(gatewayv1.GatewayConditionProgrammed), status, reason, message),
+		}, generation)
+	})
+	if !updated {
+		return
+	}
+	if status == metav1.ConditionTrue {
+		ctlr.recordEvent(gw, v1.EventTypeNormal, DeploySucceeded, "Gateway is deployed on BIG-IP")
+	} else {
+		ctlr.recordEventf(gw, v1.EventTypeWarning, DeployFailed, "Failed to deploy the Gateway on BIG-IP: %v", message)
+	}
+}
+
+// updateGatewayStatus applies setStatus on the status of the gateway, the gateway is updated only if the status
+// is changed. Returns true if the status is updated
+func (ctlr *Controller) updateGatewayStatus(
+	gw *gatewayv1.Gateway,
+	setStatus func(status *gatewayv1.GatewayStatus, generation int64),
+) bool {
+	if ctlr.clientsets.gatewayClient == nil {
+		return false
+	}
+	updated := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		gwCopy := gw.DeepCopy()
+		setStatus(&gwCopy.Status, gwCopy.Generation)
+		if reflect.DeepEqual(gw.Status, gwCopy.Status) {
+			return nil
+		}
+		_, err := ctlr.clientsets.gatewayClient.GatewayV1().Gateways(gw.Namespace).UpdateStatus(context.TODO(),
+			gwCopy, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			if latest, getErr := ctlr.clientsets.gatewayClient.GatewayV1().Gateways(gw.Namespace).Get(context.TODO(),
+				gw.Name, metav1.GetOptions{}); getErr == nil {
+				gw = latest
+			}
+		}
+		updated = err == nil
+		return err
+	})
+	if err != nil {
+		log.Errorf("Error while updating Gateway %v/%v status: %v", gw.Namespace, gw.Name, err)
+	}
+	return updated
+}
+
+// updateGatewayClassStatus marks the gateway class as accepted by CIS
+func (ctlr *Controller) updateGatewayClassStatus(gc *gatewayv1.GatewayClass) {
+	if ctlr.clientsets.gatewayClient == nil {
+		return
+	}
+	gcCopy := gc.DeepCopy()
+	setGatewayConditions(&gcCopy.Status.Conditions, []metav1.Condition{
+		newGatewayCondition(string(gatewayv1.GatewayClassConditionStatusAccepted), metav1.ConditionTrue,
+			string(gatewayv1.GatewayClassReasonAccepted), "GatewayClass is accepted"),
+	}, gcCopy.Generation)
+	if reflect.DeepEqual(gc.Status, gcCopy.Status) {
+		return
+	}
+	_, err := ctlr.clientsets.gatewayClient.GatewayV1().GatewayClasses().UpdateStatus(context.TODO(), gcCopy,
+		metav1.UpdateOptions{})
+	if err != nil {
+		log.Errorf("Error while updating GatewayClass %v status: %v", gc.Name, err)
+	}
+}
+
+// updateGatewayRouteStatus replaces the parent statuses of the route for the gateway with the given statuses,
+// statuses of this controller for the parentRefs which are removed from the route are cleared as well
+func (ctlr *Controller) updateGatewayRouteStatus(
+	rt *gatewayRoute,
+	gw *gatewayv1.Gateway,
+	parents []gatewayv1.RouteParentStatus,
+) {
+	if ctlr.clientsets.gatewayClient == nil {
+		return
+	}
+	obj := rt.rsc
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		rtCopy := obj.(runtime.Object).DeepCopyObject()
+		status := getGatewayRouteStatus(rtCopy)
+		_, _, parentRefs := getGatewayRouteParentRefs(rtCopy)
+		var routeParents []gatewayv1.RouteParentStatus
+		for _, parent := range status.Parents {
+			if string(parent.ControllerName) != ctlr.gatewayControllerName {
+				routeParents = append(routeParents, parent)
+				continue
+			}
+			if gw != nil && isGatewayParentRef(parent.ParentRef, rt.namespace, gw) {
+				continue
+			}
+			for _, ref := range parentRefs {
+				if reflect.DeepEqual(ref, parent.ParentRef) {
+					routeParents = append(routeParents, parent)
+					break
+				}
+			}
+		}
+		for _, parent := range parents {
+			newParent := parent
+			newParent.Conditions = nil
+			for _, old := range status.Parents {
+				if string(old.ControllerName) == ctlr.gatewayControllerName && reflect.DeepEqual(old.ParentRef, parent.ParentRef) {
+					newParent.Conditions = append(newParent.Conditions, old.Conditions...)
+				}
+			}
+			setGatewayConditions(&newParent.Conditions, parent.Conditions, getGatewayRouteGeneration(rtCopy))
+			routeParents = append(routeParents, newParent)
+		}
+		if reflect.DeepEqual(status.Parents, routeParents) {
+			return nil
+		}
+		status.Parents = routeParents
+		err := ctlr.updateGatewayRouteObjectStatus(rtCopy)
+		if apierrors.IsConflict(err) {
+			if latest, getErr := ctlr.getGatewayRouteObject(rt.kind, rt.namespace, rt.name); getErr == nil {
+				obj = latest
+			}
+		}
+		return err
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Errorf("Error while updating %v %v/%v status: %v", rt.kind, rt.namespace, rt.name, err)
+	}
+}
+
+func (ctlr *Controller) updateGatewayRouteObjectStatus(obj runtime.Object) error {
+	var err error
+	client := ctlr.clientsets.gatewayClient
+	switch rt := obj.(type) {
+	case *gatewayv1.HTTPRoute:
+		_, err = client.GatewayV1().HTTPRoutes(rt.Namespace).UpdateStatus(context.TODO(), rt, metav1.UpdateOptions{})
+	case *gatewayv1alpha2.TLSRoute:
+		_, err = client.GatewayV1alpha2().TLSRoutes(rt.Namespace).UpdateStatus(context.TODO(), rt, metav1.UpdateOptions{})
+	case *gatewayv1alpha2.TCPRoute:
+		_, err = client.GatewayV1alpha2().TCPRoutes(rt.Namespace).UpdateStatus(context.TODO(), rt, metav1.UpdateOptions{})
+	case *gatewayv1alpha2.UDPRoute:
+		_, err = client.GatewayV1alpha2().UDPRoutes(rt.Namespace).UpdateStatus(context.TODO(), rt, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+func (ctlr *Controller) getGatewayRouteObject(kind, namespace, name string) (interface{}, error) {
+	client := ctlr.clientsets.gatewayClient
+	switch kind {
+	case HTTPRoute:
+		return client.GatewayV1().HTTPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	case TLSRoute:
+		return client.GatewayV1alpha2().TLSRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	case TCPRoute:
+		return client.GatewayV1alpha2().TCPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	case UDPRoute:
+		return client.GatewayV1alpha2().UDPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	}
+	return nil, fmt.Errorf("unknown route kind %v", kind)
+}
+
+func getGatewayRouteStatus(obj interface{}) *gatewayv1.RouteStatus {
+	switch rt := obj.(type) {
+	case *gatewayv1.HTTPRoute:
+		return &rt.Status.RouteStatus
+	case *gatewayv1alpha2.TLSRoute:
+		return &rt.Status.RouteStatus
+	case *gatewayv1alpha2.TCPRoute:
+		return &rt.Status.RouteStatus
+	case *gatewayv1alpha2.UDPRoute:
+		return &rt.Status.RouteStatus
+	}
+	return &gatewayv1.RouteStatus{}
+}
+
+func getGatewayRouteGeneration(obj interface{}) int64 {
+	if accessor, err := apimeta.Accessor(obj); err == nil {
+		return accessor.GetGeneration()
+	}
+	return 0
+}
+
+func newGatewayCondition(conditionType string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// setGatewayConditions sets the conditions, transition time is retained if the condition status is unchanged
+func setGatewayConditions(conditions *[]metav1.Condition, newConditions []metav1.Condition, generation int64) {
+	for _, condition := range newConditions {
+		condition.ObservedGeneration = generation
+		if existing := apimeta.FindStatusCondition(*conditions, condition.Type); existing == nil ||
+			existing.Status != condition.Status {
+			condition.LastTransitionTime = metav1.NewTime(time.Now().Truncate(time.Second))
+		}
+		apimeta.SetStatusCondition(conditions, condition)
+	}
+}
+
+// isGatewayKindParentRef checks if the parentRef refers a Gateway
+func isGatewayKindParentRef(ref gatewayv1.ParentReference) bool {
+	return (ref.Group == nil || *ref.Group == gatewayv1.GroupName) && (ref.Kind == nil || *ref.Kind == Gateway)
+}
+
+// isGatewayParentRef checks if the parentRef of the route refers the gateway
+func isGatewayParentRef(ref gatewayv1.ParentReference, routeNamespace string, gw *gatewayv1.Gateway) bool {
+	if !isGatewayKindParentRef(ref) {
+		return false
+	}
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	return namespace == gw.Namespace && string(ref.Name) == gw.Name
+}
+
+func gatewayGroup() *gatewayv1.Group {
+	group := gatewayv1.Group(gatewayv1.GroupName)
+	return &group
+}
+
+// newGatewayRoute returns the route details used for attaching the route to the gateways
+func newGatewayRoute(kind string, obj interface{}) *gatewayRoute {
+	accessor, err := apimeta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+	_, _, parentRefs := getGatewayRouteParentRefs(obj)
+	rt := &gatewayRoute{
+		kind:       kind,
+		namespace:  accessor.GetNamespace(),
+		name:       accessor.GetName(),
+		generation: accessor.GetGeneration(),
+		created:    accessor.GetCreationTimestamp(),
+		parentRefs: parentRefs,
+		rsc:        obj,
+	}
+	switch route := obj.(type) {
+	case *gatewayv1.HTTPRoute:
+		rt.hostnames = route.Spec.Hostnames
+	case *gatewayv1alpha2.TLSRoute:
+		rt.hostnames = route.Spec.Hostnames
+	}
+	return rt
+}
+
+// getGatewayRoutes returns the routes referring the gateway, ordered by the creation time
+func (ctlr *Controller) getGatewayRoutes(gw *gatewayv1.Gateway) []*gatewayRoute {
+	var routes []*gatewayRoute
+	for _, gwInf := range ctlr.gwInformers {
+		for _, rsc := range []struct {
+			kind string
+			objs []interface{}
+		}{
+			{HTTPRoute, gwInf.httpRouteInformer.GetIndexer().List()},
+			{TLSRoute, gwInf.tlsRouteInformer.GetIndexer().List()},
+			{TCPRoute, gwInf.tcpRouteInformer.GetIndexer().List()},
+			{UDPRoute, gwInf.udpRouteInformer.GetIndexer().List()},
+		} {
+			for _, obj := range rsc.objs {
+				rt := newGatewayRoute(rsc.kind, obj)
+				if rt == nil {
+					continue
+				}
+				for _, ref := range rt.parentRefs {
+					if isGatewayParentRef(ref, rt.namespace, gw) {
+						routes = append(routes, rt)
+						break
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if !routes[i].created.Equal(&routes[j].created) {
+			return routes[i].created.Before(&routes[j].created)
+		}
+		return routes[i].namespace+"/"+routes[i].name < routes[j].namespace+"/"+routes[j].name
+	})
+	return routes
+}
+
+// getGatewayRoute returns the route from the informer cache
+func (ctlr *Controller) getGatewayRoute(kind, namespace, name string) *gatewayRoute {
+	gwInf, ok := ctlr.getNamespacedGatewayInformer(namespace)
+	if !ok {
+		return nil
+	}
+	var obj interface{}
+	var found bool
+	var err error
+	key := namespace + "/" + name
+	switch kind {
+	case HTTPRoute:
+		obj, found, err = gwInf.httpRouteInformer.GetIndexer().GetByKey(key)
+	case TLSRoute:
+		obj, found, err = gwInf.tlsRouteInformer.GetIndexer().GetByKey(key)
+	case TCPRoute:
+		obj, found, err = gwInf.tcpRouteInformer.GetIndexer().GetByKey(key)
+	case UDPRoute:
+		obj, found, err = gwInf.udpRouteInformer.GetIndexer().GetByKey(key)
+	}
+	if err != nil || !found {
+		return nil
+	}
+	return newGatewayRoute(kind, obj)
+}
+
+// getGatewayClass returns the gateway class from the informer cache
+func (ctlr *Controller) getGatewayClass(name string) *gatewayv1.GatewayClass {
+	if ctlr.gcInformer == nil {
+		return nil
+	}
+	obj, found, err := ctlr.gcInformer.gcInformer.GetIndexer().GetByKey(name)
+	if err != nil || !found {
+		return nil
+	}
+	return obj.(*gatewayv1.GatewayClass)
+}
+
+// getGateway returns the gateway from the informer cache
+func (ctlr *Controller) getGateway(namespace, name string) *gatewayv1.Gateway {
+	gwInf, ok := ctlr.getNamespacedGatewayInformer(namespace)
+	if !ok {
+		return nil
+	}
+	obj, found, err := gwInf.gatewayInformer.GetIndexer().GetByKey(namespace + "/" + name)
+	if err != nil || !found {
+		return nil
+	}
+	return obj.(*gatewayv1.Gateway)
+}
+
+// getAllGateways returns the gateways of the namespace, gateways of all the namespaces if namespace is empty
+func (ctlr *Controller) getAllGateways(namespace string) []*gatewayv1.Gateway {
+	var gateways []*gatewayv1.Gateway
+	for _, gwInf := range ctlr.gwInformers {
+		var objs []interface{}
+		if namespace == "" {
+			objs = gwInf.gatewayInformer.GetIndexer().List()
+		} else {
+			var err error
+			objs, err = gwInf.gatewayInformer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+			if err != nil {
+				log.Errorf("Unable to list Gateways in namespace %v: %v", namespace, err)
+				continue
+			}
+		}
+		for _, obj := range objs {
+			gateways = append(gateways, obj.(*gatewayv1.Gateway))
+		}
+	}
+	return gateways
+}
+
+// getGatewaysForSecret returns the gateways whose listeners refer the secret
+func (ctlr *Controller) getGatewaysForSecret(secret *v1.Secret) []*gatewayv1.Gateway {
+	var gateways []*gatewayv1.Gateway
+	for _, gw := range ctlr.getAllGateways(secret.Namespace) {
+		if gatewayRefersSecret(gw, secret) {
+			gateways = append(gateways, gw)
+		}
+	}
+	return gateways
+}
+
+func gatewayRefersSecret(gw *gatewayv1.Gateway, secret *v1.Secret) bool {
+	for _, l := range gw.Spec.Listeners {
+		if l.TLS == nil {
+			continue
+		}
+		for _, ref := range l.TLS.CertificateRefs {
+			namespace := gw.Namespace
+			if ref.Namespace != nil {
+				namespace = string(*ref.Namespace)
+			}
+			if namespace == secret.Namespace && string(ref.Name) == secret.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (ctlr *Controller) getGatewaySecret(namespace, name string) *v1.Secret {
+	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
+	if !ok || comInf.secretsInformer == nil {
+		return nil
+	}
+	obj, found, err := comInf.secretsInformer.GetIndexer().GetByKey(namespace + "/" + name)
+	if err != nil || !found {
+		return nil
+	}
+	return obj.(*v1.Secret)
+}
@@ -0,0 +1,286 @@
+package controller
+
+import (
+	"context"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+)
+
+var _ = Describe("Gateway API", func() {
+	var mockCtlr *mockController
+	var gc *gatewayv1.GatewayClass
+	var gw *gatewayv1.Gateway
+	var httpRoute *gatewayv1.HTTPRoute
+	var tcpRoute *gatewayv1alpha2.TCPRoute
+	var secret *v1.Secret
+	var partition string
+	namespace := "default"
+
+	newListener := func(name string, port int32, protocol gatewayv1.ProtocolType) gatewayv1.Listener {
+		return gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(name),
+			Port:     gatewayv1.PortNumber(port),
+			Protocol: protocol,
+		}
+	}
+	newBackendRef := func(name string, port int32) gatewayv1.BackendRef {
+		portNumber := gatewayv1.PortNumber(port)
+		return gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(name),
+				Port: &portNumber,
+			},
+		}
+	}
+	gatewayRef := gatewayv1.ParentReference{Name: "gw1"}
+
+	// setup adds the objects to the informer caches and the fake clientset
+	setup := func() {
+		// the tracker guesses "gatewaies" as the resource of Gateway, so add the objects with their resources
+		gwClient := gatewayfake.NewSimpleClientset(gc, httpRoute, tcpRoute)
+		_ = gwClient.Tracker().Create(gatewayv1.SchemeGroupVersion.WithResource("gateways"), gw, namespace)
+		mockCtlr.clientsets.gatewayClient = gwClient
+		mockCtlr.gcInformer = mockCtlr.newGatewayClassInformer()
+		_ = mockCtlr.gcInformer.gcInformer.GetIndexer().Add(gc)
+		_ = mockCtlr.addNamespacedInformers(namespace, false)
+		gwInf := mockCtlr.gwInformers[namespace]
+		_ = gwInf.gatewayInformer.GetIndexer().Add(gw)
+		_ = gwInf.httpRouteInformer.GetIndexer().Add(httpRoute)
+		_ = gwInf.tcpRouteInformer.GetIndexer().Add(tcpRoute)
+		comInf := mockCtlr.comInformers[namespace]
+		_ = comInf.svcInformer.GetIndexer().Add(test.NewService("svc1", "1", namespace, v1.ServiceTypeClusterIP,
+			[]v1.ServicePort{{Port: 80, Name: "http"}}))
+		_ = comInf.svcInformer.GetIndexer().Add(test.NewService("svc2", "1", namespace, v1.ServiceTypeClusterIP,
+			[]v1.ServicePort{{Port: 80, Name: "http"}}))
+		_ = comInf.secretsInformer.GetIndexer().Add(secret)
+	}
+	getGatewayStatus := func() gatewayv1.GatewayStatus {
+		latest, err := mockCtlr.clientsets.gatewayClient.GatewayV1().Gateways(namespace).Get(context.TODO(), gw.Name,
+			metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		return latest.Status
+	}
+	getHTTPRouteStatus := func() gatewayv1.RouteStatus {
+		latest, err := mockCtlr.clientsets.gatewayClient.GatewayV1().HTTPRoutes(namespace).Get(context.TODO(),
+			httpRoute.Name, metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		return latest.Status.RouteStatus
+	}
+	getVirtuals := func() ResourceMap {
+		return mockCtlr.resources.getPartitionResourceMap(partition, mockCtlr.getBIGIPConfig(BigIPLabel))
+	}
+
+	BeforeEach(func() {
+		mockCtlr = newMockController()
+		partition = "test"
+		bigipConfig := cisapiv1.BigIpConfig{BigIpLabel: "bigip1", DefaultPartition: partition, BigIpAddress: "10.8.3.11"}
+		mockCtlr.bigIpMap[bigipConfig] = BigIpResourceConfig{ltmConfig: make(LTMConfig), gtmConfig: make(GTMConfig)}
+		mockCtlr.managedResources.ManageGatewayAPI = true
+		mockCtlr.managedResources.ManageVirtualServer = false
+		mockCtlr.managedResources.ManageTLSProfile = false
+		mockCtlr.gatewayControllerName = DefaultGatewayControllerName
+		mockCtlr.namespaces = map[string]bool{namespace: true}
+		mockCtlr.clientsets.kubeClient = k8sfake.NewSimpleClientset()
+		mockCtlr.comInformers = make(map[string]*CommonInformer)
+		mockCtlr.crInformers = make(map[string]*CRInformer)
+		mockCtlr.gwInformers = make(map[string]*GWInformer)
+		mockCtlr.resources = NewResourceStore()
+		mockCtlr.multiClusterResources = newMultiClusterResourceStore()
+
+		gc = &gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "f5"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultGatewayControllerName},
+		}
+		gw = &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gw1", Namespace: namespace, Generation: 1},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "f5",
+				Addresses:        []gatewayv1.GatewayAddress{{Value: "10.1.1.1"}},
+				Listeners: []gatewayv1.Listener{
+					newListener("http", 80, gatewayv1.HTTPProtocolType),
+					newListener("tcp", 8080, gatewayv1.TCPProtocolType),
+				},
+			},
+		}
+		pathPrefix := gatewayv1.PathMatchPathPrefix
+		httpRoute = &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "route1", Namespace: namespace, Generation: 1},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{gatewayRef}},
+				Hostnames:       []gatewayv1.Hostname{"foo.example.com"},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Matches: []gatewayv1.HTTPRouteMatch{{
+						Path: &gatewayv1.HTTPPathMatch{Type: &pathPrefix, Value: stringPtr("/api")},
+					}},
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{BackendRef: newBackendRef("svc1", 80)},
+						{BackendRef: newBackendRef("svc2", 80)},
+					},
+				}},
+			},
+		}
+		tcpRoute = &gatewayv1alpha2.TCPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "tcp1", Namespace: namespace},
+			Spec: gatewayv1alpha2.TCPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{gatewayRef}},
+				Rules:           []gatewayv1alpha2.TCPRouteRule{{BackendRefs: []gatewayv1.BackendRef{newBackendRef("svc1", 80)}}},
+			},
+		}
+		secret = test.NewSecret("tls-secret", namespace, "cert", "key")
+	})
+
+	It("Gateway with HTTP and TCP listeners", func() {
+		setup()
+		Expect(mockCtlr.processGateway(gw, false)).To(BeNil())
+
+		virtuals := getVirtuals()
+		Expect(virtuals).To(HaveLen(2))
+		httpVirtual := virtuals["default_gw1_80"]
+		Expect(httpVirtual).NotTo(BeNil())
+		Expect(httpVirtual.MetaData.ResourceType).To(Equal(VirtualServer))
+		Expect(httpVirtual.MetaData.baseResources).To(Equal(map[string]string{"default/gw1": Gateway}))
+		Expect(httpVirtual.Virtual.Destination).To(ContainSubstring("10.1.1.1"))
+		Expect(httpVirtual.Pools).To(HaveLen(2), "pools for the weighted backends")
+		Expect(httpVirtual.MetaData.hosts).To(Equal([]string{"foo.example.com"}))
+		tcpVirtual := virtuals["default_gw1_8080"]
+		Expect(tcpVirtual).NotTo(BeNil())
+		Expect(tcpVirtual.MetaData.ResourceType).To(Equal(TransportServer))
+		Expect(tcpVirtual.Virtual.IpProtocol).To(Equal("tcp"))
+		Expect(tcpVirtual.Pools).To(HaveLen(1))
+
+		status := getGatewayStatus()
+		Expect(status.Addresses).To(HaveLen(1))
+		Expect(status.Addresses[0].Value).To(Equal("10.1.1.1"))
+		Expect(apimeta.IsStatusConditionTrue(status.Conditions, string(gatewayv1.GatewayConditionAccepted))).To(BeTrue())
+		programmed := apimeta.FindStatusCondition(status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+		Expect(programmed.Reason).To(Equal(string(gatewayv1.GatewayReasonPending)))
+		Expect(status.Listeners).To(HaveLen(2))
+		Expect(status.Listeners[0].AttachedRoutes).To(BeEquivalentTo(1))
+		Expect(status.Listeners[1].AttachedRoutes).To(BeEquivalentTo(1))
+
+		routeStatus := getHTTPRouteStatus()
+		Expect(routeStatus.Parents).To(HaveLen(1))
+		Expect(string(routeStatus.Parents[0].ControllerName)).To(Equal(DefaultGatewayControllerName))
+		Expect(apimeta.IsStatusConditionTrue(routeStatus.Parents[0].Conditions,
+			string(gatewayv1.RouteConditionAccepted))).To(BeTrue())
+		Expect(apimeta.IsStatusConditionTrue(routeStatus.Parents[0].Conditions,
+			string(gatewayv1.RouteConditionResolvedRefs))).To(BeTrue())
+
+		// BIG-IP post result updates the Programmed condition
+		_ = mockCtlr.gwInformers[namespace].gatewayInformer.GetIndexer().Update(&gatewayv1.Gateway{
+			ObjectMeta: gw.ObjectMeta, Spec: gw.Spec, Status: getGatewayStatus()})
+		mockCtlr.updateGatewayProgrammedStatus(namespace, gw.Name, metav1.ConditionTrue,
+			string(gatewayv1.GatewayReasonProgrammed), "Gateway is programmed on BIG-IP")
+		status = getGatewayStatus()
+		Expect(apimeta.IsStatusConditionTrue(status.Conditions, string(gatewayv1.GatewayConditionProgrammed))).To(BeTrue())
+		Expect(status.Listeners).To(HaveLen(2))
+
+		// virtuals are removed on delete and the route is detached
+		latestRoute := httpRoute.DeepCopy()
+		latestRoute.Status.RouteStatus = getHTTPRouteStatus()
+		_ = mockCtlr.gwInformers[namespace].httpRouteInformer.GetIndexer().Update(latestRoute)
+		Expect(mockCtlr.processGateway(gw, true)).To(BeNil())
+		Expect(getVirtuals()).To(BeEmpty())
+		Expect(getHTTPRouteStatus().Parents).To(BeEmpty())
+	})
+
+	It("Gateway with HTTPS listener", func() {
+		terminate := gatewayv1.TLSModeTerminate
+		httpsListener := newListener("https", 443, gatewayv1.HTTPSProtocolType)
+		httpsListener.TLS = &gatewayv1.GatewayTLSConfig{
+			Mode:            &terminate,
+			CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "tls-secret"}},
+		}
+		gw.Spec.Listeners = []gatewayv1.Listener{httpsListener}
+		setup()
+		Expect(mockCtlr.processGateway(gw, false)).To(BeNil())
+		httpsVirtual := getVirtuals()["default_gw1_443"]
+		Expect(httpsVirtual).NotTo(BeNil())
+		Expect(httpsVirtual.MetaData.Protocol).To(Equal(HTTPS))
+		Expect(httpsVirtual.customProfiles).To(HaveKey(SecretKey{Name: "tls-secret", ResourceName: "default_gw1_443"}))
+		Expect(mockCtlr.getGatewaysForSecret(secret)).To(HaveLen(1))
+
+		// missing secret invalidates the listener
+		gw.Spec.Listeners[0].TLS.CertificateRefs[0].Name = "unknown"
+		Expect(mockCtlr.processGateway(gw, false)).To(BeNil())
+		Expect(getVirtuals()).To(BeEmpty())
+		listener := getGatewayStatus().Listeners[0]
+		resolvedRefs := apimeta.FindStatusCondition(listener.Conditions, string(gatewayv1.ListenerConditionResolvedRefs))
+		Expect(resolvedRefs.Reason).To(Equal(string(gatewayv1.ListenerReasonInvalidCertificateRef)))
+		Expect(apimeta.IsStatusConditionFalse(getGatewayStatus().Conditions,
+			string(gatewayv1.GatewayConditionAccepted))).To(BeTrue())
+	})
+
+	It("Routes not matching the listeners", func() {
+		listenerHost := gatewayv1.Hostname("*.test.com")
+		gw.Spec.Listeners[0].Hostname = &listenerHost
+		setup()
+		Expect(mockCtlr.processGateway(gw, false)).To(BeNil())
+		Expect(getVirtuals()).NotTo(HaveKey("default_gw1_80"))
+		accepted := apimeta.FindStatusCondition(getHTTPRouteStatus().Parents[0].Conditions,
+			string(gatewayv1.RouteConditionAccepted))
+		Expect(accepted.Reason).To(Equal(string(gatewayv1.RouteReasonNoMatchingListenerHostname)))
+
+		// route attached to a missing listener
+		sectionName := gatewayv1.SectionName("unknown")
+		httpRoute.Spec.ParentRefs[0].SectionName = &sectionName
+		_ = mockCtlr.gwInformers[namespace].httpRouteInformer.GetIndexer().Update(httpRoute)
+		Expect(mockCtlr.processGatewayRoute(HTTPRoute, httpRoute, false)).To(BeNil())
+		routeStatus := getHTTPRouteStatus()
+		Expect(routeStatus.Parents).To(HaveLen(1))
+		Expect(*routeStatus.Parents[0].ParentRef.SectionName).To(Equal(sectionName))
+		accepted = apimeta.FindStatusCondition(routeStatus.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+		Expect(accepted.Reason).To(Equal(string(gatewayv1.RouteReasonNoMatchingParent)))
+	})
+
+	It("Gateways of other controllers are ignored", func() {
+		gc.Spec.ControllerName = "example.com/gateway-controller"
+		setup()
+		Expect(mockCtlr.processGateway(gw, false)).To(BeNil())
+		Expect(getVirtuals()).To(BeEmpty())
+		Expect(getGatewayStatus().Conditions).To(BeEmpty())
+		Expect(getHTTPRouteStatus().Parents).To(BeEmpty())
+	})
+
+	It("Listener validation", func() {
+		passthrough := gatewayv1.TLSModePassthrough
+		tlsListener := newListener("tls", 80, gatewayv1.TLSProtocolType)
+		tlsListener.TLS = &gatewayv1.GatewayTLSConfig{Mode: &passthrough}
+		gw.Spec.Listeners = append(gw.Spec.Listeners, tlsListener, newListener("sctp", 9000, "SCTP"))
+		listeners := mockCtlr.validateGatewayListeners(gw)
+		Expect(listeners).To(HaveLen(4))
+		Expect(listeners[0].valid).To(BeFalse(), "HTTP listener conflicts with TLS listener")
+		Expect(apimeta.IsStatusConditionTrue(listeners[0].conditions,
+			string(gatewayv1.ListenerConditionConflicted))).To(BeTrue())
+		Expect(listeners[1].valid).To(BeTrue())
+		Expect(listeners[1].supportedKinds[0].Kind).To(BeEquivalentTo(TCPRoute))
+		Expect(listeners[2].valid).To(BeFalse())
+		accepted := apimeta.FindStatusCondition(listeners[3].conditions, string(gatewayv1.ListenerConditionAccepted))
+		Expect(accepted.Reason).To(Equal(string(gatewayv1.ListenerReasonUnsupportedProtocol)))
+	})
+
+	It("Hostname intersection", func() {
+		listenerHost := gatewayv1.Hostname("*.example.com")
+		Expect(intersectGatewayHostnames(nil, nil, false)).To(Equal([]string{""}))
+		Expect(intersectGatewayHostnames(nil, nil, true)).To(BeEmpty())
+		Expect(intersectGatewayHostnames(&listenerHost, nil, false)).To(Equal([]string{"*.example.com"}))
+		Expect(intersectGatewayHostnames(&listenerHost, []gatewayv1.Hostname{"a.b.example.com", "example.com",
+			"foo.com"}, false)).To(Equal([]string{"a.b.example.com"}))
+		Expect(intersectGatewayHostnames(&listenerHost, []gatewayv1.Hostname{"*.com"}, false)).To(
+			Equal([]string{"*.example.com"}))
+	})
+})
+
+func stringPtr(s string) *string {
+	return &s
+}
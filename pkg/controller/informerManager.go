@@ -21,6 +21,21 @@ func (ctlr *Controller) setupInformers() error {
 	nodeInf := ctlr.getNodeInformer("")
 	ctlr.multiClusterNodeInformers[""] = &nodeInf
 	ctlr.addNodeEventUpdateHandler(&nodeInf)
+	if ctlr.managedResources.ManageGatewayAPI {
+		// GatewayClasses are cluster scoped
+		ctlr.gcInformer = ctlr.newGatewayClassInformer()
+		ctlr.addGatewayClassEventHandlers(ctlr.gcInformer)
+	}
+	if ctlr.StaticRoutingMode && ctlr.PoolMemberType != NodePort && ctlr.OrchestrationCNI == CALICO {
+		// Calico IPAM blocks are used as the pod subnets of the nodes
+		ctlr.calicoInformer = ctlr.newCalicoInformer()
+		ctlr.addCalicoEventHandlers(ctlr.calicoInformer)
+	}
+	if ctlr.managedResources.ManageIngress {
+		// IngressClasses are cluster scoped
+		ctlr.icInformer = ctlr.newIngressClassInformer()
+		ctlr.addIngressClassEventHandlers(ctlr.icInformer)
+	}
 	return nil
 }
 
@@ -47,6 +62,9 @@ func (ctlr *Controller) initController() {
 	// update the agent params
 	ctlr.PostParams.AS3Config = configCR.Spec.AS3Config
 	ctlr.PostParams.tokenManager = ctlr.CMTokenManager
+	// the declaration history is persisted in the namespace of CIS config CR
+	ctlr.PostParams.kubeClient = ctlr.clientsets.kubeClient
+	ctlr.PostParams.historyNamespace = configCR.Namespace
 	if ctlr.managedResources.ManageRoutes {
 		// initialize the processed host-path map
 		var processedHostPath ProcessedHostPath
@@ -59,6 +77,7 @@ func (ctlr *Controller) initController() {
 	ctlr.multiClusterNodeInformers = make(map[string]*NodeInformer)
 	ctlr.nrInformers = make(map[string]*NRInformer)
 	ctlr.crInformers = make(map[string]*CRInformer)
+	ctlr.gwInformers = make(map[string]*GWInformer)
 	ctlr.nsInformers = make(map[string]*NSInformer)
 	ctlr.namespaces = make(map[string]bool)
 	if ctlr.resourceSelectorConfig.NamespaceLabel == "" {
@@ -90,11 +109,18 @@ func (ctlr *Controller) startInformers() {
 		nodeInf.start()
 	}
 
+	if ctlr.calicoInformer != nil {
+		ctlr.calicoInformer.start()
+	}
+
 	// start comInformers for all modes
 	for _, inf := range ctlr.comInformers {
 		inf.start()
 	}
-	if ctlr.managedResources.ManageRoutes { // nrInformers only with openShiftMode
+	if ctlr.managedResources.ManageIngress && ctlr.icInformer != nil {
+		ctlr.icInformer.start()
+	}
+	if ctlr.managedResources.ManageRoutes || ctlr.managedResources.ManageIngress { // nrInformers only with openShiftMode or ingresses
 		for _, inf := range ctlr.nrInformers {
 			inf.start()
 		}
@@ -104,20 +130,39 @@ func (ctlr *Controller) startInformers() {
 			inf.start()
 		}
 	}
+	if ctlr.managedResources.ManageGatewayAPI { // start gateway API informers only when enabled
+		if ctlr.gcInformer != nil {
+			ctlr.gcInformer.start()
+		}
+		for _, inf := range ctlr.gwInformers {
+			inf.start()
+		}
+	}
 }
 
 // stop the informers for controller
 func (ctlr *Controller) stopInformers() {
-	if ctlr.managedResources.ManageRoutes { // stop native resource informers
+	if ctlr.managedResources.ManageRoutes || ctlr.managedResources.ManageIngress { // stop native resource informers
 		for _, inf := range ctlr.nrInformers {
 			inf.stop()
 		}
 	}
+	if ctlr.managedResources.ManageIngress && ctlr.icInformer != nil {
+		ctlr.icInformer.stop()
+	}
 	if ctlr.managedResources.ManageCustomResources { // stop custom resource informers
 		for _, inf := range ctlr.crInformers {
 			inf.stop()
 		}
 	}
+	if ctlr.managedResources.ManageGatewayAPI { // stop gateway API informers
+		if ctlr.gcInformer != nil {
+			ctlr.gcInformer.stop()
+		}
+		for _, inf := range ctlr.gwInformers {
+			inf.stop()
+		}
+	}
 
 	// stop common informers & namespace informers in all modes
 	for ns, inf := range ctlr.comInformers {
@@ -130,6 +175,10 @@ func (ctlr *Controller) stopInformers() {
 	for _, nodeInf := range ctlr.multiClusterNodeInformers {
 		nodeInf.stop()
 	}
+	if ctlr.calicoInformer != nil {
+		ctlr.calicoInformer.stop()
+		ctlr.calicoInformer = nil
+	}
 
 	// stop multi cluster informers
 	for _, poolInformers := range ctlr.multiClusterPoolInformers {
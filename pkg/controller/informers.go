@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"reflect"
 	"time"
@@ -33,8 +34,12 @@ import (
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	routeapi "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	discoveryinfv1 "k8s.io/client-go/informers/discovery/v1"
+	netinfv1 "k8s.io/client-go/informers/networking/v1"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -150,6 +155,11 @@ func (nrInfr *NRInformer) start() {
 		go nrInfr.routeInformer.Run(nrInfr.stopCh)
 		cacheSyncs = append(cacheSyncs, nrInfr.routeInformer.HasSynced)
 	}
+	if nrInfr.ingressInformer != nil {
+		log.Debugf("Starting ingress informer for namespace %v", nrInfr.namespace)
+		go nrInfr.ingressInformer.Run(nrInfr.stopCh)
+		cacheSyncs = append(cacheSyncs, nrInfr.ingressInformer.HasSynced)
+	}
 	cache.WaitForNamedCacheSync(
 		"F5 CIS Ingress Controller",
 		nrInfr.stopCh,
@@ -158,10 +168,25 @@ func (nrInfr *NRInformer) start() {
 }
 
 func (nrInfr *NRInformer) stop() {
-	log.Debugf("Stopping route informer for namespace %v", nrInfr.namespace)
+	log.Debugf("Stopping route and ingress informers for namespace %v", nrInfr.namespace)
 	close(nrInfr.stopCh)
 }
 
+func (icInfr *IngressClassInformer) start() {
+	log.Debugf("Starting ingressClass informer")
+	go icInfr.icInformer.Run(icInfr.stopCh)
+	cache.WaitForNamedCacheSync(
+		"F5 CIS Ingress Controller",
+		icInfr.stopCh,
+		icInfr.icInformer.HasSynced,
+	)
+}
+
+func (icInfr *IngressClassInformer) stop() {
+	log.Debugf("Stopping ingressClass informer")
+	close(icInfr.stopCh)
+}
+
 func (comInfr *CommonInformer) start() {
 	var cacheSyncs []cache.InformerSynced
 	if comInfr.svcInformer != nil {
@@ -302,7 +327,8 @@ func (ctlr *Controller) addNamespacedInformers(
 		}
 	}
 
-	if ctlr.managedResources.ManageRoutes { // Create native resource informers in openshift mode only
+	// Create native resource informers in openshift mode or when ingresses are managed
+	if ctlr.managedResources.ManageRoutes || ctlr.managedResources.ManageIngress {
 		if _, found := ctlr.nrInformers[namespace]; !found {
 			nrInf := ctlr.newNamespacedNativeResourceInformer(namespace)
 			ctlr.addNativeResourceEventHandlers(nrInf)
@@ -323,6 +349,16 @@ func (ctlr *Controller) addNamespacedInformers(
 			}
 		}
 	}
+	if ctlr.managedResources.ManageGatewayAPI { // create gateway API informers only when enabled
+		if _, found := ctlr.gwInformers[namespace]; !found {
+			gwInf := ctlr.newNamespacedGatewayInformer(namespace)
+			ctlr.addGatewayEventHandlers(gwInf)
+			ctlr.gwInformers[namespace] = gwInf
+			if startInformer {
+				gwInf.start()
+			}
+		}
+	}
 	return nil
 }
 
@@ -410,10 +446,30 @@ func (ctlr *Controller) newNamespacedNativeResourceInformer(
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 		)
 	}
+	if ctlr.managedResources.ManageIngress {
+		nrInformer.ingressInformer = netinfv1.NewIngressInformer(
+			ctlr.clientsets.kubeClient,
+			namespace,
+			resyncPeriod,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		)
+	}
 
 	return nrInformer
 }
 
+func (ctlr *Controller) newIngressClassInformer() *IngressClassInformer {
+	log.Debugf("Creating ingressClass informer")
+	return &IngressClassInformer{
+		stopCh: make(chan struct{}),
+		icInformer: netinfv1.NewIngressClassInformer(
+			ctlr.clientsets.kubeClient,
+			0*time.Second,
+			cache.Indexers{},
+		),
+	}
+}
+
 func (ctlr *Controller) getNodeInformer(clusterName string) NodeInformer {
 	resyncPeriod := 0 * time.Second
 	var restClientv1 rest.Interface
@@ -445,7 +501,8 @@ func (ctlr *Controller) getNodeInformer(clusterName string) NodeInformer {
 
 func (ctlr *Controller) addNodeEventUpdateHandler(nodeInformer *NodeInformer) {
 	if nodeInformer.nodeInformer != nil {
-		nodeInformer.nodeInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			nodeInformer.nodeInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc: func(obj interface{}) { ctlr.SetupNodeProcessing(nodeInformer.clusterName) },
 				UpdateFunc: func(obj, cur interface{}) {
@@ -519,17 +576,7 @@ func (ctlr *Controller) newNamespacedCommonResourceInformer(
 	if ctlr.PoolMemberType != Cluster && ctlr.multiClusterMode != "" {
 		log.Debugf("[Multicluster] Skipping endpoint informer creation for namespace %v", namespace)
 	} else {
-		comInf.epsInformer = cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
-				restClientv1,
-				"endpoints",
-				namespace,
-				everything,
-			),
-			&corev1.Endpoints{},
-			resyncPeriod,
-			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-		)
+		comInf.epsInformer = newEndpointSliceInformer(ctlr.clientsets.kubeClient, namespace, resyncPeriod)
 	}
 
 	if ctlr.managedResources.ManageEDNS {
@@ -577,7 +624,8 @@ func (ctlr *Controller) newNamespacedCommonResourceInformer(
 
 func (ctlr *Controller) addCustomResourceEventHandlers(crInf *CRInformer) {
 	if crInf.vsInformer != nil {
-		crInf.vsInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			crInf.vsInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueVirtualServer(obj) },
 				UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedVirtualServer(old, cur) },
@@ -588,7 +636,8 @@ func (ctlr *Controller) addCustomResourceEventHandlers(crInf *CRInformer) {
 	}
 
 	if crInf.tlsInformer != nil {
-		crInf.tlsInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			crInf.tlsInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueTLSProfile(obj, Create) },
 				UpdateFunc: func(old, cur interface{}) { ctlr.enqueueTLSProfile(cur, Update) },
@@ -599,7 +648,8 @@ func (ctlr *Controller) addCustomResourceEventHandlers(crInf *CRInformer) {
 	}
 
 	if crInf.tsInformer != nil {
-		crInf.tsInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			crInf.tsInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueTransportServer(obj) },
 				UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedTransportServer(old, cur) },
@@ -610,7 +660,8 @@ func (ctlr *Controller) addCustomResourceEventHandlers(crInf *CRInformer) {
 	}
 
 	if crInf.ilInformer != nil {
-		crInf.ilInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			crInf.ilInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueIngressLink(obj) },
 				UpdateFunc: func(oldObj, newObj interface{}) { ctlr.enqueueUpdatedIngressLink(oldObj, newObj) },
@@ -623,7 +674,8 @@ func (ctlr *Controller) addCustomResourceEventHandlers(crInf *CRInformer) {
 
 func (ctlr *Controller) addCommonResourceEventHandlers(comInf *CommonInformer) {
 	if comInf.svcInformer != nil {
-		comInf.svcInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			comInf.svcInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueService(obj, "") },
 				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueUpdatedService(obj, cur, "") },
@@ -634,18 +686,19 @@ func (ctlr *Controller) addCommonResourceEventHandlers(comInf *CommonInformer) {
 	}
 
 	if comInf.epsInformer != nil {
-		comInf.epsInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			comInf.epsInformer,
 			&cache.ResourceEventHandlerFuncs{
-				AddFunc:    func(obj interface{}) { ctlr.enqueueEndpoints(obj, Create, "") },
-				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueEndpoints(cur, Update, "") },
-				DeleteFunc: func(obj interface{}) { ctlr.enqueueEndpoints(obj, Delete, "") },
+				AddFunc:    func(obj interface{}) { ctlr.enqueueEndpointSlice(obj, Create, "") },
+				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueEndpointSlice(cur, Update, "") },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueEndpointSlice(obj, Delete, "") },
 			},
 		)
-		comInf.epsInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(Endpoints, Local))
+		comInf.epsInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(EndpointSlice, Local))
 	}
 
 	if comInf.ednsInformer != nil {
-		comInf.ednsInformer.AddEventHandler(
+		ctlr.addEventHandler(comInf.ednsInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueExternalDNS(obj) },
 				UpdateFunc: func(oldObj, newObj interface{}) { ctlr.enqueueUpdatedExternalDNS(oldObj, newObj) },
@@ -655,7 +708,8 @@ func (ctlr *Controller) addCommonResourceEventHandlers(comInf *CommonInformer) {
 	}
 
 	if comInf.plcInformer != nil {
-		comInf.plcInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			comInf.plcInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueuePolicy(obj, Create) },
 				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueuePolicy(cur, Update) },
@@ -666,7 +720,8 @@ func (ctlr *Controller) addCommonResourceEventHandlers(comInf *CommonInformer) {
 	}
 
 	if comInf.podInformer != nil {
-		comInf.podInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			comInf.podInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueuePod(obj, "") },
 				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueuePod(cur, "") },
@@ -677,7 +732,8 @@ func (ctlr *Controller) addCommonResourceEventHandlers(comInf *CommonInformer) {
 	}
 
 	if comInf.secretsInformer != nil {
-		comInf.secretsInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			comInf.secretsInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueSecret(obj, Create) },
 				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueSecret(cur, Update) },
@@ -688,7 +744,8 @@ func (ctlr *Controller) addCommonResourceEventHandlers(comInf *CommonInformer) {
 	}
 
 	if comInf.configCRInformer != nil {
-		comInf.configCRInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			comInf.configCRInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueConfigCR(obj, Create) },
 				UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedConfigCR(old, cur) },
@@ -702,7 +759,8 @@ func (ctlr *Controller) addCommonResourceEventHandlers(comInf *CommonInformer) {
 
 func (ctlr *Controller) addNativeResourceEventHandlers(nrInf *NRInformer) {
 	if nrInf.routeInformer != nil {
-		nrInf.routeInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			nrInf.routeInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueRoute(obj, Create) },
 				UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedRoute(old, cur) },
@@ -711,6 +769,29 @@ func (ctlr *Controller) addNativeResourceEventHandlers(nrInf *NRInformer) {
 		)
 		nrInf.routeInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(Route, Local))
 	}
+	if nrInf.ingressInformer != nil {
+		ctlr.addEventHandler(
+			nrInf.ingressInformer,
+			&cache.ResourceEventHandlerFuncs{
+				AddFunc:    func(obj interface{}) { ctlr.enqueueIngress(obj, Create) },
+				UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedIngress(old, cur) },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueIngress(obj, Delete) },
+			},
+		)
+		nrInf.ingressInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(Ingress, Local))
+	}
+}
+
+func (ctlr *Controller) addIngressClassEventHandlers(icInf *IngressClassInformer) {
+	ctlr.addEventHandler(
+		icInf.icInformer,
+		&cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctlr.enqueueIngressClass(obj, Create) },
+			UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedIngressClass(old, cur) },
+			DeleteFunc: func(obj interface{}) { ctlr.enqueueIngressClass(obj, Delete) },
+		},
+	)
+	icInf.icInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(IngressClass, Local))
 }
 
 func (ctlr *Controller) getEventHandlerForIPAM() *cache.ResourceEventHandlerFuncs {
@@ -724,7 +805,8 @@ func (ctlr *Controller) getEventHandlerForIPAM() *cache.ResourceEventHandlerFunc
 func (ctlr *Controller) enqueueIPAM(obj interface{}) {
 	ipamObj := obj.(*ficV1.IPAM)
 
-	if ipamObj.Namespace+"/"+ipamObj.Name != ctlr.ipamCR {
+	// the resources of the new leader request the IP addresses again, so followers skip the IPAM events
+	if !ctlr.IsLeader() || ipamObj.Namespace+"/"+ipamObj.Name != ctlr.ipamCR {
 		return
 	}
 
@@ -744,7 +826,7 @@ func (ctlr *Controller) enqueueUpdatedIPAM(oldObj, newObj interface{}) {
 	oldIpam := oldObj.(*ficV1.IPAM)
 	curIpam := newObj.(*ficV1.IPAM)
 
-	if curIpam.Namespace+"/"+curIpam.Name != ctlr.ipamCR {
+	if !ctlr.IsLeader() || curIpam.Namespace+"/"+curIpam.Name != ctlr.ipamCR {
 		return
 	}
 
@@ -767,7 +849,8 @@ func (ctlr *Controller) enqueueUpdatedIPAM(oldObj, newObj interface{}) {
 func (ctlr *Controller) enqueueDeletedIPAM(obj interface{}) {
 	ipamObj := obj.(*ficV1.IPAM)
 
-	if ipamObj.Namespace+"/"+ipamObj.Name != ctlr.ipamCR {
+	// the resources of the new leader request the IP addresses again, so followers skip the IPAM events
+	if !ctlr.IsLeader() || ipamObj.Namespace+"/"+ipamObj.Name != ctlr.ipamCR {
 		return
 	}
 
@@ -805,12 +888,9 @@ func (ctlr *Controller) enqueueUpdatedVirtualServer(oldObj, newObj interface{})
 		return
 	}
 	updateEvent := true
-	oldVSPartition := ctlr.getCRPartition(oldVS.Spec.Partition)
-	newVSPartition := ctlr.getCRPartition(newVS.Spec.Partition)
-	////TODO: get bigipLabel from cr resource or service address cr resource
-	//	//Phase1 setting bigipLabel to default
-	bigipLabel := BigIPLabel
-	bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
+	oldVSPartition := ctlr.getCRPartition(oldVS.Spec.Partition, oldVS.Spec.BigIpLabel)
+	newVSPartition := ctlr.getCRPartition(newVS.Spec.Partition, newVS.Spec.BigIpLabel)
+	bigipConfig := ctlr.getBIGIPConfig(oldVS.Spec.BigIpLabel)
 	if oldVS.Spec.VirtualServerAddress != newVS.Spec.VirtualServerAddress ||
 		oldVS.Spec.VirtualServerHTTPPort != newVS.Spec.VirtualServerHTTPPort ||
 		oldVS.Spec.VirtualServerHTTPSPort != newVS.Spec.VirtualServerHTTPSPort ||
@@ -818,6 +898,7 @@ func (ctlr *Controller) enqueueUpdatedVirtualServer(oldObj, newObj interface{})
 		oldVS.Spec.Host != newVS.Spec.Host ||
 		oldVS.Spec.IPAMLabel != newVS.Spec.IPAMLabel ||
 		oldVS.Spec.HostGroup != newVS.Spec.HostGroup ||
+		oldVS.Spec.BigIpLabel != newVS.Spec.BigIpLabel ||
 		oldVSPartition != newVSPartition {
 		log.Debugf("Enqueueing Old VirtualServer: %v", oldVS)
 
@@ -901,17 +982,15 @@ func (ctlr *Controller) enqueueUpdatedTransportServer(oldObj, newObj interface{}
 		return
 	}
 	updateEvent := true
-	oldVSPartition := ctlr.getCRPartition(oldVS.Spec.Partition)
-	newVSPartition := ctlr.getCRPartition(newVS.Spec.Partition)
-	////TODO: get bigipLabel from cr resource or service address cr resource
-	//	//Phase1 setting bigipLabel to default
-	bigipLabel := BigIPLabel
-	bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
+	oldVSPartition := ctlr.getCRPartition(oldVS.Spec.Partition, oldVS.Spec.BigIpLabel)
+	newVSPartition := ctlr.getCRPartition(newVS.Spec.Partition, newVS.Spec.BigIpLabel)
+	bigipConfig := ctlr.getBIGIPConfig(oldVS.Spec.BigIpLabel)
 	if oldVS.Spec.VirtualServerAddress != newVS.Spec.VirtualServerAddress ||
 		oldVS.Spec.VirtualServerPort != newVS.Spec.VirtualServerPort ||
 		oldVS.Spec.VirtualServerName != newVS.Spec.VirtualServerName ||
 		oldVS.Spec.IPAMLabel != newVS.Spec.IPAMLabel ||
 		oldVS.Spec.HostGroup != newVS.Spec.HostGroup ||
+		oldVS.Spec.BigIpLabel != newVS.Spec.BigIpLabel ||
 		oldVSPartition != newVSPartition {
 		log.Debugf("Enqueueing TransportServer: %v", oldVS)
 
@@ -1019,14 +1098,12 @@ func (ctlr *Controller) enqueueUpdatedIngressLink(oldObj, newObj interface{}) {
 	oldIngLink := oldObj.(*cisapiv1.IngressLink)
 	newIngLink := newObj.(*cisapiv1.IngressLink)
 
-	oldILPartition := ctlr.getCRPartition(oldIngLink.Spec.Partition)
-	newILPartition := ctlr.getCRPartition(newIngLink.Spec.Partition)
-	////TODO: get bigipLabel from cr resource or service address cr resource
-	//	//Phase1 setting bigipLabel to default
-	bigipLabel := BigIPLabel
-	bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
+	oldILPartition := ctlr.getCRPartition(oldIngLink.Spec.Partition, oldIngLink.Spec.BigIpLabel)
+	newILPartition := ctlr.getCRPartition(newIngLink.Spec.Partition, newIngLink.Spec.BigIpLabel)
+	bigipConfig := ctlr.getBIGIPConfig(oldIngLink.Spec.BigIpLabel)
 	if oldIngLink.Spec.VirtualServerAddress != newIngLink.Spec.VirtualServerAddress ||
 		oldIngLink.Spec.IPAMLabel != newIngLink.Spec.IPAMLabel ||
+		oldIngLink.Spec.BigIpLabel != newIngLink.Spec.BigIpLabel ||
 		oldILPartition != newILPartition {
 
 		// delete vs from previous partition on priority when partition is changed
@@ -1151,6 +1228,11 @@ func (ctlr *Controller) enqueueUpdatedService(obj, cur interface{}, clusterName
 
 	if (svc.Spec.Type != curSvc.Spec.Type && svc.Spec.Type == corev1.ServiceTypeLoadBalancer) ||
 		(svc.Annotations[LBServiceIPAMLabelAnnotation] != curSvc.Annotations[LBServiceIPAMLabelAnnotation]) ||
+		(svc.Annotations[LBServiceBigIpLabelAnnotation] != curSvc.Annotations[LBServiceBigIpLabelAnnotation]) ||
+		(svc.Annotations[LBServiceIPAnnotation] != curSvc.Annotations[LBServiceIPAnnotation]) ||
+		svc.Spec.LoadBalancerIP != curSvc.Spec.LoadBalancerIP ||
+		!reflect.DeepEqual(svc.Spec.LoadBalancerClass, curSvc.Spec.LoadBalancerClass) ||
+		!reflect.DeepEqual(svc.Spec.IPFamilies, curSvc.Spec.IPFamilies) ||
 		!reflect.DeepEqual(svc.Labels, curSvc.Labels) || !reflect.DeepEqual(svc.Spec.Ports, curSvc.Spec.Ports) ||
 		!reflect.DeepEqual(svc.Spec.Selector, curSvc.Spec.Selector) {
 		log.Debugf("Enqueueing Old Service: %v %v", svc, getClusterLog(clusterName))
@@ -1203,23 +1285,38 @@ func (ctlr *Controller) enqueueDeletedService(obj interface{}, clusterName strin
 	ctlr.resourceQueue.Add(key)
 }
 
-func (ctlr *Controller) enqueueEndpoints(obj interface{}, event string, clusterName string) {
-	eps := obj.(*corev1.Endpoints)
+func (ctlr *Controller) enqueueEndpointSlice(obj interface{}, event string, clusterName string) {
+	var slice *discoveryv1.EndpointSlice
+	switch obj := obj.(type) {
+	case *discoveryv1.EndpointSlice:
+		slice = obj
+	case cache.DeletedFinalStateUnknown:
+		slice, _ = obj.Obj.(*discoveryv1.EndpointSlice)
+	}
+	if slice == nil {
+		return
+	}
+	svcName := slice.Labels[discoveryv1.LabelServiceName]
+	// Ignore the EndpointSlices which are not managed for a Service
+	if svcName == "" {
+		return
+	}
 	// Ignore K8S Core Services
-	if _, ok := K8SCoreServices[eps.Name]; ok {
+	if _, ok := K8SCoreServices[svcName]; ok {
 		return
 	}
 	if ctlr.managedResources.ManageRoutes {
-		if _, ok := OSCPCoreServices[eps.Name]; ok {
+		if _, ok := OSCPCoreServices[svcName]; ok {
 			return
 		}
 	}
-	log.Debugf("Enqueueing Endpoints: %v %v", eps, getClusterLog(clusterName))
+	log.Debugf("Enqueueing EndpointSlice: %v/%v of Service %v %v", slice.Namespace, slice.Name, svcName,
+		getClusterLog(clusterName))
 	key := &rqKey{
-		namespace:   eps.ObjectMeta.Namespace,
-		kind:        Endpoints,
-		rscName:     eps.ObjectMeta.Name,
-		rsc:         obj,
+		namespace:   slice.ObjectMeta.Namespace,
+		kind:        EndpointSlice,
+		rscName:     slice.ObjectMeta.Name,
+		rsc:         slice,
 		event:       event,
 		clusterName: clusterName,
 	}
@@ -1348,6 +1445,50 @@ func (ctlr *Controller) enqueueDeletedRoute(obj interface{}) {
 	ctlr.resourceQueue.Add(key)
 }
 
+func (ctlr *Controller) enqueueIngress(obj interface{}, event string) {
+	ing := obj.(*networkingv1.Ingress)
+	log.Debugf("Enqueueing Ingress: %v/%v", ing.Namespace, ing.Name)
+	key := &rqKey{
+		namespace: ing.Namespace,
+		kind:      Ingress,
+		rscName:   ing.Name,
+		rsc:       obj,
+		event:     event,
+	}
+	ctlr.resourceQueue.Add(key)
+}
+
+func (ctlr *Controller) enqueueUpdatedIngress(old, cur interface{}) {
+	oldIng := old.(*networkingv1.Ingress)
+	newIng := cur.(*networkingv1.Ingress)
+	// Skip ingresses on status updates
+	if reflect.DeepEqual(oldIng.Spec, newIng.Spec) && reflect.DeepEqual(oldIng.Annotations, newIng.Annotations) {
+		return
+	}
+	ctlr.enqueueIngress(cur, Update)
+}
+
+func (ctlr *Controller) enqueueIngressClass(obj interface{}, event string) {
+	ic := obj.(*networkingv1.IngressClass)
+	log.Debugf("Enqueueing IngressClass: %v", ic.Name)
+	key := &rqKey{
+		kind:    IngressClass,
+		rscName: ic.Name,
+		rsc:     obj,
+		event:   event,
+	}
+	ctlr.resourceQueue.Add(key)
+}
+
+func (ctlr *Controller) enqueueUpdatedIngressClass(old, cur interface{}) {
+	oldIC := old.(*networkingv1.IngressClass)
+	newIC := cur.(*networkingv1.IngressClass)
+	if reflect.DeepEqual(oldIC.Spec, newIC.Spec) && reflect.DeepEqual(oldIC.Annotations, newIC.Annotations) {
+		return
+	}
+	ctlr.enqueueIngressClass(cur, Update)
+}
+
 func (ctlr *Controller) enqueuePod(obj interface{}, clusterName string) {
 	pod := obj.(*corev1.Pod)
 	//skip if pod belongs to coreService
@@ -1456,7 +1597,8 @@ func (ctlr *Controller) createNamespaceLabeledInformer(label string) error {
 		),
 	}
 
-	ctlr.nsInformers[label].nsInformer.AddEventHandlerWithResyncPeriod(
+	ctlr.addEventHandlerWithResyncPeriod(
+		ctlr.nsInformers[label].nsInformer,
 		&cache.ResourceEventHandlerFuncs{
 			AddFunc:    func(obj interface{}) { ctlr.enqueueNamespace(obj) },
 			DeleteFunc: func(obj interface{}) { ctlr.enqueueDeletedNamespace(obj) },
@@ -1533,3 +1675,36 @@ func (ctlr *Controller) getErrorHandlerFunc(rsType, clusterName string) func(r *
 		}
 	}
 }
+
+// newEndpointSliceInformer returns the EndpointSlice informer indexed by the owning Service
+func newEndpointSliceInformer(
+	kubeClient kubernetes.Interface,
+	namespace string,
+	resyncPeriod time.Duration,
+) cache.SharedIndexInformer {
+	return discoveryinfv1.NewFilteredEndpointSliceInformer(
+		kubeClient,
+		namespace,
+		resyncPeriod,
+		cache.Indexers{
+			cache.NamespaceIndex:      cache.MetaNamespaceIndexFunc,
+			EndpointSliceServiceIndex: endpointSliceServiceIndexFunc,
+		},
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = discoveryv1.LabelServiceName
+		},
+	)
+}
+
+// endpointSliceServiceIndexFunc indexes the EndpointSlices with namespace/name of the Service
+func endpointSliceServiceIndexFunc(obj interface{}) ([]string, error) {
+	slice, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		return nil, fmt.Errorf("object is not an EndpointSlice")
+	}
+	svcName := slice.Labels[discoveryv1.LabelServiceName]
+	if svcName == "" {
+		return nil, nil
+	}
+	return []string{slice.Namespace + "/" + svcName}, nil
+}
@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 	routeapi "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
@@ -504,31 +505,31 @@ var _ = Describe("Informers Tests", func() {
 			Expect(mockCtlr.resourceQueue.Len()).To(BeEquivalentTo(0), "Invalid Service")
 		})
 
-		It("Endpoints", func() {
-			eps := test.NewEndpoints(
+		It("EndpointSlice", func() {
+			eps := test.NewEndpointSlice(
 				"SampleSVC",
 				"1",
 				"worker1",
 				namespace,
 				[]string{"10.20.30.40"},
 				nil,
-				[]v1.EndpointPort{
+				convertSvcPortsToEndpointPorts([]v1.ServicePort{
 					{
 						Name: "port1",
 						Port: 80,
 					},
-				},
+				}),
 			)
-			mockCtlr.enqueueEndpoints(eps, Create, "")
+			mockCtlr.enqueueEndpointSlice(eps, Create, "")
 			key, quit := mockCtlr.resourceQueue.Get()
-			Expect(key).ToNot(BeNil(), "Enqueue New Endpoints Failed")
-			Expect(quit).To(BeFalse(), "Enqueue New Endpoints  Failed")
+			Expect(key).ToNot(BeNil(), "Enqueue New EndpointSlice Failed")
+			Expect(quit).To(BeFalse(), "Enqueue New EndpointSlice Failed")
 
-			mockCtlr.enqueueEndpoints(eps, Create, "")
+			mockCtlr.enqueueEndpointSlice(eps, Create, "")
 			Expect(mockCtlr.processResources()).To(Equal(true))
 
-			eps.Name = "kube-dns"
-			mockCtlr.enqueueEndpoints(eps, Create, "")
+			eps.Labels[discoveryv1.LabelServiceName] = "kube-dns"
+			mockCtlr.enqueueEndpointSlice(eps, Create, "")
 			Expect(mockCtlr.resourceQueue.Len()).To(BeEquivalentTo(0), "Invalid Endpoint")
 		})
 
@@ -0,0 +1,503 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+)
+
+// processIngressClass processes all the ingresses as the change of a class may add or remove them from CIS
+func (ctlr *Controller) processIngressClass(ic *networkingv1.IngressClass) error {
+	log.Debugf("Processing ingresses for IngressClass %v", ic.Name)
+	var err error
+	for _, ing := range ctlr.getAllIngresses("") {
+		if ingErr := ctlr.processIngress(ing, false); ingErr != nil {
+			err = ingErr
+		}
+	}
+	return err
+}
+
+// processIngress processes the virtuals of the address of the ingress and of the addresses it was served on,
+// the ingresses sharing an address are served by the same virtuals like the routes of a route group
+func (ctlr *Controller) processIngress(ing *networkingv1.Ingress, isDelete bool) error {
+	startTime := time.Now()
+	defer func() {
+		log.Debugf("Finished syncing Ingress %v/%v (%v)", ing.Namespace, ing.Name, time.Since(startTime))
+	}()
+
+	ingKey := ing.Namespace + "/" + ing.Name
+	bigipConfig := ctlr.getBIGIPConfig(BigIPLabel)
+	partition := ctlr.getCRPartition("", BigIPLabel)
+	var addresses []string
+	for _, rsCfg := range ctlr.resources.getPartitionResourceMap(partition, bigipConfig) {
+		ip := rsCfg.Virtual.VirtualAddress.BindAddr
+		if rsCfg.MetaData.baseResources[ingKey] == Ingress && !slices.Contains(addresses, ip) {
+			addresses = append(addresses, ip)
+		}
+	}
+	managed := !isDelete && ctlr.isManagedIngress(ing)
+	if managed {
+		if ip := ing.Annotations[F5VsBindAddrAnnotation]; ip == "" {
+			log.Warningf("No %v annotation found for Ingress %v", F5VsBindAddrAnnotation, ingKey)
+			ctlr.recordEventf(ing, v1.EventTypeWarning, InvalidSpec, "No %v annotation found", F5VsBindAddrAnnotation)
+		} else if !slices.Contains(addresses, ip) {
+			addresses = append(addresses, ip)
+		}
+	}
+
+	var err error
+	for _, ip := range addresses {
+		if addrErr := ctlr.processIngressAddress(ip, partition, bigipConfig); addrErr != nil {
+			err = addrErr
+		}
+	}
+	if !managed || ing.Annotations[F5VsBindAddrAnnotation] == "" {
+		ctlr.updateIngressStatus(ing, "")
+	}
+	ctlr.TeemData.Lock()
+	ctlr.TeemData.ResourceType.Ingresses[ing.Namespace] = len(ctlr.getManagedIngresses(ing.Namespace))
+	ctlr.TeemData.Unlock()
+	return err
+}
+
+// processIngressAddress recreates the HTTP and HTTPS virtuals of the ingresses with the address
+func (ctlr *Controller) processIngressAddress(ip, partition string, bigipConfig cisapiv1.BigIpConfig) error {
+	for _, portStruct := range getBasicVirtualPorts() {
+		ctlr.deleteVirtualServer(partition, frameIngressVSName(ip, portStruct.port), bigipConfig)
+	}
+	var ingresses []*networkingv1.Ingress
+	for _, ing := range ctlr.getManagedIngresses("") {
+		if ing.Annotations[F5VsBindAddrAnnotation] != ip {
+			continue
+		}
+		ingresses = append(ingresses, ing)
+		ctlr.deleteResourceExternalClusterSvcRouteReference(resourceRef{
+			kind:      Ingress,
+			namespace: ing.Namespace,
+			name:      ing.Name,
+		})
+	}
+	if len(ingresses) == 0 {
+		return nil
+	}
+
+	vsMap := make(ResourceMap)
+	for _, portStruct := range getBasicVirtualPorts() {
+		rsCfg, err := ctlr.prepareIngressVirtual(ingresses, ip, partition, portStruct)
+		if err != nil {
+			log.Errorf("Unable to process ingresses of address %v: %v", ip, err)
+			return err
+		}
+		if len(rsCfg.Pools) == 0 && len(rsCfg.Virtual.IRules) == 0 {
+			continue
+		}
+		vsMap[rsCfg.Virtual.Name] = rsCfg
+	}
+	rsMap := ctlr.resources.getPartitionResourceMap(partition, bigipConfig)
+	for rsName, rsCfg := range vsMap {
+		rsMap[rsName] = rsCfg
+	}
+	return nil
+}
+
+// prepareIngressVirtual prepares the virtual for the port, hosts of the rules are translated to VirtualServers with
+// the paths served by the oldest ingress
+func (ctlr *Controller) prepareIngressVirtual(
+	ingresses []*networkingv1.Ingress,
+	ip string,
+	partition string,
+	portStruct portStruct,
+) (*ResourceConfig, error) {
+	rsCfg := &ResourceConfig{}
+	rsCfg.Virtual.Partition = partition
+	rsCfg.MetaData.ResourceType = VirtualServer
+	rsCfg.Virtual.Enabled = true
+	rsCfg.Virtual.Name = frameIngressVSName(ip, portStruct.port)
+	rsCfg.MetaData.Protocol = portStruct.protocol
+	rsCfg.Virtual.SetVirtualAddress(ip, portStruct.port)
+	rsCfg.MetaData.baseResources = make(map[string]string)
+	rsCfg.IntDgMap = make(InternalDataGroupMap)
+	rsCfg.IRulesMap = make(IRulesMap)
+	rsCfg.customProfiles = make(map[SecretKey]CustomProfile)
+	secure := portStruct.protocol == HTTPS
+	if !secure {
+		// for unsecured vs, disable mrf router always
+		enabled := false
+		rsCfg.Virtual.HttpMrfRoutingEnabled = &enabled
+	}
+
+	claimedPaths := make(map[string]struct{})
+	defaultPoolClaimed := false
+	for _, ing := range ingresses {
+		ingRef := resourceRef{
+			kind:      Ingress,
+			namespace: ing.Namespace,
+			name:      ing.Name,
+		}
+		if missing := ctlr.getMissingIngressSecrets(ing); len(missing) > 0 {
+			log.Errorf("Secrets %v not found for Ingress %v/%v", missing, ing.Namespace, ing.Name)
+			ctlr.recordEventf(ing, v1.EventTypeWarning, InvalidSpec, "Secrets %v not found", missing)
+			continue
+		}
+		httpTraffic := getIngressHTTPTraffic(ing)
+		var secrets []string
+		var tlsPoolPathRefs []poolPathRef
+		var vsHostname string
+		served := false
+		for _, host := range getIngressHosts(ing) {
+			secret, tlsHost := getIngressTLSSecret(ing, host)
+			if (secure && !tlsHost) || (!secure && tlsHost && httpTraffic == TLSNoInsecure) {
+				continue
+			}
+			vs := newIngressVirtualServer(ing, host, portStruct.port, secure)
+			vs.Spec.Pools = ctlr.getIngressPools(ing, host, claimedPaths)
+			if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil && !defaultPoolClaimed {
+				defaultPoolClaimed = true
+				vs.Spec.DefaultPool = cisapiv1.DefaultPool{
+					Reference:        ServiceRef,
+					Service:          ing.Spec.DefaultBackend.Service.Name,
+					ServicePort:      getIngressServicePort(ing.Spec.DefaultBackend.Service.Port),
+					ServiceNamespace: ing.Namespace,
+					Balance:          ing.Annotations[F5VsBalanceAnnotation],
+				}
+			}
+			if len(vs.Spec.Pools) == 0 && vs.Spec.DefaultPool.Service == "" {
+				continue
+			}
+			served = true
+			log.Debugf("Processing Ingress %v/%v with host %q for virtual %v", ing.Namespace, ing.Name, host,
+				rsCfg.Virtual.Name)
+			// the insecure requests of the TLS hosts are not forwarded to the pools on redirect
+			if !tlsHost || secure || httpTraffic == TLSAllowInsecure {
+				var tlsTermination string
+				if tlsHost {
+					tlsTermination = TLSEdge
+				}
+				err := ctlr.prepareRSConfigFromVirtualServerSpec(rsCfg, vs, ingRef, false, tlsTermination)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if tlsHost {
+				if !slices.Contains(secrets, secret) {
+					secrets = append(secrets, secret)
+				}
+				if vsHostname == "" {
+					vsHostname = host
+				}
+				tlsPoolPathRefs = append(tlsPoolPathRefs, ctlr.getVirtualServerPoolPathRefs(vs)...)
+			}
+		}
+		if !served {
+			continue
+		}
+		rsCfg.MetaData.baseResources[ing.Namespace+"/"+ing.Name] = Ingress
+		if len(tlsPoolPathRefs) == 0 {
+			continue
+		}
+		// the certificates of the TLS hosts are configured as the client ssl profiles selected by SNI
+		processed := ctlr.handleTLS(rsCfg, TLSContext{
+			name:          ing.Name,
+			namespace:     ing.Namespace,
+			resourceType:  Ingress,
+			referenceType: Secret,
+			vsHostname:    vsHostname,
+			httpsPort:     DEFAULT_HTTPS_PORT,
+			httpPort:      DEFAULT_HTTP_PORT,
+			ipAddress:     ip,
+			termination:   TLSEdge,
+			httpTraffic:   httpTraffic,
+			poolPathRefs:  tlsPoolPathRefs,
+			bigIPSSLProfiles: BigIPSSLProfiles{
+				clientSSLs: secrets,
+			},
+		})
+		if !processed {
+			return nil, fmt.Errorf("failed to process the TLS of Ingress %v/%v", ing.Namespace, ing.Name)
+		}
+	}
+	return rsCfg, nil
+}
+
+// isManagedIngress checks whether the ingress belongs to an IngressClass of the CIS controller, ingresses without a
+// class belong to the default IngressClass
+func (ctlr *Controller) isManagedIngress(ing *networkingv1.Ingress) bool {
+	var className string
+	if ing.Spec.IngressClassName != nil {
+		className = *ing.Spec.IngressClassName
+	} else {
+		className = ing.Annotations[IngressClassAnnotation]
+	}
+	if className != "" {
+		ic := ctlr.getIngressClass(className)
+		return ic != nil && ic.Spec.Controller == ctlr.ingressControllerName
+	}
+	if ctlr.icInformer == nil {
+		return false
+	}
+	for _, obj := range ctlr.icInformer.icInformer.GetIndexer().List() {
+		ic := obj.(*networkingv1.IngressClass)
+		if ic.Spec.Controller == ctlr.ingressControllerName &&
+			ic.Annotations[networkingv1.AnnotationIsDefaultIngressClass] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// getIngressHosts returns the hosts of the rules, the default backend is served for any host without rules
+func getIngressHosts(ing *networkingv1.Ingress) []string {
+	var hosts []string
+	for _, rule := range ing.Spec.Rules {
+		if !slices.Contains(hosts, rule.Host) {
+			hosts = append(hosts, rule.Host)
+		}
+	}
+	if len(hosts) == 0 && ing.Spec.DefaultBackend != nil {
+		hosts = append(hosts, "")
+	}
+	return hosts
+}
+
+// getIngressTLSSecret returns the secret of the host, TLS entries without hosts apply to all the hosts
+func getIngressTLSSecret(ing *networkingv1.Ingress, host string) (string, bool) {
+	for _, tls := range ing.Spec.TLS {
+		if tls.SecretName == "" {
+			continue
+		}
+		if len(tls.Hosts) == 0 || slices.Contains(tls.Hosts, host) {
+			return tls.SecretName, true
+		}
+	}
+	return "", false
+}
+
+// getIngressHTTPTraffic returns the handling of the insecure requests for the TLS hosts, they are redirected by
+// default and served with allow-http when the redirect is disabled
+func getIngressHTTPTraffic(ing *networkingv1.Ingress) string {
+	if strings.ToLower(ing.Annotations[IngressSslRedirectAnnotation]) != "false" {
+		return TLSRedirectInsecure
+	}
+	if strings.ToLower(ing.Annotations[IngressAllowHttpAnnotation]) == "true" {
+		return TLSAllowInsecure
+	}
+	return TLSNoInsecure
+}
+
+// getIngressPools returns the pools of the paths of the host which are not claimed by the older ingresses.
+// Paths are matched as prefixes, so the Exact paths are not supported.
+func (ctlr *Controller) getIngressPools(ing *networkingv1.Ingress, host string, claimedPaths map[string]struct{}) []cisapiv1.VSPool {
+	var pools []cisapiv1.VSPool
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host != host || rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service == nil {
+				log.Warningf("Skipping path %v of Ingress %v/%v as only service backends are supported", path.Path,
+					ing.Namespace, ing.Name)
+				continue
+			}
+			if path.PathType != nil && *path.PathType == networkingv1.PathTypeExact {
+				log.Warningf("Skipping path %v of Ingress %v/%v as path type %v is not supported", path.Path,
+					ing.Namespace, ing.Name, *path.PathType)
+				ctlr.recordEventf(ing, v1.EventTypeWarning, InvalidSpec, "Path type %v of path %v is not supported",
+					*path.PathType, path.Path)
+				continue
+			}
+			p := path.Path
+			if p == "" {
+				p = "/"
+			}
+			if _, ok := claimedPaths[host+p]; ok {
+				log.Warningf("Skipping path %v%v of Ingress %v/%v as it is served by another ingress", host, p,
+					ing.Namespace, ing.Name)
+				continue
+			}
+			claimedPaths[host+p] = struct{}{}
+			pools = append(pools, cisapiv1.VSPool{
+				Path:             p,
+				Service:          path.Backend.Service.Name,
+				ServicePort:      getIngressServicePort(path.Backend.Service.Port),
+				ServiceNamespace: ing.Namespace,
+				Balance:          ing.Annotations[F5VsBalanceAnnotation],
+			})
+		}
+	}
+	return pools
+}
+
+func getIngressServicePort(port networkingv1.ServiceBackendPort) intstr.IntOrString {
+	if port.Name != "" {
+		return intstr.FromString(port.Name)
+	}
+	return intstr.FromInt(int(port.Number))
+}
+
+// newIngressVirtualServer returns the VirtualServer used to translate the rules of the ingress for the host
+func newIngressVirtualServer(ing *networkingv1.Ingress, host string, port int32, secure bool) *cisapiv1.VirtualServer {
+	vs := &cisapiv1.VirtualServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ing.Name,
+			Namespace: ing.Namespace,
+		},
+		Spec: cisapiv1.VirtualServerSpec{
+			Host: host,
+			WAF:  ing.Annotations[F5VsWAFPolicy],
+		},
+	}
+	if secure {
+		vs.Spec.VirtualServerHTTPSPort = port
+	} else {
+		vs.Spec.VirtualServerHTTPPort = port
+	}
+	return vs
+}
+
+func frameIngressVSName(ip string, port int32) string {
+	return formatCustomVirtualServerName("ingress_"+ip, port)
+}
+
+// getMissingIngressSecrets returns the TLS secrets of the ingress which are not found
+func (ctlr *Controller) getMissingIngressSecrets(ing *networkingv1.Ingress) []string {
+	var missing []string
+	for _, tls := range ing.Spec.TLS {
+		if tls.SecretName == "" || slices.Contains(missing, tls.SecretName) {
+			continue
+		}
+		comInf, ok := ctlr.getNamespacedCommonInformer(ing.Namespace)
+		if !ok || comInf.secretsInformer == nil {
+			missing = append(missing, tls.SecretName)
+			continue
+		}
+		_, found, _ := comInf.secretsInformer.GetIndexer().GetByKey(ing.Namespace + "/" + tls.SecretName)
+		if !found {
+			missing = append(missing, tls.SecretName)
+		}
+	}
+	return missing
+}
+
+// updateIngressStatus sets the address of the ingress in the status, the status is cleared for an empty address
+func (ctlr *Controller) updateIngressStatus(ing *networkingv1.Ingress, ip string) {
+	var lbIngress []networkingv1.IngressLoadBalancerIngress
+	if ip != "" {
+		lbIngress = append(lbIngress, networkingv1.IngressLoadBalancerIngress{IP: ip})
+	}
+	if len(ing.Status.LoadBalancer.Ingress) == len(lbIngress) &&
+		(len(lbIngress) == 0 || reflect.DeepEqual(ing.Status.LoadBalancer.Ingress, lbIngress)) {
+		return
+	}
+	client := ctlr.clientsets.kubeClient.NetworkingV1().Ingresses(ing.Namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := client.Get(context.TODO(), ing.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		latest.Status.LoadBalancer.Ingress = lbIngress
+		_, err = client.UpdateStatus(context.TODO(), latest, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Warningf("Error while updating Ingress %v/%v status: %v", ing.Namespace, ing.Name, err)
+		}
+		return
+	}
+	if ip != "" {
+		ctlr.recordEventf(ing, v1.EventTypeNormal, DeploySucceeded, "Ingress is served on BIG-IP at %v", ip)
+	}
+}
+
+func (ctlr *Controller) getIngressClass(name string) *networkingv1.IngressClass {
+	if ctlr.icInformer == nil {
+		return nil
+	}
+	obj, found, err := ctlr.icInformer.icInformer.GetIndexer().GetByKey(name)
+	if err != nil || !found {
+		return nil
+	}
+	return obj.(*networkingv1.IngressClass)
+}
+
+func (ctlr *Controller) getIngress(namespace, name string) *networkingv1.Ingress {
+	nrInf, ok := ctlr.getNamespacedNativeInformer(namespace)
+	if !ok || nrInf.ingressInformer == nil {
+		return nil
+	}
+	obj, found, err := nrInf.ingressInformer.GetIndexer().GetByKey(namespace + "/" + name)
+	if err != nil || !found {
+		return nil
+	}
+	return obj.(*networkingv1.Ingress)
+}
+
+// getAllIngresses returns the ingresses of the namespace sorted by the creation time, all the watched namespaces
+// are considered for an empty namespace
+func (ctlr *Controller) getAllIngresses(namespace string) []*networkingv1.Ingress {
+	var objs []interface{}
+	for ns, nrInf := range ctlr.nrInformers {
+		if nrInf.ingressInformer == nil {
+			continue
+		}
+		if namespace == "" {
+			objs = append(objs, nrInf.ingressInformer.GetIndexer().List()...)
+		} else if ns == namespace || ns == "" {
+			nsObjs, err := nrInf.ingressInformer.GetIndexer().ByIndex("namespace", namespace)
+			if err != nil {
+				log.Errorf("Unable to get list of Ingresses for namespace '%v': %v", namespace, err)
+				continue
+			}
+			objs = append(objs, nsObjs...)
+		}
+	}
+	ingresses := make([]*networkingv1.Ingress, 0, len(objs))
+	for _, obj := range objs {
+		ingresses = append(ingresses, obj.(*networkingv1.Ingress))
+	}
+	sort.Slice(ingresses, func(i, j int) bool {
+		if !ingresses[i].CreationTimestamp.Equal(&ingresses[j].CreationTimestamp) {
+			return ingresses[i].CreationTimestamp.Before(&ingresses[j].CreationTimestamp)
+		}
+		return ingresses[i].Namespace+"/"+ingresses[i].Name < ingresses[j].Namespace+"/"+ingresses[j].Name
+	})
+	return ingresses
+}
+
+func (ctlr *Controller) getManagedIngresses(namespace string) []*networkingv1.Ingress {
+	var ingresses []*networkingv1.Ingress
+	for _, ing := range ctlr.getAllIngresses(namespace) {
+		if ctlr.isManagedIngress(ing) {
+			ingresses = append(ingresses, ing)
+		}
+	}
+	return ingresses
+}
+
+// getIngressesForSecret returns the managed ingresses which refer the secret in the TLS
+func (ctlr *Controller) getIngressesForSecret(secret *v1.Secret) []*networkingv1.Ingress {
+	var ingresses []*networkingv1.Ingress
+	for _, ing := range ctlr.getManagedIngresses(secret.Namespace) {
+		for _, tls := range ing.Spec.TLS {
+			if tls.SecretName == secret.Name {
+				ingresses = append(ingresses, ing)
+				break
+			}
+		}
+	}
+	return ingresses
+}
@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/teem"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("Ingress", func() {
+	var mockCtlr *mockController
+	var ic *networkingv1.IngressClass
+	var ing *networkingv1.Ingress
+	var partition string
+	namespace := "default"
+	pathPrefix := networkingv1.PathTypePrefix
+
+	newIngressBackend := func(svc string, port int32) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{
+				Name: svc,
+				Port: networkingv1.ServiceBackendPort{Number: port},
+			},
+		}
+	}
+	newIngressRule := func(host, path, svc string) networkingv1.IngressRule {
+		return networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     path,
+						PathType: &pathPrefix,
+						Backend:  newIngressBackend(svc, 80),
+					}},
+				},
+			},
+		}
+	}
+	addIngress := func(ingresses ...*networkingv1.Ingress) {
+		for _, ingress := range ingresses {
+			_ = mockCtlr.nrInformers[namespace].ingressInformer.GetIndexer().Add(ingress)
+			_, _ = mockCtlr.clientsets.kubeClient.NetworkingV1().Ingresses(namespace).Create(context.TODO(), ingress,
+				metav1.CreateOptions{})
+		}
+	}
+	getVirtuals := func() ResourceMap {
+		return mockCtlr.resources.getPartitionResourceMap(partition, mockCtlr.getBIGIPConfig(BigIPLabel))
+	}
+
+	BeforeEach(func() {
+		mockCtlr = newMockController()
+		partition = "test"
+		bigipConfig := cisapiv1.BigIpConfig{BigIpLabel: "bigip1", DefaultPartition: partition, BigIpAddress: "10.8.3.11"}
+		mockCtlr.bigIpMap[bigipConfig] = BigIpResourceConfig{ltmConfig: make(LTMConfig), gtmConfig: make(GTMConfig)}
+		mockCtlr.managedResources.ManageIngress = true
+		mockCtlr.managedResources.ManageVirtualServer = false
+		mockCtlr.managedResources.ManageTLSProfile = false
+		mockCtlr.ingressControllerName = DefaultIngressControllerName
+		mockCtlr.namespaces = map[string]bool{namespace: true}
+		mockCtlr.clientsets.kubeClient = k8sfake.NewSimpleClientset()
+		mockCtlr.comInformers = make(map[string]*CommonInformer)
+		mockCtlr.nrInformers = make(map[string]*NRInformer)
+		mockCtlr.crInformers = make(map[string]*CRInformer)
+		mockCtlr.resources = NewResourceStore()
+		mockCtlr.multiClusterResources = newMultiClusterResourceStore()
+		mockCtlr.TeemData = &teem.TeemsData{
+			ResourceType: teem.ResourceTypes{
+				Ingresses: make(map[string]int),
+			},
+		}
+		_ = mockCtlr.addNamespacedInformers(namespace, false)
+		mockCtlr.icInformer = mockCtlr.newIngressClassInformer()
+
+		ic = &networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "f5",
+				Annotations: map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"},
+			},
+			Spec: networkingv1.IngressClassSpec{Controller: DefaultIngressControllerName},
+		}
+		_ = mockCtlr.icInformer.icInformer.GetIndexer().Add(ic)
+		comInf := mockCtlr.comInformers[namespace]
+		for _, svc := range []string{"svc1", "svc2", "svc3"} {
+			_ = comInf.svcInformer.GetIndexer().Add(test.NewService(svc, "1", namespace, v1.ServiceTypeClusterIP,
+				[]v1.ServicePort{{Port: 80, Name: "http"}}))
+		}
+		_ = comInf.secretsInformer.GetIndexer().Add(test.NewSecret("tls-secret", namespace, "cert", "key"))
+
+		defaultBackend := newIngressBackend("svc3", 80)
+		ing = &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "ing1",
+				Namespace:         namespace,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+				Annotations:       map[string]string{F5VsBindAddrAnnotation: "10.1.1.1"},
+			},
+			Spec: networkingv1.IngressSpec{
+				DefaultBackend: &defaultBackend,
+				Rules: []networkingv1.IngressRule{
+					newIngressRule("foo.example.com", "/api", "svc1"),
+					newIngressRule("bar.example.com", "", "svc2"),
+				},
+			},
+		}
+	})
+
+	It("Ingress with rules and default backend", func() {
+		addIngress(ing)
+		Expect(mockCtlr.processIngress(ing, false)).To(BeNil())
+
+		virtuals := getVirtuals()
+		Expect(virtuals).To(HaveLen(1))
+		rsCfg := virtuals["ingress_10_1_1_1_80"]
+		Expect(rsCfg).NotTo(BeNil())
+		Expect(rsCfg.MetaData.baseResources).To(Equal(map[string]string{"default/ing1": Ingress}))
+		Expect(rsCfg.MetaData.hosts).To(Equal([]string{"foo.example.com", "bar.example.com"}))
+		Expect(rsCfg.Pools).To(HaveLen(3), "pools of the paths and the default backend")
+		Expect(rsCfg.Virtual.PoolName).NotTo(BeEmpty())
+		Expect(rsCfg.Policies).To(HaveLen(1))
+		Expect(mockCtlr.TeemData.ResourceType.Ingresses[namespace]).To(Equal(1))
+
+		// ingress is removed from BIG-IP on delete
+		_ = mockCtlr.nrInformers[namespace].ingressInformer.GetIndexer().Delete(ing)
+		Expect(mockCtlr.processIngress(ing, true)).To(BeNil())
+		Expect(getVirtuals()).To(BeEmpty())
+	})
+
+	It("Ingress with Exact path", func() {
+		recorder := record.NewFakeRecorder(10)
+		mockCtlr.eventRecorder = recorder
+		pathExact := networkingv1.PathTypeExact
+		ing.Spec.Rules[0].HTTP.Paths[0].PathType = &pathExact
+		addIngress(ing)
+		Expect(mockCtlr.processIngress(ing, false)).To(BeNil())
+		Expect(getVirtuals()["ingress_10_1_1_1_80"].Pools).To(HaveLen(2), "pools of the Prefix path and the default backend")
+		Expect(recorder.Events).To(Receive(Equal("Warning InvalidSpec Path type Exact of path /api is not supported")))
+	})
+
+	It("Ingress with TLS", func() {
+		ing.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{"foo.example.com"}, SecretName: "tls-secret"}}
+		addIngress(ing)
+		Expect(mockCtlr.processIngress(ing, false)).To(BeNil())
+
+		virtuals := getVirtuals()
+		Expect(virtuals).To(HaveLen(2))
+		httpsVirtual := virtuals["ingress_10_1_1_1_443"]
+		Expect(httpsVirtual).NotTo(BeNil())
+		Expect(httpsVirtual.MetaData.hosts).To(Equal([]string{"foo.example.com"}))
+		Expect(httpsVirtual.customProfiles).To(HaveKey(SecretKey{Name: "tls-secret", ResourceName: "ingress_10_1_1_1_443"}))
+		httpVirtual := virtuals["ingress_10_1_1_1_80"]
+		Expect(httpVirtual.MetaData.hosts).To(Equal([]string{"bar.example.com"}),
+			"insecure requests of the TLS host are redirected")
+		Expect(httpVirtual.Virtual.IRules).NotTo(BeEmpty())
+		Expect(mockCtlr.getIngressesForSecret(test.NewSecret("tls-secret", namespace, "", ""))).To(HaveLen(1))
+
+		// insecure requests of the TLS host are served with allow-http
+		ing.Annotations[IngressSslRedirectAnnotation] = "false"
+		ing.Annotations[IngressAllowHttpAnnotation] = "true"
+		Expect(mockCtlr.processIngress(ing, false)).To(BeNil())
+		Expect(getVirtuals()["ingress_10_1_1_1_80"].MetaData.hosts).To(ConsistOf("foo.example.com", "bar.example.com"))
+
+		// ingress with a missing secret is not served
+		ing.Spec.TLS[0].SecretName = "unknown"
+		Expect(mockCtlr.processIngress(ing, false)).To(BeNil())
+		Expect(getVirtuals()).To(BeEmpty())
+	})
+
+	It("Ingresses sharing the address", func() {
+		ing2 := &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "ing2",
+				Namespace:         namespace,
+				CreationTimestamp: metav1.NewTime(time.Now()),
+				Annotations:       map[string]string{F5VsBindAddrAnnotation: "10.1.1.1"},
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					newIngressRule("foo.example.com", "/api", "svc2"),
+					newIngressRule("baz.example.com", "/", "svc2"),
+				},
+			},
+		}
+		addIngress(ing, ing2)
+		Expect(mockCtlr.processIngress(ing2, false)).To(BeNil())
+		rsCfg := getVirtuals()["ingress_10_1_1_1_80"]
+		Expect(rsCfg.MetaData.baseResources).To(Equal(map[string]string{"default/ing1": Ingress, "default/ing2": Ingress}))
+		Expect(rsCfg.MetaData.hosts).To(Equal([]string{"foo.example.com", "bar.example.com", "baz.example.com"}),
+			"path of foo.example.com is served by the oldest ingress")
+
+		// ingress moved to another address
+		ing2.Annotations = map[string]string{F5VsBindAddrAnnotation: "10.1.1.2"}
+		_ = mockCtlr.nrInformers[namespace].ingressInformer.GetIndexer().Update(ing2)
+		Expect(mockCtlr.processIngress(ing2, false)).To(BeNil())
+		virtuals := getVirtuals()
+		Expect(virtuals).To(HaveLen(2))
+		Expect(virtuals["ingress_10_1_1_1_80"].MetaData.baseResources).To(Equal(map[string]string{"default/ing1": Ingress}))
+		Expect(virtuals["ingress_10_1_1_2_80"].MetaData.baseResources).To(Equal(map[string]string{"default/ing2": Ingress}))
+		Expect(virtuals["ingress_10_1_1_2_80"].MetaData.hosts).To(Equal([]string{"foo.example.com", "baz.example.com"}))
+	})
+
+	It("Ingresses of other controllers are ignored", func() {
+		other := &networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+			Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+		}
+		_ = mockCtlr.icInformer.icInformer.GetIndexer().Add(other)
+		className := "nginx"
+		ing.Spec.IngressClassName = &className
+		addIngress(ing)
+		Expect(mockCtlr.isManagedIngress(ing)).To(BeFalse())
+		Expect(mockCtlr.processIngress(ing, false)).To(BeNil())
+		Expect(getVirtuals()).To(BeEmpty())
+
+		ing.Spec.IngressClassName = nil
+		ing.Annotations[IngressClassAnnotation] = "f5"
+		Expect(mockCtlr.isManagedIngress(ing)).To(BeTrue())
+		delete(ing.Annotations, IngressClassAnnotation)
+		ic.Annotations = nil
+		Expect(mockCtlr.isManagedIngress(ing)).To(BeFalse(), "no default IngressClass")
+	})
+
+	It("Ingress status", func() {
+		addIngress(ing)
+		mockCtlr.updateIngressStatus(ing, "10.1.1.1")
+		latest, err := mockCtlr.clientsets.kubeClient.NetworkingV1().Ingresses(namespace).Get(context.TODO(), ing.Name,
+			metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		Expect(latest.Status.LoadBalancer.Ingress).To(Equal([]networkingv1.IngressLoadBalancerIngress{{IP: "10.1.1.1"}}))
+
+		mockCtlr.updateIngressStatus(latest, "")
+		latest, err = mockCtlr.clientsets.kubeClient.NetworkingV1().Ingresses(namespace).Get(context.TODO(), ing.Name,
+			metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		Expect(latest.Status.LoadBalancer.Ingress).To(BeEmpty())
+	})
+})
@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/networkmanager"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+)
+
+// networkObjectPrefix is the prefix of the network objects created by CIS, so that they are adopted after a restart
+const networkObjectPrefix = "k8s-"
+
+// l3Kinds are the kinds of network objects in the order they are created, they are deleted in the reverse order
+var l3Kinds = []string{networkmanager.RouteDomains, networkmanager.VLANs, networkmanager.Tunnels, networkmanager.SelfIPs,
+	networkmanager.FDBRecords, networkmanager.ARPs}
+
+// deployL3Config creates, updates and deletes the network objects of the BIG-IP instance
+func (postMgr *PostManager) deployL3Config(cfg *l3Config) error {
+	l3PostMgr := postMgr.L3PostManager
+	if l3PostMgr.deployedConfig == nil && isL3ConfigEmpty(cfg) {
+		// nothing is deployed by CIS
+		cfg.deployL3Status = true
+		return nil
+	}
+	if l3PostMgr.deployedConfig != nil && reflect.DeepEqual(l3PostMgr.deployedConfig.networkConfig, cfg.networkConfig) &&
+		reflect.DeepEqual(l3PostMgr.deployedConfig.vxlanConfig, cfg.vxlanConfig) {
+		cfg.deployL3Status = true
+		return nil
+	}
+	if l3PostMgr.networkManager == nil {
+		l3PostMgr.networkManager = networkmanager.NewNetworkManager(postMgr.tokenManager, "")
+	}
+	if l3PostMgr.instanceId == "" {
+		instanceId, err := l3PostMgr.networkManager.GetInstanceId(postMgr.bigIpKey.BigIpAddress)
+		if err != nil {
+			return err
+		}
+		l3PostMgr.instanceId = instanceId
+	}
+	desiredObjects := getL3NetworkObjects(cfg)
+	if l3PostMgr.objects == nil {
+		if err := l3PostMgr.syncNetworkObjects(cfg.vxlanConfig.tunnelName); err != nil {
+			return err
+		}
+	}
+	nm := l3PostMgr.networkManager
+	// create or update the network objects
+	for _, kind := range l3Kinds {
+		for _, name := range getSortedL3ObjectNames(desiredObjects[kind]) {
+			payload := desiredObjects[kind][name]
+			deployed, found := l3PostMgr.objects[kind][name]
+			if !found {
+				id, err := nm.PostNetworkObject(l3PostMgr.instanceId, kind, payload)
+				if err != nil {
+					return fmt.Errorf("failed to create %v %v: %v", getL3KindName(kind), name, err)
+				}
+				log.Debugf("[L3]%v Created %v %v", postMgr.postManagerPrefix, getL3KindName(kind), name)
+				l3PostMgr.objects[kind][name] = networkmanager.NetworkObject{ID: id, Name: name, Payload: payload}
+			} else if !isL3PayloadEqual(deployed.Payload, payload) {
+				if err := nm.PutNetworkObject(l3PostMgr.instanceId, kind, deployed.ID, payload); err != nil {
+					return fmt.Errorf("failed to update %v %v: %v", getL3KindName(kind), name, err)
+				}
+				log.Debugf("[L3]%v Updated %v %v", postMgr.postManagerPrefix, getL3KindName(kind), name)
+				deployed.Payload = payload
+				l3PostMgr.objects[kind][name] = deployed
+			}
+		}
+	}
+	// delete the network objects which are removed from the network config
+	for i := len(l3Kinds) - 1; i >= 0; i-- {
+		kind := l3Kinds[i]
+		var removedObjects []string
+		for name := range l3PostMgr.objects[kind] {
+			if _, found := desiredObjects[kind][name]; !found {
+				removedObjects = append(removedObjects, name)
+			}
+		}
+		sort.Strings(removedObjects)
+		for _, name := range removedObjects {
+			if err := nm.DeleteNetworkObject(l3PostMgr.instanceId, kind, l3PostMgr.objects[kind][name].ID); err != nil {
+				return fmt.Errorf("failed to delete %v %v: %v", getL3KindName(kind), name, err)
+			}
+			log.Debugf("[L3]%v Deleted %v %v", postMgr.postManagerPrefix, getL3KindName(kind), name)
+			delete(l3PostMgr.objects[kind], name)
+		}
+	}
+	cfg.deployL3Status = true
+	deployedConfig := *cfg
+	l3PostMgr.deployedConfig = &deployedConfig
+	log.Infof("[L3]%v Network config is deployed on %v", postMgr.postManagerPrefix, postMgr.bigIpKey.BigIpAddress)
+	return nil
+}
+
+// syncNetworkObjects adopts the network objects on the instance which are created by CIS, the adopted objects which
+// are not in the network config anymore are deleted
+func (l3PostMgr *L3PostManager) syncNetworkObjects(tunnelName string) error {
+	objects := make(map[string]map[string]networkmanager.NetworkObject)
+	for _, kind := range l3Kinds {
+		objects[kind] = make(map[string]networkmanager.NetworkObject)
+		existingObjects, err := l3PostMgr.networkManager.GetNetworkObjects(l3PostMgr.instanceId, kind)
+		if err != nil {
+			return err
+		}
+		for name, object := range existingObjects {
+			// objects which are not created by CIS are not managed by CIS
+			if isNetworkObjectManaged(kind, object, tunnelName) {
+				objects[kind][name] = object
+			}
+		}
+	}
+	l3PostMgr.objects = objects
+	return nil
+}
+
+// isNetworkObjectManaged checks if the network object on the instance is created by CIS. The objects are named with
+// the networkObjectPrefix, except the FDB records which are adopted by the VXLAN tunnel of CIS.
+func isNetworkObjectManaged(kind string, object networkmanager.NetworkObject, tunnelName string) bool {
+	if kind != networkmanager.FDBRecords {
+		return strings.HasPrefix(object.Name, networkObjectPrefix)
+	}
+	payload, ok := object.Payload.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	tunnel, _ := payload["tunnel"].(string)
+	return (tunnelName != "" && tunnel == tunnelName) || strings.HasPrefix(tunnel, networkObjectPrefix)
+}
+
+// getL3NetworkObjects returns the network objects of the l3 config, keyed by kind and name. The declared objects
+// are named with the networkObjectPrefix on the instance, along with the references to the declared objects.
+func getL3NetworkObjects(cfg *l3Config) map[string]map[string]interface{} {
+	config := cfg.networkConfig
+	objects := make(map[string]map[string]interface{})
+	for _, kind := range l3Kinds {
+		objects[kind] = make(map[string]interface{})
+	}
+	routeDomains := make(map[string]struct{})
+	for _, routeDomain := range config.RouteDomains {
+		routeDomains[routeDomain.Name] = struct{}{}
+	}
+	tunnels := make(map[string]struct{})
+	for _, tunnel := range config.Tunnels {
+		tunnels[tunnel.Name] = struct{}{}
+	}
+	// self IPs are assigned to the VLANs or the tunnels
+	selfIPInterfaces := make(map[string]struct{})
+	for _, vlan := range config.VLANs {
+		selfIPInterfaces[vlan.Name] = struct{}{}
+	}
+	for tunnel := range tunnels {
+		selfIPInterfaces[tunnel] = struct{}{}
+	}
+	for _, routeDomain := range config.RouteDomains {
+		routeDomain.Name = networkObjectPrefix + routeDomain.Name
+		objects[networkmanager.RouteDomains][routeDomain.Name] = routeDomain
+	}
+	for _, vlan := range config.VLANs {
+		vlan.Name = networkObjectPrefix + vlan.Name
+		vlan.RouteDomain = getNetworkObjectName(vlan.RouteDomain, routeDomains)
+		objects[networkmanager.VLANs][vlan.Name] = vlan
+	}
+	for _, tunnel := range config.Tunnels {
+		tunnel.Name = networkObjectPrefix + tunnel.Name
+		objects[networkmanager.Tunnels][tunnel.Name] = tunnel
+	}
+	for _, selfIP := range config.SelfIPs {
+		selfIP.Name = networkObjectPrefix + selfIP.Name
+		selfIP.VLAN = getNetworkObjectName(selfIP.VLAN, selfIPInterfaces)
+		objects[networkmanager.SelfIPs][selfIP.Name] = selfIP
+	}
+	for _, record := range cfg.vxlanConfig.fdbRecords {
+		record.Tunnel = getNetworkObjectName(record.Tunnel, tunnels)
+		objects[networkmanager.FDBRecords][record.Name] = record
+	}
+	for _, entry := range cfg.vxlanConfig.arpEntries {
+		objects[networkmanager.ARPs][entry.Name] = entry
+	}
+	return objects
+}
+
+// getNetworkObjectName returns the name of the referred network object on the instance
+func getNetworkObjectName(name string, declared map[string]struct{}) string {
+	if _, found := declared[name]; found {
+		return networkObjectPrefix + name
+	}
+	return name
+}
+
+// isL3PayloadEqual compares the payloads in their JSON form, as the payloads of the adopted objects are
+// decoded maps and the payloads of the network config are typed structs
+func isL3PayloadEqual(deployed, desired interface{}) bool {
+	deployedJSON, err := json.Marshal(deployed)
+	if err != nil {
+		return false
+	}
+	desiredJSON, err := json.Marshal(desired)
+	if err != nil {
+		return false
+	}
+	return DeepEqualJSON(as3Declaration(deployedJSON), as3Declaration(desiredJSON))
+}
+
+func isL3ConfigEmpty(cfg *l3Config) bool {
+	config := cfg.networkConfig
+	return len(config.RouteDomains) == 0 && len(config.VLANs) == 0 && len(config.SelfIPs) == 0 && len(config.Tunnels) == 0 &&
+		len(cfg.vxlanConfig.fdbRecords) == 0 && len(cfg.vxlanConfig.arpEntries) == 0
+}
+
+// getL3KindName returns the kind name used in the logs
+func getL3KindName(kind string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(kind, "/"), "s")
+}
+
+// getSortedL3ObjectNames returns the names of the network objects in sorted order
+func getSortedL3ObjectNames(objects map[string]interface{}) []string {
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// failTenants reports the tenants to be posted as failed, so that they are retried
+func (cfg *as3Config) failTenants(message string) {
+	tenants := make([]string, 0, len(cfg.tenantResponseMap))
+	if len(cfg.failedTenants) > 0 {
+		for tenant := range cfg.failedTenants {
+			tenants = append(tenants, tenant)
+		}
+	} else {
+		for tenant := range cfg.tenantResponseMap {
+			tenants = append(tenants, tenant)
+		}
+	}
+	for _, tenant := range tenants {
+		cfg.tenantResponseMap[tenant] = tenantResponse{agentResponseCode: http.StatusServiceUnavailable, message: message}
+	}
+}
+
+// setL3NetworkConfigs updates the network config of the BIG-IPs and returns the addresses of the BIG-IPs whose config is changed
+func (req *RequestHandler) setL3NetworkConfigs(configs []cisapiv1.L3NetworkConfig) []string {
+	configMap := make(map[string]cisapiv1.L3NetworkConfig)
+	for _, config := range configs {
+		configMap[config.BigIpAddress] = config
+	}
+	req.L3NetworkConfigs.Lock()
+	defer req.L3NetworkConfigs.Unlock()
+	var updatedBigIps []string
+	for address, config := range configMap {
+		if oldConfig, found := req.L3NetworkConfigs.configMap[address]; !found || !reflect.DeepEqual(oldConfig, config) {
+			updatedBigIps = append(updatedBigIps, address)
+		}
+	}
+	for address := range req.L3NetworkConfigs.configMap {
+		if _, found := configMap[address]; !found {
+			updatedBigIps = append(updatedBigIps, address)
+		}
+	}
+	req.L3NetworkConfigs.configMap = configMap
+	return updatedBigIps
+}
+
+// getL3NetworkConfig returns the network config of the BIG-IP
+func (req *RequestHandler) getL3NetworkConfig(address string) cisapiv1.L3NetworkConfig {
+	req.L3NetworkConfigs.RLock()
+	defer req.L3NetworkConfigs.RUnlock()
+	return req.L3NetworkConfigs.configMap[address]
+}
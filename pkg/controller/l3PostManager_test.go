@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/networkmanager"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("L3 Post Manager", func() {
+	const (
+		instanceId = "41073280-8f16-4b1f-9808-8908910e8fc2"
+		taskRef    = "/v1/tasks/9bb9a35e-83f0-4998-af41-95f3fcc4ac09"
+	)
+	var mockPM *mockPostManager
+	var server *ghttp.Server
+	var networkConfig cisapiv1.L3NetworkConfig
+
+	taskResponse := func(kind, id string) map[string]interface{} {
+		return map[string]interface{}{
+			"_links": map[string]interface{}{"task": map[string]interface{}{"href": taskRef}},
+			"path":   networkmanager.InstancesURI + instanceId + kind + "/" + id,
+		}
+	}
+	taskCompleted := ghttp.CombineHandlers(
+		ghttp.VerifyRequest("GET", networkmanager.TaskBaseURI+taskRef),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{"status": networkmanager.Completed}),
+	)
+	networkObjects := func(kind string, objects ...map[string]interface{}) http.HandlerFunc {
+		embedded := map[string]interface{}{kind[1:]: objects}
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", networkmanager.InstancesURI+instanceId+kind),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{"_embedded": embedded}),
+		)
+	}
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		mockPM = newMockPostManger()
+		mockPM.tokenManager = tokenmanager.NewTokenManager(server.URL(), tokenmanager.Credentials{}, "", true)
+		mockPM.bigIpKey = BigIpKey{BigIpAddress: "10.10.10.1", BigIpLabel: "bigip1"}
+		networkConfig = cisapiv1.L3NetworkConfig{
+			BigIpAddress: "10.10.10.1",
+			VLANs:        []cisapiv1.VLAN{{Name: "external", Tag: 100, Interfaces: []string{"1.1"}}},
+			SelfIPs:      []cisapiv1.SelfIP{{Name: "external-self", Address: "10.1.1.10/24", VLAN: "external"}},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("skips the deployment when there is no network config", func() {
+		cfg := l3Config{}
+		Expect(mockPM.deployL3Config(&cfg)).To(Succeed())
+		Expect(cfg.deployL3Status).To(BeTrue())
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("creates, updates and deletes the network objects", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", networkmanager.InventoryURI),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{
+					"_embedded": map[string]interface{}{
+						"devices": []map[string]interface{}{{"address": "10.10.10.1", "id": instanceId}},
+					},
+				}),
+			),
+			networkObjects(networkmanager.RouteDomains),
+			// the VLAN created by CIS is adopted and the other VLAN is not managed by CIS
+			networkObjects(networkmanager.VLANs,
+				map[string]interface{}{"id": "vlan-1", "payload": map[string]interface{}{"name": "k8s-external", "tag": 10}},
+				map[string]interface{}{"id": "vlan-2", "payload": map[string]interface{}{"name": "internal", "tag": 20}},
+			),
+			networkObjects(networkmanager.Tunnels),
+			// the self IP created by CIS and removed from the network config during a restart is deleted
+			networkObjects(networkmanager.SelfIPs,
+				map[string]interface{}{"id": "self-0", "payload": map[string]interface{}{"name": "k8s-stale-self"}},
+				map[string]interface{}{"id": "self-2", "payload": map[string]interface{}{"name": "external-self"}},
+			),
+			networkObjects(networkmanager.FDBRecords),
+			networkObjects(networkmanager.ARPs),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", networkmanager.InstancesURI+instanceId+networkmanager.VLANs+"/vlan-1"),
+				ghttp.VerifyJSONRepresenting(cisapiv1.VLAN{Name: "k8s-external", Tag: 100, Interfaces: []string{"1.1"}}),
+				ghttp.RespondWithJSONEncoded(http.StatusAccepted, taskResponse(networkmanager.VLANs, "vlan-1")),
+			),
+			taskCompleted,
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", networkmanager.InstancesURI+instanceId+networkmanager.SelfIPs),
+				ghttp.VerifyJSONRepresenting(cisapiv1.SelfIP{Name: "k8s-external-self", Address: "10.1.1.10/24", VLAN: "k8s-external"}),
+				ghttp.RespondWithJSONEncoded(http.StatusAccepted, taskResponse(networkmanager.SelfIPs, "self-1")),
+			),
+			taskCompleted,
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", networkmanager.InstancesURI+instanceId+networkmanager.SelfIPs+"/self-0"),
+				ghttp.RespondWithJSONEncoded(http.StatusAccepted, taskResponse(networkmanager.SelfIPs, "self-0")),
+			),
+			taskCompleted,
+		)
+		cfg := l3Config{networkConfig: networkConfig}
+		Expect(mockPM.deployL3Config(&cfg)).To(Succeed())
+		Expect(cfg.deployL3Status).To(BeTrue())
+		Expect(server.ReceivedRequests()).To(HaveLen(13))
+		Expect(mockPM.L3PostManager.objects[networkmanager.VLANs]).To(HaveLen(1))
+		Expect(mockPM.L3PostManager.objects[networkmanager.SelfIPs]).To(HaveLen(1))
+		Expect(mockPM.L3PostManager.objects[networkmanager.SelfIPs]["k8s-external-self"].ID).To(Equal("self-1"))
+
+		// unchanged network config is not deployed again
+		cfg = l3Config{networkConfig: networkConfig}
+		Expect(mockPM.deployL3Config(&cfg)).To(Succeed())
+		Expect(server.ReceivedRequests()).To(HaveLen(13))
+
+		// removed self IP is deleted
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", networkmanager.InstancesURI+instanceId+networkmanager.SelfIPs+"/self-1"),
+				ghttp.RespondWithJSONEncoded(http.StatusAccepted, taskResponse(networkmanager.SelfIPs, "self-1")),
+			),
+			taskCompleted,
+		)
+		networkConfig.SelfIPs = nil
+		cfg = l3Config{networkConfig: networkConfig}
+		Expect(mockPM.deployL3Config(&cfg)).To(Succeed())
+		Expect(server.ReceivedRequests()).To(HaveLen(15))
+		Expect(mockPM.L3PostManager.objects[networkmanager.SelfIPs]).To(BeEmpty())
+	})
+
+	It("does not update the adopted objects which match the network config", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", networkmanager.InventoryURI),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{
+					"_embedded": map[string]interface{}{
+						"devices": []map[string]interface{}{{"address": "10.10.10.1", "id": instanceId}},
+					},
+				}),
+			),
+			networkObjects(networkmanager.RouteDomains),
+			networkObjects(networkmanager.VLANs, map[string]interface{}{"id": "vlan-1", "payload": map[string]interface{}{
+				"name": "k8s-external", "tag": 100, "interfaces": []string{"1.1"},
+			}}),
+			networkObjects(networkmanager.Tunnels),
+			networkObjects(networkmanager.SelfIPs, map[string]interface{}{"id": "self-1", "payload": map[string]interface{}{
+				"name": "k8s-external-self", "address": "10.1.1.10/24", "vlan": "k8s-external",
+			}}),
+			networkObjects(networkmanager.FDBRecords),
+			networkObjects(networkmanager.ARPs),
+		)
+		cfg := l3Config{networkConfig: networkConfig}
+		Expect(mockPM.deployL3Config(&cfg)).To(Succeed())
+		Expect(cfg.deployL3Status).To(BeTrue())
+		Expect(server.ReceivedRequests()).To(HaveLen(7))
+	})
+
+	It("names the network objects and their references with the prefix of CIS", func() {
+		networkConfig.RouteDomains = []cisapiv1.RouteDomain{{Name: "rd1", ID: 1}}
+		networkConfig.VLANs[0].RouteDomain = "rd1"
+		networkConfig.VLANs = append(networkConfig.VLANs, cisapiv1.VLAN{Name: "internal", RouteDomain: "0"})
+		networkConfig.Tunnels = []cisapiv1.Tunnel{{Name: "vxlan-tunnel", LocalAddress: "10.1.1.10"}}
+		networkConfig.SelfIPs = append(networkConfig.SelfIPs,
+			cisapiv1.SelfIP{Name: "tunnel-self", Address: "10.244.0.10/16", VLAN: "vxlan-tunnel"},
+			cisapiv1.SelfIP{Name: "mgmt-self", Address: "10.2.1.10/24", VLAN: "mgmt"})
+		objects := getL3NetworkObjects(&l3Config{
+			networkConfig: networkConfig,
+			vxlanConfig: vxlanConfig{
+				tunnelName: "vxlan-tunnel",
+				fdbRecords: []fdbRecord{{Name: "0a:58:0a:f4:01:00", Tunnel: "vxlan-tunnel", Endpoint: "10.1.1.20"}},
+			},
+		})
+		Expect(objects[networkmanager.RouteDomains]).To(HaveKey("k8s-rd1"))
+		Expect(objects[networkmanager.VLANs]["k8s-external"].(cisapiv1.VLAN).RouteDomain).To(Equal("k8s-rd1"))
+		// references to the objects which are not declared are kept
+		Expect(objects[networkmanager.VLANs]["k8s-internal"].(cisapiv1.VLAN).RouteDomain).To(Equal("0"))
+		Expect(objects[networkmanager.SelfIPs]["k8s-external-self"].(cisapiv1.SelfIP).VLAN).To(Equal("k8s-external"))
+		Expect(objects[networkmanager.SelfIPs]["k8s-tunnel-self"].(cisapiv1.SelfIP).VLAN).To(Equal("k8s-vxlan-tunnel"))
+		Expect(objects[networkmanager.SelfIPs]["k8s-mgmt-self"].(cisapiv1.SelfIP).VLAN).To(Equal("mgmt"))
+		Expect(objects[networkmanager.FDBRecords]["0a:58:0a:f4:01:00"].(fdbRecord).Tunnel).To(Equal("k8s-vxlan-tunnel"))
+		// the network config is not modified
+		Expect(networkConfig.VLANs[0].Name).To(Equal("external"))
+	})
+
+	It("reports the tenants as failed when the network config is not deployed", func() {
+		server.AppendHandlers(
+			ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{}),
+		)
+		cfg := l3Config{networkConfig: networkConfig}
+		err := mockPM.deployL3Config(&cfg)
+		Expect(err).To(HaveOccurred())
+		Expect(cfg.deployL3Status).To(BeFalse())
+
+		as3cfg := as3Config{
+			tenantResponseMap: map[string]tenantResponse{"test": {}},
+			invalidTenants:    make(map[string]string),
+		}
+		as3cfg.failTenants(fmt.Sprintf("failed to deploy the network config: %v", err))
+		mockPM.updateTenantCache(&as3cfg)
+		Expect(as3cfg.failedTenants).To(HaveKey("test"))
+		Expect(as3cfg.hasRetryableTenants()).To(BeTrue())
+		Expect(getTenantErrorMessage(as3cfg.tenantResponseMap["test"])).To(ContainSubstring("not found in the Central Manager inventory"))
+
+		// the failed network config is retried even without tenants
+		config := agentConfig{l3Config: l3Config{deployL3Error: err.Error()}}
+		Expect(config.isRetryable()).To(BeTrue())
+		config.l3Config.deployL3Error = ""
+		Expect(config.isRetryable()).To(BeFalse())
+	})
+
+	It("tracks the updated network config of the BIG-IPs", func() {
+		requestHandler := newMockAgent("")
+		Expect(requestHandler.setL3NetworkConfigs([]cisapiv1.L3NetworkConfig{networkConfig})).To(ConsistOf("10.10.10.1"))
+		Expect(requestHandler.setL3NetworkConfigs([]cisapiv1.L3NetworkConfig{networkConfig})).To(BeEmpty())
+		Expect(requestHandler.getL3NetworkConfig("10.10.10.1")).To(Equal(networkConfig))
+		Expect(requestHandler.setL3NetworkConfigs(nil)).To(ConsistOf("10.10.10.1"))
+		Expect(requestHandler.getL3NetworkConfig("10.10.10.1")).To(Equal(cisapiv1.L3NetworkConfig{}))
+	})
+})
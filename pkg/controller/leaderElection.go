@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// getLeaseNamespace returns the namespace to hold the leader election lease, defaults to the namespace of CIS config CR
+func (lec *LeaderElectionConfig) getLeaseNamespace(cisConfigCRKey string) string {
+	if lec.LeaseNamespace != "" {
+		return lec.LeaseNamespace
+	}
+	if ns := strings.Split(cisConfigCRKey, "/"); len(ns) == 2 && ns[0] != "" {
+		return ns[0]
+	}
+	return DefaultLeaseNamespace
+}
+
+// getLeaderElectionIdentity returns unique identity of the CIS replica participating in leader election
+func getLeaderElectionIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = os.Getenv("HOSTNAME")
+	}
+	// append a uuid so that a restarted pod with the same hostname is not considered as the older leader
+	return hostname + "_" + string(uuid.NewUUID())
+}
+
+// newLeaderElector creates the lease based leader elector for CIS replicas
+func (ctlr *Controller) newLeaderElector(config *LeaderElectionConfig, onStartedLeading func()) (*leaderelection.LeaderElector, error) {
+	if ctlr.clientsets == nil || ctlr.clientsets.kubeClient == nil {
+		return nil, fmt.Errorf("kubernetes client not available")
+	}
+	identity := getLeaderElectionIdentity()
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metaV1.ObjectMeta{
+			Name:      config.LeaseName,
+			Namespace: config.getLeaseNamespace(ctlr.CISConfigCRKey),
+		},
+		Client: ctlr.clientsets.kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity:      identity,
+			EventRecorder: ctlr.eventRecorder,
+		},
+	}
+	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		Name:            config.LeaseName,
+		LeaseDuration:   config.LeaseDuration,
+		RenewDeadline:   config.RenewDeadline,
+		RetryPeriod:     config.RetryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				log.Infof("[LeaderElection] %v acquired the lease %v/%v, starting to process resources",
+					identity, lock.LeaseMeta.Namespace, lock.LeaseMeta.Name)
+				ctlr.setLeader(true)
+				onStartedLeading()
+			},
+			OnStoppedLeading: func() {
+				// invoked on shutdown of the followers as well
+				if !ctlr.IsLeader() {
+					return
+				}
+				ctlr.setLeader(false)
+				select {
+				case <-ctlr.leaderElectionCtx.Done():
+					// lease is released as CIS is shutting down
+					log.Infof("[LeaderElection] %v released the lease %v/%v", identity, lock.LeaseMeta.Namespace,
+						lock.LeaseMeta.Name)
+				default:
+					// the caches and the declarations of this replica can't be trusted anymore, so restart and
+					// join the election as a follower
+					log.Fatalf("[LeaderElection] %v lost the lease %v/%v, restarting", identity,
+						lock.LeaseMeta.Namespace, lock.LeaseMeta.Name)
+				}
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					log.Infof("[LeaderElection] %v is the leader, running as follower", leader)
+				}
+			},
+		},
+	})
+}
+
+// runLeaderElection blocks until CIS is stopped, onStartedLeading is invoked once this replica becomes the leader
+func (ctlr *Controller) runLeaderElection(config *LeaderElectionConfig, onStartedLeading func()) {
+	defer close(ctlr.leaderElectionDone)
+	le, err := ctlr.newLeaderElector(config, onStartedLeading)
+	if err != nil {
+		log.Fatalf("[LeaderElection] Failed to setup leader election: %v", err)
+		return
+	}
+	log.Infof("[LeaderElection] Waiting to acquire the lease %v/%v", config.getLeaseNamespace(ctlr.CISConfigCRKey),
+		config.LeaseName)
+	le.Run(ctlr.leaderElectionCtx)
+}
+
+// stopLeaderElection releases the lease if held, so that a follower takes over without waiting for the lease to expire
+func (ctlr *Controller) stopLeaderElection() {
+	if ctlr.leaderElectionCancel == nil {
+		return
+	}
+	ctlr.leaderElectionCancel()
+	select {
+	case <-ctlr.leaderElectionDone:
+	case <-time.After(timeoutSmall):
+		log.Warningf("[LeaderElection] Timed out while releasing the lease")
+	}
+}
+
+func (ctlr *Controller) setLeader(isLeader bool) {
+	ctlr.leaderLock.Lock()
+	defer ctlr.leaderLock.Unlock()
+	ctlr.isLeader = isLeader
+	if !isLeader {
+		return
+	}
+	// informers replay their caches to the handlers registered late, so the new leader processes the current state
+	// of the resources
+	for _, eh := range ctlr.pendingEventHandlers {
+		registerEventHandler(eh)
+	}
+	ctlr.pendingEventHandlers = nil
+}
+
+// addEventHandler registers the event handler of the informer, followers defer the registration until they start
+// leading so that the resources are not queued while nothing processes them
+func (ctlr *Controller) addEventHandler(informer cache.SharedIndexInformer, handler cache.ResourceEventHandler) {
+	ctlr.addEventHandlerWithResyncPeriod(informer, handler, 0)
+}
+
+// addEventHandlerWithResyncPeriod registers the event handler of the informer with the resync period
+func (ctlr *Controller) addEventHandlerWithResyncPeriod(
+	informer cache.SharedIndexInformer,
+	handler cache.ResourceEventHandler,
+	resyncPeriod time.Duration,
+) {
+	eh := informerEventHandler{informer: informer, handler: handler, resyncPeriod: resyncPeriod}
+	ctlr.leaderLock.Lock()
+	defer ctlr.leaderLock.Unlock()
+	if ctlr.leaderElectionConfig != nil && !ctlr.isLeader {
+		ctlr.pendingEventHandlers = append(ctlr.pendingEventHandlers, eh)
+		return
+	}
+	registerEventHandler(eh)
+}
+
+func registerEventHandler(eh informerEventHandler) {
+	var err error
+	if eh.resyncPeriod > 0 {
+		_, err = eh.informer.AddEventHandlerWithResyncPeriod(eh.handler, eh.resyncPeriod)
+	} else {
+		_, err = eh.informer.AddEventHandler(eh.handler)
+	}
+	if err != nil {
+		log.Errorf("[LeaderElection] Failed to register the event handler of the informer: %v", err)
+	}
+}
+
+// IsLeader returns true if this CIS replica processes the resources, it is always true when leader election is disabled
+func (ctlr *Controller) IsLeader() bool {
+	if ctlr.leaderElectionConfig == nil {
+		return true
+	}
+	ctlr.leaderLock.RLock()
+	defer ctlr.leaderLock.RUnlock()
+	return ctlr.isLeader
+}
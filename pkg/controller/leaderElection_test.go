@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ = Describe("Leader Election", func() {
+	var leader, follower *mockController
+	var leaderStarted, followerStarted chan struct{}
+
+	newLeaderElectionConfig := func() *LeaderElectionConfig {
+		return &LeaderElectionConfig{
+			LeaseDuration: 2 * time.Second,
+			RenewDeadline: time.Second,
+			RetryPeriod:   200 * time.Millisecond,
+		}
+	}
+
+	BeforeEach(func() {
+		kubeClient := k8sfake.NewSimpleClientset()
+		leader = newMockController()
+		leader.clientsets.kubeClient = kubeClient
+		leader.CISConfigCRKey = "kube-system/cis-config"
+		follower = newMockController()
+		follower.clientsets.kubeClient = kubeClient
+		follower.CISConfigCRKey = "kube-system/cis-config"
+		leaderStarted = make(chan struct{})
+		followerStarted = make(chan struct{})
+	})
+
+	It("Leader election defaults", func() {
+		Expect(leader.IsLeader()).To(BeTrue(), "CIS should process resources when leader election is disabled")
+		Expect(leader.leaderElectionRole()).To(BeEmpty())
+
+		leConfig := &LeaderElectionConfig{}
+		leader.setupLeaderElection(leConfig)
+		Expect(leConfig.LeaseName).To(Equal(DefaultLeaseName))
+		Expect(leConfig.LeaseDuration).To(Equal(DefaultLeaseDuration))
+		Expect(leConfig.RenewDeadline).To(Equal(DefaultRenewDeadline))
+		Expect(leConfig.RetryPeriod).To(Equal(DefaultRetryPeriod))
+		Expect(leConfig.getLeaseNamespace(leader.CISConfigCRKey)).To(Equal("kube-system"))
+		Expect(leConfig.getLeaseNamespace("")).To(Equal(DefaultLeaseNamespace))
+		leConfig.LeaseNamespace = "cis"
+		Expect(leConfig.getLeaseNamespace(leader.CISConfigCRKey)).To(Equal("cis"))
+		Expect(leader.IsLeader()).To(BeFalse(), "CIS should wait for the lease when leader election is enabled")
+		Expect(leader.leaderElectionRole()).To(Equal(": " + FollowerRole))
+	})
+
+	It("Follower registers the event handlers once it starts leading", func() {
+		kubeClient := k8sfake.NewSimpleClientset(&v1.Service{ObjectMeta: metaV1.ObjectMeta{Name: "svc1", Namespace: "default"}})
+		svcInformer := cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(options metaV1.ListOptions) (runtime.Object, error) {
+					return kubeClient.CoreV1().Services("").List(context.TODO(), options)
+				},
+				WatchFunc: func(options metaV1.ListOptions) (watch.Interface, error) {
+					return kubeClient.CoreV1().Services("").Watch(context.TODO(), options)
+				},
+			},
+			&v1.Service{},
+			0,
+			cache.Indexers{},
+		)
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+		var events int32
+		follower.setupLeaderElection(newLeaderElectionConfig())
+		follower.addEventHandler(svcInformer, &cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { atomic.AddInt32(&events, 1) },
+		})
+		go svcInformer.Run(stopCh)
+		Eventually(svcInformer.HasSynced, 5*time.Second).Should(BeTrue())
+		Consistently(func() int32 { return atomic.LoadInt32(&events) }, time.Second).Should(BeZero())
+
+		// the informer replays the cached Service to the new leader
+		follower.setLeader(true)
+		Eventually(func() int32 { return atomic.LoadInt32(&events) }, 5*time.Second).Should(Equal(int32(1)))
+		Expect(follower.pendingEventHandlers).To(BeEmpty())
+	})
+
+	It("Only the leader processes resources and follower takes over on release", func() {
+		leader.setupLeaderElection(newLeaderElectionConfig())
+		go leader.runLeaderElection(leader.leaderElectionConfig, func() { close(leaderStarted) })
+		Eventually(leaderStarted, 5*time.Second).Should(BeClosed())
+		Expect(leader.IsLeader()).To(BeTrue())
+		Expect(leader.leaderElectionRole()).To(Equal(": " + LeaderRole))
+
+		follower.setupLeaderElection(newLeaderElectionConfig())
+		go follower.runLeaderElection(follower.leaderElectionConfig, func() { close(followerStarted) })
+		Consistently(followerStarted, 3*time.Second).ShouldNot(BeClosed(), "follower should not process resources")
+		Expect(follower.IsLeader()).To(BeFalse())
+
+		lease, err := leader.clientsets.kubeClient.CoordinationV1().Leases("kube-system").Get(context.TODO(),
+			DefaultLeaseName, metaV1.GetOptions{})
+		Expect(err).To(BeNil())
+		Expect(*lease.Spec.HolderIdentity).NotTo(BeEmpty())
+
+		// leader releases the lease on stop
+		leader.stopLeaderElection()
+		Expect(leader.IsLeader()).To(BeFalse())
+		Eventually(followerStarted, 5*time.Second).Should(BeClosed())
+		Expect(follower.IsLeader()).To(BeTrue())
+		follower.stopLeaderElection()
+	})
+})
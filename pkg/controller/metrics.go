@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	bigIPPrometheus "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -27,9 +28,14 @@ import (
 func (ctlr *Controller) enableHttpEndpoint(httpAddress string) {
 	// Expose Prometheus metrics
 	http.Handle("/metrics", promhttp.Handler())
-	bigIPPrometheus.RegisterMetrics(ctlr.RequestHandler.httpClientMetrics, ctlr.CMTokenManager.ServerURL)
+	bigIPPrometheus.RegisterMetrics(ctlr.RequestHandler.httpClientMetrics, ctlr.CMTokenManager.GetServerURL())
 	// Expose cis health endpoint
 	http.Handle("/health", ctlr.CISHealthCheckHandler())
+	// Expose the declaration history and rollback of the tenants, only when enabled as the endpoints are not authenticated
+	if ctlr.declarationHistoryAPI {
+		http.Handle("/declarations", ctlr.DeclarationHistoryHandler())
+		http.Handle("/declarations/rollback", ctlr.RollbackHandler())
+	}
 	log.Fatal(http.ListenAndServe(httpAddress, nil).Error())
 }
 
@@ -42,16 +48,20 @@ func (ctlr *Controller) CISHealthCheckHandler() http.Handler {
 			if err != nil {
 				response = "kube-api server is not reachable."
 			}
-			// TODO Check if Central Manager is reachable
+			// Check if the Central Manager access token is available
+			if tokenStatus := ctlr.CMTokenManager.Status(); !tokenStatus.Healthy() {
+				response = response + fmt.Sprintf("central manager access token is not available: %v.",
+					tokenStatus.LastError)
+			}
 			// Check if big-ip server is reachable
 			//_, _, _, err2 := ctlr.Agent.GetBigipAS3Version()
 			//if err2 != nil {
 			//	response = response + "big-ip server is not reachable."
 			//}
 			// if err2 == nil && err == nil {
-			if err == nil {
+			if response == "" {
 				w.WriteHeader(http.StatusOK)
-				w.Write([]byte("Ok"))
+				w.Write([]byte("Ok" + ctlr.leaderElectionRole()))
 			} else {
 				w.WriteHeader(http.StatusInternalServerError)
 				w.Write([]byte(response))
@@ -59,3 +69,15 @@ func (ctlr *Controller) CISHealthCheckHandler() http.Handler {
 		}
 	})
 }
+
+// leaderElectionRole returns the role of CIS replica to be reported in health response when leader election is enabled
+// followers are healthy and ready as well, so that they can take over whenever the leader goes down
+func (ctlr *Controller) leaderElectionRole() string {
+	if ctlr.leaderElectionConfig == nil {
+		return ""
+	}
+	if ctlr.IsLeader() {
+		return ": " + LeaderRole
+	}
+	return ": " + FollowerRole
+}
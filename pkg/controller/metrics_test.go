@@ -2,12 +2,15 @@ package controller
 
 import (
 	"fmt"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"io/ioutil"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
 	"net/http"
 	"time"
 )
@@ -41,6 +44,36 @@ var _ = Describe("Metrics", func() {
 	})
 })
 
+var _ = Describe("Post pipeline metrics", func() {
+	It("Records the tenant responses", func() {
+		mockPM := newMockPostManger()
+		mockPM.bigIpKey = BigIpKey{BigIpAddress: "10.10.10.5"}
+		cfg := &as3Config{tenantResponseMap: map[string]tenantResponse{
+			"test":  {agentResponseCode: http.StatusOK},
+			"test1": {agentResponseCode: http.StatusUnprocessableEntity},
+		}}
+		mockPM.recordTenantResponses(cfg)
+		mockPM.recordTenantResponses(cfg)
+		Expect(testutil.ToFloat64(prometheus.TenantResponses.WithLabelValues("10.10.10.5", "test", "200"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(prometheus.TenantResponses.WithLabelValues("10.10.10.5", "test1", "422"))).To(Equal(2.0))
+	})
+	It("Records the time of the first resource event", func() {
+		mockCtlr := newMockController()
+		Expect(mockCtlr.getFirstEventTime().IsZero()).To(BeTrue())
+		mockCtlr.resourceQueue = newResourceEventQueue(workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "test-resource-queue"))
+		defer mockCtlr.resourceQueue.ShutDown()
+		start := time.Now()
+		mockCtlr.resourceQueue.Add("key1")
+		time.Sleep(10 * time.Millisecond)
+		mockCtlr.resourceQueue.Add("key2")
+		Expect(mockCtlr.resourceQueue.Len()).To(Equal(2))
+		eventTime := mockCtlr.getFirstEventTime()
+		Expect(eventTime).To(BeTemporally("~", start, 5*time.Millisecond))
+		Expect(mockCtlr.getFirstEventTime().IsZero()).To(BeTrue(), "event time should be reset once it is read")
+	})
+})
+
 func makeHTTPRequest(url string) (string, error) {
 	// Make the HTTP GET request
 	resp, err := http.Get(url)
@@ -19,7 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
-	"k8s.io/client-go/rest"
+	"k8s.io/client-go/kubernetes"
 	"os"
 	"sort"
 	"time"
@@ -97,7 +97,7 @@ func (poolInfr *MultiClusterPoolInformer) stop() {
 func (ctlr *Controller) addMultiClusterNamespacedInformers(
 	clusterName string,
 	namespace string,
-	restClientV1 rest.Interface,
+	kubeClient kubernetes.Interface,
 	startInformer bool,
 ) error {
 
@@ -106,7 +106,7 @@ func (ctlr *Controller) addMultiClusterNamespacedInformers(
 		ctlr.multiClusterPoolInformers[clusterName] = make(map[string]*MultiClusterPoolInformer)
 	}
 	if _, found := ctlr.multiClusterPoolInformers[clusterName][namespace]; !found {
-		poolInfr := ctlr.newMultiClusterNamespacedPoolInformer(namespace, clusterName, restClientV1)
+		poolInfr := ctlr.newMultiClusterNamespacedPoolInformer(namespace, clusterName, kubeClient)
 		ctlr.addMultiClusterPoolEventHandlers(poolInfr)
 		ctlr.multiClusterPoolInformers[clusterName][namespace] = poolInfr
 		if startInformer {
@@ -119,13 +119,14 @@ func (ctlr *Controller) addMultiClusterNamespacedInformers(
 func (ctlr *Controller) newMultiClusterNamespacedPoolInformer(
 	namespace string,
 	clusterName string,
-	restClientv1 rest.Interface,
+	kubeClient kubernetes.Interface,
 ) *MultiClusterPoolInformer {
 	log.Debugf("[MultiCluster] Creating multi cluster pool Informers for Namespace: %v %v", namespace, getClusterLog(clusterName))
 	everything := func(options *metav1.ListOptions) {
 		options.LabelSelector = ""
 	}
 	resyncPeriod := 0 * time.Second
+	restClientv1 := kubeClient.CoreV1().RESTClient()
 	comInf := &MultiClusterPoolInformer{
 		namespace:   namespace,
 		clusterName: clusterName,
@@ -158,24 +159,15 @@ func (ctlr *Controller) newMultiClusterNamespacedPoolInformer(
 	}
 	// enable endpoint informer in the cluster and nextGen routes mode only
 	if ctlr.PoolMemberType == Cluster {
-		comInf.epsInformer = cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
-				restClientv1,
-				"endpoints",
-				namespace,
-				everything,
-			),
-			&corev1.Endpoints{},
-			resyncPeriod,
-			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-		)
+		comInf.epsInformer = newEndpointSliceInformer(kubeClient, namespace, resyncPeriod)
 	}
 	return comInf
 }
 
 func (ctlr *Controller) addMultiClusterPoolEventHandlers(poolInf *MultiClusterPoolInformer) {
 	if poolInf.svcInformer != nil {
-		poolInf.svcInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			poolInf.svcInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueService(obj, poolInf.clusterName) },
 				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueUpdatedService(obj, cur, poolInf.clusterName) },
@@ -186,17 +178,19 @@ func (ctlr *Controller) addMultiClusterPoolEventHandlers(poolInf *MultiClusterPo
 	}
 
 	if poolInf.epsInformer != nil {
-		poolInf.epsInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			poolInf.epsInformer,
 			&cache.ResourceEventHandlerFuncs{
-				AddFunc:    func(obj interface{}) { ctlr.enqueueEndpoints(obj, Create, poolInf.clusterName) },
-				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueEndpoints(cur, Update, poolInf.clusterName) },
-				DeleteFunc: func(obj interface{}) { ctlr.enqueueEndpoints(obj, Delete, poolInf.clusterName) },
+				AddFunc:    func(obj interface{}) { ctlr.enqueueEndpointSlice(obj, Create, poolInf.clusterName) },
+				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueEndpointSlice(cur, Update, poolInf.clusterName) },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueEndpointSlice(obj, Delete, poolInf.clusterName) },
 			},
 		)
-		poolInf.epsInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(Endpoints, poolInf.clusterName))
+		poolInf.epsInformer.SetWatchErrorHandler(ctlr.getErrorHandlerFunc(EndpointSlice, poolInf.clusterName))
 	}
 	if poolInf.podInformer != nil {
-		poolInf.podInformer.AddEventHandler(
+		ctlr.addEventHandler(
+			poolInf.podInformer,
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueuePod(obj, poolInf.clusterName) },
 				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueuePod(cur, poolInf.clusterName) },
@@ -249,8 +243,7 @@ func (ctlr *Controller) stopMultiClusterInformers(clusterName string, stopInform
 // setup multi cluster informer
 func (ctlr *Controller) setupAndStartMultiClusterInformers(svcKey MultiClusterServiceKey, startInformer bool) error {
 	if config, ok := ctlr.multiClusterConfigs.ClusterConfigs[svcKey.clusterName]; ok {
-		restClient := config.KubeClient.CoreV1().RESTClient()
-		if err := ctlr.addMultiClusterNamespacedInformers(svcKey.clusterName, svcKey.namespace, restClient, startInformer); err != nil {
+		if err := ctlr.addMultiClusterNamespacedInformers(svcKey.clusterName, svcKey.namespace, config.KubeClient, startInformer); err != nil {
 			log.Errorf("[MultiCluster] unable to setup informer for cluster: %v, namespace: %v, Error: %v", svcKey.clusterName, svcKey.namespace, err)
 			return err
 		}
@@ -266,10 +259,10 @@ func (ctlr *Controller) setupAndStartMultiClusterInformers(svcKey MultiClusterSe
 
 // setupAndStartHAClusterInformers sets up and starts informers for the HA pair cluster
 func (ctlr *Controller) setupAndStartHAClusterInformers(clusterName string) error {
-	restClient := ctlr.multiClusterConfigs.ClusterConfigs[clusterName].KubeClient.CoreV1().RESTClient()
+	kubeClient := ctlr.multiClusterConfigs.ClusterConfigs[clusterName].KubeClient
 	// Setup informers with namespaces which are watched by CIS
 	for n := range ctlr.namespaces {
-		if err := ctlr.addMultiClusterNamespacedInformers(clusterName, n, restClient, true); err != nil {
+		if err := ctlr.addMultiClusterNamespacedInformers(clusterName, n, kubeClient, true); err != nil {
 			log.Errorf("[MultiCluster] unable to setup informer for cluster: %v, namespace: %v, Error: %v", clusterName, n, err)
 			return err
 		}
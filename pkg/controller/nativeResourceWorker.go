@@ -59,10 +59,15 @@ func (ctlr *Controller) processRoutes(routeGroup string, triggerDelete bool) err
 	}
 
 	routes := ctlr.getGroupedRoutes(routeGroup, annotationsUsed, policySSLProfiles)
-	// TODO: phase2 get bigipLabel from cr resource or service address cr
-	// Phase1 setting bigipLabel to empty string
-	bigipLabel := BigIPLabel
-	bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
+	bigipConfig, err := ctlr.getResourceBIGIPConfig(extdSpec.BigIpLabel)
+	if err != nil {
+		if triggerDelete || len(routes) == 0 {
+			// nothing is posted for the RouteGroup
+			return nil
+		}
+		return fmt.Errorf("RouteGroup %v: %v", routeGroup, err)
+	}
+	bigipLabel := bigipConfig.BigIpLabel
 	if triggerDelete || len(routes) == 0 {
 		// Delete all possible virtuals for this route group
 		for _, portStruct := range getBasicVirtualPorts() {
@@ -99,6 +104,7 @@ func (ctlr *Controller) processRoutes(routeGroup string, triggerDelete bool) err
 		rsCfg := &ResourceConfig{}
 		rsCfg.Virtual.Partition = partition
 		rsCfg.MetaData.ResourceType = VirtualServer
+		rsCfg.MetaData.bigIpLabel = bigipLabel
 		rsCfg.Virtual.Enabled = true
 		rsCfg.Virtual.Name = rsName
 		rsCfg.MetaData.Protocol = portStruct.protocol
@@ -431,9 +437,7 @@ func (ctlr *Controller) prepareResourceConfigFromRoute(
 	}
 
 	var clusterSvcs []cisapiv1.MultiClusterServiceReference
-	///TODO: get bigipLabel from cr resource or service address cr resource
-	//	//Phase1 setting bigipLabel to default
-	bigipLabel := BigIPLabel
+	bigipLabel := rsCfg.MetaData.bigIpLabel
 	if ctlr.multiClusterMode != "" {
 		//check for external service reference annotation
 		if annotation := route.Annotations[MultiClusterServicesAnnotation]; annotation != "" {
@@ -848,7 +852,7 @@ func (ctlr *Controller) UpdatePoolHealthMonitors(svcKey MultiClusterServiceKey)
 										break
 									}
 								}
-								bigipConfig := ctlr.getBIGIPConfig(BigIPLabel)
+								bigipConfig := ctlr.getBIGIPConfig(poolId.bigIpLabel)
 								_ = ctlr.resources.setResourceConfig(poolId.partition, poolId.rsName, freshRsCfg, bigipConfig)
 							}
 						}
@@ -997,9 +1001,7 @@ func (ctlr *Controller) processRouteConfigFromGlobalCM(es cisapiv1.ExtendedSpec,
 	if len(es.BaseRouteConfig.DefaultRouteGroupConfig.BigIpPartition) > 0 {
 		partition = es.BaseRouteConfig.DefaultRouteGroupConfig.BigIpPartition
 	} else {
-		//TODO: get bigipLabel from route resource or service address cr and get parition from specific bigip agent
-		//Phase1 getting partition from bigipconfig index 0
-		partition = ctlr.getPartitionForBIGIP("")
+		partition = ctlr.getPartitionForBIGIP(es.BaseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.BigIpLabel)
 	}
 
 	if es.BaseRouteConfig.DefaultRouteGroupConfig != (cisapiv1.DefaultRouteGroupConfig{}) {
@@ -1039,7 +1041,7 @@ func (ctlr *Controller) processRouteConfigFromGlobalCM(es cisapiv1.ExtendedSpec,
 		if len(ergc.BigIpPartition) > 0 {
 			partition = ergc.BigIpPartition
 		} else {
-			partition = ctlr.getPartitionForBIGIP("")
+			partition = ctlr.getPartitionForBIGIP(ergc.BigIpLabel)
 		}
 		newExtdSpecMap[routeGroup] = &extendedParsedSpec{
 			override:   allowOverride,
@@ -1079,6 +1081,8 @@ func (ctlr *Controller) processRouteConfigFromGlobalCM(es cisapiv1.ExtendedSpec,
 	)
 	for _, routeGroupKey := range deletedSpecs {
 		routeGroupsToBeProcessed[routeGroupKey] = struct{}{}
+		// routes are not managed by CIS anymore, so clear their admit status
+		ctlr.eraseRouteGroupAdmitStatus(routeGroupKey)
 		_ = ctlr.processRoutes(routeGroupKey, true)
 		if ctlr.resources.extdSpecMap[routeGroupKey].local == nil {
 			delete(ctlr.resources.extdSpecMap, routeGroupKey)
@@ -1100,15 +1104,12 @@ func (ctlr *Controller) processRouteConfigFromGlobalCM(es cisapiv1.ExtendedSpec,
 		}
 
 	}
-	//TODO: get bigipLabel from route resource or service address cr and get parition from specific bigip agent
-	//Phase1 getting partition from bigipconfig index 0
-	bigipLabel := BigIPLabel
-	bigipconfig := ctlr.getBIGIPConfig(bigipLabel)
 	for _, routeGroupKey := range modifiedSpecs {
 		routeGroupsToBeProcessed[routeGroupKey] = struct{}{}
 		_ = ctlr.processRoutes(routeGroupKey, true)
 		// deleting the bigip partition when partition is changes
 		if ctlr.resources.extdSpecMap[routeGroupKey].partition != newExtdSpecMap[routeGroupKey].partition {
+			bigipconfig := ctlr.getBIGIPConfig(ctlr.resources.getRouteGroupBigIpLabel(routeGroupKey))
 			if _, ok := ctlr.resources.bigIpMap[bigipconfig].ltmConfig[ctlr.resources.extdSpecMap[routeGroupKey].partition]; ok {
 				ctlr.resources.updatePartitionPriority(ctlr.resources.extdSpecMap[routeGroupKey].partition, 1, bigipconfig)
 			}
@@ -1370,16 +1371,18 @@ func getOperationalExtendedConfigCRSpecs(
 		if !reflect.DeepEqual(spec, newMap[routeGroupKey]) {
 			if routeGroupKey == defaultRouteGroupName {
 				//handle update to vserverName or partition in defaultRouteGroup
-				if spec.defaultrg.VServerName != newSpec.defaultrg.VServerName || spec.partition != newSpec.partition {
-					// Update to VServerName or override should trigger delete and recreation of object
+				if spec.defaultrg.VServerName != newSpec.defaultrg.VServerName || spec.partition != newSpec.partition ||
+					spec.defaultrg.BigIpLabel != newSpec.defaultrg.BigIpLabel {
+					// Update to VServerName, partition or bigIpLabel should trigger delete and recreation of object
 					modifiedSpecs = append(modifiedSpecs, routeGroupKey)
 				} else {
 					updatedSpecs = append(updatedSpecs, routeGroupKey)
 					updateMap[routeGroupKey] = true
 				}
 			} else {
-				if spec.global.VServerName != newSpec.global.VServerName || spec.override != newSpec.override || spec.partition != newSpec.partition {
-					// Update to VServerName or override should trigger delete and recreation of object
+				if spec.global.VServerName != newSpec.global.VServerName || spec.override != newSpec.override || spec.partition != newSpec.partition ||
+					spec.global.BigIpLabel != newSpec.global.BigIpLabel {
+					// Update to VServerName, override, partition or bigIpLabel should trigger delete and recreation of object
 					modifiedSpecs = append(modifiedSpecs, routeGroupKey)
 				} else {
 					updatedSpecs = append(updatedSpecs, routeGroupKey)
@@ -1549,7 +1552,7 @@ func (ctlr *Controller) updateRouteAdmitStatus(
 ) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Errorf("CIS recovered from the panic caused by route status update: %v\n")
+			log.Errorf("CIS recovered from the panic caused by route status update: %v\n", r)
 		}
 	}()
 	for retryCount := 0; retryCount < 3; retryCount++ {
@@ -1562,8 +1565,9 @@ func (ctlr *Controller) updateRouteAdmitStatus(
 		var routeStatusIngress []routeapi.RouteIngress
 		for _, routeIngress := range route.Status.Ingress {
 			if routeIngress.RouterName == F5RouterName {
+				// skip the update if the route already has the same admit status
 				for _, condition := range routeIngress.Conditions {
-					if condition.Status == status {
+					if condition.Status == status && condition.Reason == reason && condition.Message == message {
 						Admitted = true
 					}
 				}
@@ -1590,6 +1594,11 @@ func (ctlr *Controller) updateRouteAdmitStatus(
 		_, err := ctlr.clientsets.routeClientV1.Routes(route.ObjectMeta.Namespace).UpdateStatus(context.TODO(), route, metaV1.UpdateOptions{})
 		if err == nil {
 			log.Infof("Admitted Route -  %v", route.ObjectMeta.Name)
+			if status == v1.ConditionTrue {
+				ctlr.recordEvent(route, v1.EventTypeNormal, DeploySucceeded, "Route admitted by F5 BIG-IP")
+			} else {
+				ctlr.recordEvent(route, v1.EventTypeWarning, reason, message)
+			}
 			return
 		}
 		log.Errorf("Error while Updating Route Admit Status: %v\n", err)
@@ -1640,7 +1649,11 @@ func (ctlr *Controller) eraseRouteAdmitStatus(rscKey string) {
 	// Fetching the latest copy of route
 	route := ctlr.fetchRoute(rscKey)
 	if route == nil {
-		return
+		// Route is not watched by CIS anymore, fetch it from the API server
+		route = ctlr.fetchUnmonitoredRoute(rscKey)
+		if route == nil {
+			return
+		}
 	}
 	for i := 0; i < len(route.Status.Ingress); i++ {
 		if route.Status.Ingress[i].RouterName == F5RouterName {
@@ -1666,6 +1679,33 @@ func (ctlr *Controller) eraseRouteAdmitStatus(rscKey string) {
 	}
 }
 
+// fetchUnmonitoredRoute fetches the route which has left the managed route group from the API server
+func (ctlr *Controller) fetchUnmonitoredRoute(rscKey string) *routeapi.Route {
+	rscRef := strings.Split(rscKey, "/")
+	if len(rscRef) != 2 || ctlr.clientsets.routeClientV1 == nil {
+		return nil
+	}
+	route, err := ctlr.clientsets.routeClientV1.Routes(rscRef[0]).Get(context.TODO(), rscRef[1], metaV1.GetOptions{})
+	if err != nil {
+		log.Debugf("Route Not Found: %v: %v", rscKey, err)
+		return nil
+	}
+	return route
+}
+
+// eraseRouteGroupAdmitStatus removes the route admit status for all the routes of the route group
+func (ctlr *Controller) eraseRouteGroupAdmitStatus(routeGroup string) {
+	extdSpec, ok := ctlr.resources.extdSpecMap[routeGroup]
+	if !ok {
+		return
+	}
+	for _, namespace := range extdSpec.namespaces {
+		for _, route := range ctlr.getOrderedRoutes(namespace) {
+			go ctlr.eraseRouteAdmitStatus(fmt.Sprintf("%v/%v", route.Namespace, route.Name))
+		}
+	}
+}
+
 func (ctlr *Controller) fetchRoute(rscKey string) *routeapi.Route {
 	ns := strings.Split(rscKey, "/")[0]
 	nrInf, ok := ctlr.getNamespacedNativeInformer(ns)
@@ -16,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"net/http"
 	"time"
 )
 
@@ -127,10 +128,10 @@ var _ = Describe("Routes", func() {
 			foo := test.NewService("foo", "1", ns, "NodePort", fooPorts)
 			mockCtlr.addService(foo)
 			fooIps := []string{"10.1.1.1"}
-			fooEndpts := test.NewEndpoints(
+			fooEndpts := test.NewEndpointSlice(
 				"foo", "1", "node0", ns, fooIps, []string{},
 				convertSvcPortsToEndpointPorts(fooPorts))
-			mockCtlr.addEndpoints(fooEndpts)
+			mockCtlr.addEndpointSlice(fooEndpts)
 			mockCtlr.resources.invertedNamespaceLabelMap[ns] = ns
 
 			err := mockCtlr.processRoutes(ns, false)
@@ -200,6 +201,84 @@ var _ = Describe("Routes", func() {
 			route = mockCtlr.fetchRoute(rskey)
 			Expect(len(route.Status.Ingress)).To(BeEquivalentTo(0), "Incorrect route admit status")
 		})
+		It("Route Admit Status from post response", func() {
+			spec1 := routeapi.RouteSpec{
+				Host: "foo.com",
+				Path: "/foo",
+				To: routeapi.RouteTargetReference{
+					Kind: "Service",
+					Name: "foo",
+				},
+			}
+			route1 := test.NewRoute("route1", "1", "default", spec1, nil)
+			mockCtlr.addRoute(route1)
+			mockCtlr.clientsets.routeClientV1.Routes("default").Create(context.TODO(), route1, metav1.CreateOptions{})
+			rskey := fmt.Sprintf("%v/%v", route1.Namespace, route1.Name)
+			bigIpKey := BigIpKey{BigIpAddress: "10.8.3.11", BigIpLabel: "bigip1"}
+			respChan := make(chan *agentConfig, 1)
+			go mockCtlr.responseHandler(respChan)
+			time.Sleep(10 * time.Millisecond)
+			// failed tenants of an older request are not retried
+			mockCtlr.requestMap.Lock()
+			mockCtlr.requestMap.requestMap[bigIpKey] = requestMeta{id: 2}
+			mockCtlr.requestMap.Unlock()
+			getCondition := func() routeapi.RouteIngressCondition {
+				route := mockCtlr.fetchRoute(rskey)
+				for _, ingress := range route.Status.Ingress {
+					if ingress.RouterName == F5RouterName && len(ingress.Conditions) > 0 {
+						return ingress.Conditions[0]
+					}
+				}
+				return routeapi.RouteIngressCondition{}
+			}
+			newAgentConfig := func(code int, message string) *agentConfig {
+				cfg := &agentConfig{
+					id: 1,
+					as3Config: as3Config{
+						failedTenants:     make(map[string]struct{}),
+						tenantResponseMap: make(map[string]tenantResponse),
+					},
+					BigIpKey: bigIpKey,
+					reqMeta:  requestMeta{id: 1, partitionMap: map[string]map[string]string{"test": {rskey: Route}}},
+				}
+				if code != http.StatusOK {
+					cfg.as3Config.failedTenants["test"] = struct{}{}
+				}
+				cfg.as3Config.tenantResponseMap["test"] = tenantResponse{agentResponseCode: code, message: message}
+				return cfg
+			}
+
+			respChan <- newAgentConfig(http.StatusServiceUnavailable, "")
+			Eventually(getCondition, 5*time.Second, 50*time.Millisecond).Should(And(
+				HaveField("Status", v1.ConditionFalse), HaveField("Reason", DeployRetrying)))
+
+			respChan <- newAgentConfig(http.StatusUnprocessableEntity, "invalid pool")
+			Eventually(getCondition, 5*time.Second, 50*time.Millisecond).Should(And(
+				HaveField("Status", v1.ConditionFalse), HaveField("Reason", DeployFailed), HaveField("Message", "invalid pool")))
+
+			respChan <- newAgentConfig(http.StatusOK, "")
+			Eventually(getCondition, 5*time.Second, 50*time.Millisecond).Should(HaveField("Status", v1.ConditionTrue))
+			close(respChan)
+		})
+		It("Erase Route Admit Status of unmonitored route", func() {
+			spec1 := routeapi.RouteSpec{
+				Host: "foo.com",
+				Path: "/foo",
+				To: routeapi.RouteTargetReference{
+					Kind: "Service",
+					Name: "foo",
+				},
+			}
+			route1 := test.NewRoute("route1", "1", "default", spec1, nil)
+			route1.Status.Ingress = []routeapi.RouteIngress{{RouterName: F5RouterName, Host: "foo.com"}, {RouterName: "default", Host: "foo.com"}}
+			mockCtlr.clientsets.routeClientV1.Routes("default").Create(context.TODO(), route1, metav1.CreateOptions{})
+			// route is not present in the informer as it has left the route group
+			mockCtlr.eraseRouteAdmitStatus("default/route1")
+			route, err := mockCtlr.clientsets.routeClientV1.Routes("default").Get(context.TODO(), "route1", metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(route.Status.Ingress).To(HaveLen(1))
+			Expect(route.Status.Ingress[0].RouterName).To(Equal("default"))
+		})
 		It("Check Valid Route", func() {
 			var configCR *cisapiv1.DeployConfig
 			configSpec := cisapiv1.DeployConfigSpec{}
@@ -468,10 +547,10 @@ var _ = Describe("Routes", func() {
 					foo := test.NewService("foo", "1", namespace1, "NodePort", fooPorts)
 					mockCtlr.addService(foo)
 					fooIps := []string{"10.1.1.1"}
-					fooEndpts := test.NewEndpoints(
+					fooEndpts := test.NewEndpointSlice(
 						"foo", "1", "node0", namespace1, fooIps, []string{},
 						convertSvcPortsToEndpointPorts(fooPorts))
-					mockCtlr.addEndpoints(fooEndpts)
+					mockCtlr.addEndpointSlice(fooEndpts)
 
 					//Add new Route
 					annotation1 := make(map[string]string)
@@ -485,10 +564,10 @@ var _ = Describe("Routes", func() {
 					bar := test.NewService("bar", "1", namespace2, "NodePort", fooPorts)
 					mockCtlr.addService(bar)
 					barIPs := []string{"10.1.1.1"}
-					barEndpts := test.NewEndpoints(
+					barEndpts := test.NewEndpointSlice(
 						"bar", "1", "node0", namespace2, barIPs, []string{},
 						convertSvcPortsToEndpointPorts(fooPorts))
-					mockCtlr.addEndpoints(barEndpts)
+					mockCtlr.addEndpointSlice(barEndpts)
 
 					//Add new Route
 					annotation2 := make(map[string]string)
@@ -847,10 +926,10 @@ var _ = Describe("Routes", func() {
 			foo := test.NewService("foo", "1", routeGroup, "NodePort", fooPorts)
 			mockCtlr.addService(foo)
 			fooIps := []string{"10.1.1.1"}
-			fooEndpts := test.NewEndpoints(
+			fooEndpts := test.NewEndpointSlice(
 				"foo", "1", "node0", routeGroup, fooIps, []string{},
 				convertSvcPortsToEndpointPorts(fooPorts))
-			mockCtlr.addEndpoints(fooEndpts)
+			mockCtlr.addEndpointSlice(fooEndpts)
 			//Domain Based Route
 			annotation1 := make(map[string]string)
 			annotation1[F5ServerSslProfileAnnotation] = "/Common/serverssl"
@@ -990,10 +1069,10 @@ var _ = Describe("Routes", func() {
 			foo := test.NewService("bar", "1", routeGroup, "NodePort", fooPorts)
 			mockCtlr.addService(foo)
 			fooIps := []string{"10.1.1.1"}
-			fooEndpts := test.NewEndpoints(
+			fooEndpts := test.NewEndpointSlice(
 				"foo", "1", "node0", routeGroup, fooIps, []string{},
 				convertSvcPortsToEndpointPorts(fooPorts))
-			mockCtlr.addEndpoints(fooEndpts)
+			mockCtlr.addEndpointSlice(fooEndpts)
 			route3 := test.NewRoute("route1", "1", routeGroup, spec2, nil)
 			mockCtlr.addRoute(route3)
 			// server ssl profile missing in policy. invalid route
@@ -1349,10 +1428,10 @@ var _ = Describe("Routes", func() {
 			foo := test.NewService("foo", "1", ns, "NodePort", fooPorts)
 			mockCtlr.addService(foo)
 			fooIps := []string{"10.1.1.1"}
-			fooEndpts := test.NewEndpoints(
+			fooEndpts := test.NewEndpointSlice(
 				"foo", "1", "node0", ns, fooIps, []string{},
 				convertSvcPortsToEndpointPorts(fooPorts))
-			mockCtlr.addEndpoints(fooEndpts)
+			mockCtlr.addEndpointSlice(fooEndpts)
 			mockCtlr.resources.invertedNamespaceLabelMap[ns] = ns
 
 			err := mockCtlr.processRoutes(ns, false)
@@ -1911,10 +1990,10 @@ var _ = Describe("Routes", func() {
 			foo := test.NewService("foo", "1", routeGroup, "NodePort", fooPorts)
 			mockCtlr.addService(foo)
 			fooIps := []string{"10.1.1.1"}
-			fooEndpts := test.NewEndpoints(
+			fooEndpts := test.NewEndpointSlice(
 				"foo", "1", "node0", routeGroup, fooIps, []string{},
 				convertSvcPortsToEndpointPorts(fooPorts))
-			mockCtlr.addEndpoints(fooEndpts)
+			mockCtlr.addEndpointSlice(fooEndpts)
 			annotations := make(map[string]string)
 			annotations["virtual-server.f5.com/balance"] = "least-connections-node"
 			annotations[F5ServerSslProfileAnnotation] = "/Common/serverssl"
@@ -2030,10 +2109,10 @@ var _ = Describe("Routes", func() {
 			mockCtlr.addService(foo)
 
 			fooIps := []string{"10.1.1.1"}
-			fooEndpts := test.NewEndpoints(
+			fooEndpts := test.NewEndpointSlice(
 				"foo", "1", "node0", routeGroup, fooIps, []string{},
 				convertSvcPortsToEndpointPorts(fooPorts))
-			mockCtlr.addEndpoints(fooEndpts)
+			mockCtlr.addEndpointSlice(fooEndpts)
 			annotations := make(map[string]string)
 			annotations[F5ClientSslProfileAnnotation] = "/Common/clientssl"
 			route1 := test.NewRoute("route1", "1", routeGroup, spec1, annotations)
@@ -2434,7 +2513,7 @@ var _ = Describe("Multi Cluster with Routes", func() {
 		It("Process Route with multi cluster annotation with multicluster config", func() {
 			mockCtlr.multiClusterMode = PrimaryCIS
 			mockCtlr.processGlobalDeployConfigCR()
-			restClient := mockCtlr.multiClusterConfigs.ClusterConfigs["cluster3"].KubeClient.CoreV1().RESTClient()
+			kubeClient := mockCtlr.multiClusterConfigs.ClusterConfigs["cluster3"].KubeClient
 			clusterName := "cluster3"
 			// Setup informers with namespaces which are watched by CIS
 			for namespace := range mockCtlr.namespaces {
@@ -2443,7 +2522,7 @@ var _ = Describe("Multi Cluster with Routes", func() {
 					mockCtlr.multiClusterPoolInformers[clusterName] = make(map[string]*MultiClusterPoolInformer)
 				}
 				if _, found := mockCtlr.multiClusterPoolInformers[clusterName][namespace]; !found {
-					poolInfr := mockCtlr.newMultiClusterNamespacedPoolInformer(namespace, clusterName, restClient)
+					poolInfr := mockCtlr.newMultiClusterNamespacedPoolInformer(namespace, clusterName, kubeClient)
 					mockCtlr.addMultiClusterPoolEventHandlers(poolInfr)
 					mockCtlr.multiClusterPoolInformers[clusterName][namespace] = poolInfr
 				}
@@ -2774,7 +2853,7 @@ var _ = Describe("Multi Cluster with CRD", func() {
 					},
 				},
 			)
-			restClient := mockCtlr.multiClusterConfigs.ClusterConfigs["cluster3"].KubeClient.CoreV1().RESTClient()
+			kubeClient := mockCtlr.multiClusterConfigs.ClusterConfigs["cluster3"].KubeClient
 			clusterName := "cluster3"
 			// Setup informers with namespaces which are watched by CIS
 			for namespace := range mockCtlr.namespaces {
@@ -2783,7 +2862,7 @@ var _ = Describe("Multi Cluster with CRD", func() {
 					mockCtlr.multiClusterPoolInformers[clusterName] = make(map[string]*MultiClusterPoolInformer)
 				}
 				if _, found := mockCtlr.multiClusterPoolInformers[clusterName][namespace]; !found {
-					poolInfr := mockCtlr.newMultiClusterNamespacedPoolInformer(namespace, clusterName, restClient)
+					poolInfr := mockCtlr.newMultiClusterNamespacedPoolInformer(namespace, clusterName, kubeClient)
 					mockCtlr.addMultiClusterPoolEventHandlers(poolInfr)
 					mockCtlr.multiClusterPoolInformers[clusterName][namespace] = poolInfr
 				}
@@ -2876,7 +2955,7 @@ var _ = Describe("Multi Cluster with CRD", func() {
 					},
 				},
 			)
-			restClient := mockCtlr.multiClusterConfigs.ClusterConfigs["cluster3"].KubeClient.CoreV1().RESTClient()
+			kubeClient := mockCtlr.multiClusterConfigs.ClusterConfigs["cluster3"].KubeClient
 			clusterName := "cluster3"
 			// Setup informers with namespaces which are watched by CIS
 			for namespace := range mockCtlr.namespaces {
@@ -2885,7 +2964,7 @@ var _ = Describe("Multi Cluster with CRD", func() {
 					mockCtlr.multiClusterPoolInformers[clusterName] = make(map[string]*MultiClusterPoolInformer)
 				}
 				if _, found := mockCtlr.multiClusterPoolInformers[clusterName][namespace]; !found {
-					poolInfr := mockCtlr.newMultiClusterNamespacedPoolInformer(namespace, clusterName, restClient)
+					poolInfr := mockCtlr.newMultiClusterNamespacedPoolInformer(namespace, clusterName, kubeClient)
 					mockCtlr.addMultiClusterPoolEventHandlers(poolInfr)
 					mockCtlr.multiClusterPoolInformers[clusterName][namespace] = poolInfr
 				}
@@ -64,6 +64,10 @@ func (ctlr *Controller) ProcessNodeUpdate(obj interface{}, clusterName string) {
 						ctlr.UpdatePoolMembersForNodeUpdate(clusterName)
 					}
 				}
+				// FDB records of the VXLAN tunnel are updated on node updates
+				if ctlr.TunnelName != "" {
+					ctlr.resourceQueue.Add(&rqKey{kind: NodeUpdate})
+				}
 			}
 		}
 	} else {
@@ -142,6 +146,9 @@ func (ctlr *Controller) getNodes(
 				for k, v := range node.ObjectMeta.Labels {
 					n.Labels[k] = v
 				}
+				if ctlr.TunnelName != "" {
+					setNodeVtep(&n, &node)
+				}
 				watchedNodes = append(watchedNodes, n)
 			}
 		}
@@ -170,13 +177,21 @@ func (ctlr *Controller) getNodesWithLabel(
 	return nodes
 }
 
-func ciliumPodCidr(annotation map[string]string) string {
-	if subnet, ok := annotation[CiliumK8sNodeSubnetAnnotation13]; ok {
-		return subnet
-	} else if subnet, ok := annotation[CiliumK8sNodeSubnetAnnotation12]; ok {
-		return subnet
+// ciliumPodCidrs returns the IPv4 and IPv6 pod cidrs of the node from the cilium annotations
+func ciliumPodCidrs(annotation map[string]string) []string {
+	var podCidrs []string
+	for _, annotations := range [][]string{
+		{CiliumK8sNodeSubnetAnnotation13, CiliumK8sNodeSubnetAnnotation12},
+		{CiliumK8sNodeSubnetV6Annotation13, CiliumK8sNodeSubnetV6Annotation12},
+	} {
+		for _, ann := range annotations {
+			if subnet, ok := annotation[ann]; ok {
+				podCidrs = append(podCidrs, subnet)
+				break
+			}
+		}
 	}
-	return ""
+	return podCidrs
 }
 
 func (ctlr *Controller) processStaticRouteUpdate() {
@@ -193,6 +208,10 @@ func (ctlr *Controller) processStaticRouteUpdate() {
 		log.Debugf("Processing Node Updates for static routes")
 		// reset the route store to handle the deleted nodes
 		staticRouteMap := make(map[networkmanager.StaticRouteConfig]networkmanager.L3Forward)
+		var calicoNodeBlocks map[string][]string
+		if ctlr.OrchestrationCNI == CALICO {
+			calicoNodeBlocks = ctlr.getCalicoNodeBlocks()
+		}
 		for _, obj := range nodes {
 			node := obj.(*v1.Node)
 			// Ignore the Nodes with status NotReady
@@ -206,10 +225,8 @@ func (ctlr *Controller) processStaticRouteUpdate() {
 			if notExecutable == true {
 				continue
 			}
-			l3Forward := networkmanager.L3Forward{
-				Config: networkmanager.StaticRouteConfig{},
-				VLANs:  []int{},
-			}
+			var podSubnets, nodeIPs []string
+			var err error
 			// For ovn-k8s get pod subnet and node ip from annotation
 			if ctlr.OrchestrationCNI == OVN_K8S {
 				annotations := node.Annotations
@@ -217,100 +234,101 @@ func (ctlr *Controller) processStaticRouteUpdate() {
 					log.Warningf("Node subnet annotation %v not found on node %v static route not added", OVNK8sNodeSubnetAnnotation, node.Name)
 					continue
 				} else {
-					nodesubnet, err := parseNodeSubnet(nodeSubnetAnn, node.Name)
+					podSubnets, err = parseNodeSubnets(nodeSubnetAnn, node.Name)
 					if err != nil {
 						log.Warningf("Node subnet annotation %v not properly configured for node %v:%v", OVNK8sNodeSubnetAnnotation, node.Name, err)
 						continue
 					}
-					l3Forward.Config.Destination = nodesubnet
 				}
 				if ctlr.StaticRouteNodeCIDR != "" {
-					_, nodenetwork, err := net.ParseCIDR(ctlr.StaticRouteNodeCIDR)
+					nodeNetworks, err := parseNodeNetworks(ctlr.StaticRouteNodeCIDR)
 					if err != nil {
 						log.Errorf("Unable to parse cidr %v with error %v", ctlr.StaticRouteNodeCIDR, err)
 						continue
-					} else {
-						var hostaddresses string
-						var ok bool
-						var nodeIP string
-						var err error
-						if hostaddresses, ok = annotations[OVNK8sNodeIPAnnotation2]; !ok {
-							//For ocp 4.14 and above check for new annotation
-							if hostaddresses, ok = annotations[OvnK8sNodeIPAnnotation3]; !ok {
-								log.Warningf("Host addresses annotation %v not found on node %v static route not added", OVNK8sNodeIPAnnotation2, node.Name)
-								continue
-							} else {
-								nodeIP, err = parseHostCIDRS(hostaddresses, nodenetwork)
-								if err != nil {
-									log.Warningf("Node IP annotation %v not properly configured for node %v:%v", OvnK8sNodeIPAnnotation3, node.Name, err)
-									continue
-								}
-								l3Forward.Config.Gateway = nodeIP
-								l3Forward.Config.L3ForwardType = networkmanager.L3RouteGateway
-								l3Forward.Name = fmt.Sprintf("%v/%v/%v", ctlr.ControllerIdentifier, node.Name, nodeIP)
-							}
-						} else {
-							nodeIP, err = parseHostAddresses(hostaddresses, nodenetwork)
-							if err != nil {
-								log.Warningf("Node IP annotation %v not properly configured for node %v:%v", OVNK8sNodeIPAnnotation2, node.Name, err)
-								continue
-							}
-							l3Forward.Config.Gateway = nodeIP
-							l3Forward.Config.L3ForwardType = networkmanager.L3RouteGateway
-							l3Forward.Name = fmt.Sprintf("%v/%v/%v", ctlr.ControllerIdentifier, node.Name, nodeIP)
+					}
+					if hostaddresses, ok := annotations[OVNK8sNodeIPAnnotation2]; ok {
+						nodeIPs, err = parseHostAddresses(hostaddresses, nodeNetworks)
+						if err != nil {
+							log.Warningf("Node IP annotation %v not properly configured for node %v:%v", OVNK8sNodeIPAnnotation2, node.Name, err)
+							continue
+						}
+					} else if hostcidrs, ok := annotations[OvnK8sNodeIPAnnotation3]; ok {
+						//For ocp 4.14 and above check for new annotation
+						nodeIPs, err = parseHostCIDRS(hostcidrs, nodeNetworks)
+						if err != nil {
+							log.Warningf("Node IP annotation %v not properly configured for node %v:%v", OvnK8sNodeIPAnnotation3, node.Name, err)
+							continue
 						}
+					} else {
+						log.Warningf("Host addresses annotation %v not found on node %v static route not added", OVNK8sNodeIPAnnotation2, node.Name)
+						continue
 					}
 				} else {
 					if nodeIPAnn, ok := annotations[OVNK8sNodeIPAnnotation]; !ok {
 						log.Warningf("Node IP annotation %v not found on node %v static route not added", OVNK8sNodeSubnetAnnotation, node.Name)
 						continue
 					} else {
-						nodeIP, err := parseNodeIP(nodeIPAnn, node.Name)
+						nodeIPs, err = parseNodeIPs(nodeIPAnn, node.Name)
 						if err != nil {
 							log.Warningf("Node IP annotation %v not properly configured for node %v:%v", OVNK8sNodeIPAnnotation, node.Name, err)
 							continue
 						}
-						l3Forward.Config.Gateway = nodeIP
-						l3Forward.Config.L3ForwardType = networkmanager.L3RouteGateway
-						l3Forward.Name = fmt.Sprintf("%v/%v/%v", ctlr.ControllerIdentifier, node.Name, nodeIP)
 					}
 				}
 			} else if ctlr.OrchestrationCNI == CILIUM {
-				nodesubnet := ciliumPodCidr(node.ObjectMeta.Annotations)
-				if nodesubnet == "" {
+				podSubnets = ciliumPodCidrs(node.ObjectMeta.Annotations)
+				if len(podSubnets) == 0 {
 					log.Warningf("Cilium node podCIDR annotation not found on node %v, node has spec.podCIDR ?", node.Name)
 					continue
-				} else {
-					l3Forward.Config.Destination = nodesubnet
-					nodeAddrs := node.Status.Addresses
-					for _, addr := range nodeAddrs {
-						if addr.Type == addrType {
-							l3Forward.Config.Gateway = addr.Address
-							l3Forward.Config.L3ForwardType = networkmanager.L3RouteGateway
-							l3Forward.Name = fmt.Sprintf("%v/%v/%v", ctlr.ControllerIdentifier, node.Name, addr.Address)
-						}
-					}
-
 				}
+				nodeIPs = getNodeAddresses(node, addrType)
+			} else if ctlr.OrchestrationCNI == CALICO {
+				// Calico doesn't set the node spec podCIDR, the IPAM blocks affine to the node are used instead
+				podSubnets = calicoNodeBlocks[node.Name]
+				if len(podSubnets) == 0 {
+					log.Debugf("Calico IPAM blocks are not found for node %v so not adding the static route for node", node.Name)
+					continue
+				}
+				nodeIPs = getNodeAddresses(node, addrType)
 			} else {
 				//For k8s CNI like flannel, antrea etc we can get subnet from node spec
-				podCIDR := node.Spec.PodCIDR
-				if podCIDR != "" {
-					l3Forward.Config.Destination = podCIDR
-					nodeAddrs := node.Status.Addresses
-					for _, addr := range nodeAddrs {
-						if addr.Type == addrType {
-							l3Forward.Config.Gateway = addr.Address
-							l3Forward.Config.L3ForwardType = networkmanager.L3RouteGateway
-							l3Forward.Name = fmt.Sprintf("%v/%v/%v", ctlr.ControllerIdentifier, node.Name, addr.Address)
-						}
-					}
-				} else {
+				podSubnets = node.Spec.PodCIDRs
+				if len(podSubnets) == 0 && node.Spec.PodCIDR != "" {
+					podSubnets = []string{node.Spec.PodCIDR}
+				}
+				if len(podSubnets) == 0 {
 					log.Debugf("podCIDR is not found on node %v so not adding the static route for node", node.Name)
 					continue
 				}
+				nodeIPs = getNodeAddresses(node, addrType)
+			}
+			// route each pod subnet through the node address of the same IP family
+			for _, podSubnet := range podSubnets {
+				if _, _, err := net.ParseCIDR(podSubnet); err != nil {
+					log.Warningf("Invalid podCIDR %v on node %v: %v", podSubnet, node.Name, err)
+					continue
+				}
+				nodeIP := getSameFamilyIP(podSubnet, nodeIPs)
+				if nodeIP == "" {
+					log.Warningf("No node address of the podCIDR %v IP family found on node %v static route not added", podSubnet, node.Name)
+					continue
+				}
+				name := fmt.Sprintf("%v/%v/%v", ctlr.ControllerIdentifier, node.Name, nodeIP)
+				if ctlr.OrchestrationCNI == CALICO {
+					// a node may own multiple IPAM blocks of the same IP family
+					name = fmt.Sprintf("%v/%v/%v", ctlr.ControllerIdentifier, node.Name, podSubnet)
+				}
+				l3Forward := networkmanager.L3Forward{
+					Name: name,
+					Config: networkmanager.StaticRouteConfig{
+						Gateway:       nodeIP,
+						Destination:   podSubnet,
+						L3ForwardType: networkmanager.L3RouteGateway,
+					},
+					VLANs: []int{},
+				}
+				staticRouteMap[l3Forward.Config] = l3Forward
 			}
-			staticRouteMap[l3Forward.Config] = l3Forward
 		}
 		if len(staticRouteMap) > 0 {
 			routeStore := make(networkmanager.RouteStore)
@@ -328,75 +346,137 @@ func (ctlr *Controller) processStaticRouteUpdate() {
 	}
 }
 
-func parseNodeSubnet(ann, nodeName string) (string, error) {
+// getNodeAddresses returns the node addresses of the given type
+func getNodeAddresses(node *v1.Node, addrType v1.NodeAddressType) []string {
+	var nodeIPs []string
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == addrType {
+			nodeIPs = append(nodeIPs, addr.Address)
+		}
+	}
+	return nodeIPs
+}
+
+// isIPv6 returns true if the address or cidr belongs to the IPv6 family
+func isIPv6(addr string) bool {
+	ip := net.ParseIP(strings.Split(addr, "/")[0])
+	return ip != nil && ip.To4() == nil
+}
+
+// getSameFamilyIP returns the first ip of the same IP family as the cidr
+func getSameFamilyIP(cidr string, ips []string) string {
+	for _, ip := range ips {
+		if net.ParseIP(ip) != nil && isIPv6(ip) == isIPv6(cidr) {
+			return ip
+		}
+	}
+	return ""
+}
+
+// parseNodeNetworks parses the comma separated node network cidrs
+func parseNodeNetworks(cidrs string) ([]*net.IPNet, error) {
+	var nodeNetworks []*net.IPNet
+	for _, cidr := range strings.Split(cidrs, ",") {
+		_, nodeNetwork, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, err
+		}
+		nodeNetworks = append(nodeNetworks, nodeNetwork)
+	}
+	return nodeNetworks, nil
+}
+
+func parseNodeSubnets(ann, nodeName string) ([]string, error) {
 	var subnetDict map[string]interface{}
 	json.Unmarshal([]byte(ann), &subnetDict)
 	if nodeSubnet, ok := subnetDict["default"]; ok {
 		switch nodeSubnetObj := nodeSubnet.(type) {
 		case string:
-			return nodeSubnet.(string), nil
+			return []string{nodeSubnetObj}, nil
 		case []interface{}:
+			var subnets []string
 			for _, subnet := range nodeSubnetObj {
-				ip, _, err := net.ParseCIDR(subnet.(string))
-				if err != nil {
+				subnetStr, _ := subnet.(string)
+				if _, _, err := net.ParseCIDR(subnetStr); err != nil {
 					log.Errorf("Unable to parse cidr for subnet %v with err %v", subnet, err)
 				} else {
-					//check for ipv4 address
-					if nil != ip.To4() {
-						return subnet.(string), nil
-					}
+					subnets = append(subnets, subnetStr)
 				}
 			}
+			if len(subnets) > 0 {
+				return subnets, nil
+			}
 		default:
-			return "", fmt.Errorf("Unsupported annotation format")
+			return nil, fmt.Errorf("Unsupported annotation format")
 		}
 	}
 	err := fmt.Errorf("%s annotation for "+
 		"node '%s' has invalid format; cannot validate node subnet. "+
 		"Should be of the form: '{\"default\":\"<node-subnet>\"}'", OVNK8sNodeSubnetAnnotation, nodeName)
-	return "", err
+	return nil, err
 }
 
-func parseNodeIP(ann, nodeName string) (string, error) {
+func parseNodeIPs(ann, nodeName string) ([]string, error) {
 	var IPDict map[string]interface{}
 	json.Unmarshal([]byte(ann), &IPDict)
-	if IP, ok := IPDict["ipv4"]; ok {
-		ipmask := IP.(string)
-		nodeip := strings.Split(ipmask, "/")[0]
-		return nodeip, nil
+	var nodeIPs []string
+	for _, family := range []string{"ipv4", "ipv6"} {
+		if IP, ok := IPDict[family].(string); ok {
+			nodeIPs = append(nodeIPs, strings.Split(IP, "/")[0])
+		}
+	}
+	if len(nodeIPs) > 0 {
+		return nodeIPs, nil
 	}
 	err := fmt.Errorf("%s annotation for "+
 		"node '%s' has invalid format; cannot validate node IP. "+
 		"Should be of the form: '{\"ipv4\":\"<node-ip>\"}'", OVNK8sNodeIPAnnotation, nodeName)
-	return "", err
+	return nil, err
 }
 
-func parseHostAddresses(ann string, nodenetwork *net.IPNet) (string, error) {
+func networksContain(nodeNetworks []*net.IPNet, ip net.IP) bool {
+	for _, nodeNetwork := range nodeNetworks {
+		if nodeNetwork.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseHostAddresses(ann string, nodeNetworks []*net.IPNet) ([]string, error) {
 	var hostaddresses []string
 	json.Unmarshal([]byte(ann), &hostaddresses)
+	var nodeIPs []string
 	for _, IP := range hostaddresses {
 		ip := net.ParseIP(IP)
-		if nodenetwork.Contains(ip) {
-			return ip.String(), nil
+		if ip != nil && networksContain(nodeNetworks, ip) {
+			nodeIPs = append(nodeIPs, ip.String())
 		}
 	}
-	err := fmt.Errorf("Cannot get nodeip from %s within nodenetwork %v", OVNK8sNodeIPAnnotation2, nodenetwork)
-	return "", err
+	if len(nodeIPs) > 0 {
+		return nodeIPs, nil
+	}
+	err := fmt.Errorf("Cannot get nodeip from %s within nodenetwork %v", OVNK8sNodeIPAnnotation2, nodeNetworks)
+	return nil, err
 }
 
-func parseHostCIDRS(ann string, nodenetwork *net.IPNet) (string, error) {
+func parseHostCIDRS(ann string, nodeNetworks []*net.IPNet) ([]string, error) {
 	var hostcidrs []string
 	json.Unmarshal([]byte(ann), &hostcidrs)
+	var nodeIPs []string
 	for _, cidr := range hostcidrs {
 		ip, _, err := net.ParseCIDR(cidr)
 		if err != nil {
 			log.Errorf("Unable to parse cidr %v with error %v", cidr, err)
 		} else {
-			if nodenetwork.Contains(ip) {
-				return ip.String(), nil
+			if networksContain(nodeNetworks, ip) {
+				nodeIPs = append(nodeIPs, ip.String())
 			}
 		}
 	}
-	err := fmt.Errorf("Cannot get nodeip from %s within nodenetwork %v", OvnK8sNodeIPAnnotation3, nodenetwork)
-	return "", err
+	if len(nodeIPs) > 0 {
+		return nodeIPs, nil
+	}
+	err := fmt.Errorf("Cannot get nodeip from %s within nodenetwork %v", OvnK8sNodeIPAnnotation3, nodeNetworks)
+	return nil, err
 }
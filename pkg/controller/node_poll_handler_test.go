@@ -7,6 +7,9 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 )
 
@@ -322,6 +325,182 @@ var _ = Describe("Node Poller Handler", func() {
 		Expect(l3Forward.Config.Gateway).To(Equal("1.2.3.4"))
 	})
 
+	It("Dual-stack static routes", func() {
+		nodeInf := mockCtlr.getNodeInformer("")
+		mockCtlr.multiClusterNodeInformers[""] = &nodeInf
+		mockCtlr.addNodeEventUpdateHandler(&nodeInf)
+		mockCtlr.UseNodeInternal = true
+		mockCtlr.StaticRoutingMode = true
+		mockCtlr.ControllerIdentifier = "cluster-1"
+		networkManager = networkmanager.NewNetworkManager(mockCtlr.CMTokenManager, "")
+		networkManager.NetworkChan = make(chan *networkmanager.NetworkConfigRequest, 10)
+		networkManager.DeviceMap["10.8.3.11"] = "dummy-id"
+		networkManager.L3ForwardStore.InstanceStaticRoutes["dummy-id"] = networkmanager.StaticRouteMap{}
+		mockCtlr.networkManager = networkManager
+		mockCtlr.resources = NewResourceStore()
+		bigipconfig := cisapiv1.BigIpConfig{
+			BigIpLabel:       "bigip1",
+			BigIpAddress:     "10.8.3.11",
+			DefaultPartition: "test",
+		}
+		ltmConfig := make(map[string]*PartitionConfig, 0)
+		ltmConfig["test"] = &PartitionConfig{}
+		mockCtlr.resources.bigIpMap[bigipconfig] = BigIpResourceConfig{ltmConfig: ltmConfig, gtmConfig: make(GTMConfig)}
+
+		node := test.NewNode("worker1", "1", false, []v1.NodeAddress{
+			{Type: v1.NodeInternalIP, Address: "1.2.3.4"},
+			{Type: v1.NodeInternalIP, Address: "fd00:10::4"},
+		}, nil, nil)
+		node.Spec.PodCIDR = "10.244.0.0/24"
+		node.Spec.PodCIDRs = []string{"10.244.0.0/24", "fd00:244::/64"}
+		mockCtlr.addNode(node)
+
+		getRoutes := func() map[string]string {
+			routes := make(map[string]string)
+			for len(networkManager.NetworkChan) > 0 {
+				req := <-networkManager.NetworkChan
+				Expect(req.Action).To(Equal(networkmanager.Create))
+				l3Forward := req.NetworkConfig.(networkmanager.L3Forward)
+				Expect(l3Forward.Name).To(Equal("cluster-1/worker1/" + l3Forward.Config.Gateway))
+				routes[l3Forward.Config.Destination] = l3Forward.Config.Gateway
+			}
+			return routes
+		}
+
+		// k8s CNI with podCIDRs of both families
+		mockCtlr.processStaticRouteUpdate()
+		Expect(getRoutes()).To(Equal(map[string]string{"10.244.0.0/24": "1.2.3.4", "fd00:244::/64": "fd00:10::4"}))
+
+		// IPv6 podCIDR without IPv6 node address
+		node.Status.Addresses = []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "1.2.3.4"}}
+		mockCtlr.updateStatusNode(node, "")
+		mockCtlr.processStaticRouteUpdate()
+		Expect(getRoutes()).To(Equal(map[string]string{"10.244.0.0/24": "1.2.3.4"}))
+
+		// IPv6 only cluster
+		node.Status.Addresses = []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "fd00:10::4"}}
+		node.Spec.PodCIDR = "fd00:244::/64"
+		node.Spec.PodCIDRs = nil
+		mockCtlr.updateStatusNode(node, "")
+		mockCtlr.processStaticRouteUpdate()
+		Expect(getRoutes()).To(Equal(map[string]string{"fd00:244::/64": "fd00:10::4"}))
+
+		// cilium ipv4 and ipv6 pod cidr annotations
+		mockCtlr.OrchestrationCNI = CILIUM
+		node.Status.Addresses = []v1.NodeAddress{
+			{Type: v1.NodeInternalIP, Address: "1.2.3.4"},
+			{Type: v1.NodeInternalIP, Address: "fd00:10::4"},
+		}
+		node.Annotations = map[string]string{
+			CiliumK8sNodeSubnetAnnotation13:   "10.244.1.0/24",
+			CiliumK8sNodeSubnetV6Annotation12: "fd00:245::/64",
+		}
+		mockCtlr.updateStatusNode(node, "")
+		mockCtlr.processStaticRouteUpdate()
+		Expect(getRoutes()).To(Equal(map[string]string{"10.244.1.0/24": "1.2.3.4", "fd00:245::/64": "fd00:10::4"}))
+
+		// ovn-k8s node subnets and primary interface addresses
+		mockCtlr.OrchestrationCNI = OVN_K8S
+		node.Annotations = map[string]string{
+			OVNK8sNodeSubnetAnnotation: `{"default":["10.244.2.0/24","fd00:246::/64"]}`,
+			OVNK8sNodeIPAnnotation:     `{"ipv4":"10.8.0.4/24","ipv6":"fd00:8::4/64"}`,
+		}
+		mockCtlr.updateNode(node, "")
+		mockCtlr.processStaticRouteUpdate()
+		Expect(getRoutes()).To(Equal(map[string]string{"10.244.2.0/24": "10.8.0.4", "fd00:246::/64": "fd00:8::4"}))
+
+		// ovn-k8s host cidrs within the node network cidrs
+		mockCtlr.StaticRouteNodeCIDR = "10.9.0.0/16, fd00:9::/64"
+		node.Annotations[OvnK8sNodeIPAnnotation3] = `["10.8.0.4/24","10.9.0.4/16","fd00:8::4/64","fd00:9::4/64"]`
+		mockCtlr.updateNode(node, "")
+		mockCtlr.processStaticRouteUpdate()
+		Expect(getRoutes()).To(Equal(map[string]string{"10.244.2.0/24": "10.9.0.4", "fd00:246::/64": "fd00:9::4"}))
+	})
+
+	It("Calico static routes", func() {
+		nodeInf := mockCtlr.getNodeInformer("")
+		mockCtlr.multiClusterNodeInformers[""] = &nodeInf
+		mockCtlr.UseNodeInternal = true
+		mockCtlr.StaticRoutingMode = true
+		mockCtlr.PoolMemberType = Cluster
+		mockCtlr.OrchestrationCNI = CALICO
+		mockCtlr.ControllerIdentifier = "cluster-1"
+		mockCtlr.clientsets.dynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		networkManager = networkmanager.NewNetworkManager(mockCtlr.CMTokenManager, "")
+		networkManager.NetworkChan = make(chan *networkmanager.NetworkConfigRequest, 10)
+		networkManager.DeviceMap["10.8.3.11"] = "dummy-id"
+		networkManager.L3ForwardStore.InstanceStaticRoutes["dummy-id"] = networkmanager.StaticRouteMap{}
+		mockCtlr.networkManager = networkManager
+		mockCtlr.resources = NewResourceStore()
+		bigipconfig := cisapiv1.BigIpConfig{
+			BigIpLabel:       "bigip1",
+			BigIpAddress:     "10.8.3.11",
+			DefaultPartition: "test",
+		}
+		ltmConfig := make(map[string]*PartitionConfig, 0)
+		ltmConfig["test"] = &PartitionConfig{}
+		mockCtlr.resources.bigIpMap[bigipconfig] = BigIpResourceConfig{ltmConfig: ltmConfig, gtmConfig: make(GTMConfig)}
+		Expect(mockCtlr.setupInformers()).To(Succeed())
+		Expect(mockCtlr.calicoInformer).NotTo(BeNil())
+
+		for name, addr := range map[string]string{"worker1": "1.2.3.4", "worker2": "1.2.3.5"} {
+			mockCtlr.addNode(test.NewNode(name, "1", false,
+				[]v1.NodeAddress{{Type: v1.NodeInternalIP, Address: addr}}, nil, nil))
+		}
+		newBlockAffinity := func(name, node, cidr, state string) *unstructured.Unstructured {
+			return &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "crd.projectcalico.org/v1",
+				"kind":       "BlockAffinity",
+				"metadata":   map[string]interface{}{"name": name},
+				"spec": map[string]interface{}{
+					"node":    node,
+					"cidr":    cidr,
+					"state":   state,
+					"deleted": "false",
+				},
+			}}
+		}
+		getRoutes := func() map[string]networkmanager.L3Forward {
+			routes := make(map[string]networkmanager.L3Forward)
+			for len(networkManager.NetworkChan) > 0 {
+				req := <-networkManager.NetworkChan
+				l3Forward := req.NetworkConfig.(networkmanager.L3Forward)
+				if req.Action == networkmanager.Create {
+					routes[l3Forward.Config.Destination] = l3Forward
+				}
+			}
+			return routes
+		}
+
+		// nodes without IPAM blocks
+		mockCtlr.processStaticRouteUpdate()
+		Expect(getRoutes()).To(BeEmpty())
+
+		// a route for each confirmed block
+		store := mockCtlr.calicoInformer.blockAffinityInformer.GetStore()
+		store.Add(newBlockAffinity("worker1-10-244-1-0-26", "worker1", "10.244.1.0/26", CalicoBlockAffinityConfirmed))
+		store.Add(newBlockAffinity("worker1-10-244-1-64-26", "worker1", "10.244.1.64/26", CalicoBlockAffinityConfirmed))
+		store.Add(newBlockAffinity("worker2-10-244-2-0-26", "worker2", "10.244.2.0/26", "pending"))
+		Expect(mockCtlr.getCalicoNodeBlocks()).To(Equal(map[string][]string{"worker1": {"10.244.1.0/26", "10.244.1.64/26"}}))
+		mockCtlr.processStaticRouteUpdate()
+		routes := getRoutes()
+		Expect(routes).To(HaveLen(2))
+		Expect(routes["10.244.1.0/26"].Config.Gateway).To(Equal("1.2.3.4"))
+		Expect(routes["10.244.1.0/26"].Name).To(Equal("cluster-1/worker1/10.244.1.0/26"))
+		Expect(routes["10.244.1.64/26"].Config.Gateway).To(Equal("1.2.3.4"))
+		Expect(routes["10.244.1.64/26"].Name).To(Equal("cluster-1/worker1/10.244.1.64/26"))
+
+		// block moved to another node
+		store.Update(newBlockAffinity("worker1-10-244-1-64-26", "worker2", "10.244.1.64/26", CalicoBlockAffinityConfirmed))
+		mockCtlr.processStaticRouteUpdate()
+		routes = getRoutes()
+		Expect(routes["10.244.1.64/26"].Config.Gateway).To(Equal("1.2.3.5"))
+		Expect(routes["10.244.1.0/26"].Config.Gateway).To(Equal("1.2.3.4"))
+
+		mockCtlr.stopInformers()
+		Expect(mockCtlr.calicoInformer).To(BeNil())
+	})
+
 	//Describe("Processes CIS monitored resources on node update", func() {
 	//	BeforeEach(func() {
 	//		mockCtlr.clientsets.kubeCRClient = crdfake.NewSimpleClientset()
@@ -18,12 +18,12 @@ package controller
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -38,73 +38,106 @@ func NewPostManager(params PostParams, partition string) *PostManager {
 		AS3PostManager: &AS3PostManager{
 			AS3Config: params.AS3Config,
 		},
+		L3PostManager:          &L3PostManager{},
 		tokenManager:           params.tokenManager,
 		cachedTenantDeclMap:    make(map[string]as3Tenant),
 		postChan:               make(chan agentConfig, 1),
 		defaultPartition:       partition,
 		tenantDeclarationIDMap: make(map[string]string),
+		declarationHistory:     newDeclarationHistory(params.AS3Config.History.Size),
+		tenantFailures:         make(map[string]int),
+		rollbackChan:           make(chan rollbackRequest),
 	}
+	pm.PostParams = params
+	pm.setupBIGIPRESTClient()
 	// postManager runs as a separate go routine
 	// blocks on postChan to get new/updated AS3/L3 declaration to be posted to BIG-IP
 	go pm.postManager()
-	pm.PostParams = params
-	pm.setupBIGIPRESTClient()
 	return pm
 }
 
 // blocks on post channel and handles posting of AS3,L3 declaration to BIGIP pairs.
 func (postMgr *PostManager) postManager() {
-	for config := range postMgr.postChan {
-		// For the very first post after starting controller, need not wait to post
-		if !postMgr.AS3PostManager.firstPost && postMgr.AS3PostManager.AS3Config.PostDelayAS3 != 0 {
-			// Time (in seconds) that CIS waits to post the AS3 declaration to BIG-IP.
-			log.Debugf("[AS3] Delaying post to BIG-IP for %v seconds ", postMgr.AS3PostManager.AS3Config.PostDelayAS3)
-			_ = <-time.After(time.Duration(postMgr.AS3PostManager.AS3Config.PostDelayAS3) * time.Second)
+	// Recover the declaration history, so that the tenants can be rolled back to the revisions deployed before the restart
+	if postMgr.AS3Config.History.ConfigMap {
+		postMgr.loadDeclarationHistory()
+	}
+	// Drift detection runs in between the posts, so that the deployed tenants are compared with the latest posted tenants
+	var driftCheck <-chan time.Time
+	if postMgr.AS3Config.DriftDetection.Interval > 0 {
+		switch postMgr.AS3Config.DriftDetection.Policy {
+		case "", DriftPolicyReportOnly, DriftPolicyAutoHeal:
+		default:
+			log.Warningf("[AS3][Drift]%v Unknown drift detection policy %v, drifted tenants are only reported",
+				postMgr.postManagerPrefix, postMgr.AS3Config.DriftDetection.Policy)
 		}
-		// Set the target address to BIG-IP and docID and acceptedID if two step deployment is enabled
-		if postMgr.AS3Config.DocumentAPI {
-			config.as3Config.targetAddress = config.BigIpKey.BigIpAddress
+		ticker := time.NewTicker(time.Duration(postMgr.AS3Config.DriftDetection.Interval) * time.Second)
+		defer ticker.Stop()
+		driftCheck = ticker.C
+	}
+	for {
+		select {
+		case config, ok := <-postMgr.postChan:
+			if !ok {
+				return
+			}
+			postMgr.processConfig(config)
+		case <-driftCheck:
+			postMgr.detectDrift()
+		case request := <-postMgr.rollbackChan:
+			request.result <- postMgr.handleRollbackRequest(request)
 		}
+	}
+}
+
+// processConfig posts the AS3 declaration and notifies the response handler
+func (postMgr *PostManager) processConfig(config agentConfig) {
+	prometheus.QueueDepth.WithLabelValues(PostChannelName, postMgr.bigIpKey.BigIpAddress).Set(float64(len(postMgr.postChan)))
+	// For the very first post after starting controller, need not wait to post
+	if !postMgr.AS3PostManager.firstPost && postMgr.AS3PostManager.AS3Config.PostDelayAS3 != 0 {
+		// Time (in seconds) that CIS waits to post the AS3 declaration to BIG-IP.
+		log.Debugf("[AS3] Delaying post to BIG-IP for %v seconds ", postMgr.AS3PostManager.AS3Config.PostDelayAS3)
+		_ = <-time.After(time.Duration(postMgr.AS3PostManager.AS3Config.PostDelayAS3) * time.Second)
+	}
+	// Set the target address to BIG-IP and docID and acceptedID if two step deployment is enabled
+	if postMgr.AS3Config.DocumentAPI {
+		config.as3Config.targetAddress = config.BigIpKey.BigIpAddress
+	}
+	// Handle L3 post, the network objects are deployed before the AS3 tenants which depend on them
+	config.l3Config.deployL3Error = ""
+	if err := postMgr.deployL3Config(&config.l3Config); err != nil {
+		log.Errorf("%v[L3]%v Failed to deploy the network config: %v", getRequestPrefix(config.id), postMgr.postManagerPrefix, err)
+		// the network config is retried even when no tenant is posted, the tenants are retried along with it
+		config.l3Config.deployL3Error = err.Error()
+		config.as3Config.failTenants(fmt.Sprintf("failed to deploy the network config: %v", err))
+	} else if len(config.as3Config.incomingTenantDeclMap) == 0 && len(config.as3Config.failedTenants) == 0 {
+		// requests updating only the network config have no tenants to post
+		log.Debugf("%v[AS3]%v No tenants to post", getRequestPrefix(config.id), postMgr.postManagerPrefix)
+	} else if postMgr.AS3Config.DocumentAPI && !postMgr.recoverDocumentIDs() {
+		// documents are not created for the tenants until the documents posted before the restart are known
+		config.as3Config.failTenants("could not recover the document IDs from Central Manager")
+	} else {
 		//Handle AS3 post
 		postMgr.publishConfig(&config.as3Config)
-		//TODO: L3 post manger handling
-		//TODO: after post check for failed state and update retry chan
+	}
 
-		if !postMgr.AS3Config.DocumentAPI {
-			postMgr.updateTenantCache(&config.as3Config)
-		}
+	postMgr.updateTenantCache(&config.as3Config)
 
-		/*
-			If there are any tenants with 201 response code,
-			poll for its status continuously and block incoming requests
-		*/
-		postMgr.pollTenantStatus(&config.as3Config)
+	/*
+		If there are any tenants with 201 response code,
+		poll for its status continuously and block incoming requests
+	*/
+	postMgr.pollTenantStatus(&config.as3Config)
+	postMgr.rollbackFailedTenants(&config.as3Config)
+	postMgr.recordTenantResponses(&config.as3Config)
 
-		// notify resourceStatusUpdate response handler on successful tenant update
-		postMgr.respChan <- &config
-	}
+	// notify resourceStatusUpdate response handler on successful tenant update
+	postMgr.respChan <- &config
 }
 
 func (postMgr *PostManager) setupBIGIPRESTClient() {
-	// Get the SystemCertPool, continue with an empty pool on error
-	rootCAs, _ := x509.SystemCertPool()
-	if rootCAs == nil {
-		rootCAs = x509.NewCertPool()
-	}
-	// TODO: Make sure appMgr sets certificates in bigipInfo
-	certs := []byte(postMgr.tokenManager.TrustedCerts)
-
-	// Append our certs to the system pool
-	if ok := rootCAs.AppendCertsFromPEM(certs); !ok {
-		log.Debugf("[AS3]%v No certs appended, using only system certs", postMgr.postManagerPrefix)
-	}
-
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: postMgr.tokenManager.SslInsecure,
-			RootCAs:            rootCAs,
-		},
-	}
+	// transport of the token manager always uses the latest trusted certs
+	tr := postMgr.tokenManager.Transport()
 
 	if postMgr.HTTPClientMetrics {
 		log.Debug("[BIGIP] Http client instrumented with metrics!")
@@ -129,12 +162,12 @@ func (postMgr *PostManager) setupBIGIPRESTClient() {
 
 func (postMgr *PostManager) getAS3APIURL(tenants []string) string {
 	// TODO: Add tenant filtering when support is added in Central Manger AS3
-	//apiURL := postMgr.tokenManager.ServerURL + "/mgmt/shared/appsvcs/declare/" + strings.Join(tenants, ",")
+	//apiURL := postMgr.tokenManager.GetServerURL() + "/mgmt/shared/appsvcs/declare/" + strings.Join(tenants, ",")
 	var apiURL string
 	if !postMgr.AS3Config.DocumentAPI {
-		apiURL = postMgr.tokenManager.ServerURL + "/mgmt/shared/appsvcs/declare/"
+		apiURL = postMgr.tokenManager.GetServerURL() + "/mgmt/shared/appsvcs/declare/"
 	} else {
-		apiURL = postMgr.tokenManager.ServerURL + CM_DECLARE_API
+		apiURL = postMgr.tokenManager.GetServerURL() + CM_DECLARE_API
 	}
 	return apiURL
 }
@@ -142,13 +175,13 @@ func (postMgr *PostManager) getAS3APIURL(tenants []string) string {
 func (postMgr *PostManager) getAS3TaskIdURL(taskId string) string {
 	var apiURL string
 	if !postMgr.AS3Config.DocumentAPI {
-		apiURL = postMgr.tokenManager.ServerURL + "/mgmt/shared/appsvcs/task/" + taskId
+		apiURL = postMgr.tokenManager.GetServerURL() + "/mgmt/shared/appsvcs/task/" + taskId
 	} else {
 		ids := strings.Split(taskId, "/")
 		if len(ids) != 2 {
 			return ""
 		}
-		apiURL = postMgr.tokenManager.ServerURL + CM_DECLARE_API + ids[0] + "/deployments/" + ids[1]
+		apiURL = postMgr.tokenManager.GetServerURL() + CM_DECLARE_API + ids[0] + "/deployments/" + ids[1]
 	}
 	return apiURL
 }
@@ -156,12 +189,16 @@ func (postMgr *PostManager) getAS3TaskIdURL(taskId string) string {
 // publishConfig posts incoming configuration to BIG-IP
 func (postMgr *PostManager) publishConfig(cfg *as3Config) {
 	log.Debugf("[AS3]%v PostManager Accepted the configuration", postMgr.postManagerPrefix)
+	startTime := time.Now()
+	api := "as3"
 	// postConfig updates the tenantResponseMap with response codes
 	if !postMgr.AS3Config.DocumentAPI {
 		postMgr.postConfig(cfg)
 	} else {
+		api = "document"
 		postMgr.postConfigUsingDocumentAPI(cfg)
 	}
+	prometheus.PostDuration.WithLabelValues(postMgr.bigIpKey.BigIpAddress, api).Observe(time.Since(startTime).Seconds())
 }
 
 func (postMgr *PostManager) postConfig(cfg *as3Config) {
@@ -173,7 +210,10 @@ func (postMgr *PostManager) postConfig(cfg *as3Config) {
 	var tenants []string
 	if len(cfg.failedTenants) > 0 {
 		for tenant := range cfg.failedTenants {
-			tenants = append(tenants, tenant)
+			// tenants which failed the schema validation or are rolled back are not posted
+			if _, invalid := cfg.invalidTenants[tenant]; !invalid {
+				tenants = append(tenants, tenant)
+			}
 		}
 	} else {
 		for tenant := range cfg.incomingTenantDeclMap {
@@ -232,11 +272,13 @@ func (postMgr *PostManager) postConfigUsingDocumentAPI(cfg *as3Config) {
 	if postMgr.AS3PostManager.AS3Config.DebugAS3 {
 		postMgr.logAS3Request(cfg.data)
 	}
-	httpReqBody := bytes.NewBuffer([]byte(cfg.data))
 	var tenants []string
 	if len(cfg.failedTenants) > 0 {
 		for tenant := range cfg.failedTenants {
-			tenants = append(tenants, tenant)
+			// tenants which failed the schema validation are not posted
+			if _, invalid := cfg.invalidTenants[tenant]; !invalid {
+				tenants = append(tenants, tenant)
+			}
 		}
 	} else {
 		for tenant := range cfg.incomingTenantDeclMap {
@@ -247,29 +289,19 @@ func (postMgr *PostManager) postConfigUsingDocumentAPI(cfg *as3Config) {
 			}
 		}
 	}
-	// TODO: support deletion case for custom tenant
+	// Delete the default partition document when the complete config is removed
 	if len(tenants) == 0 {
-		tenants = append(tenants, postMgr.defaultPartition)
-	}
-	cfg.as3APIURL = postMgr.getAS3APIURL(tenants)
-	method := "POST"
-	declarationID := postMgr.tenantDeclarationIDMap[tenants[0]]
-	if declarationID != "" {
 		if !cfg.deleted {
-			method = "PUT"
-		} else {
-			method = "DELETE"
+			return
 		}
+		tenants = append(tenants, postMgr.defaultPartition)
 	}
-	if declarationID == "" && method == "DELETE" {
-		log.Errorf("[AS3]%v Document ID is required for deletion of declaration", postMgr.postManagerPrefix)
-		return
-	}
+	sort.Strings(tenants)
+	cfg.as3APIURL = postMgr.getAS3APIURL(tenants)
 	// add authorization header to the req
 	if postMgr.tokenManager.GetToken() == "" {
 		log.Debugf("[AS3] Waiting for max 5 seconds for token syncing..")
-		t := 0
-		for t < 5 {
+		for t := 0; t < 5; t++ {
 			time.Sleep(1 * time.Second)
 			if postMgr.tokenManager.GetToken() != "" {
 				log.Debugf("[AS3] Token is now available")
@@ -281,19 +313,56 @@ func (postMgr *PostManager) postConfigUsingDocumentAPI(cfg *as3Config) {
 			return
 		}
 	}
-	if method == "DELETE" {
-		postMgr.deleteDocumentAPI(tenants[0], cfg, declarationID)
+	// Each tenant is maintained as a separate document on Central Manager
+	for _, tenant := range tenants {
+		postMgr.postTenantUsingDocumentAPI(cfg, tenant)
+	}
+}
+
+func (postMgr *PostManager) postTenantUsingDocumentAPI(cfg *as3Config, tenant string) {
+	declarationID := postMgr.tenantDeclarationIDMap[tenant]
+	tenantDecl, found := cfg.incomingTenantDeclMap[tenant]
+	if cfg.deleted || !found || isDeletedTenantDeclaration(tenantDecl) {
+		if declarationID == "" {
+			log.Debugf("[AS3]%v Document ID not found for tenant %v, nothing to delete", postMgr.postManagerPrefix, tenant)
+			postMgr.updateTenantResponseCode(http.StatusOK, cfg, tenant, true)
+			return
+		}
+		postMgr.deleteDocumentAPI(tenant, cfg, declarationID)
 		return
 	}
-	if method == "PUT" {
-		declarationID = postMgr.updateDocumentAPI(tenants[0], cfg, httpReqBody, declarationID)
+	httpReqBody := bytes.NewBuffer([]byte(postMgr.AS3PostManager.createAS3Declaration(
+		map[string]as3Tenant{tenant: tenantDecl}, cfg.userAgent, cfg.targetAddress)))
+	if declarationID != "" {
+		postMgr.updateDocumentAPI(tenant, cfg, httpReqBody, declarationID)
 		return
 	}
-	declarationID = postMgr.declareDocumentAPI(cfg, httpReqBody, tenants[0], method)
+	declarationID = postMgr.declareDocumentAPI(cfg, httpReqBody, tenant, "POST")
 	if declarationID == "" {
 		return
 	}
-	postMgr.deployDocumentAPI(cfg, declarationID, tenants[0])
+	postMgr.deployDocumentAPI(cfg, declarationID, tenant)
+}
+
+// isDeletedTenantDeclaration checks whether the tenant declaration removes all the applications of the tenant
+func isDeletedTenantDeclaration(tenantDecl as3Tenant) bool {
+	for _, value := range tenantDecl {
+		var app map[string]interface{}
+		switch v := value.(type) {
+		case as3Application:
+			app = v
+		case map[string]interface{}:
+			app = v
+		default:
+			continue
+		}
+		for appKey := range app {
+			if appKey != "class" && appKey != "template" {
+				return false
+			}
+		}
+	}
+	return true
 }
 
 func (postMgr *PostManager) deployDocumentAPI(cfg *as3Config, declarationID string, tenant string) {
@@ -326,7 +395,7 @@ func (postMgr *PostManager) deployDocumentAPI(cfg *as3Config, declarationID stri
 		postMgr.handleDocumentAPIResponseStatusOK(deployResponseMap, cfg, tenant, httpDeployResp.StatusCode)
 	case http.StatusAccepted:
 		log.Infof("%v[AS3]%v post resulted in ACCEPTED", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
-		postMgr.handleDocumentAPIResponseAccepted(deployResponseMap, declarationID, cfg)
+		postMgr.handleDocumentAPIResponseAccepted(deployResponseMap, declarationID, cfg, tenant)
 	default:
 		postMgr.handleDocumentAPIResponseFailureStatus(deployResponseMap, cfg, tenant, httpDeployResp.StatusCode)
 		log.Errorf("[AS3]%v Failed to post declaration to %v", postMgr.postManagerPrefix, cfg.as3APIURL)
@@ -354,10 +423,10 @@ func (postMgr *PostManager) declareDocumentAPI(cfg *as3Config, httpReqBody io.Re
 	case http.StatusOK:
 		if id, ok := declareResponseMap["id"].(string); ok {
 			docID = id
-			postMgr.tenantDeclarationIDMap[tenant] = docID // Since we are only supporting single tenant as of now
+			postMgr.tenantDeclarationIDMap[tenant] = docID
 		}
 		log.Debugf("[AS3]%v Successfully posted declare request to %v", postMgr.postManagerPrefix, cfg.as3APIURL)
-		if cfg.acceptedTaskId != "" {
+		if _, ok := cfg.tenantTaskIdMap[tenant]; ok {
 			postMgr.handleDocumentAPIResponseStatusOK(declareResponseMap, cfg, tenant, httpDeclareResp.StatusCode)
 			return ""
 		}
@@ -386,13 +455,17 @@ func (postMgr *PostManager) updateDocumentAPI(tenant string, cfg *as3Config, htt
 
 	// Read the document ID
 	switch httpUpdateResp.StatusCode {
-	case http.StatusOK, http.StatusAccepted:
+	case http.StatusOK:
 		if id, ok := updateResponseMap["id"].(string); ok {
 			docID = id
-			postMgr.tenantDeclarationIDMap[tenant] = docID // Since we are only supporting single tenant as of now
+			postMgr.tenantDeclarationIDMap[tenant] = docID
 		}
 		postMgr.handleDocumentAPIResponseStatusOK(updateResponseMap, cfg, tenant, httpUpdateResp.StatusCode)
 		log.Debugf("[AS3]%v Successfully posted update request to %v", postMgr.postManagerPrefix, cfg.as3APIURL)
+	case http.StatusAccepted:
+		// Central Manager redeploys the updated document, the deployment is polled for the result
+		postMgr.handleDocumentAPIResponseAccepted(updateResponseMap, docID, cfg, tenant)
+		log.Debugf("[AS3]%v Update request to %v is accepted", postMgr.postManagerPrefix, cfg.as3APIURL)
 	default:
 		postMgr.handleDocumentAPIResponseFailureStatus(updateResponseMap, cfg, tenant, httpUpdateResp.StatusCode)
 		log.Errorf("[AS3]%v Failed to post update request to %v", postMgr.postManagerPrefix, cfg.as3APIURL)
@@ -421,9 +494,14 @@ func (postMgr *PostManager) deleteDocumentAPI(tenant string, cfg *as3Config, doc
 		log.Debugf("[AS3]%v Successfully posted delete request to %v", postMgr.postManagerPrefix, cfg.as3APIURL+docID)
 		delete(postMgr.tenantDeclarationIDMap, tenant)
 		postMgr.updateTenantResponseCode(200, cfg, tenant, true)
+	case http.StatusNotFound:
+		// the document is already removed on Central Manager
+		log.Debugf("[AS3]%v Document %v of tenant %v is not found, nothing to delete", postMgr.postManagerPrefix, docID, tenant)
+		delete(postMgr.tenantDeclarationIDMap, tenant)
+		postMgr.updateTenantResponseCode(http.StatusOK, cfg, tenant, true)
 	default:
 		log.Errorf("[AS3]%v Failed to post delete request for tenant: %v to %v", postMgr.postManagerPrefix, tenant, cfg.as3APIURL+docID)
-		postMgr.updateTenantResponseCode(200, cfg, tenant, true)
+		postMgr.handleDocumentAPIResponseFailureStatus(declareResponseMap, cfg, tenant, httpDeclareResp.StatusCode)
 	}
 }
 
@@ -443,6 +521,9 @@ func (postMgr *PostManager) httpPOST(request *http.Request) (*http.Response, map
 		return nil, nil
 	}
 	defer httpResp.Body.Close()
+	if httpResp.StatusCode == http.StatusUnauthorized {
+		postMgr.tokenManager.ForceRelogin()
+	}
 
 	body, err := io.ReadAll(httpResp.Body)
 	if err != nil {
@@ -462,16 +543,33 @@ func (postMgr *PostManager) httpPOST(request *http.Request) (*http.Response, map
 }
 
 func (postMgr *PostManager) updateTenantResponseCode(code int, cfg *as3Config, tenant string, isDeleted bool) {
+	postMgr.updateTenantResponse(code, "", cfg, tenant, isDeleted)
+}
+
+// updateTenantResponse updates the response code along with the error message reported by BIG-IP for the tenant
+func (postMgr *PostManager) updateTenantResponse(code int, message string, cfg *as3Config, tenant string, isDeleted bool) {
 	// Update status for a specific tenant if mentioned, else update the response for all tenants
 	if tenant != "" {
-		cfg.tenantResponseMap[tenant] = tenantResponse{code, isDeleted}
+		cfg.tenantResponseMap[tenant] = tenantResponse{code, isDeleted, message}
 	} else {
 		for tenant := range cfg.tenantResponseMap {
-			cfg.tenantResponseMap[tenant] = tenantResponse{code, false}
+			if _, invalid := cfg.invalidTenants[tenant]; invalid {
+				continue
+			}
+			cfg.tenantResponseMap[tenant] = tenantResponse{code, false, message}
 		}
 	}
 }
 
+// getResultMessage returns the message of a tenant result along with the detailed response if available
+func getResultMessage(result map[string]interface{}) string {
+	message, _ := result["message"].(string)
+	if response, ok := result["response"]; ok {
+		return fmt.Sprintf("%v %v", message, response)
+	}
+	return message
+}
+
 func (postMgr *PostManager) handleResponseStatusOK(responseMap map[string]interface{}, cfg *as3Config) {
 	// traverse all response results
 	results := (responseMap["results"]).([]interface{})
@@ -504,17 +602,13 @@ func (postMgr *PostManager) handleDocumentAPIResponseFailureStatus(responseMap m
 		msg, _ = responseMap["message"]
 	}
 	log.Errorf("%v[AS3]%v Big-IP Responded with error code: %v, Error: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, statusCode, msg)
-	postMgr.updateTenantResponseCode(statusCode, cfg, tenant, false)
+	postMgr.updateTenantResponse(statusCode, fmt.Sprintf("%v", msg), cfg, tenant, false)
 
 }
 
-func (postMgr *PostManager) getTenantConfigStatus(id string, cfg *as3Config) {
-	var url string
-	if !postMgr.AS3Config.DocumentAPI {
-		url = postMgr.getAS3TaskIdURL(id)
-	} else {
-		url = postMgr.getAS3TaskIdURL(id)
-	}
+// getTenantConfigStatus polls the status of an accepted task, tenant is set only for Document API deployments
+func (postMgr *PostManager) getTenantConfigStatus(id string, cfg *as3Config, tenant string) {
+	url := postMgr.getAS3TaskIdURL(id)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		log.Errorf("[AS3]%v Creating new HTTP request error: %v ", postMgr.postManagerPrefix, err)
@@ -547,27 +641,43 @@ func (postMgr *PostManager) getTenantConfigStatus(id string, cfg *as3Config) {
 			}
 		}
 		declaration := (responseMap[declarationKey]).(interface{}).(map[string]interface{})
-		// reset the accepted task id
-		cfg.acceptedTaskId = ""
 		for _, value := range results {
 			v := value.(map[string]interface{})
 			if msg, ok := v["message"]; ok && msg.(string) == "in progress" {
+				// keep polling the accepted task until it completes
 				return
+			}
+		}
+		// reset the accepted task id
+		resetAcceptedTask(cfg, tenant)
+		for _, value := range results {
+			v := value.(map[string]interface{})
+			// reset task id, so that any failed tenants will go to post call in the next retry
+			code := int(v["code"].(float64))
+			if code == http.StatusOK {
+				postMgr.updateTenantResponseCode(code, cfg, v["tenant"].(string), updateTenantDeletion(v["tenant"].(string), declaration))
 			} else {
-				// reset task id, so that any failed tenants will go to post call in the next retry
-				postMgr.updateTenantResponseCode(int(v["code"].(float64)), cfg, v["tenant"].(string), updateTenantDeletion(v["tenant"].(string), declaration))
-				if _, ok := v["response"]; ok {
-					log.Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"], v["response"])
-				} else {
-					log.Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
-				}
-				log.Infof("%v[AS3]%v post resulted in SUCCESS", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
+				postMgr.updateTenantResponse(code, getResultMessage(v), cfg, v["tenant"].(string), false)
+			}
+			if _, ok := v["response"]; ok {
+				log.Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"], v["response"])
+			} else {
+				log.Debugf("[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v", postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
 			}
+			log.Infof("%v[AS3]%v post resulted in SUCCESS", getRequestPrefix(cfg.id), postMgr.postManagerPrefix)
 		}
 	} else if httpResp.StatusCode != http.StatusServiceUnavailable {
 		// reset task id, so that any failed tenants will go to post call in the next retry
+		resetAcceptedTask(cfg, tenant)
+		postMgr.updateTenantResponseCode(httpResp.StatusCode, cfg, tenant, false)
+	}
+}
+
+func resetAcceptedTask(cfg *as3Config, tenant string) {
+	if tenant != "" {
+		delete(cfg.tenantTaskIdMap, tenant)
+	} else {
 		cfg.acceptedTaskId = ""
-		postMgr.updateTenantResponseCode(httpResp.StatusCode, cfg, "", false)
 	}
 }
 
@@ -578,7 +688,7 @@ func (postMgr *PostManager) handleMultiStatus(responseMap map[string]interface{}
 			v := value.(map[string]interface{})
 
 			if v["code"].(float64) != 200 {
-				postMgr.updateTenantResponseCode(int(v["code"].(float64)), cfg, v["tenant"].(string), false)
+				postMgr.updateTenantResponse(int(v["code"].(float64)), getResultMessage(v), cfg, v["tenant"].(string), false)
 				log.Errorf("%v[AS3]%v Error response from BIG-IP: code: %v --- tenant:%v --- message: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
 			} else {
 				postMgr.updateTenantResponseCode(int(v["code"].(float64)), cfg, v["tenant"].(string), updateTenantDeletion(v["tenant"].(string), declaration))
@@ -596,10 +706,13 @@ func (postMgr *PostManager) handleResponseAccepted(responseMap map[string]interf
 	}
 }
 
-func (postMgr *PostManager) handleDocumentAPIResponseAccepted(responseMap map[string]interface{}, docID string, cfg *as3Config) {
+func (postMgr *PostManager) handleDocumentAPIResponseAccepted(responseMap map[string]interface{}, docID string, cfg *as3Config, tenant string) {
 	var deploymentID string
 	deploymentID, _ = (responseMap["id"]).(string)
-	cfg.acceptedTaskId = docID + "/" + deploymentID
+	if cfg.tenantTaskIdMap == nil {
+		cfg.tenantTaskIdMap = make(map[string]string)
+	}
+	cfg.tenantTaskIdMap[tenant] = docID + "/" + deploymentID
 	log.Debugf("[AS3]%v Response from BIG-IP: code 201/202 id %v, waiting %v seconds to poll response", postMgr.postManagerPrefix, docID, timeoutMedium)
 }
 
@@ -631,11 +744,11 @@ func (postMgr *PostManager) handleResponseOthers(responseMap map[string]interfac
 		for _, value := range results {
 			v := value.(map[string]interface{})
 			log.Errorf("%v[AS3]%v Response from BIG-IP: code: %v --- tenant:%v --- message: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, v["code"], v["tenant"], v["message"])
-			postMgr.updateTenantResponseCode(int(v["code"].(float64)), cfg, v["tenant"].(string), false)
+			postMgr.updateTenantResponse(int(v["code"].(float64)), getResultMessage(v), cfg, v["tenant"].(string), false)
 		}
 	} else if err, ok := (responseMap["error"]).(map[string]interface{}); ok {
 		log.Errorf("%v[AS3]%v Big-IP Responded with error code: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, err["code"])
-		postMgr.updateTenantResponseCode(int(err["code"].(float64)), cfg, "", false)
+		postMgr.updateTenantResponse(int(err["code"].(float64)), getResultMessage(err), cfg, "", false)
 	} else {
 		log.Errorf("%v[AS3]%v Big-IP Responded with code: %v", getRequestPrefix(cfg.id), postMgr.postManagerPrefix, responseMap["code"])
 		postMgr.updateTenantResponseCode(int(responseMap["code"].(float64)), cfg, "", false)
@@ -722,7 +835,7 @@ func (postMgr *PostManager) GetAS3DeclarationFromBigIP() (map[string]interface{}
 
 	log.Debugf("[AS3]%v posting GET BIGIP AS3 declaration request on %v", postMgr.postManagerPrefix, url)
 	// add authorization header to the req
-	req.Header.Add("Authorization", postMgr.tokenManager.GetToken())
+	req.Header.Add("Authorization", "Bearer "+postMgr.tokenManager.GetToken())
 
 	httpResp, responseMap := postMgr.httpReq(req)
 	if httpResp == nil || responseMap == nil {
@@ -742,6 +855,139 @@ func (postMgr *PostManager) GetAS3DeclarationFromBigIP() (map[string]interface{}
 	return nil, fmt.Errorf("Error response from BIGIP with status code %v", httpResp.StatusCode)
 }
 
+// recoverDocumentIDs recovers the documents posted before the controller restart, so that they are updated instead
+// of re-created. The recovery is retried with backoff before the first post and on the retries of the failed posts.
+func (postMgr *PostManager) recoverDocumentIDs() bool {
+	if postMgr.documentIDsRecovered {
+		return true
+	}
+	interval := time.Second
+	for attempt := 1; ; attempt++ {
+		err := postMgr.recoverTenantDeclarationIDs()
+		if err == nil {
+			postMgr.documentIDsRecovered = true
+			return true
+		}
+		log.Errorf("[AS3]%v Could not recover the document IDs from Central Manager (attempt %v/%v): %v",
+			postMgr.postManagerPrefix, attempt, documentIDRecoveryAttempts, err)
+		if attempt == documentIDRecoveryAttempts {
+			return false
+		}
+		<-time.After(interval)
+		interval *= 2
+	}
+}
+
+// recoverTenantDeclarationIDs rebuilds the tenant to document ID map from the documents available on Central Manager.
+// The document IDs are recovered even if some of the documents could not be fetched.
+func (postMgr *PostManager) recoverTenantDeclarationIDs() error {
+	_, tenantDocIDs, err := postMgr.getDocumentTenantsFromCM()
+	for tenant, docID := range tenantDocIDs {
+		log.Debugf("[AS3]%v Recovered document ID %v for tenant %v", postMgr.postManagerPrefix, docID, tenant)
+		postMgr.tenantDeclarationIDMap[tenant] = docID
+	}
+	return err
+}
+
+// getDocumentTenantsFromCM returns the CIS tenants of the documents available on Central Manager along with their
+// document IDs. The tenants of the documents which could be fetched are returned along with the error otherwise.
+func (postMgr *PostManager) getDocumentTenantsFromCM() (map[string]interface{}, map[string]string, error) {
+	documents, err := postMgr.getDocumentsFromCM()
+	if err != nil {
+		return nil, nil, err
+	}
+	tenants := make(map[string]interface{})
+	tenantDocIDs := make(map[string]string)
+	var fetchErr error
+	for _, document := range documents {
+		docID, ok := document["id"].(string)
+		if !ok || docID == "" {
+			continue
+		}
+		declaration, ok := document["declaration"].(map[string]interface{})
+		if !ok {
+			declaration, err = postMgr.getDocumentDeclarationFromCM(docID)
+			if err != nil {
+				fetchErr = fmt.Errorf("could not fetch the document %v: %v", docID, err)
+				continue
+			}
+		}
+		docTenants := make(map[string]interface{})
+		for tenant, value := range declaration {
+			if decl, ok := value.(map[string]interface{}); ok {
+				if label, found := decl["label"]; found && label == postMgr.defaultPartition {
+					docTenants[tenant] = decl
+				}
+			}
+		}
+		// CIS posts a document per tenant, updating a document with several tenants would remove the other tenants
+		if len(docTenants) > 1 {
+			log.Warningf("[AS3]%v Skipping the document %v which declares several tenants", postMgr.postManagerPrefix, docID)
+			continue
+		}
+		for tenant, decl := range docTenants {
+			tenants[tenant] = decl
+			tenantDocIDs[tenant] = docID
+		}
+	}
+	return tenants, tenantDocIDs, fetchErr
+}
+
+// getDocumentsFromCM lists the AS3 documents available on Central Manager
+func (postMgr *PostManager) getDocumentsFromCM() ([]map[string]interface{}, error) {
+	url := postMgr.getAS3APIURL([]string{})
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("[AS3]%v posting GET documents request on %v", postMgr.postManagerPrefix, url)
+	req.Header.Add("Authorization", "Bearer "+postMgr.tokenManager.GetToken())
+
+	httpResp, responseMap := postMgr.httpReq(req)
+	if httpResp == nil || responseMap == nil {
+		return nil, fmt.Errorf("Internal Error")
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error response from Central Manager with status code %v", httpResp.StatusCode)
+	}
+	var documents []map[string]interface{}
+	if embedded, ok := responseMap["_embedded"].(map[string]interface{}); ok {
+		for _, value := range embedded {
+			if docs, ok := value.([]interface{}); ok {
+				for _, doc := range docs {
+					if document, ok := doc.(map[string]interface{}); ok {
+						documents = append(documents, document)
+					}
+				}
+			}
+		}
+	}
+	return documents, nil
+}
+
+// getDocumentDeclarationFromCM fetches the declaration of an AS3 document from Central Manager
+func (postMgr *PostManager) getDocumentDeclarationFromCM(docID string) (map[string]interface{}, error) {
+	url := postMgr.getAS3APIURL([]string{}) + docID
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("[AS3]%v posting GET document request on %v", postMgr.postManagerPrefix, url)
+	req.Header.Add("Authorization", "Bearer "+postMgr.tokenManager.GetToken())
+
+	httpResp, responseMap := postMgr.httpReq(req)
+	if httpResp == nil || responseMap == nil {
+		return nil, fmt.Errorf("Internal Error")
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error response from Central Manager with status code %v", httpResp.StatusCode)
+	}
+	if declaration, ok := responseMap["declaration"].(map[string]interface{}); ok {
+		return declaration, nil
+	}
+	return responseMap, nil
+}
+
 func (postMgr *PostManager) httpReq(request *http.Request) (*http.Response, map[string]interface{}) {
 	httpResp, err := postMgr.httpClient.Do(request)
 	if err != nil {
@@ -749,6 +995,9 @@ func (postMgr *PostManager) httpReq(request *http.Request) (*http.Response, map[
 		return nil, nil
 	}
 	defer httpResp.Body.Close()
+	if httpResp.StatusCode == http.StatusUnauthorized {
+		postMgr.tokenManager.ForceRelogin()
+	}
 
 	body, err := io.ReadAll(httpResp.Body)
 	if err != nil {
@@ -768,12 +1017,12 @@ func (postMgr *PostManager) httpReq(request *http.Request) (*http.Response, map[
 }
 
 func (postMgr *PostManager) getAS3VersionURL() string {
-	apiURL := postMgr.tokenManager.ServerURL + "/mgmt/shared/appsvcs/info"
+	apiURL := postMgr.tokenManager.GetServerURL() + "/mgmt/shared/appsvcs/info"
 	return apiURL
 }
 
 func (postMgr *PostManager) getBigipRegKeyURL() string {
-	apiURL := postMgr.tokenManager.ServerURL + "/mgmt/tm/shared/licensing/registration"
+	apiURL := postMgr.tokenManager.GetServerURL() + "/mgmt/tm/shared/licensing/registration"
 	return apiURL
 }
 
@@ -850,6 +1099,10 @@ func (postMgr *PostManager) updateTenantCache(cfg *as3Config) {
 	*/
 	// re-initialize the failed tenants map
 	cfg.failedTenants = make(map[string]struct{})
+	// tenants which failed the schema validation are not posted, so report the validation error for them
+	for tenant, message := range cfg.invalidTenants {
+		cfg.tenantResponseMap[tenant] = tenantResponse{agentResponseCode: http.StatusUnprocessableEntity, message: message}
+	}
 	for tenant, resp := range cfg.tenantResponseMap {
 		if resp.agentResponseCode == 200 {
 			// update the post manager's tenant cache
@@ -858,6 +1111,7 @@ func (postMgr *PostManager) updateTenantCache(cfg *as3Config) {
 				delete(postMgr.cachedTenantDeclMap, tenant)
 			} else {
 				postMgr.cachedTenantDeclMap[tenant] = cfg.incomingTenantDeclMap[tenant]
+				postMgr.recordDeclaration(tenant, cfg.incomingTenantDeclMap[tenant])
 			}
 		} else {
 			// update the failed tenants list
@@ -869,18 +1123,43 @@ func (postMgr *PostManager) updateTenantCache(cfg *as3Config) {
 func (postMgr *PostManager) pollTenantStatus(cfg *as3Config) {
 	// Keep retrying until accepted tenant statuses are updated
 	// This prevents agent from unlocking and thus any incoming post requests (config changes) also need to hold on
+	if cfg.acceptedTaskId != "" || len(cfg.tenantTaskIdMap) > 0 {
+		startTime := time.Now()
+		defer func() {
+			prometheus.TaskPollDuration.WithLabelValues(postMgr.bigIpKey.BigIpAddress).Observe(time.Since(startTime).Seconds())
+		}()
+	}
+	if postMgr.AS3Config.DocumentAPI {
+		postMgr.pollDocumentTenantStatus(cfg)
+		return
+	}
 	for cfg.acceptedTaskId != "" {
-		if !postMgr.AS3Config.DocumentAPI {
-			<-time.After(timeoutMedium)
-		} else {
-			<-time.After(timeoutSmall)
-		}
+		<-time.After(timeoutMedium)
 		cfg.tenantResponseMap = make(map[string]tenantResponse)
-		postMgr.getTenantConfigStatus(cfg.acceptedTaskId, cfg)
+		postMgr.getTenantConfigStatus(cfg.acceptedTaskId, cfg, "")
+		postMgr.updateTenantCache(cfg)
+	}
+}
+
+func (postMgr *PostManager) pollDocumentTenantStatus(cfg *as3Config) {
+	// Every tenant is deployed as a separate document, so poll each of the accepted deployments
+	for len(cfg.tenantTaskIdMap) > 0 {
+		<-time.After(timeoutSmall)
+		for tenant, taskId := range cfg.tenantTaskIdMap {
+			postMgr.getTenantConfigStatus(taskId, cfg, tenant)
+		}
 		postMgr.updateTenantCache(cfg)
 	}
 }
 
+// recordTenantResponses updates the tenant response metrics with the final response of each posted tenant
+func (postMgr *PostManager) recordTenantResponses(cfg *as3Config) {
+	for tenant, resp := range cfg.tenantResponseMap {
+		prometheus.TenantResponses.WithLabelValues(postMgr.bigIpKey.BigIpAddress, tenant,
+			strconv.Itoa(resp.agentResponseCode)).Inc()
+	}
+}
+
 // function for returning the prefix string for request id
 func getRequestPrefix(id int) string {
 	if id == 0 {
@@ -160,14 +160,18 @@ var _ = Describe("AS3PostManager Tests", func() {
 			}, http.MethodGet)
 			as3Cfg := as3Config{
 				id:                1,
+				acceptedTaskId:    "100",
 				tenantResponseMap: make(map[string]tenantResponse),
 			}
-			mockPM.getTenantConfigStatus("100", &as3Cfg)
+			mockPM.getTenantConfigStatus("100", &as3Cfg, "")
 			Expect(len(as3Cfg.tenantResponseMap)).To(BeZero(), "Posting Failed")
-			mockPM.getTenantConfigStatus("100", &as3Cfg)
+			// task in progress is polled again
+			Expect(as3Cfg.acceptedTaskId).To(Equal("100"))
+			mockPM.getTenantConfigStatus("100", &as3Cfg, "")
+			Expect(as3Cfg.acceptedTaskId).To(BeEmpty())
 			Expect(len(as3Cfg.tenantResponseMap)).To(Equal(1), "Posting Failed")
 			Expect(as3Cfg.tenantResponseMap[tnt].agentResponseCode).To(Equal(http.StatusOK))
-			mockPM.getTenantConfigStatus("100", &as3Cfg)
+			mockPM.getTenantConfigStatus("100", &as3Cfg, "")
 			Expect(len(as3Cfg.tenantResponseMap)).To(Equal(1), "Posting Failed")
 			Expect(as3Cfg.tenantResponseMap[tnt].agentResponseCode).To(Equal(http.StatusUnprocessableEntity))
 		})
@@ -264,6 +268,197 @@ var _ = Describe("AS3PostManager Tests", func() {
 		})
 	})
 
+	Describe("Recover Document IDs", func() {
+		BeforeEach(func() {
+			mockPM.AS3Config.DocumentAPI = true
+			mockPM.defaultPartition = "test"
+			mockPM.tenantDeclarationIDMap = make(map[string]string)
+		})
+		It("Recover document IDs for CIS tenants", func() {
+			mockPM.setResponses([]responceCtx{
+				{
+					tenant: "test",
+					status: http.StatusOK,
+					body: `{"_embedded": {"appsvcs": [{"id": "doc1", "declaration": {"class": "ADC", "test": {"class": "Tenant", "label": "test"}}},
+						{"id": "doc2"}, {"id": "doc3", "declaration": {"class": "ADC", "other": {"class": "Tenant", "label": "other"}}},
+						{"id": "doc4", "declaration": {"class": "ADC", "test1": {"class": "Tenant", "label": "test"},
+						"test2": {"class": "Tenant", "label": "test"}}}]}}`,
+				},
+				{
+					tenant: "test_gtm",
+					status: http.StatusOK,
+					body:   `{"id": "doc2", "declaration": {"class": "ADC", "test_gtm": {"class": "Tenant", "label": "test"}}}`,
+				},
+			}, http.MethodGet)
+			// documents with several tenants are not updated per tenant
+			Expect(mockPM.recoverDocumentIDs()).To(BeTrue())
+			Expect(mockPM.tenantDeclarationIDMap).To(Equal(map[string]string{"test": "doc1", "test_gtm": "doc2"}))
+			// document IDs are recovered only once
+			Expect(mockPM.recoverDocumentIDs()).To(BeTrue())
+		})
+		It("Handle failure while listing documents", func() {
+			mockPM.setResponses([]responceCtx{
+				{
+					tenant: "test",
+					status: http.StatusServiceUnavailable,
+					body:   fmt.Sprintf(`{"code":%d}`, http.StatusServiceUnavailable),
+				},
+			}, http.MethodGet)
+			Expect(mockPM.recoverTenantDeclarationIDs()).NotTo(Succeed())
+			Expect(mockPM.tenantDeclarationIDMap).To(BeEmpty())
+			Expect(mockPM.documentIDsRecovered).To(BeFalse())
+		})
+		It("Fail the tenants until the document IDs are recovered", func() {
+			mockPM.setMethodResponses(map[string][]responceCtx{
+				http.MethodGet: {
+					{status: http.StatusServiceUnavailable, body: fmt.Sprintf(`{"code":%d}`, http.StatusServiceUnavailable)},
+					{status: http.StatusServiceUnavailable, body: fmt.Sprintf(`{"code":%d}`, http.StatusServiceUnavailable)},
+					{status: http.StatusServiceUnavailable, body: fmt.Sprintf(`{"code":%d}`, http.StatusServiceUnavailable)},
+				},
+			})
+			as3Cfg := as3Config{
+				tenantResponseMap:     map[string]tenantResponse{"test": {}},
+				tenantTaskIdMap:       make(map[string]string),
+				invalidTenants:        make(map[string]string),
+				incomingTenantDeclMap: map[string]as3Tenant{"test": {"class": "Tenant"}},
+			}
+			mockPM.processConfig(agentConfig{as3Config: as3Cfg})
+			config := <-mockPM.respChan
+			Expect(config.as3Config.failedTenants).To(HaveKey("test"))
+			Expect(config.as3Config.tenantResponseMap["test"].agentResponseCode).To(Equal(http.StatusServiceUnavailable))
+			Expect(config.isRetryable()).To(BeTrue())
+			Expect(mockPM.documentIDsRecovered).To(BeFalse())
+		})
+	})
+
+	Describe("Post Config using Document API", func() {
+		var as3Cfg as3Config
+		BeforeEach(func() {
+			mockPM.AS3Config.DocumentAPI = true
+			mockPM.defaultPartition = "test"
+			mockPM.tenantDeclarationIDMap = make(map[string]string)
+			as3Cfg = as3Config{
+				as3APIURL:         mockPM.getAS3APIURL([]string{"test"}),
+				targetAddress:     "10.1.1.1",
+				tenantResponseMap: make(map[string]tenantResponse),
+				tenantTaskIdMap:   make(map[string]string),
+				failedTenants:     make(map[string]struct{}),
+				incomingTenantDeclMap: map[string]as3Tenant{
+					"test":  {"class": "Tenant", "Shared": as3Application{"class": "Application", "template": "shared", "vs": "test"}},
+					"test1": {"class": "Tenant", "Shared": as3Application{"class": "Application", "template": "shared", "vs": "test1"}},
+					"test2": {"class": "Tenant"},
+				},
+			}
+		})
+
+		It("Create, update and delete documents for each tenant", func() {
+			mockPM.tenantDeclarationIDMap["test1"] = "doc1"
+			mockPM.tenantDeclarationIDMap["test2"] = "doc2"
+			mockPM.setMethodResponses(map[string][]responceCtx{
+				http.MethodPost: {
+					{tenant: "test", status: http.StatusOK, body: `{"id": "doc0"}`},
+					{tenant: "test", status: http.StatusOK, body: `{"id": "deploy0", "message": "success"}`},
+				},
+				http.MethodPut: {
+					{tenant: "test1", status: http.StatusAccepted, body: `{"id": "deploy1"}`},
+				},
+				http.MethodDelete: {
+					{tenant: "test2", status: http.StatusOK, body: `{}`},
+				},
+				http.MethodGet: {
+					{tenant: "test1", status: http.StatusOK, body: `{"request": {"test1": {}}, "response": {"results": [{"code": 200, "message": "success", "tenant": "test1"}]}}`},
+				},
+			})
+			for _, tenant := range []string{"test", "test1", "test2"} {
+				mockPM.postTenantUsingDocumentAPI(&as3Cfg, tenant)
+			}
+			Expect(mockPM.tenantDeclarationIDMap).To(Equal(map[string]string{"test": "doc0", "test1": "doc1"}))
+			// accepted update is polled for the result of the deployment
+			Expect(as3Cfg.tenantTaskIdMap).To(Equal(map[string]string{"test1": "doc1/deploy1"}))
+			Expect(as3Cfg.tenantResponseMap).NotTo(HaveKey("test1"))
+			mockPM.pollTenantStatus(&as3Cfg)
+			Expect(as3Cfg.tenantTaskIdMap).To(BeEmpty())
+			Expect(as3Cfg.tenantResponseMap).To(Equal(map[string]tenantResponse{
+				"test":  {agentResponseCode: http.StatusOK},
+				"test1": {agentResponseCode: http.StatusOK},
+				"test2": {agentResponseCode: http.StatusOK, isDeleted: true},
+			}))
+			mockPM.updateTenantCache(&as3Cfg)
+			Expect(as3Cfg.failedTenants).To(BeEmpty())
+			Expect(mockPM.cachedTenantDeclMap).To(HaveKey("test"))
+			Expect(mockPM.cachedTenantDeclMap).To(HaveKey("test1"))
+			Expect(mockPM.cachedTenantDeclMap).NotTo(HaveKey("test2"))
+		})
+
+		It("Track failed tenants separately", func() {
+			mockPM.setMethodResponses(map[string][]responceCtx{
+				http.MethodPost: {
+					{tenant: "test", status: http.StatusOK, body: `{"id": "doc0"}`},
+					{tenant: "test", status: http.StatusOK, body: `{"id": "deploy0"}`},
+					{tenant: "test1", status: http.StatusUnprocessableEntity, body: `{"message": "invalid declaration"}`},
+				},
+			})
+			mockPM.postTenantUsingDocumentAPI(&as3Cfg, "test")
+			mockPM.postTenantUsingDocumentAPI(&as3Cfg, "test1")
+			// tenant without a document needs no deletion
+			mockPM.postTenantUsingDocumentAPI(&as3Cfg, "test2")
+			mockPM.updateTenantCache(&as3Cfg)
+			Expect(as3Cfg.failedTenants).To(Equal(map[string]struct{}{"test1": {}}))
+			Expect(mockPM.tenantDeclarationIDMap).To(Equal(map[string]string{"test": "doc0"}))
+		})
+
+		It("Poll accepted deployments of each tenant", func() {
+			mockPM.setMethodResponses(map[string][]responceCtx{
+				http.MethodPost: {
+					{tenant: "test", status: http.StatusOK, body: `{"id": "doc0"}`},
+					{tenant: "test", status: http.StatusAccepted, body: `{"id": "deploy0"}`},
+					{tenant: "test1", status: http.StatusOK, body: `{"id": "doc1"}`},
+					{tenant: "test1", status: http.StatusAccepted, body: `{"id": "deploy1"}`},
+				},
+				http.MethodGet: {
+					{tenant: "test", status: http.StatusOK, body: `{"request": {"test": {}}, "response": {"results": [{"code": 200, "message": "success", "tenant": "test"}]}}`},
+					{tenant: "test1", status: http.StatusOK, body: `{"request": {"test1": {}}, "response": {"results": [{"code": 422, "message": "failed", "tenant": "test1"}]}}`},
+				},
+			})
+			mockPM.postTenantUsingDocumentAPI(&as3Cfg, "test")
+			mockPM.postTenantUsingDocumentAPI(&as3Cfg, "test1")
+			Expect(as3Cfg.tenantTaskIdMap).To(Equal(map[string]string{"test": "doc0/deploy0", "test1": "doc1/deploy1"}))
+			mockPM.pollTenantStatus(&as3Cfg)
+			Expect(as3Cfg.tenantTaskIdMap).To(BeEmpty())
+			Expect(as3Cfg.failedTenants).To(HaveLen(1))
+			Expect(as3Cfg.tenantResponseMap).To(HaveLen(2))
+		})
+
+		It("Poll the deployments in progress until they complete", func() {
+			as3Cfg.tenantTaskIdMap["test"] = "doc0/deploy0"
+			mockPM.setResponses([]responceCtx{
+				{tenant: "test", status: http.StatusOK, body: `{"request": {"test": {}}, "response": {"results": [{"code": 0, "message": "in progress", "tenant": "test"}]}}`},
+				{tenant: "test", status: http.StatusOK, body: `{"request": {"test": {}}, "response": {"results": [{"code": 200, "message": "success", "tenant": "test"}]}}`},
+			}, http.MethodGet)
+			mockPM.getTenantConfigStatus("doc0/deploy0", &as3Cfg, "test")
+			Expect(as3Cfg.tenantTaskIdMap).To(HaveKey("test"))
+			Expect(as3Cfg.tenantResponseMap).NotTo(HaveKey("test"))
+			mockPM.getTenantConfigStatus("doc0/deploy0", &as3Cfg, "test")
+			Expect(as3Cfg.tenantTaskIdMap).To(BeEmpty())
+			Expect(as3Cfg.tenantResponseMap["test"].agentResponseCode).To(Equal(http.StatusOK))
+		})
+
+		It("Retry the failed deletion of a document", func() {
+			mockPM.tenantDeclarationIDMap["test2"] = "doc2"
+			mockPM.setResponses([]responceCtx{
+				{tenant: "test2", status: http.StatusInternalServerError, body: `{"message": "internal error"}`},
+			}, http.MethodDelete)
+			mockPM.postTenantUsingDocumentAPI(&as3Cfg, "test2")
+			mockPM.updateTenantCache(&as3Cfg)
+			Expect(as3Cfg.tenantResponseMap["test2"]).To(Equal(tenantResponse{
+				agentResponseCode: http.StatusInternalServerError,
+				message:           "internal error",
+			}))
+			Expect(as3Cfg.failedTenants).To(HaveKey("test2"))
+			Expect(mockPM.tenantDeclarationIDMap).To(HaveKeyWithValue("test2", "doc2"))
+		})
+	})
+
 	Describe("Get BIGIP AS3 Declaration", func() {
 		It("Get Declaration successfully", func() {
 			tnt := "test"
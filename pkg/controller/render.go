@@ -0,0 +1,347 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	crdfake "github.com/F5Networks/k8s-bigip-ctlr/v3/config/client/clientset/versioned/fake"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/clustermanager"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/teem"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	routeapi "github.com/openshift/api/route/v1"
+	fakeRouteClient "github.com/openshift/client-go/route/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// RenderParams holds the resources which are rendered offline into the AS3 declarations
+type RenderParams struct {
+	// DeployConfig is the global DeployConfig CR of CIS
+	DeployConfig *cisapiv1.DeployConfig
+	// Objects are the VirtualServers, TransportServers, TLSProfiles, Policies, Routes, Services,
+	// Endpoints, EndpointSlices, Secrets, Nodes and Namespaces
+	Objects         []runtime.Object
+	UserAgent       string
+	UseNodeInternal bool
+}
+
+// RenderDeclarations processes the resources the same way as the controller against the fake clientsets and
+// returns the AS3 declaration which CIS would post for each BIG-IP
+func RenderDeclarations(params RenderParams) (map[BigIpKey]string, error) {
+	if params.DeployConfig == nil {
+		return nil, fmt.Errorf("DeployConfig is required to render the declarations")
+	}
+	var kubeObjs, crObjs, routeObjs []runtime.Object
+	crObjs = append(crObjs, params.DeployConfig)
+	manageRoutes := false
+	for _, obj := range params.Objects {
+		switch rsc := obj.(type) {
+		case *cisapiv1.VirtualServer, *cisapiv1.TransportServer, *cisapiv1.TLSProfile, *cisapiv1.Policy,
+			*cisapiv1.IngressLink, *cisapiv1.ExternalDNS:
+			crObjs = append(crObjs, obj)
+		case *routeapi.Route:
+			routeObjs = append(routeObjs, obj)
+			manageRoutes = true
+		case *v1.Service, *v1.Secret, *v1.Node, *v1.Namespace, *v1.Pod, *discoveryv1.EndpointSlice:
+			kubeObjs = append(kubeObjs, obj)
+		case *v1.Endpoints:
+			for _, slice := range endpointSlicesForEndpoints(rsc) {
+				kubeObjs = append(kubeObjs, slice)
+			}
+		case *cisapiv1.DeployConfig:
+			return nil, fmt.Errorf("only one DeployConfig is supported, found %v/%v", rsc.Namespace, rsc.Name)
+		default:
+			return nil, fmt.Errorf("unsupported resource %T", obj)
+		}
+	}
+
+	ctlr := newRenderController(params, manageRoutes)
+	ctlr.clientsets = &ClientSets{
+		kubeClient:    k8sfake.NewSimpleClientset(kubeObjs...),
+		kubeCRClient:  crdfake.NewSimpleClientset(crObjs...),
+		routeClientV1: fakeRouteClient.NewSimpleClientset(routeObjs...).RouteV1(),
+	}
+	ctlr.initController()
+	// static routes and L3 forwards are configured on Central Manager, they are not part of the declaration
+	ctlr.StaticRoutingMode = false
+	ctlr.networkManager = nil
+	ctlr.setRenderNamespaces(kubeObjs)
+	if err := ctlr.setupInformers(); err != nil {
+		return nil, err
+	}
+	ctlr.NewRequestHandler(params.UserAgent, false)
+	ctlr.requestMap = &requestMap{sync.Mutex{}, make(map[BigIpKey]requestMeta)}
+
+	// informers are not started, the resources are added to the informer stores
+	var resources []interface{}
+	for _, obj := range append(append(crObjs, routeObjs...), kubeObjs...) {
+		if ctlr.addToRenderStore(obj) {
+			resources = append(resources, obj)
+		}
+	}
+
+	// process the nodes and DeployConfig CR as in the init state of the controller
+	_ = ctlr.SetupNodeProcessing("")
+	ctlr.processGlobalDeployConfigCR()
+	ctlr.initState = false
+	for _, rsc := range resources {
+		switch obj := rsc.(type) {
+		case *cisapiv1.VirtualServer:
+			ctlr.enqueueVirtualServer(obj)
+		case *cisapiv1.TransportServer:
+			ctlr.enqueueTransportServer(obj)
+		case *cisapiv1.IngressLink:
+			ctlr.enqueueIngressLink(obj)
+		case *cisapiv1.ExternalDNS:
+			ctlr.enqueueExternalDNS(obj)
+		case *routeapi.Route:
+			ctlr.enqueueRoute(obj, Create)
+		case *v1.Service:
+			ctlr.enqueueService(obj, "")
+		}
+	}
+	for ctlr.resourceQueue.Len() > 0 {
+		ctlr.processResources()
+	}
+	ctlr.resourceQueue.ShutDown()
+
+	return ctlr.renderDeclarations(params.UserAgent), nil
+}
+
+// newRenderController creates the controller without the clients to Central Manager and the cluster
+func newRenderController(params RenderParams, manageRoutes bool) *Controller {
+	ctlr := &Controller{
+		resources:             NewResourceStore(),
+		UseNodeInternal:       params.UseNodeInternal,
+		initState:             true,
+		multiClusterConfigs:   clustermanager.NewMultiClusterConfig(),
+		multiClusterResources: newMultiClusterResourceStore(),
+		clusterRatio:          make(map[string]*int),
+		clusterAdminState:     make(map[string]cisapiv1.AdminState),
+		respChan:              make(chan *agentConfig, 1),
+		CMTokenManager:        tokenmanager.NewTokenManager("", tokenmanager.Credentials{}, "", true),
+		managedResources: ManagedResources{
+			ManageRoutes:          manageRoutes,
+			ManageCustomResources: true,
+			ManageVirtualServer:   true,
+			ManageTransportServer: true,
+			ManageTLSProfile:      true,
+			ManageIL:              true,
+			ManageEDNS:            true,
+			ManageSecrets:         true,
+		},
+		bigIpMap:   make(BigIpMap),
+		PostParams: PostParams{},
+		TeemData: &teem.TeemsData{
+			ResourceType: teem.ResourceTypes{
+				Ingresses:       make(map[string]int),
+				Routes:          make(map[string]int),
+				Configmaps:      make(map[string]int),
+				VirtualServer:   make(map[string]int),
+				TransportServer: make(map[string]int),
+				ExternalDNS:     make(map[string]int),
+				IngressLink:     make(map[string]int),
+				IPAMVS:          make(map[string]int),
+				IPAMTS:          make(map[string]int),
+				IPAMSvcLB:       make(map[string]int),
+				NativeRoutes:    make(map[string]int),
+				RouteGroups:     make(map[string]int),
+			},
+		},
+	}
+	ctlr.CISConfigCRKey = params.DeployConfig.Namespace + "/" + params.DeployConfig.Name
+	ctlr.resourceQueue = workqueue.NewNamedRateLimitingQueue(
+		workqueue.DefaultControllerRateLimiter(), "render-resource-controller")
+	return ctlr
+}
+
+// setRenderNamespaces monitors the namespaces matching the namespace label, as the namespace informer is not started
+func (ctlr *Controller) setRenderNamespaces(kubeObjs []runtime.Object) {
+	if ctlr.resourceSelectorConfig.NamespaceLabel == "" {
+		return
+	}
+	selector, err := createLabelSelector(ctlr.resourceSelectorConfig.NamespaceLabel)
+	if err != nil {
+		log.Errorf("[Render] %v", err)
+		return
+	}
+	for _, obj := range kubeObjs {
+		if ns, ok := obj.(*v1.Namespace); ok && selector.Matches(labels.Set(ns.Labels)) {
+			ctlr.namespaces[ns.Name] = true
+		}
+	}
+}
+
+// addToRenderStore adds the resource to the store of its informer, it returns false for the unmonitored resources
+func (ctlr *Controller) addToRenderStore(obj runtime.Object) bool {
+	if node, ok := obj.(*v1.Node); ok {
+		if nodeInf, found := ctlr.multiClusterNodeInformers[""]; found {
+			_ = nodeInf.nodeInformer.GetStore().Add(node)
+		}
+		return false
+	}
+	if _, ok := obj.(*v1.Namespace); ok {
+		return false
+	}
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return false
+	}
+	namespace := meta.GetNamespace()
+	comInf, found := ctlr.getNamespacedCommonInformer(namespace)
+	if !found {
+		log.Warningf("[Render] Skipping %v/%v as the namespace is not monitored", namespace, meta.GetName())
+		return false
+	}
+	crInf, _ := ctlr.getNamespacedCRInformer(namespace)
+	nrInf, _ := ctlr.getNamespacedNativeInformer(namespace)
+	var err error
+	switch rsc := obj.(type) {
+	case *cisapiv1.DeployConfig:
+		err = comInf.configCRInformer.GetStore().Add(rsc)
+	case *cisapiv1.Policy:
+		err = comInf.plcInformer.GetStore().Add(rsc)
+	case *cisapiv1.ExternalDNS:
+		err = comInf.ednsInformer.GetStore().Add(rsc)
+	case *cisapiv1.VirtualServer:
+		err = crInf.vsInformer.GetStore().Add(rsc)
+	case *cisapiv1.TransportServer:
+		err = crInf.tsInformer.GetStore().Add(rsc)
+	case *cisapiv1.TLSProfile:
+		err = crInf.tlsInformer.GetStore().Add(rsc)
+	case *cisapiv1.IngressLink:
+		err = crInf.ilInformer.GetStore().Add(rsc)
+	case *routeapi.Route:
+		err = nrInf.routeInformer.GetStore().Add(rsc)
+	case *v1.Service:
+		err = comInf.svcInformer.GetStore().Add(rsc)
+	case *discoveryv1.EndpointSlice:
+		err = comInf.epsInformer.GetStore().Add(rsc)
+	case *v1.Secret:
+		err = comInf.secretsInformer.GetStore().Add(rsc)
+	case *v1.Pod:
+		if comInf.podInformer == nil {
+			return false
+		}
+		err = comInf.podInformer.GetStore().Add(rsc)
+	}
+	if err != nil {
+		log.Errorf("[Render] Unable to add %v/%v: %v", namespace, meta.GetName(), err)
+		return false
+	}
+	return true
+}
+
+// renderDeclarations creates the AS3 declaration for each BIG-IP from the processed resource config
+func (ctlr *Controller) renderDeclarations(userAgent string) map[BigIpKey]string {
+	declarations := make(map[BigIpKey]string)
+	for bigip := range ctlr.bigIpMap {
+		config, ok := ctlr.resources.bigIpMap[bigip]
+		if !ok {
+			config = BigIpResourceConfig{ltmConfig: make(LTMConfig), gtmConfig: make(GTMConfig)}
+		}
+		as3PM := &AS3PostManager{
+			AS3VersionInfo: as3VersionInfo{
+				as3Version: defaultAS3Version,
+				as3Release: defaultAS3Version + "-" + defaultAS3Build,
+			},
+			AS3Config:       ctlr.PostParams.AS3Config,
+			bigIPAS3Version: as3Version,
+		}
+		tenantDeclMap := make(map[string]as3Tenant)
+		for tenant, cfg := range as3PM.createAS3BIGIPConfig(config, bigip.DefaultPartition, map[string]as3Tenant{}) {
+			tenantDeclMap[tenant] = cfg.(as3Tenant)
+		}
+		for _, bigIpKey := range getBigIpList(bigip) {
+			declarations[bigIpKey] = string(as3PM.createAS3Declaration(tenantDeclMap, userAgent, bigIpKey.BigIpAddress))
+		}
+	}
+	return declarations
+}
+
+// endpointSlicesForEndpoints converts the Endpoints to the EndpointSlices, one for each address type
+func endpointSlicesForEndpoints(eps *v1.Endpoints) []*discoveryv1.EndpointSlice {
+	slices := make(map[discoveryv1.AddressType]*discoveryv1.EndpointSlice)
+	var addressTypes []string
+	for _, subset := range eps.Subsets {
+		var ports []discoveryv1.EndpointPort
+		for _, port := range subset.Ports {
+			port := port
+			ports = append(ports, discoveryv1.EndpointPort{Name: &port.Name, Port: &port.Port, Protocol: &port.Protocol})
+		}
+		addEndpoint := func(addr v1.EndpointAddress, ready bool) {
+			addressType := discoveryv1.AddressTypeIPv4
+			if ip := net.ParseIP(addr.IP); ip != nil && ip.To4() == nil {
+				addressType = discoveryv1.AddressTypeIPv6
+			}
+			slice, ok := slices[addressType]
+			if !ok {
+				slice = &discoveryv1.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("%s-%s", eps.Name, addressType),
+						Namespace: eps.Namespace,
+						Labels:    map[string]string{discoveryv1.LabelServiceName: eps.Name},
+					},
+					AddressType: addressType,
+				}
+				slices[addressType] = slice
+				addressTypes = append(addressTypes, string(addressType))
+			}
+			slice.Ports = appendEndpointPorts(slice.Ports, ports)
+			slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+				Addresses:  []string{addr.IP},
+				Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+				NodeName:   addr.NodeName,
+			})
+		}
+		for _, addr := range subset.Addresses {
+			addEndpoint(addr, true)
+		}
+		for _, addr := range subset.NotReadyAddresses {
+			addEndpoint(addr, false)
+		}
+	}
+	sort.Strings(addressTypes)
+	var result []*discoveryv1.EndpointSlice
+	for _, addressType := range addressTypes {
+		result = append(result, slices[discoveryv1.AddressType(addressType)])
+	}
+	return result
+}
+
+// appendEndpointPorts appends the ports which are not present in the slice
+func appendEndpointPorts(existing, ports []discoveryv1.EndpointPort) []discoveryv1.EndpointPort {
+	for _, port := range ports {
+		found := false
+		for _, p := range existing {
+			if *p.Name == *port.Name && *p.Port == *port.Port {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, port)
+		}
+	}
+	return existing
+}
+
+// FormatDeclaration indents the declaration for the review
+func FormatDeclaration(declaration string) (string, error) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(declaration), "", "  "); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"encoding/json"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	routeapi "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("Render", func() {
+	var deployConfig *cisapiv1.DeployConfig
+	var svc *v1.Service
+	var eps *v1.Endpoints
+	var vs *cisapiv1.VirtualServer
+	var node *v1.Node
+
+	BeforeEach(func() {
+		deployConfig = &cisapiv1.DeployConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "global-cm", Namespace: "kube-system"},
+			Spec: cisapiv1.DeployConfigSpec{
+				BaseConfig: cisapiv1.BaseConfig{ControllerIdentifier: "cluster-1"},
+				NetworkConfig: cisapiv1.NetworkConfig{
+					OrchestrationCNI: OVN_K8S,
+					MetaData:         cisapiv1.CNIConfigMeta{PoolMemberType: Cluster, StaticRoutingMode: true},
+				},
+				BigIpConfig: []cisapiv1.BigIpConfig{{
+					BigIpLabel:       "bigip1",
+					DefaultPartition: "test",
+					BigIpAddress:     "10.8.3.11",
+				}},
+			},
+		}
+		node = test.NewNode("worker1", "1", false,
+			[]v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.10.10.1"}}, nil, nil)
+		svc = test.NewService("svc1", "1", "default", v1.ServiceTypeClusterIP,
+			[]v1.ServicePort{{Name: "port0", Port: 80, TargetPort: intstr.FromInt(8080)}})
+		eps = &v1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc1", Namespace: "default"},
+			Subsets: []v1.EndpointSubset{{
+				Addresses:         []v1.EndpointAddress{{IP: "10.244.1.5", NodeName: &node.Name}},
+				NotReadyAddresses: []v1.EndpointAddress{{IP: "10.244.1.6"}},
+				Ports:             []v1.EndpointPort{{Name: "port0", Port: 8080, Protocol: v1.ProtocolTCP}},
+			}},
+		}
+		vs = test.NewVirtualServer("SampleVS", "default", cisapiv1.VirtualServerSpec{
+			Host:                 "test.com",
+			VirtualServerAddress: "1.2.3.4",
+			Pools:                []cisapiv1.VSPool{{Path: "/path", Service: "svc1", ServicePort: intstr.FromInt(80)}},
+		})
+	})
+
+	It("renders the declaration of the VirtualServer", func() {
+		declarations, err := RenderDeclarations(RenderParams{
+			DeployConfig:    deployConfig,
+			Objects:         []runtime.Object{node, svc, eps, vs},
+			UserAgent:       "cis-render",
+			UseNodeInternal: true,
+		})
+		Expect(err).To(BeNil())
+		key := BigIpKey{BigIpAddress: "10.8.3.11", BigIpLabel: "bigip1"}
+		Expect(declarations).To(HaveKey(key))
+
+		var decl map[string]interface{}
+		Expect(json.Unmarshal([]byte(declarations[key]), &decl)).To(Succeed())
+		adc := decl["declaration"].(map[string]interface{})
+		Expect(adc["target"]).To(Equal(map[string]interface{}{"address": "10.8.3.11"}))
+		Expect(adc).To(HaveKey("test"))
+		Expect(declarations[key]).To(ContainSubstring("1.2.3.4"))
+		Expect(declarations[key]).To(ContainSubstring("10.244.1.5"))
+		Expect(declarations[key]).To(ContainSubstring("cis-render"))
+
+		formatted, err := FormatDeclaration(declarations[key])
+		Expect(err).To(BeNil())
+		Expect(formatted).To(ContainSubstring("\n  \"declaration\": {"))
+	})
+
+	It("renders the declaration of the Route", func() {
+		deployConfig.Spec.ExtendedSpec = cisapiv1.ExtendedSpec{
+			ExtendedRouteGroupConfigs: []cisapiv1.ExtendedRouteGroupConfig{{
+				Namespace:      "default",
+				BigIpPartition: "routes",
+				ExtendedRouteGroupSpec: cisapiv1.ExtendedRouteGroupSpec{
+					VServerName: "routevs",
+					VServerAddr: "10.8.3.100",
+				},
+			}},
+		}
+		route := test.NewRoute("route1", "1", "default", routeapi.RouteSpec{
+			Host: "route.com",
+			Path: "/foo",
+			To:   routeapi.RouteTargetReference{Kind: "Service", Name: "svc1"},
+			Port: &routeapi.RoutePort{TargetPort: intstr.FromInt(8080)},
+		}, nil)
+		declarations, err := RenderDeclarations(RenderParams{
+			DeployConfig:    deployConfig,
+			Objects:         []runtime.Object{node, svc, eps, route},
+			UseNodeInternal: true,
+		})
+		Expect(err).To(BeNil())
+		key := BigIpKey{BigIpAddress: "10.8.3.11", BigIpLabel: "bigip1"}
+		Expect(declarations).To(HaveKey(key))
+		Expect(declarations[key]).To(ContainSubstring("routes"))
+		Expect(declarations[key]).To(ContainSubstring("10.8.3.100"))
+		Expect(declarations[key]).To(ContainSubstring("10.244.1.5"))
+	})
+
+	It("renders the same declaration every time", func() {
+		params := RenderParams{DeployConfig: deployConfig, Objects: []runtime.Object{node, svc, eps, vs}, UseNodeInternal: true}
+		first, err := RenderDeclarations(params)
+		Expect(err).To(BeNil())
+		second, err := RenderDeclarations(params)
+		Expect(err).To(BeNil())
+		Expect(second).To(Equal(first))
+	})
+
+	It("rejects the unsupported resources", func() {
+		_, err := RenderDeclarations(RenderParams{Objects: []runtime.Object{vs}})
+		Expect(err).NotTo(BeNil())
+		_, err = RenderDeclarations(RenderParams{
+			DeployConfig: deployConfig,
+			Objects:      []runtime.Object{&v1.ConfigMap{}},
+		})
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("converts the Endpoints to the EndpointSlices", func() {
+		eps.Subsets[0].Addresses = append(eps.Subsets[0].Addresses, v1.EndpointAddress{IP: "fd00::5"})
+		slices := endpointSlicesForEndpoints(eps)
+		Expect(slices).To(HaveLen(2))
+		Expect(slices[0].Name).To(Equal("svc1-IPv4"))
+		Expect(slices[0].Endpoints).To(HaveLen(2))
+		Expect(*slices[0].Endpoints[1].Conditions.Ready).To(BeFalse())
+		Expect(slices[1].Name).To(Equal("svc1-IPv6"))
+		Expect(slices[1].Labels).To(HaveKeyWithValue("kubernetes.io/service-name", "svc1"))
+		Expect(*slices[1].Ports[0].Port).To(Equal(int32(8080)))
+	})
+})
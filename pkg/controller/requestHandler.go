@@ -35,6 +35,8 @@ func (req *RequestHandler) startPostManager(config cisapiv1.BigIpConfig) {
 			pm := NewPostManager(req.PostParams, config.DefaultPartition)
 			pm.respChan = req.respChan
 			pm.tokenManager = req.CMTokenManager
+			pm.bigIpKey = bigIpKey
+			pm.UserAgent = req.userAgent
 			// update agent Map
 			req.PostManagers.PostManagerMap[bigIpKey] = pm
 			// increase the Agent Count
@@ -54,18 +56,21 @@ func (req *RequestHandler) EnqueueRequestConfig(rsConfig ResourceConfigRequest)
 	case req.reqChan <- rsConfig:
 	case <-time.After(3 * time.Millisecond):
 	}
+	prometheus.QueueDepth.WithLabelValues(RequestChannelName, "").Set(float64(len(req.reqChan)))
 }
 
 // RequestHandler blocks on reqChan
 // whenever it gets unblocked, it creates an as3, l3 declaration for respective bigip and puts on post channel for postmanger to handle
 func (req *RequestHandler) requestHandler() {
 	for rsConfig := range req.reqChan {
+		prometheus.QueueDepth.WithLabelValues(RequestChannelName, "").Set(float64(len(req.reqChan)))
 		req.PostManagers.RLock()
 		if pm, ok := req.PostManagers.PostManagerMap[rsConfig.bigIpKey]; ok {
 			//create post config declaration for BigIp pair and put in post channel
 			cfg := req.createDeclarationForBIGIP(rsConfig, pm)
 			if !reflect.DeepEqual(cfg, agentConfig{}) {
 				pm.postChan <- cfg
+				prometheus.QueueDepth.WithLabelValues(PostChannelName, rsConfig.bigIpKey.BigIpAddress).Set(float64(len(pm.postChan)))
 			}
 		}
 		req.PostManagers.RUnlock()
@@ -104,14 +109,18 @@ func (req *RequestHandler) createDeclarationForBIGIP(rsConfig ResourceConfigRequ
 	//for each request config create AS3, L3 declaration
 	// create the AS3 declaration for the bigip
 	as3cfg := req.createAS3Config(rsConfig, pm)
-	if len(rsConfig.bigIpResourceConfig.ltmConfig) == 0 {
+	if len(rsConfig.bigIpResourceConfig.ltmConfig) == 0 && !rsConfig.bigIpResourceConfig.gtmConfig.hasWideIPs() {
 		as3cfg.deleted = true
 	}
-	// TODO : Create the L3 declaration for the bigip
+	// create the L3 declaration for the bigip
+	l3cfg := l3Config{
+		networkConfig: req.getL3NetworkConfig(rsConfig.bigIpKey.BigIpAddress),
+		vxlanConfig:   req.getVxlanConfig(rsConfig.bigIpResourceConfig.ltmConfig),
+	}
 	agentCfg = agentConfig{
 		id:        rsConfig.reqMeta.id,
 		as3Config: as3cfg,
-		l3Config:  l3Config{},
+		l3Config:  l3cfg,
 		BigIpKey:  rsConfig.bigIpKey,
 		reqMeta:   rsConfig.reqMeta}
 	return agentCfg
@@ -3,6 +3,7 @@ package controller
 import (
 	"encoding/json"
 	v1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/as3schema"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
 	"net/http"
 	"strings"
@@ -396,91 +397,161 @@ var _ = Describe("Backend Tests", func() {
 	})
 
 	Describe("GTM Config", func() {
-		//var requesthandler Agent
+		var as3PM *AS3PostManager
 		BeforeEach(func() {
-			//requesthandler = newMockAgent()
+			as3PM = &AS3PostManager{AS3Config: v1.AS3Config{}}
 			DEFAULT_PARTITION = "default"
 		})
-		// Commenting this test case
-		// with new GTM partition support we will not delete partition, instead we flush contents
-		//It("Empty GTM Config", func() {
-		//	adc := as3ADC{}
-		//	adc = requesthandler.createAS3GTMConfigADC(ResourceConfigRequest{
-		//		gtmConfig: GTMConfig{},
-		//	}, adc)
-		//
-		//	Expect(len(adc)).To(BeZero(), "Invalid GTM Config")
-		//})
-
-		//It("Empty GTM Partition Config / Delete Case", func() {
-		//	adc := as3ADC{}
-		//	adc = requesthandler.createAS3GTMConfigADC(BigIpResourceConfig{
-		//		gtmConfig: GTMConfig{
-		//			DEFAULT_PARTITION: GTMPartitionConfig{},
-		//		},
-		//	}, adc)
-		//	Expect(len(adc)).To(Equal(1), "Invalid GTM Config")
-		//
-		//	Expect(adc).To(HaveKey(DEFAULT_PARTITION))
-		//	tenant := adc[DEFAULT_PARTITION].(as3Tenant)
-		//
-		//	Expect(tenant).To(HaveKey(as3SharedApplication))
-		//	sharedApp := tenant[as3SharedApplication].(as3Application)
-		//	Expect(len(sharedApp)).To(Equal(2))
-		//	Expect(sharedApp).To(HaveKeyWithValue("class", "Application"))
-		//	Expect(sharedApp).To(HaveKeyWithValue("template", "shared"))
-		//})
-
-		//It("Valid GTM Config", func() {
-		//	monitors := []Monitor{
-		//		{
-		//			Name:     "pool1_monitor",
-		//			Interval: 10,
-		//			Timeout:  10,
-		//			Type:     "http",
-		//			Send:     "GET /health",
-		//		},
-		//	}
-		//	gtmConfig := GTMConfig{
-		//		DEFAULT_PARTITION: GTMPartitionConfig{
-		//			WideIPs: map[string]WideIP{
-		//				"test.com": {
-		//					DomainName: "test.com",
-		//					RecordType: "A",
-		//					LBMethod:   "round-robin",
-		//					Pools: []GSLBPool{
-		//						{
-		//							Name:       "pool1",
-		//							RecordType: "A",
-		//							LBMethod:   "round-robin",
-		//							Members:    []string{"vs1", "vs2"},
-		//							Monitors:   monitors,
-		//						},
-		//					},
-		//				},
-		//			},
-		//		},
-		//	}
-		//	adc := requesthandler.createAS3GTMConfigADC(
-		//		BigIpResourceConfig{gtmConfig: gtmConfig},
-		//		as3ADC{},
-		//	)
-		//
-		//	Expect(adc).To(HaveKey(DEFAULT_PARTITION))
-		//	tenant := adc[DEFAULT_PARTITION].(as3Tenant)
-		//
-		//	Expect(tenant).To(HaveKey(as3SharedApplication))
-		//	sharedApp := tenant[as3SharedApplication].(as3Application)
-		//
-		//	Expect(sharedApp).To(HaveKey("test.com"))
-		//	Expect(sharedApp["test.com"].(as3GLSBDomain).Class).To(Equal("GSLB_Domain"))
-		//
-		//	Expect(sharedApp).To(HaveKey("pool1"))
-		//	Expect(sharedApp["pool1"].(as3GSLBPool).Class).To(Equal("GSLB_Pool"))
-		//
-		//	Expect(sharedApp).To(HaveKey("pool1_monitor"))
-		//	Expect(sharedApp["pool1_monitor"].(as3GSLBMonitor).Class).To(Equal("GSLB_Monitor"))
-		//})
+
+		It("Empty GTM Config", func() {
+			adc := as3PM.createAS3GTMConfigADC(BigIpResourceConfig{
+				gtmConfig: GTMConfig{},
+			}, as3ADC{}, DEFAULT_PARTITION, make(map[string]as3Tenant))
+
+			Expect(len(adc)).To(BeZero(), "Invalid GTM Config")
+		})
+
+		It("Empty GTM Partition Config / Delete Case", func() {
+			cachedTenantDeclMap := map[string]as3Tenant{
+				getGTMTenantName(DEFAULT_PARTITION): {"class": "Tenant"},
+			}
+			adc := as3PM.createAS3GTMConfigADC(BigIpResourceConfig{
+				gtmConfig: GTMConfig{
+					DEFAULT_GTM_PARTITION: GTMPartitionConfig{WideIPs: map[string]WideIP{}},
+				},
+			}, as3ADC{}, DEFAULT_PARTITION, cachedTenantDeclMap)
+			Expect(len(adc)).To(Equal(1), "Invalid GTM Config")
+			Expect(adc).To(HaveKeyWithValue(getGTMTenantName(DEFAULT_PARTITION), as3Tenant{"class": "Tenant"}))
+		})
+
+		It("Valid GTM Config", func() {
+			monitors := []Monitor{
+				{
+					Name:     "pool1_monitor",
+					Interval: 10,
+					Timeout:  10,
+					Type:     "http",
+					Send:     "GET /health",
+				},
+			}
+			gtmConfig := GTMConfig{
+				DEFAULT_GTM_PARTITION: GTMPartitionConfig{
+					WideIPs: map[string]WideIP{
+						"test.com": {
+							DomainName: "test.com",
+							RecordType: "A",
+							LBMethod:   "round-robin",
+							Pools: []GSLBPool{
+								{
+									Name:       "pool1",
+									RecordType: "A",
+									LBMethod:   "round-robin",
+									Members:    []string{"/default/Shared/vs2", "/default/Shared/vs1"},
+									Monitors:   monitors,
+									DataServer: "/Common/GSLBServer",
+									Ratio:      2,
+								},
+							},
+						},
+					},
+				},
+			}
+			adc := as3PM.createAS3GTMConfigADC(
+				BigIpResourceConfig{gtmConfig: gtmConfig},
+				as3ADC{},
+				DEFAULT_PARTITION,
+				make(map[string]as3Tenant),
+			)
+
+			Expect(adc).To(HaveKey(getGTMTenantName(DEFAULT_PARTITION)))
+			tenant := adc[getGTMTenantName(DEFAULT_PARTITION)].(as3Tenant)
+			Expect(tenant).To(HaveKeyWithValue("label", DEFAULT_PARTITION))
+
+			Expect(tenant).To(HaveKey(as3SharedApplication))
+			sharedApp := tenant[as3SharedApplication].(as3Application)
+
+			Expect(sharedApp).To(HaveKey("test.com"))
+			Expect(sharedApp["test.com"].(as3GLSBDomain).Class).To(Equal("GSLB_Domain"))
+			Expect(sharedApp["test.com"].(as3GLSBDomain).Pools).To(Equal([]as3GSLBDomainPool{{Use: "pool1", Ratio: 2}}))
+
+			Expect(sharedApp).To(HaveKey("pool1"))
+			gslbPool := sharedApp["pool1"].(as3GSLBPool)
+			Expect(gslbPool.Class).To(Equal("GSLB_Pool"))
+			Expect(len(gslbPool.Members)).To(Equal(2))
+			Expect(gslbPool.Members[0].VirtualServer).To(Equal("/default/Shared/vs1"))
+			Expect(gslbPool.Members[0].Server.BigIP).To(Equal("/Common/GSLBServer"))
+			Expect(gslbPool.Monitors).To(Equal([]as3ResourcePointer{{Use: "pool1_monitor"}}))
+
+			Expect(sharedApp).To(HaveKey("pool1_monitor"))
+			Expect(sharedApp["pool1_monitor"].(as3GSLBMonitor).Class).To(Equal("GSLB_Monitor"))
+		})
+
+		It("GTM tenant is retained with LTM tenant deletion", func() {
+			pm := &PostManager{
+				AS3PostManager:      &AS3PostManager{AS3Config: v1.AS3Config{}},
+				tokenManager:        &tokenmanager.TokenManager{},
+				cachedTenantDeclMap: map[string]as3Tenant{getGTMTenantName("test"): {"class": "Tenant"}},
+				defaultPartition:    "test",
+			}
+			config := ResourceConfigRequest{
+				bigIpResourceConfig: BigIpResourceConfig{ltmConfig: LTMConfig{}, gtmConfig: GTMConfig{
+					DEFAULT_GTM_PARTITION: GTMPartitionConfig{WideIPs: map[string]WideIP{
+						"test.com": {DomainName: "test.com", RecordType: "A", LBMethod: "round-robin"},
+					}},
+				}},
+			}
+			as3Cfg := newMockAgent("as3").createAS3Config(config, pm)
+			Expect(as3Cfg.incomingTenantDeclMap).To(HaveKey(getGTMTenantName("test")))
+			Expect(as3Cfg.incomingTenantDeclMap[getGTMTenantName("test")]).To(HaveKey(as3SharedApplication))
+			Expect(strings.Contains(as3Cfg.data, "GSLB_Domain")).To(BeTrue())
+		})
+
+		It("Tenants failing the schema validation are not posted", func() {
+			validator, err := as3schema.NewValidator("../../schemas/as3-schema-3.48.0-10-cis.json")
+			Expect(err).To(BeNil())
+			pm := &PostManager{
+				AS3PostManager:      &AS3PostManager{AS3Config: v1.AS3Config{}},
+				PostParams:          PostParams{as3Validator: validator},
+				tokenManager:        &tokenmanager.TokenManager{},
+				cachedTenantDeclMap: make(map[string]as3Tenant),
+				defaultPartition:    "test",
+			}
+			newTS := func(snat string) *ResourceConfig {
+				rsCfg := &ResourceConfig{}
+				rsCfg.MetaData.ResourceType = TransportServer
+				rsCfg.Virtual.Name = "crd_ts_172_13_14_6_1600"
+				rsCfg.Virtual.Mode = "standard"
+				rsCfg.Virtual.IpProtocol = "tcp"
+				rsCfg.Virtual.Destination = "172.13.14.6:1600"
+				rsCfg.Virtual.SNAT = snat
+				return rsCfg
+			}
+			zero := 0
+			config := ResourceConfigRequest{
+				bigIpResourceConfig: BigIpResourceConfig{ltmConfig: LTMConfig{
+					"valid":   &PartitionConfig{ResourceMap: ResourceMap{"ts": newTS("auto")}, Priority: &zero},
+					"invalid": &PartitionConfig{ResourceMap: ResourceMap{"ts": newTS("")}, Priority: &zero},
+				}},
+			}
+			as3Cfg := newMockAgent("as3").createAS3Config(config, pm)
+			Expect(as3Cfg.incomingTenantDeclMap).To(HaveKey("valid"))
+			Expect(as3Cfg.incomingTenantDeclMap).NotTo(HaveKey("invalid"))
+			Expect(as3Cfg.invalidTenants).To(HaveKey("invalid"))
+			Expect(as3Cfg.invalidTenants["invalid"]).To(ContainSubstring("/Shared/crd_ts_172_13_14_6_1600/snat"))
+			Expect(strings.Contains(as3Cfg.data, `"valid"`)).To(BeTrue())
+			Expect(strings.Contains(as3Cfg.data, `"invalid"`)).To(BeFalse())
+
+			// invalid tenant is reported as failed, but not retried
+			as3Cfg.tenantResponseMap["valid"] = tenantResponse{agentResponseCode: http.StatusOK}
+			pm.updateTenantCache(&as3Cfg)
+			Expect(pm.cachedTenantDeclMap).To(HaveKey("valid"))
+			Expect(as3Cfg.failedTenants).To(Equal(map[string]struct{}{"invalid": {}}))
+			Expect(as3Cfg.tenantResponseMap["invalid"].agentResponseCode).To(Equal(http.StatusUnprocessableEntity))
+			Expect(getTenantErrorMessage(as3Cfg.tenantResponseMap["invalid"])).To(HavePrefix("AS3 schema validation failed"))
+			Expect(as3Cfg.hasRetryableTenants()).To(BeFalse())
+			as3Cfg.failedTenants["valid"] = struct{}{}
+			Expect(as3Cfg.hasRetryableTenants()).To(BeTrue())
+		})
 	})
 
 	Describe("Misc", func() {
@@ -34,6 +34,7 @@ import (
 	routeapi "github.com/openshift/api/route/v1"
 
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/clustermanager"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	v1 "k8s.io/api/core/v1"
 )
@@ -431,6 +432,23 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 	passthroughVS bool,
 	tlsTermination string,
 ) error {
+	rsRef := resourceRef{
+		name:      vs.Name,
+		namespace: vs.Namespace,
+		kind:      VirtualServer,
+	}
+	return ctlr.prepareRSConfigFromVirtualServerSpec(rsCfg, vs, rsRef, passthroughVS, tlsTermination)
+}
+
+// prepareRSConfigFromVirtualServerSpec prepares resource config based on VirtualServer spec, the pools are
+// tracked against the rsRef which owns the virtual
+func (ctlr *Controller) prepareRSConfigFromVirtualServerSpec(
+	rsCfg *ResourceConfig,
+	vs *cisapiv1.VirtualServer,
+	rsRef resourceRef,
+	passthroughVS bool,
+	tlsTermination string,
+) error {
 
 	var httpsPort int32
 	var httpPort int32
@@ -449,15 +467,8 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 	var pools Pools
 	var rules *Rules
 
-	rsRef := resourceRef{
-		name:      vs.Name,
-		namespace: vs.Namespace,
-		kind:      VirtualServer,
-	}
 	framedPools := make(map[string]struct{})
-	///TODO: get bigipLabel from cr resource or service address cr resource
-	//	//Phase1 setting bigipLabel to default
-	bigipLabel := BigIPLabel
+	bigipLabel := ctlr.getBIGIPConfig(vs.Spec.BigIpLabel).BigIpLabel
 	for _, pl := range vs.Spec.Pools {
 		//Fetch service backends with weights for pool
 		backendSvcs := ctlr.GetPoolBackends(&pl)
@@ -781,9 +792,7 @@ func (ctlr *Controller) handleDefaultPool(
 	if rsCfg.MetaData.Protocol == HTTP && len(vs.Spec.TLSProfileName) > 0 && (vs.Spec.HTTPTraffic == TLSRedirectInsecure || vs.Spec.HTTPTraffic == TLSNoInsecure) {
 		return
 	}
-	///TODO: get bigipLabel from cr resource or service address cr resource
-	//	//Phase1 setting bigipLabel to default
-	bigipLabel := BigIPLabel
+	bigipLabel := ctlr.getBIGIPConfig(vs.Spec.BigIpLabel).BigIpLabel
 	if !reflect.DeepEqual(vs.Spec.DefaultPool, cisapiv1.DefaultPool{}) {
 		if vs.Spec.DefaultPool.Reference == BIGIP && vs.Spec.DefaultPool.Name != "" {
 			rsCfg.Virtual.PoolName = vs.Spec.DefaultPool.Name
@@ -1128,6 +1137,18 @@ func (ctlr *Controller) handleTLS(
 	return true
 }
 
+// getVirtualServerPoolPathRefs returns the pool path references of the pools of the VirtualServer host
+func (ctlr *Controller) getVirtualServerPoolPathRefs(vs *cisapiv1.VirtualServer) []poolPathRef {
+	var poolPathRefs []poolPathRef
+	for _, pl := range vs.Spec.Pools {
+		for _, backend := range ctlr.GetPoolBackends(&pl) {
+			poolName := ctlr.framePoolNameForVs(vs.Namespace, pl, vs.Spec.Host, backend)
+			poolPathRefs = append(poolPathRefs, poolPathRef{pl.Path, poolName, []string{vs.Spec.Host}})
+		}
+	}
+	return poolPathRefs
+}
+
 // handleVirtualServerTLS handles TLS configuration for the Virtual Server resource
 // Return value is whether or not a custom profile was updated
 func (ctlr *Controller) handleVirtualServerTLS(
@@ -1859,6 +1880,21 @@ func (ctlr *Controller) prepareRSConfigFromTransportServer(
 	rsCfg *ResourceConfig,
 	vs *cisapiv1.TransportServer,
 ) error {
+	rsRef := resourceRef{
+		name:      vs.Name,
+		namespace: vs.Namespace,
+		kind:      TransportServer,
+	}
+	return ctlr.prepareRSConfigFromTransportServerSpec(rsCfg, vs, rsRef)
+}
+
+// prepareRSConfigFromTransportServerSpec prepares resource config based on TransportServer spec, the pool is
+// tracked against the rsRef which owns the virtual
+func (ctlr *Controller) prepareRSConfigFromTransportServerSpec(
+	rsCfg *ResourceConfig,
+	vs *cisapiv1.TransportServer,
+	rsRef resourceRef,
+) error {
 
 	poolName := ctlr.framePoolNameForTS(
 		vs.ObjectMeta.Namespace,
@@ -1889,14 +1925,7 @@ func (ctlr *Controller) prepareRSConfigFromTransportServer(
 		clusterName: "",
 		namespace:   vs.Namespace,
 	}
-	rsRef := resourceRef{
-		name:      vs.Name,
-		namespace: vs.Namespace,
-		kind:      TransportServer,
-	}
-	//TODO: get bigipLabel from cr resource or service address cr resource
-	//	//Phase1 setting bigipLabel to default
-	bigipLabel := BigIPLabel
+	bigipLabel := ctlr.getBIGIPConfig(vs.Spec.BigIpLabel).BigIpLabel
 	// update the pool identifier for service
 	ctlr.updatePoolIdentifierForService(svcKey, rsRef, vs.Spec.Pool.ServicePort, pool.Name, pool.Partition, rsCfg.Virtual.Name, "", bigipLabel)
 
@@ -2039,9 +2068,7 @@ func (ctlr *Controller) prepareRSConfigFromLBService(
 		namespace: svc.Namespace,
 		kind:      Service,
 	}
-	///TODO: get bigipLabel from cr resource or service address cr resource
-	//	//Phase1 setting bigipLabel to default
-	bigipLabel := BigIPLabel
+	bigipLabel := ctlr.getBIGIPConfig(svc.Annotations[LBServiceBigIpLabelAnnotation]).BigIpLabel
 	// update the pool identifier for service
 	ctlr.updatePoolIdentifierForService(svcKey, rsRef, pool.ServicePort, pool.Name, pool.Partition, rsCfg.Virtual.Name, "", bigipLabel)
 	// Update the pool Members
@@ -2270,6 +2297,7 @@ func (rs *ResourceStore) getExtendedRouteSpec(routeGroup string) (*cisapiv1.Exte
 			VServerName:   extdSpec.global.VServerName,
 			VServerAddr:   extdSpec.global.VServerAddr,
 			AllowOverride: extdSpec.global.AllowOverride,
+			BigIpLabel:    extdSpec.global.BigIpLabel,
 		}
 
 		if extdSpec.local.VServerName != "" {
@@ -2291,6 +2319,14 @@ func (rs *ResourceStore) getExtendedRouteSpec(routeGroup string) (*cisapiv1.Exte
 	return extdSpec.global, extdSpec.partition
 }
 
+// getRouteGroupBigIpLabel returns the bigIpLabel of the BIG-IP where the RouteGroup is posted
+func (rs *ResourceStore) getRouteGroupBigIpLabel(routeGroup string) string {
+	if extdSpec, _ := rs.getExtendedRouteSpec(routeGroup); extdSpec != nil {
+		return extdSpec.BigIpLabel
+	}
+	return ""
+}
+
 // handleRouteTLS handles TLS configuration for the Route resource
 // Return value is whether or not a custom profile was updated
 func (ctlr *Controller) handleRouteTLS(
@@ -2714,8 +2750,10 @@ func (ctlr *Controller) GetPoolBackends(pool *cisapiv1.VSPool) []SvcBackendCxt {
 // updatePoolMembersConfig updates the common config related to pool members
 func (ctlr *Controller) updatePoolMembersConfig(poolMembers *[]PoolMember, clusterName string, podConnections int32) {
 	for i := 0; i < len(*poolMembers); i++ {
-		// updates the admin state of pool members based on the cluster admin state
-		if adminState, ok := ctlr.clusterAdminState[clusterName]; ok && adminState != "" {
+		// updates the admin state of pool members based on the cluster admin state, draining members of terminating
+		// endpoints are not enabled
+		if adminState, ok := ctlr.clusterAdminState[clusterName]; ok && adminState != "" &&
+			!(adminState == clustermanager.Enable && (*poolMembers)[i].AdminState == string(clustermanager.Disable)) {
 			(*poolMembers)[i].AdminState = string(adminState)
 		}
 		// updates the connection limit of pool members based on the pod connections allowed
@@ -2785,16 +2823,17 @@ func ParseRewriteAction(targetUrlPath, valueUrlPath string) string {
 }
 
 func (rs *ResourceStore) getBigIpResourceConfig(bigipLabel string) BigIpResourceConfig {
-	for bigIp, rsCfg := range rs.bigIpMap {
-		//Phase1 getting bigipconfig from index 0 if bigipLabel is not specified
-		if bigipLabel == "" {
-			return rsCfg
-		} else {
-			if bigIp.BigIpLabel == bigipLabel {
-				return rsCfg
-			}
+	var bigipConfigs BIGIPConfigs
+	for bigIp := range rs.bigIpMap {
+		// resources without bigipLabel belong to the BIG-IP which comes first in the order of the bigIpLabel
+		if bigipLabel == "" || bigIp.BigIpLabel == bigipLabel {
+			bigipConfigs = append(bigipConfigs, bigIp)
 		}
 	}
-	log.Debugf("No BigIpResourceConfig found for bigipLabel: %s", bigipLabel)
-	return BigIpResourceConfig{}
+	if len(bigipConfigs) == 0 {
+		log.Debugf("No BigIpResourceConfig found for bigipLabel: %s", bigipLabel)
+		return BigIpResourceConfig{}
+	}
+	sort.Sort(bigipConfigs)
+	return rs.bigIpMap[bigipConfigs[0]]
 }
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// resourceEventQueue records the time of the oldest resource event which is not posted yet
+type resourceEventQueue struct {
+	workqueue.RateLimitingInterface
+	lock           sync.Mutex
+	firstEventTime time.Time
+}
+
+func newResourceEventQueue(queue workqueue.RateLimitingInterface) *resourceEventQueue {
+	return &resourceEventQueue{RateLimitingInterface: queue}
+}
+
+// Add records the time of the event and adds the resource key to the queue,
+// resource keys which are retried with rate limit are not recorded as new events
+func (q *resourceEventQueue) Add(item interface{}) {
+	q.lock.Lock()
+	if q.firstEventTime.IsZero() {
+		q.firstEventTime = time.Now()
+	}
+	q.lock.Unlock()
+	q.RateLimitingInterface.Add(item)
+}
+
+// getFirstEventTime returns the time of the oldest event which is not posted yet and resets it
+func (ctlr *Controller) getFirstEventTime() time.Time {
+	q, ok := ctlr.resourceQueue.(*resourceEventQueue)
+	if !ok {
+		return time.Time{}
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	eventTime := q.firstEventTime
+	q.firstEventTime = time.Time{}
+	return eventTime
+}
@@ -1,14 +1,19 @@
 package controller
 
 import (
+	"fmt"
 	ficV1 "github.com/F5Networks/f5-ipam-controller/pkg/ipamapis/apis/fic/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
 
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func (ctlr *Controller) enqueueReq(config BigIpResourceConfig, bigIpKey BigIpKey) requestMeta {
@@ -37,25 +42,37 @@ func (ctlr *Controller) enqueueReq(config BigIpResourceConfig, bigIpKey BigIpKey
 func (ctlr *Controller) responseHandler(respChan chan *agentConfig) {
 	// todo: update only when there is a change(success to fail or vice versa) in tenant status
 	ctlr.requestMap = &requestMap{sync.Mutex{}, make(map[BigIpKey]requestMeta)}
-	//TODO: Need to get bigipLabel from rspchan
-	bigipLabel := BigIPLabel
-	bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
 	for config := range respChan {
+		bigipConfig := ctlr.getBIGIPConfig(config.BigIpKey.BigIpLabel)
 		ctlr.requestMap.Lock()
 		latestRequestMeta, _ := ctlr.requestMap.requestMap[config.BigIpKey]
 		ctlr.requestMap.Unlock()
-		if len(config.as3Config.failedTenants) > 0 && latestRequestMeta.id == config.id {
-			// if the current request id is same as the failed tenant request id, then retry the failed tenants
+		if config.isRetryable() && latestRequestMeta.id == config.id {
+			// if the current request id is same as the failed request id, then retry the failed tenants and network config
 			ctlr.RequestHandler.PostManagers.RLock()
 			pm := ctlr.RequestHandler.PostManagers.PostManagerMap[config.BigIpKey]
+			for tenant := range config.as3Config.failedTenants {
+				if _, invalid := config.as3Config.invalidTenants[tenant]; !invalid {
+					prometheus.TenantRetries.WithLabelValues(config.BigIpKey.BigIpAddress, tenant).Inc()
+				}
+			}
 			// Delay the retry of failed tenants
 			<-time.After(timeoutMedium)
 			pm.postChan <- *config
 			ctlr.RequestHandler.PostManagers.RUnlock()
 		}
-		if latestRequestMeta.id >= config.id && len(config.as3Config.failedTenants) == 0 {
-			// Handle the network routes after successful post of tenants
-			ctlr.processStaticRouteUpdate()
+		if latestRequestMeta.id == config.id {
+			// promote the staged requests once the canary BIG-IP has deployed the declaration
+			ctlr.RequestHandler.handleRolloutResponse(config)
+		}
+		if latestRequestMeta.id >= config.id {
+			if len(config.as3Config.failedTenants) == 0 {
+				// Handle the network routes after successful post of tenants
+				ctlr.processStaticRouteUpdate()
+				if !config.reqMeta.eventTime.IsZero() {
+					prometheus.DeployLatency.WithLabelValues(config.BigIpKey.BigIpAddress).Observe(time.Since(config.reqMeta.eventTime).Seconds())
+				}
+			}
 			// if the current request id is less than or equal to the latest request id, then udpate the status for current request
 			for partition, meta := range config.reqMeta.partitionMap {
 				// Check if it's a priority tenant and not in failedTenants map, if so then update the priority back to zero
@@ -69,42 +86,46 @@ func (ctlr *Controller) responseHandler(respChan chan *agentConfig) {
 					ctlr.removeUnusedIPAMEntries(kind)
 					ns := strings.Split(rscKey, "/")[0]
 					switch kind {
-					//case VirtualServer:
-					//	// update status
-					//	crInf, ok := ctlr.getNamespacedCRInformer(ns)
-					//	if !ok {
-					//		log.Debugf("VirtualServer Informer not found for namespace: %v", ns)
-					//		continue
-					//	}
-					//	obj, exist, err := crInf.vsInformer.GetIndexer().GetByKey(rscKey)
-					//	if err != nil {
-					//		log.Debugf("Could not fetch VirtualServer: %v: %v", rscKey, err)
-					//		continue
-					//	}
-					//	if !exist {
-					//		log.Debugf("VirtualServer Not Found: %v", rscKey)
-					//		continue
-					//	}
-					//	virtual := obj.(*cisapiv1.VirtualServer)
-					//	if virtual.Namespace+"/"+virtual.Name == rscKey {
-					//		if _, found := config.as3Config.failedTenants[partition]; !found {
-					//			// update the status for virtual server as tenant posting is success
-					//			ctlr.updateVirtualServerStatus(virtual, virtual.Status.VSAddress, "Ok")
-					//			// Update Corresponding Service Status of Type LB
-					//			for _, pool := range virtual.Spec.Pools {
-					//				var svcNamespace string
-					//				if pool.ServiceNamespace != "" {
-					//					svcNamespace = pool.ServiceNamespace
-					//				} else {
-					//					svcNamespace = virtual.Namespace
-					//				}
-					//				svc := ctlr.GetService(svcNamespace, pool.Service)
-					//				if svc != nil && svc.Spec.Type == v1.ServiceTypeLoadBalancer {
-					//					ctlr.setLBServiceIngressStatus(svc, virtual.Status.VSAddress)
-					//				}
-					//			}
-					//		}
-					//	}
+					case VirtualServer:
+						// update status
+						crInf, ok := ctlr.getNamespacedCRInformer(ns)
+						if !ok {
+							log.Debugf("VirtualServer Informer not found for namespace: %v", ns)
+							continue
+						}
+						obj, exist, err := crInf.vsInformer.GetIndexer().GetByKey(rscKey)
+						if err != nil {
+							log.Debugf("Could not fetch VirtualServer: %v: %v", rscKey, err)
+							continue
+						}
+						if !exist {
+							log.Debugf("VirtualServer Not Found: %v", rscKey)
+							continue
+						}
+						virtual := obj.(*cisapiv1.VirtualServer)
+						if virtual.Namespace+"/"+virtual.Name == rscKey {
+							if _, found := config.as3Config.failedTenants[partition]; found {
+								// update the status for virtual server with the error reported for the tenant
+								ctlr.updateVirtualServerStatus(virtual, virtual.Status.VSAddress, StatusFailed,
+									getTenantErrorMessage(config.as3Config.tenantResponseMap[partition]))
+								continue
+							}
+							// update the status for virtual server as tenant posting is success
+							ctlr.updateVirtualServerStatus(virtual, virtual.Status.VSAddress, StatusOk, "")
+							// Update Corresponding Service Status of Type LB
+							for _, pool := range virtual.Spec.Pools {
+								var svcNamespace string
+								if pool.ServiceNamespace != "" {
+									svcNamespace = pool.ServiceNamespace
+								} else {
+									svcNamespace = virtual.Namespace
+								}
+								svc := ctlr.GetService(svcNamespace, pool.Service)
+								if svc != nil && svc.Spec.Type == v1.ServiceTypeLoadBalancer && virtual.Status.VSAddress != "" {
+									ctlr.setLBServiceIngressStatus(svc, virtual.Status.VSAddress)
+								}
+							}
+						}
 
 					case TransportServer:
 						// update status
@@ -124,9 +145,12 @@ func (ctlr *Controller) responseHandler(respChan chan *agentConfig) {
 						}
 						virtual := obj.(*cisapiv1.TransportServer)
 						if virtual.Namespace+"/"+virtual.Name == rscKey {
-							if _, found := config.as3Config.failedTenants[partition]; !found {
+							if _, found := config.as3Config.failedTenants[partition]; found {
+								ctlr.recordEventf(virtual, v1.EventTypeWarning, DeployFailed, "Failed to deploy TransportServer on BIG-IP: %v",
+									getTenantErrorMessage(config.as3Config.tenantResponseMap[partition]))
+							} else {
 								// update the status for transport server as tenant posting is success
-								ctlr.updateTransportServerStatus(virtual, virtual.Status.VSAddress, "Ok")
+								ctlr.updateTransportServerStatus(virtual, virtual.Status.VSAddress, StatusOk)
 								//// Update Corresponding Service Status of Type LB
 								//var svcNamespace string
 								//if virtual.Spec.Pool.ServiceNamespace != "" {
@@ -140,13 +164,44 @@ func (ctlr *Controller) responseHandler(respChan chan *agentConfig) {
 								//}
 							}
 						}
-						//case Route:
-						//	if _, found := config.as3Config.failedTenants[partition]; found {
-						//		// TODO : distinguish between a 503 and an actual failure
-						//		go ctlr.updateRouteAdmitStatus(rscKey, "Failure while updating config", "Please check logs for more information", v1.ConditionFalse)
-						//	} else {
-						//		go ctlr.updateRouteAdmitStatus(rscKey, "", "", v1.ConditionTrue)
-						//	}
+					case Route:
+						if _, found := config.as3Config.failedTenants[partition]; found {
+							resp := config.as3Config.tenantResponseMap[partition]
+							// BIG-IP busy responses are retried, so distinguish them from actual failures
+							reason := DeployFailed
+							if resp.agentResponseCode == http.StatusServiceUnavailable {
+								reason = DeployRetrying
+							}
+							go ctlr.updateRouteAdmitStatus(rscKey, reason, getTenantErrorMessage(resp), v1.ConditionFalse)
+						} else {
+							go ctlr.updateRouteAdmitStatus(rscKey, "", "", v1.ConditionTrue)
+						}
+					case Ingress:
+						ing := ctlr.getIngress(ns, strings.TrimPrefix(rscKey, ns+"/"))
+						if ing == nil {
+							log.Debugf("Ingress Not Found: %v", rscKey)
+							continue
+						}
+						if _, found := config.as3Config.failedTenants[partition]; found {
+							ctlr.recordEventf(ing, v1.EventTypeWarning, DeployFailed, "Failed to deploy Ingress on BIG-IP: %v",
+								getTenantErrorMessage(config.as3Config.tenantResponseMap[partition]))
+						} else {
+							go ctlr.updateIngressStatus(ing, ing.Annotations[F5VsBindAddrAnnotation])
+						}
+					case Gateway:
+						name := strings.TrimPrefix(rscKey, ns+"/")
+						if _, found := config.as3Config.failedTenants[partition]; found {
+							resp := config.as3Config.tenantResponseMap[partition]
+							reason := string(gatewayv1.GatewayReasonInvalid)
+							if resp.agentResponseCode == http.StatusServiceUnavailable {
+								reason = string(gatewayv1.GatewayReasonPending)
+							}
+							go ctlr.updateGatewayProgrammedStatus(ns, name, metav1.ConditionFalse, reason,
+								getTenantErrorMessage(resp))
+						} else {
+							go ctlr.updateGatewayProgrammedStatus(ns, name, metav1.ConditionTrue,
+								string(gatewayv1.GatewayReasonProgrammed), "Gateway is programmed on BIG-IP")
+						}
 					}
 				}
 			}
@@ -154,7 +209,34 @@ func (ctlr *Controller) responseHandler(respChan chan *agentConfig) {
 	}
 }
 
+// isRetryable checks if the network config or the tenants of the request failed to deploy and are retried
+func (config *agentConfig) isRetryable() bool {
+	return config.l3Config.deployL3Error != "" || config.as3Config.hasRetryableTenants()
+}
+
+// hasRetryableTenants checks for the failed tenants which are retried, tenants failing the schema validation
+// are not retried until their resources are updated
+func (cfg *as3Config) hasRetryableTenants() bool {
+	for tenant := range cfg.failedTenants {
+		if _, invalid := cfg.invalidTenants[tenant]; !invalid {
+			return true
+		}
+	}
+	return false
+}
+
+// getTenantErrorMessage returns the error reported by BIG-IP for the failed tenant
+func getTenantErrorMessage(resp tenantResponse) string {
+	if resp.message != "" {
+		return resp.message
+	}
+	return fmt.Sprintf("BIG-IP responded with code: %v", resp.agentResponseCode)
+}
+
 func (ctlr *Controller) removeUnusedIPAMEntries(kind string) {
+	if ctlr.builtinIPAM != nil && (kind == VirtualServer || kind == TransportServer) {
+		ctlr.builtinIPAM.pruneAllocations()
+	}
 	// Remove Unused IPAM entries in IPAM CR after CIS restarts, applicable to only first PostCall
 	if !ctlr.firstPostResponse && ctlr.ipamCli != nil && (kind == VirtualServer || kind == TransportServer) {
 		ctlr.firstPostResponse = true
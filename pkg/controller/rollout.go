@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+)
+
+// EnqueueRequestConfigs enqueues the requests of the updated BIG-IPs.
+// With the staged rollout the requests of the BIG-IPs other than the canary BIG-IP are staged, they are
+// promoted once the canary BIG-IP has deployed the declaration.
+func (req *RequestHandler) EnqueueRequestConfigs(rsConfigs []ResourceConfigRequest) {
+	if len(rsConfigs) == 0 {
+		return
+	}
+	canaryAddress := req.PostParams.AS3Config.Rollout.CanaryBigIpAddress
+	canaryIndex := -1
+	for i, rsConfig := range rsConfigs {
+		if canaryAddress != "" && rsConfig.bigIpKey.BigIpAddress == canaryAddress {
+			canaryIndex = i
+			break
+		}
+	}
+	req.Rollout.Lock()
+	staged := req.Rollout.staged
+	if canaryIndex == -1 && staged == nil {
+		req.Rollout.Unlock()
+		for _, rsConfig := range rsConfigs {
+			req.EnqueueRequestConfig(rsConfig)
+		}
+		return
+	}
+	if canaryIndex == -1 {
+		// requests of the other BIG-IPs wait for the rollout which is staged
+		staged.stageRequests(rsConfigs)
+		req.Rollout.Unlock()
+		log.Debugf("[Rollout] Staged %v request(s) until the promotion from the canary BIG-IP %v",
+			len(rsConfigs), staged.canary.bigIpKey.BigIpAddress)
+		return
+	}
+	// requests staged earlier are superseded by the latest requests of the same BIG-IPs
+	newStaged := &stagedRollout{canary: rsConfigs[canaryIndex]}
+	if staged != nil {
+		newStaged.requests = append(newStaged.requests, staged.requests...)
+	}
+	for i, rsConfig := range rsConfigs {
+		if i != canaryIndex {
+			newStaged.stageRequests([]ResourceConfigRequest{rsConfig})
+		}
+	}
+	req.Rollout.staged = newStaged
+	req.Rollout.Unlock()
+	log.Infof("%v[Rollout] Posting to the canary BIG-IP %v, promotion to %v BIG-IP(s) is staged",
+		getRequestPrefix(newStaged.canary.reqMeta.id), newStaged.canary.bigIpKey.BigIpAddress, len(newStaged.requests))
+	req.EnqueueRequestConfig(newStaged.canary)
+}
+
+// stageRequests adds the requests to the staged rollout, replacing the staged requests of the same BIG-IPs.
+// The caller must hold the rollout lock.
+func (staged *stagedRollout) stageRequests(rsConfigs []ResourceConfigRequest) {
+	for _, rsConfig := range rsConfigs {
+		replaced := false
+		for i, stagedConfig := range staged.requests {
+			if stagedConfig.bigIpKey == rsConfig.bigIpKey {
+				staged.requests[i] = rsConfig
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			staged.requests = append(staged.requests, rsConfig)
+		}
+	}
+}
+
+// handleRolloutResponse promotes or halts the staged rollout on the response of the canary BIG-IP
+func (req *RequestHandler) handleRolloutResponse(config *agentConfig) {
+	req.Rollout.Lock()
+	defer req.Rollout.Unlock()
+	staged := req.Rollout.staged
+	if staged == nil || staged.promoting || staged.canary.bigIpKey != config.BigIpKey || staged.canary.reqMeta.id != config.id {
+		return
+	}
+	if config.l3Config.deployL3Error != "" {
+		// the rollout waits for the network config which is retried
+		return
+	}
+	if len(config.as3Config.failedTenants) > 0 {
+		// the rollout waits for the failed tenants which are retried
+		if config.as3Config.hasRetryableTenants() {
+			return
+		}
+		req.haltRollout(staged, fmt.Sprintf("%v tenant(s) failed on the canary BIG-IP", len(config.as3Config.failedTenants)))
+		return
+	}
+	staged.promoting = true
+	go req.promoteRollout(staged)
+}
+
+// promoteRollout enqueues the staged requests after the bake time and the health check of the canary BIG-IP
+func (req *RequestHandler) promoteRollout(staged *stagedRollout) {
+	rollout := req.PostParams.AS3Config.Rollout
+	if rollout.BakeTime > 0 {
+		log.Debugf("%v[Rollout] Waiting %v seconds before the promotion from the canary BIG-IP %v",
+			getRequestPrefix(staged.canary.reqMeta.id), rollout.BakeTime, staged.canary.bigIpKey.BigIpAddress)
+		<-time.After(time.Duration(rollout.BakeTime) * time.Second)
+	}
+	var err error
+	if rollout.HealthCheck {
+		err = checkVirtualAddresses(staged.canary.bigIpResourceConfig)
+	}
+	req.Rollout.Lock()
+	if req.Rollout.staged != staged {
+		req.Rollout.Unlock()
+		log.Debugf("%v[Rollout] Promotion is superseded by a later request", getRequestPrefix(staged.canary.reqMeta.id))
+		return
+	}
+	if err != nil {
+		req.haltRollout(staged, err.Error())
+		req.Rollout.Unlock()
+		return
+	}
+	req.Rollout.staged = nil
+	requests := staged.requests
+	req.Rollout.Unlock()
+	// the requests are enqueued without holding the lock, as the enqueue waits for the request handler
+	for _, rsConfig := range requests {
+		req.EnqueueRequestConfig(rsConfig)
+	}
+	prometheus.Rollouts.WithLabelValues(staged.canary.bigIpKey.BigIpAddress, RolloutPromoted).Inc()
+	log.Infof("%v[Rollout] Promoted the declaration from the canary BIG-IP %v to %v BIG-IP(s)",
+		getRequestPrefix(staged.canary.reqMeta.id), staged.canary.bigIpKey.BigIpAddress, len(requests))
+}
+
+// haltRollout drops the staged requests, the other BIG-IPs keep their declaration until a later request is promoted.
+// The caller must hold the rollout lock.
+func (req *RequestHandler) haltRollout(staged *stagedRollout, reason string) {
+	req.Rollout.staged = nil
+	prometheus.Rollouts.WithLabelValues(staged.canary.bigIpKey.BigIpAddress, RolloutHalted).Inc()
+	log.Errorf("%v[Rollout] Halted the promotion from the canary BIG-IP %v: %v",
+		getRequestPrefix(staged.canary.reqMeta.id), staged.canary.bigIpKey.BigIpAddress, reason)
+}
+
+// checkVirtualAddresses verifies that the TCP virtual addresses of the declaration accept connections
+func checkVirtualAddresses(config BigIpResourceConfig) error {
+	for _, partitionConfig := range config.ltmConfig {
+		for _, rsCfg := range partitionConfig.ResourceMap {
+			va := rsCfg.Virtual.VirtualAddress
+			if va == nil || va.BindAddr == "" || va.Port == 0 ||
+				(rsCfg.Virtual.IpProtocol != "" && rsCfg.Virtual.IpProtocol != "tcp") {
+				continue
+			}
+			address := net.JoinHostPort(va.BindAddr, strconv.Itoa(int(va.Port)))
+			conn, err := net.DialTimeout("tcp", address, timeoutSmall)
+			if err != nil {
+				return fmt.Errorf("virtual %v on %v is not reachable: %v", rsCfg.Virtual.Name, address, err)
+			}
+			_ = conn.Close()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"net"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Staged rollout", func() {
+	var requestHandler *RequestHandler
+	var canaryKey, haKey, otherKey BigIpKey
+	var rsConfigs []ResourceConfigRequest
+	BeforeEach(func() {
+		requestHandler = newMockAgent("as3")
+		requestHandler.reqChan = make(chan ResourceConfigRequest, 2)
+		canaryKey = BigIpKey{BigIpAddress: "10.10.10.1", BigIpLabel: "Hyderabad"}
+		haKey = BigIpKey{BigIpAddress: "10.10.10.2", BigIpLabel: "Hyderabad"}
+		otherKey = BigIpKey{BigIpAddress: "10.10.20.1", BigIpLabel: "Bangalore"}
+		rsConfigs = []ResourceConfigRequest{
+			{bigIpKey: canaryKey, reqMeta: requestMeta{id: 1}},
+			{bigIpKey: haKey, reqMeta: requestMeta{id: 1}},
+		}
+	})
+
+	It("Enqueues the requests of all BIG-IPs without a canary BIG-IP", func() {
+		requestHandler.EnqueueRequestConfigs(rsConfigs)
+		Expect(len(requestHandler.reqChan)).To(Equal(2))
+		Expect(requestHandler.Rollout.staged).To(BeNil())
+	})
+
+	It("Promotes the staged requests after the canary BIG-IP deploys the declaration", func() {
+		requestHandler.PostParams.AS3Config.Rollout.CanaryBigIpAddress = canaryKey.BigIpAddress
+		requestHandler.EnqueueRequestConfigs(rsConfigs)
+		Expect(len(requestHandler.reqChan)).To(Equal(1))
+		Expect((<-requestHandler.reqChan).bigIpKey).To(Equal(canaryKey))
+		Expect(requestHandler.Rollout.staged).NotTo(BeNil())
+
+		// response of an outdated request doesn't promote
+		requestHandler.handleRolloutResponse(&agentConfig{BigIpKey: canaryKey, id: 0})
+		Expect(len(requestHandler.reqChan)).To(Equal(0))
+
+		promoted := testutil.ToFloat64(prometheus.Rollouts.WithLabelValues(canaryKey.BigIpAddress, RolloutPromoted))
+		requestHandler.handleRolloutResponse(&agentConfig{BigIpKey: canaryKey, id: 1})
+		Eventually(func() int { return len(requestHandler.reqChan) }).Should(Equal(1))
+		Expect((<-requestHandler.reqChan).bigIpKey).To(Equal(haKey))
+		Expect(requestHandler.Rollout.staged).To(BeNil())
+		Expect(testutil.ToFloat64(prometheus.Rollouts.WithLabelValues(canaryKey.BigIpAddress, RolloutPromoted))).To(Equal(promoted + 1))
+	})
+
+	It("Stages the requests of all BIG-IPs until the promotion", func() {
+		requestHandler.PostParams.AS3Config.Rollout.CanaryBigIpAddress = canaryKey.BigIpAddress
+		requestHandler.reqChan = make(chan ResourceConfigRequest, 3)
+		requestHandler.EnqueueRequestConfigs(append(rsConfigs, ResourceConfigRequest{bigIpKey: otherKey, reqMeta: requestMeta{id: 1}}))
+		<-requestHandler.reqChan
+
+		// requests without the canary BIG-IP wait for the staged rollout
+		requestHandler.EnqueueRequestConfigs([]ResourceConfigRequest{{bigIpKey: otherKey, reqMeta: requestMeta{id: 2}}})
+		Expect(len(requestHandler.reqChan)).To(Equal(0))
+
+		// the latest request of the canary BIG-IP supersedes the rollout, the staged requests are kept
+		requestHandler.EnqueueRequestConfigs([]ResourceConfigRequest{{bigIpKey: canaryKey, reqMeta: requestMeta{id: 2}}})
+		Expect((<-requestHandler.reqChan).reqMeta.id).To(Equal(2))
+		requestHandler.handleRolloutResponse(&agentConfig{BigIpKey: canaryKey, id: 1})
+		Consistently(func() int { return len(requestHandler.reqChan) }).Should(Equal(0))
+
+		requestHandler.handleRolloutResponse(&agentConfig{BigIpKey: canaryKey, id: 2})
+		Eventually(func() int { return len(requestHandler.reqChan) }).Should(Equal(2))
+		promoted := map[BigIpKey]int{}
+		for i := 0; i < 2; i++ {
+			rsConfig := <-requestHandler.reqChan
+			promoted[rsConfig.bigIpKey] = rsConfig.reqMeta.id
+		}
+		Expect(promoted).To(Equal(map[BigIpKey]int{haKey: 1, otherKey: 2}))
+		Expect(requestHandler.Rollout.staged).To(BeNil())
+	})
+
+	It("Halts the promotion when tenants fail on the canary BIG-IP", func() {
+		requestHandler.PostParams.AS3Config.Rollout.CanaryBigIpAddress = canaryKey.BigIpAddress
+		requestHandler.EnqueueRequestConfigs(rsConfigs)
+		<-requestHandler.reqChan
+
+		// retried tenants keep the requests staged
+		config := &agentConfig{BigIpKey: canaryKey, id: 1}
+		config.as3Config.failedTenants = map[string]struct{}{"test": {}}
+		requestHandler.handleRolloutResponse(config)
+		Expect(requestHandler.Rollout.staged).NotTo(BeNil())
+
+		halted := testutil.ToFloat64(prometheus.Rollouts.WithLabelValues(canaryKey.BigIpAddress, RolloutHalted))
+		config.as3Config.invalidTenants = map[string]string{"test": "invalid declaration"}
+		requestHandler.handleRolloutResponse(config)
+		Expect(requestHandler.Rollout.staged).To(BeNil())
+		Expect(len(requestHandler.reqChan)).To(Equal(0))
+		Expect(testutil.ToFloat64(prometheus.Rollouts.WithLabelValues(canaryKey.BigIpAddress, RolloutHalted))).To(Equal(halted + 1))
+	})
+
+	It("Checks the virtual addresses of the canary BIG-IP", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		port := listener.Addr().(*net.TCPAddr).Port
+		rsCfg := &ResourceConfig{}
+		rsCfg.Virtual.Name = "crd_127_0_0_1"
+		rsCfg.Virtual.VirtualAddress = &virtualAddress{BindAddr: "127.0.0.1", Port: int32(port)}
+		config := BigIpResourceConfig{ltmConfig: LTMConfig{"test": &PartitionConfig{ResourceMap: ResourceMap{"vs": rsCfg}}}}
+		Expect(checkVirtualAddresses(config)).To(BeNil())
+		_ = listener.Close()
+		Expect(checkVirtualAddresses(config)).NotTo(BeNil())
+	})
+})
@@ -17,11 +17,15 @@
 package controller
 
 import (
+	"context"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/as3schema"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/networkmanager"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	"net"
 	"net/http"
 	"sync"
+	"time"
 
 	ficV1 "github.com/F5Networks/f5-ipam-controller/pkg/ipamapis/apis/fic/v1"
 
@@ -40,10 +44,14 @@ import (
 	v1 "k8s.io/api/core/v1"
 	extClient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayclient "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 )
 
 type (
@@ -51,6 +59,7 @@ type (
 	Controller struct {
 		resources              *ResourceStore
 		clientsets             *ClientSets
+		eventRecorder          record.EventRecorder
 		namespacesMutex        sync.Mutex
 		namespaces             map[string]bool
 		initialResourceCount   int
@@ -63,6 +72,7 @@ type (
 		firstPostResponse      bool
 		shareNodes             bool
 		ipamCli                *ipammachinery.IPAMClient
+		builtinIPAM            *BuiltinIPAM
 		ipamCR                 string
 		defaultRouteDomain     int
 		TeemData               *teem.TeemsData
@@ -71,6 +81,7 @@ type (
 		StaticRoutingMode      bool
 		OrchestrationCNI       string
 		StaticRouteNodeCIDR    string
+		TunnelName             string
 		cacheIPAMHostSpecs     CacheIPAM
 		multiClusterConfigs    *clustermanager.MultiClusterConfig
 		multiClusterResources  *MultiClusterResourceStore
@@ -85,6 +96,20 @@ type (
 		respChan               chan *agentConfig
 		networkManager         *networkmanager.NetworkManager
 		ControllerIdentifier   string
+		leaderElectionConfig   *LeaderElectionConfig
+		leaderElectionCtx      context.Context
+		leaderElectionCancel   context.CancelFunc
+		leaderElectionDone     chan struct{}
+		leaderLock             sync.RWMutex
+		isLeader               bool
+		gatewayControllerName  string
+		ingressControllerName  string
+		// loadBalancerClass of the Services of type LoadBalancer managed by CIS
+		loadBalancerClass           string
+		manageLoadBalancerClassOnly bool
+		declarationHistoryAPI       bool
+		// event handlers of the informers which are registered once the follower starts leading
+		pendingEventHandlers []informerEventHandler
 		resourceContext
 	}
 	ClientSets struct {
@@ -92,6 +117,8 @@ type (
 		kubeClient    kubernetes.Interface
 		kubeAPIClient *extClient.Clientset
 		routeClientV1 routeclient.RouteV1Interface
+		gatewayClient gatewayclient.Interface
+		dynamicClient dynamic.Interface
 	}
 	ManagedResources struct {
 		ManageRoutes          bool
@@ -102,6 +129,8 @@ type (
 		ManageIL              bool
 		ManageTLSProfile      bool
 		ManageSecrets         bool
+		ManageGatewayAPI      bool
+		ManageIngress         bool
 	}
 	ResourceSelectorConfig struct {
 		NamespaceLabel         string
@@ -115,6 +144,10 @@ type (
 		comInformers              map[string]*CommonInformer
 		nrInformers               map[string]*NRInformer
 		crInformers               map[string]*CRInformer
+		gwInformers               map[string]*GWInformer
+		gcInformer                *GatewayClassInformer
+		icInformer                *IngressClassInformer
+		calicoInformer            *CalicoInformer
 		nsInformers               map[string]*NSInformer
 		multiClusterPoolInformers map[string]map[string]*MultiClusterPoolInformer
 		multiClusterNodeInformers map[string]*NodeInformer
@@ -140,6 +173,17 @@ type (
 		HttpAddress           string
 		ManageCustomResources bool
 		httpClientMetrics     bool
+		LeaderElectionConfig  *LeaderElectionConfig
+		ManageGatewayAPI      bool
+		GatewayControllerName string
+		ManageIngress         bool
+		IngressControllerName string
+		AS3SchemaPath         string
+		// LoadBalancerClass of the Services of type LoadBalancer managed by CIS
+		LoadBalancerClass           string
+		ManageLoadBalancerClassOnly bool
+		// DeclarationHistoryAPI serves the declaration history and the rollback on HttpAddress
+		DeclarationHistoryAPI bool
 	}
 
 	// CMConfig defines the Central Manager config
@@ -149,6 +193,22 @@ type (
 		Password string
 	}
 
+	// LeaderElectionConfig defines the Lease based leader election config for running multiple CIS replicas
+	LeaderElectionConfig struct {
+		LeaseName      string
+		LeaseNamespace string
+		LeaseDuration  time.Duration
+		RenewDeadline  time.Duration
+		RetryPeriod    time.Duration
+	}
+
+	// informerEventHandler is an event handler deferred until CIS starts leading
+	informerEventHandler struct {
+		informer     cache.SharedIndexInformer
+		handler      cache.ResourceEventHandler
+		resyncPeriod time.Duration
+	}
+
 	// CRInformer defines the structure of Custom Resource Informer
 	CRInformer struct {
 		namespace   string
@@ -173,9 +233,64 @@ type (
 
 	// NRInformer is informer context for Native Resources of Kubernetes/Openshift
 	NRInformer struct {
-		namespace     string
-		stopCh        chan struct{}
-		routeInformer cache.SharedIndexInformer
+		namespace       string
+		stopCh          chan struct{}
+		routeInformer   cache.SharedIndexInformer
+		ingressInformer cache.SharedIndexInformer
+	}
+
+	// IngressClassInformer watches the cluster scoped IngressClasses
+	IngressClassInformer struct {
+		stopCh     chan struct{}
+		icInformer cache.SharedIndexInformer
+	}
+
+	// GWInformer is informer context for the Gateway API resources
+	GWInformer struct {
+		namespace         string
+		stopCh            chan struct{}
+		gatewayInformer   cache.SharedIndexInformer
+		httpRouteInformer cache.SharedIndexInformer
+		tlsRouteInformer  cache.SharedIndexInformer
+		tcpRouteInformer  cache.SharedIndexInformer
+		udpRouteInformer  cache.SharedIndexInformer
+	}
+
+	// gatewayRoute is a HTTPRoute, TLSRoute, TCPRoute or UDPRoute referring to a Gateway
+	gatewayRoute struct {
+		kind       string
+		namespace  string
+		name       string
+		generation int64
+		created    metav1.Time
+		hostnames  []gatewayv1.Hostname
+		parentRefs []gatewayv1.ParentReference
+		rsc        interface{}
+	}
+
+	// gatewayListener is a validated listener of a Gateway along with the routes attached to it
+	gatewayListener struct {
+		listener       gatewayv1.Listener
+		routeKind      string
+		supportedKinds []gatewayv1.RouteGroupKind
+		valid          bool
+		conditions     []metav1.Condition
+		secrets        []string
+		attachedRoutes []*gatewayRoute
+		// hostnames of the attached routes which match the listener hostname
+		routeHosts map[*gatewayRoute][]string
+	}
+
+	// GatewayClassInformer watches the cluster scoped GatewayClasses
+	GatewayClassInformer struct {
+		stopCh     chan struct{}
+		gcInformer cache.SharedIndexInformer
+	}
+
+	// CalicoInformer watches the cluster scoped Calico BlockAffinities
+	CalicoInformer struct {
+		stopCh                chan struct{}
+		blockAffinityInformer cache.SharedIndexInformer
 	}
 
 	NodeInformer struct {
@@ -210,6 +325,7 @@ type (
 		Protocol        string
 		httpTraffic     string
 		defaultPoolType string
+		bigIpLabel      string
 	}
 
 	// Virtual server config
@@ -680,12 +796,18 @@ type (
 	requestMeta struct {
 		partitionMap map[string]map[string]string
 		id           int
+		// time of the oldest kubernetes event of the request
+		eventTime time.Time
 	}
 
 	Node struct {
 		Name   string
 		Addr   string
 		Labels map[string]string
+		// VTEP of the node on the VXLAN tunnel
+		VtepMAC  string
+		VtepAddr string
+		PodCIDRs []string
 	}
 	// NPL information from pod annotation
 	NPLAnnotation struct {
@@ -722,7 +844,14 @@ type (
 	}
 )
 
+// L3PostManager deploys the network objects of the BIG-IP instance through Central Manager
 type L3PostManager struct {
+	networkManager *networkmanager.NetworkManager
+	instanceId     string
+	// network objects created or adopted by CIS, keyed by kind and name
+	objects map[string]map[string]networkmanager.NetworkObject
+	// last successfully deployed network config
+	deployedConfig *l3Config
 }
 
 type (
@@ -736,6 +865,34 @@ type (
 		HAMode                          bool
 		PrimaryClusterHealthProbeParams PrimaryClusterHealthProbeParams
 		httpClientMetrics               bool
+		L3NetworkConfigs                L3NetworkConfigs
+		VxlanTunnel                     VxlanTunnel
+		Rollout                         Rollout
+	}
+
+	// Rollout holds the requests of the BIG-IPs which are staged until the canary BIG-IP deploys the declaration
+	Rollout struct {
+		sync.Mutex
+		staged *stagedRollout
+	}
+
+	stagedRollout struct {
+		canary    ResourceConfigRequest
+		requests  []ResourceConfigRequest
+		promoting bool
+	}
+
+	// VxlanTunnel holds the nodes which are reachable over the VXLAN tunnel
+	VxlanTunnel struct {
+		sync.RWMutex
+		tunnelName string
+		nodes      []Node
+	}
+
+	// L3NetworkConfigs holds the network config of each BIG-IP, keyed by the BIG-IP address
+	L3NetworkConfigs struct {
+		sync.RWMutex
+		configMap map[string]cisapiv1.L3NetworkConfig
 	}
 
 	PostManager struct {
@@ -750,6 +907,82 @@ type (
 		PostParams
 		postManagerPrefix      string
 		tenantDeclarationIDMap map[string]string
+		bigIpKey               BigIpKey
+		declarationHistory     *DeclarationHistory
+		// consecutive failed deployments of the tenants, keyed by tenant
+		tenantFailures map[string]int
+		rollbackChan   chan rollbackRequest
+		// documentIDsRecovered is set once the document IDs posted before the restart are recovered
+		documentIDsRecovered bool
+	}
+
+	// IPAMProvider allocates the virtual server addresses of the resources from the IP address ranges of the IPAM labels
+	IPAMProvider interface {
+		// RequestIP returns the IP address allocated to the host or key along with the status of the allocation
+		RequestIP(ipamLabel string, host string, key string) (string, int)
+		// ReleaseIP releases the IP address allocated to the host or key and returns it
+		ReleaseIP(ipamLabel string, host string, key string) string
+	}
+
+	// FamilyIPAMProvider allocates the IP addresses of an IP family, so that the dual-stack Services of type
+	// LoadBalancer get an IP address of each family from the ranges of the IPAM label
+	FamilyIPAMProvider interface {
+		IPAMProvider
+		// RequestFamilyIP returns the IP address of the IP family allocated to the key along with the status
+		RequestFamilyIP(ipamLabel string, family v1.IPFamily, key string) (string, int)
+	}
+
+	// BuiltinIPAM allocates the IP addresses from the ranges declared in the DeployConfig CR without F5 IPAM Controller,
+	// the allocations are persisted in a ConfigMap, so that the resources keep their IP addresses after a restart
+	BuiltinIPAM struct {
+		sync.Mutex
+		ranges map[string][]ipRange
+		// allocations keyed by the host, or by the key of the resource when the host is not set
+		allocations map[string]ipAllocation
+		// allocations requested after the start, the other allocations are released after the first post
+		requested map[string]struct{}
+		pruned    bool
+		// staticAddresses returns the virtual server addresses specified in the resources
+		staticAddresses func() map[string]struct{}
+		kubeClient      kubernetes.Interface
+		namespace       string
+	}
+
+	ipRange struct {
+		first net.IP
+		last  net.IP
+	}
+
+	ipAllocation struct {
+		IPAMLabel string `json:"ipamLabel"`
+		Host      string `json:"host,omitempty"`
+		Key       string `json:"key,omitempty"`
+		IP        string `json:"ip"`
+	}
+
+	// DeclarationHistory holds the revisions of the tenant declarations deployed on a BIG-IP, latest revision last
+	DeclarationHistory struct {
+		sync.RWMutex
+		size      int
+		revisions map[string][]declarationRevision
+		// pinned holds the declarations of the resources of the rolled back tenants,
+		// the tenants are not posted until their declaration differs from the pinned one
+		pinned map[string]as3Tenant
+	}
+
+	declarationRevision struct {
+		Revision    int       `json:"revision"`
+		Timestamp   time.Time `json:"timestamp"`
+		Declaration as3Tenant `json:"declaration"`
+		// Redacted revisions are recovered without their certificates and keys, they are not rolled back
+		Redacted bool `json:"redacted,omitempty"`
+	}
+
+	// rollbackRequest rolls back the tenant to the revision, the previous revision is used when revision is 0
+	rollbackRequest struct {
+		tenant   string
+		revision int
+		result   chan error
 	}
 
 	PostManagers struct {
@@ -780,11 +1013,16 @@ type (
 		AS3Config         cisapiv1.AS3Config
 		tokenManager      *tokenmanager.TokenManager
 		UserAgent         string
+		as3Validator      *as3schema.Validator
+		// kubeClient and historyNamespace to persist the declaration history in ConfigMaps
+		kubeClient       kubernetes.Interface
+		historyNamespace string
 	}
 
 	tenantResponse struct {
 		agentResponseCode int
 		isDeleted         bool
+		message           string
 	}
 
 	//agentConfig holds as3config and l3config to put onto post channel
@@ -801,16 +1039,44 @@ type (
 		targetAddress         string
 		as3APIURL             string
 		id                    int
+		userAgent             string
 		tenantResponseMap     map[string]tenantResponse
 		acceptedTaskId        string
+		tenantTaskIdMap       map[string]string // accepted Document API deployments, keyed by tenant
 		failedTenants         map[string]struct{}
+		invalidTenants        map[string]string // tenants failed the schema validation along with the error
 		incomingTenantDeclMap map[string]as3Tenant
 		deleted               bool
 	}
 
-	//TODO L3Config to put into post channel. Handle with L3Postmanager implementation
+	//l3Config to put into post channel
 	l3Config struct {
+		networkConfig  cisapiv1.L3NetworkConfig
+		vxlanConfig    vxlanConfig
 		deployL3Status bool
+		// deployL3Error is the error of the failed deployment, the request is retried until the network config is deployed
+		deployL3Error string
+	}
+
+	// vxlanConfig holds the FDB records and ARP entries of the VXLAN tunnel
+	vxlanConfig struct {
+		tunnelName string
+		fdbRecords []fdbRecord
+		arpEntries []arpEntry
+	}
+
+	// fdbRecord maps the VTEP MAC of a node to the node address
+	fdbRecord struct {
+		Name     string `json:"name"`
+		Tunnel   string `json:"tunnel"`
+		Endpoint string `json:"endpoint"`
+	}
+
+	// arpEntry maps a pool member address to the VTEP MAC of its node
+	arpEntry struct {
+		Name       string `json:"name"`
+		IPAddress  string `json:"ipAddress"`
+		MACAddress string `json:"macAddress"`
 	}
 
 	// AS3 version struct
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -45,22 +46,25 @@ func (ctlr *Controller) checkValidVirtualServer(
 	// Check if HTTPTraffic is set for insecure VS
 	if vsResource.Spec.TLSProfileName == "" && vsResource.Spec.HTTPTraffic != "" {
 		log.Warningf("HTTPTraffic not allowed to be set for insecure VirtualServer: %v", vsName)
+		ctlr.recordEvent(vsResource, v1.EventTypeWarning, InvalidSpec, "HTTPTraffic not allowed to be set for insecure VirtualServer")
 		return false
 	}
 
 	bindAddr := vsResource.Spec.VirtualServerAddress
-	if ctlr.ipamCli == nil {
+	if ctlr.getIPAMProvider() == nil {
 
 		// This ensures that pool-only mode only logs the message below the first
 		// time we see a config.
 		if bindAddr == "" {
 			log.Infof("No IP was specified for the virtual server %s", vsName)
+			ctlr.recordEvent(vsResource, v1.EventTypeWarning, InvalidSpec, "No virtualServerAddress was specified")
 			return false
 		}
 	} else {
 		ipamLabel := vsResource.Spec.IPAMLabel
 		if ipamLabel == "" && bindAddr == "" {
 			log.Infof("No ipamLabel was specified for the virtual server %s", vsName)
+			ctlr.recordEvent(vsResource, v1.EventTypeWarning, InvalidSpec, "Neither virtualServerAddress nor ipamLabel was specified")
 			return false
 		}
 	}
@@ -103,17 +107,19 @@ func (ctlr *Controller) checkValidTransportServer(
 
 	bindAddr := tsResource.Spec.VirtualServerAddress
 
-	if ctlr.ipamCli == nil {
+	if ctlr.getIPAMProvider() == nil {
 		// This ensures that pool-only mode only logs the message below the first
 		// time we see a config.
 		if bindAddr == "" {
 			log.Infof("No IP was specified for the transport server %s", vsName)
+			ctlr.recordEvent(tsResource, v1.EventTypeWarning, InvalidSpec, "No virtualServerAddress was specified")
 			return false
 		}
 	} else {
 		ipamLabel := tsResource.Spec.IPAMLabel
 		if ipamLabel == "" && bindAddr == "" {
 			log.Infof("No ipamLabel was specified for the transport server %s", vsName)
+			ctlr.recordEvent(tsResource, v1.EventTypeWarning, InvalidSpec, "Neither virtualServerAddress nor ipamLabel was specified")
 			return false
 		}
 	}
@@ -122,6 +128,7 @@ func (ctlr *Controller) checkValidTransportServer(
 		tsResource.Spec.Type = "tcp"
 	} else if !(tsResource.Spec.Type == "udp" || tsResource.Spec.Type == "tcp" || tsResource.Spec.Type == "sctp") {
 		log.Warningf("Invalid type value for transport server %s. Supported values are tcp, udp and sctp only", vsName)
+		ctlr.recordEventf(tsResource, v1.EventTypeWarning, InvalidSpec, "Invalid type %v, supported values are tcp, udp and sctp only", tsResource.Spec.Type)
 		return false
 	}
 	if tsResource.Spec.Pool.MultiClusterServices != nil {
@@ -159,15 +166,17 @@ func (ctlr *Controller) checkValidIngressLink(
 
 	bindAddr := il.Spec.VirtualServerAddress
 
-	if ctlr.ipamCli == nil {
+	if ctlr.getIPAMProvider() == nil {
 		if bindAddr == "" {
 			log.Infof("No IP was specified for ingresslink %s", ilName)
+			ctlr.recordEvent(il, v1.EventTypeWarning, InvalidSpec, "No virtualServerAddress was specified")
 			return false
 		}
 	} else {
 		ipamLabel := il.Spec.IPAMLabel
 		if ipamLabel == "" && bindAddr == "" {
 			log.Infof("No ipamLabel was specified for the il server %s", ilName)
+			ctlr.recordEvent(il, v1.EventTypeWarning, InvalidSpec, "Neither virtualServerAddress nor ipamLabel was specified")
 			return false
 		}
 	}
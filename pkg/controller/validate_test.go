@@ -7,8 +7,12 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"k8s.io/client-go/tools/record"
+
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	crdfake "github.com/F5Networks/k8s-bigip-ctlr/v3/config/client/clientset/versioned/fake"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/clustermanager"
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/test"
 )
 
 var _ = Describe("Validation Tests", func() {
@@ -95,4 +99,50 @@ var _ = Describe("Validation Tests", func() {
 				"HA clusters to be defined in extendedServiceReference")))
 		})
 	})
+
+	Describe("Validating Custom Resources", func() {
+		var recorder *record.FakeRecorder
+		namespace := "default"
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			mockCtlr.eventRecorder = recorder
+			mockCtlr.clientsets.kubeCRClient = crdfake.NewSimpleClientset()
+			mockCtlr.crInformers = make(map[string]*CRInformer)
+			mockCtlr.resourceSelectorConfig.customResourceSelector, _ = createLabelSelector(DefaultCustomResourceLabel)
+			mockCtlr.crInformers[namespace] = mockCtlr.newNamespacedCustomResourceInformer(namespace)
+		})
+
+		It("Record events for invalid VirtualServer", func() {
+			vs := test.NewVirtualServer("SampleVS", namespace, cisapiv1.VirtualServerSpec{Host: "test.com"})
+			mockCtlr.crInformers[namespace].vsInformer.GetStore().Add(vs)
+			Expect(mockCtlr.checkValidVirtualServer(vs)).To(BeFalse())
+			Expect(recorder.Events).To(Receive(Equal("Warning InvalidSpec No virtualServerAddress was specified")))
+
+			vs.Spec.HTTPTraffic = TLSAllowInsecure
+			Expect(mockCtlr.checkValidVirtualServer(vs)).To(BeFalse())
+			Expect(recorder.Events).To(Receive(Equal("Warning InvalidSpec HTTPTraffic not allowed to be set for insecure VirtualServer")))
+
+			vs.Spec.HTTPTraffic = ""
+			vs.Spec.VirtualServerAddress = "10.1.1.1"
+			Expect(mockCtlr.checkValidVirtualServer(vs)).To(BeTrue())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("Record events for invalid TransportServer", func() {
+			ts := test.NewTransportServer("SampleTS", namespace, cisapiv1.TransportServerSpec{
+				VirtualServerAddress: "10.1.1.1",
+				Type:                 "http",
+			})
+			mockCtlr.crInformers[namespace].tsInformer.GetStore().Add(ts)
+			Expect(mockCtlr.checkValidTransportServer(ts)).To(BeFalse())
+			Expect(recorder.Events).To(Receive(Equal("Warning InvalidSpec Invalid type http, supported values are tcp, udp and sctp only")))
+		})
+
+		It("Record events for invalid IngressLink", func() {
+			il := test.NewIngressLink("SampleIL", namespace, "1", cisapiv1.IngressLinkSpec{})
+			mockCtlr.crInformers[namespace].ilInformer.GetStore().Add(il)
+			Expect(mockCtlr.checkValidIngressLink(il)).To(BeFalse())
+			Expect(recorder.Events).To(Receive(Equal("Warning InvalidSpec No virtualServerAddress was specified")))
+		})
+	})
 })
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+)
+
+// flannelBackendData is the backend data of the node published by flannel
+type flannelBackendData struct {
+	VtepMAC string `json:"VtepMAC"`
+}
+
+// setNodeVtep sets the VTEP of the node from the flannel annotations
+func setNodeVtep(n *Node, node *v1.Node) {
+	backendData, ok := node.Annotations[FlannelBackendDataAnnotation]
+	if !ok {
+		return
+	}
+	var data flannelBackendData
+	if err := json.Unmarshal([]byte(backendData), &data); err != nil || data.VtepMAC == "" {
+		log.Warningf("Node annotation %v not properly configured for node %v", FlannelBackendDataAnnotation, node.Name)
+		return
+	}
+	n.VtepMAC = data.VtepMAC
+	n.VtepAddr = n.Addr
+	if publicIP, ok := node.Annotations[FlannelPublicIPAnnotation]; ok && publicIP != "" {
+		n.VtepAddr = publicIP
+	}
+	if len(node.Spec.PodCIDRs) > 0 {
+		n.PodCIDRs = append([]string{}, node.Spec.PodCIDRs...)
+	} else if node.Spec.PodCIDR != "" {
+		n.PodCIDRs = []string{node.Spec.PodCIDR}
+	}
+}
+
+// updateVxlanNodes updates the nodes of the VXLAN tunnel and returns true if they are changed
+func (ctlr *Controller) updateVxlanNodes() bool {
+	if ctlr.TunnelName == "" {
+		return false
+	}
+	var nodes []Node
+	for clusterName := range ctlr.multiClusterNodeInformers {
+		for _, node := range ctlr.getNodesFromCache(clusterName) {
+			if node.VtepMAC != "" {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].VtepMAC < nodes[j].VtepMAC
+	})
+	return ctlr.RequestHandler.setVxlanNodes(ctlr.TunnelName, nodes)
+}
+
+func (req *RequestHandler) setVxlanNodes(tunnelName string, nodes []Node) bool {
+	req.VxlanTunnel.Lock()
+	defer req.VxlanTunnel.Unlock()
+	if req.VxlanTunnel.tunnelName == tunnelName && reflect.DeepEqual(req.VxlanTunnel.nodes, nodes) {
+		return false
+	}
+	req.VxlanTunnel.tunnelName = tunnelName
+	req.VxlanTunnel.nodes = nodes
+	return true
+}
+
+// getVxlanConfig returns the FDB records of the nodes and the ARP entries of the pool members on the VXLAN tunnel
+func (req *RequestHandler) getVxlanConfig(ltmConfig LTMConfig) vxlanConfig {
+	req.VxlanTunnel.RLock()
+	defer req.VxlanTunnel.RUnlock()
+	cfg := vxlanConfig{tunnelName: req.VxlanTunnel.tunnelName}
+	if cfg.tunnelName == "" {
+		return cfg
+	}
+	var podNetworks []*net.IPNet
+	var podNetworkMACs []string
+	for _, node := range req.VxlanTunnel.nodes {
+		cfg.fdbRecords = append(cfg.fdbRecords, fdbRecord{
+			Name:     node.VtepMAC,
+			Tunnel:   cfg.tunnelName,
+			Endpoint: node.VtepAddr,
+		})
+		for _, podCIDR := range node.PodCIDRs {
+			if _, podNetwork, err := net.ParseCIDR(podCIDR); err == nil {
+				podNetworks = append(podNetworks, podNetwork)
+				podNetworkMACs = append(podNetworkMACs, node.VtepMAC)
+			}
+		}
+	}
+	arpEntries := make(map[string]arpEntry)
+	for _, member := range ltmConfig.GetAllPoolMembers() {
+		// remove the route domain of the address
+		address := strings.Split(member.Address, "%")[0]
+		ip := net.ParseIP(address)
+		if ip == nil {
+			continue
+		}
+		for i, podNetwork := range podNetworks {
+			if podNetwork.Contains(ip) {
+				arpEntries[address] = arpEntry{
+					Name:       networkObjectPrefix + address,
+					IPAddress:  address,
+					MACAddress: podNetworkMACs[i],
+				}
+				break
+			}
+		}
+	}
+	for _, entry := range arpEntries {
+		cfg.arpEntries = append(cfg.arpEntries, entry)
+	}
+	sort.Slice(cfg.arpEntries, func(i, j int) bool {
+		return cfg.arpEntries[i].Name < cfg.arpEntries[j].Name
+	})
+	return cfg
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/networkmanager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("VXLAN Tunnel", func() {
+	var mockCtlr *mockController
+
+	BeforeEach(func() {
+		mockCtlr = newMockController()
+		mockCtlr.TunnelName = "flannel_vxlan"
+		mockCtlr.UseNodeInternal = true
+		mockCtlr.initState = true
+		mockCtlr.multiClusterNodeInformers = make(map[string]*NodeInformer)
+		mockCtlr.multiClusterNodeInformers[""] = &NodeInformer{}
+	})
+
+	It("posts the FDB records and ARP entries of the tunnel", func() {
+		nodes := []v1.Node{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "worker1",
+					Annotations: map[string]string{
+						FlannelBackendDataAnnotation: `{"VNI":1,"VtepMAC":"0a:58:0a:f4:01:00"}`,
+						FlannelPublicIPAnnotation:    "10.10.10.11",
+					},
+				},
+				Spec:   v1.NodeSpec{PodCIDR: "10.244.1.0/24"},
+				Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.10.10.1"}}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "worker2",
+					Annotations: map[string]string{FlannelBackendDataAnnotation: `{"VNI":1,"VtepMAC":"0a:58:0a:f4:02:00"}`},
+				},
+				Spec:   v1.NodeSpec{PodCIDRs: []string{"10.244.2.0/24"}},
+				Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.10.10.2"}}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "worker3",
+					Annotations: map[string]string{FlannelBackendDataAnnotation: `invalid`},
+				},
+				Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.10.10.3"}}},
+			},
+		}
+		mockCtlr.ProcessNodeUpdate(nodes, "")
+		nodeCache := mockCtlr.getNodesFromCache("")
+		Expect(nodeCache).To(HaveLen(3))
+		Expect(nodeCache[0].VtepAddr).To(Equal("10.10.10.11"))
+		Expect(nodeCache[2].VtepMAC).To(BeEmpty())
+
+		Expect(mockCtlr.updateVxlanNodes()).To(BeTrue())
+		Expect(mockCtlr.updateVxlanNodes()).To(BeFalse())
+
+		ltmConfig := LTMConfig{
+			"test": &PartitionConfig{ResourceMap: ResourceMap{
+				"vs1": &ResourceConfig{
+					MetaData: metaData{Active: true},
+					Pools: Pools{{Members: []PoolMember{
+						{Address: "10.244.1.5", Port: 8080},
+						{Address: "10.244.2.7%0", Port: 8080},
+						{Address: "10.244.9.1", Port: 8080},
+					}}},
+				},
+			}},
+		}
+		cfg := mockCtlr.RequestHandler.getVxlanConfig(ltmConfig)
+		Expect(cfg.tunnelName).To(Equal("flannel_vxlan"))
+		Expect(cfg.fdbRecords).To(Equal([]fdbRecord{
+			{Name: "0a:58:0a:f4:01:00", Tunnel: "flannel_vxlan", Endpoint: "10.10.10.11"},
+			{Name: "0a:58:0a:f4:02:00", Tunnel: "flannel_vxlan", Endpoint: "10.10.10.2"},
+		}))
+		Expect(cfg.arpEntries).To(Equal([]arpEntry{
+			{Name: "k8s-10.244.1.5", IPAddress: "10.244.1.5", MACAddress: "0a:58:0a:f4:01:00"},
+			{Name: "k8s-10.244.2.7", IPAddress: "10.244.2.7", MACAddress: "0a:58:0a:f4:02:00"},
+		}))
+
+		objects := getL3NetworkObjects(&l3Config{vxlanConfig: cfg})
+		Expect(objects[networkmanager.FDBRecords]).To(HaveLen(2))
+		Expect(objects[networkmanager.ARPs]).To(HaveKey("k8s-10.244.1.5"))
+	})
+
+	It("adopts the FDB records and ARP entries created by CIS", func() {
+		Expect(isNetworkObjectManaged(networkmanager.FDBRecords, networkmanager.NetworkObject{
+			Name: "0a:58:0a:f4:01:00", Payload: map[string]interface{}{"tunnel": "flannel_vxlan"}}, "flannel_vxlan")).To(BeTrue())
+		Expect(isNetworkObjectManaged(networkmanager.FDBRecords, networkmanager.NetworkObject{
+			Name: "0a:58:0a:f4:01:00", Payload: map[string]interface{}{"tunnel": "other"}}, "flannel_vxlan")).To(BeFalse())
+		// FDB records of the tunnel created by CIS
+		Expect(isNetworkObjectManaged(networkmanager.FDBRecords, networkmanager.NetworkObject{
+			Name: "0a:58:0a:f4:01:00", Payload: map[string]interface{}{"tunnel": "k8s-flannel_vxlan"}}, "")).To(BeTrue())
+		Expect(isNetworkObjectManaged(networkmanager.ARPs, networkmanager.NetworkObject{Name: "k8s-10.244.1.5"}, "flannel_vxlan")).To(BeTrue())
+		Expect(isNetworkObjectManaged(networkmanager.ARPs, networkmanager.NetworkObject{Name: "gateway"}, "flannel_vxlan")).To(BeFalse())
+		Expect(isNetworkObjectManaged(networkmanager.VLANs, networkmanager.NetworkObject{Name: "k8s-vlan"}, "")).To(BeTrue())
+		Expect(isNetworkObjectManaged(networkmanager.VLANs, networkmanager.NetworkObject{Name: "vlan"}, "flannel_vxlan")).To(BeFalse())
+	})
+})
@@ -26,6 +26,7 @@ import (
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	listerscorev1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"net"
 	"os"
 	"reflect"
 	"slices"
@@ -33,6 +34,7 @@ import (
 	"strings"
 	"time"
 
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	ficV1 "github.com/F5Networks/f5-ipam-controller/pkg/ipamapis/apis/fic/v1"
@@ -40,9 +42,12 @@ import (
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	routeapi "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // nextGenResourceWorker starts the Custom Resource Worker.
@@ -122,6 +127,12 @@ func (ctlr *Controller) setInitialResourceCount() {
 				}
 			}
 		}
+		if ctlr.managedResources.ManageGatewayAPI {
+			rscCount += len(ctlr.getAllGateways(ns))
+		}
+		if ctlr.managedResources.ManageIngress {
+			rscCount += len(ctlr.getAllIngresses(ns))
+		}
 		comInf, found := ctlr.getNamespacedCommonInformer(ns)
 		if !found {
 			continue
@@ -162,6 +173,7 @@ func (ctlr *Controller) processResources() bool {
 	var isRetryableError bool
 
 	defer ctlr.resourceQueue.Done(key)
+	prometheus.QueueDepth.WithLabelValues(ResourceQueueName, "").Set(float64(ctlr.resourceQueue.Len()))
 	// If CIS resources like CRDS, routes or servicetype LB are not present
 	// on startup, check initalresourcecount and update initState
 	if ctlr.initialResourceCount <= 0 {
@@ -172,7 +184,8 @@ func (ctlr *Controller) processResources() bool {
 	// During Init time, just process all the resources
 	if ctlr.initState && rKey.kind != Namespace {
 		if rKey.kind == VirtualServer || rKey.kind == TransportServer || rKey.kind == Service ||
-			rKey.kind == IngressLink || rKey.kind == Route || rKey.kind == ExternalDNS {
+			rKey.kind == IngressLink || rKey.kind == Route || rKey.kind == ExternalDNS || rKey.kind == Gateway ||
+			rKey.kind == Ingress {
 			if rKey.kind == Service {
 				//if svc, ok := rKey.rsc.(*v1.Service); ok {
 				//	if svc.Spec.Type == v1.ServiceTypeLoadBalancer {
@@ -223,6 +236,8 @@ func (ctlr *Controller) processResources() bool {
 			delete(ctlr.resources.processedNativeResources, resourceKey)
 			// Delete the route entry from hostPath Map
 			ctlr.deleteHostPathMapEntry(route)
+			// Clear the admit status if the route still exists but has left the managed route group
+			go ctlr.eraseRouteAdmitStatus(route.Namespace + "/" + route.Name)
 		}
 		if rKey.event != Create {
 			// update the poolMem cache, clusterSvcResource & resource-svc maps
@@ -319,7 +334,25 @@ func (ctlr *Controller) processResources() bool {
 				_ = ctlr.processRoutes(routeGroup, false)
 			}
 		}
-		if ctlr.managedResources.ManageCustomResources {
+		if ctlr.managedResources.ManageIngress {
+			for _, ing := range ctlr.getIngressesForSecret(secret) {
+				err := ctlr.processIngress(ing, false)
+				if err != nil {
+					utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+					isRetryableError = true
+				}
+			}
+		}
+		if ctlr.managedResources.ManageGatewayAPI {
+			for _, gw := range ctlr.getGatewaysForSecret(secret) {
+				err := ctlr.processGateway(gw, false)
+				if err != nil {
+					utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+					isRetryableError = true
+				}
+			}
+		}
+		if ctlr.managedResources.ManageCustomResources && ctlr.managedResources.ManageTLSProfile {
 			tlsProfiles := ctlr.getTLSProfilesForSecret(secret)
 			for _, tlsProfile := range tlsProfiles {
 				virtuals := ctlr.getVirtualsForTLSProfile(tlsProfile)
@@ -480,9 +513,9 @@ func (ctlr *Controller) processResources() bool {
 		// Update the poolMembers for affected resources
 		ctlr.updatePoolMembersForService(svcKey, rKey.svcPortUpdated)
 
-	case Endpoints:
-		ep := rKey.rsc.(*v1.Endpoints)
-		svc := ctlr.getServiceForEndpoints(ep, rKey.clusterName)
+	case EndpointSlice:
+		slice := rKey.rsc.(*discoveryv1.EndpointSlice)
+		svc := ctlr.getServiceForEndpointSlice(slice, rKey.clusterName)
 		// No Services are effected with the change in service.
 		if nil == svc {
 			break
@@ -494,7 +527,8 @@ func (ctlr *Controller) processResources() bool {
 		}
 		// Don't process the service as it's not used by any resource
 		if _, ok := ctlr.resources.poolMemCache[svcKey]; !ok {
-			log.Debugf("Skipping endpoint '%v/%v' as it's not used by any CIS monitored resource", ep.Namespace, ep.Name)
+			log.Debugf("Skipping EndpointSlice '%v/%v' as it's not used by any CIS monitored resource", slice.Namespace,
+				slice.Name)
 			break
 		}
 		_ = ctlr.processService(svc, rKey.clusterName)
@@ -547,6 +581,38 @@ func (ctlr *Controller) processResources() bool {
 	case Namespace:
 		ns := rKey.rsc.(*v1.Namespace)
 		nsName := ns.ObjectMeta.Name
+		if ctlr.managedResources.ManageGatewayAPI && rscDelete {
+			gateways := ctlr.getAllGateways("")
+			if gwInf, ok := ctlr.gwInformers[nsName]; ok {
+				gwInf.stop()
+				delete(ctlr.gwInformers, nsName)
+			}
+			// gateways of the other namespaces may have routes from the namespace
+			for _, gw := range gateways {
+				err := ctlr.processGateway(gw, gw.Namespace == nsName)
+				if err != nil {
+					utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+					isRetryableError = true
+				}
+			}
+		}
+		if ctlr.managedResources.ManageIngress && rscDelete {
+			ingresses := ctlr.getAllIngresses(nsName)
+			if !ctlr.managedResources.ManageRoutes {
+				if nrInf, ok := ctlr.nrInformers[nsName]; ok {
+					nrInf.stop()
+					delete(ctlr.nrInformers, nsName)
+				}
+			}
+			// ingresses of the other namespaces sharing the addresses are served again
+			for _, ing := range ingresses {
+				err := ctlr.processIngress(ing, true)
+				if err != nil {
+					utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+					isRetryableError = true
+				}
+			}
+		}
 		if ctlr.managedResources.ManageRoutes {
 			var triggerDelete bool
 			if rscDelete {
@@ -614,6 +680,55 @@ func (ctlr *Controller) processResources() bool {
 				log.Debugf("Added namespace: '%v' to CIS scope", nsName)
 			}
 		}
+	case GatewayClass:
+		if !ctlr.managedResources.ManageGatewayAPI {
+			break
+		}
+		gc := rKey.rsc.(*gatewayv1.GatewayClass)
+		err := ctlr.processGatewayClass(gc, rscDelete)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+			isRetryableError = true
+		}
+	case Gateway:
+		if !ctlr.managedResources.ManageGatewayAPI {
+			break
+		}
+		gw := rKey.rsc.(*gatewayv1.Gateway)
+		err := ctlr.processGateway(gw, rscDelete)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+			isRetryableError = true
+		}
+	case HTTPRoute, TLSRoute, TCPRoute, UDPRoute:
+		if !ctlr.managedResources.ManageGatewayAPI {
+			break
+		}
+		err := ctlr.processGatewayRoute(rKey.kind, rKey.rsc, rscDelete)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+			isRetryableError = true
+		}
+	case IngressClass:
+		if !ctlr.managedResources.ManageIngress {
+			break
+		}
+		ic := rKey.rsc.(*networkingv1.IngressClass)
+		err := ctlr.processIngressClass(ic)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+			isRetryableError = true
+		}
+	case Ingress:
+		if !ctlr.managedResources.ManageIngress {
+			break
+		}
+		ing := rKey.rsc.(*networkingv1.Ingress)
+		err := ctlr.processIngress(ing, rscDelete)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+			isRetryableError = true
+		}
 	case HACIS:
 		log.Debugf("posting declaration on primary cluster down event")
 	case NodeUpdate:
@@ -650,19 +765,28 @@ func (ctlr *Controller) processResources() bool {
 		}
 		// set prometheus resource metrics
 		ctlr.setPrometheusResourceCount()
+		// ARP entries and FDB records of the VXLAN tunnel are posted with the resources
+		vxlanUpdated := ctlr.updateVxlanNodes()
+		// the deploy latency of the requests is measured from the oldest event in the queue
+		eventTime := ctlr.getFirstEventTime()
 		// Put each BIGIPConfig per bigip  pair into specific requestChannel
+		// the requests of all BIG-IPs are enqueued together, so that the staged rollout spans all BIG-IPs
+		var configs []ResourceConfigRequest
 		for bigip, bigipConfig := range ctlr.resources.bigIpMap {
-			if (!reflect.DeepEqual(bigipConfig.ltmConfig, LTMConfig{}) || !reflect.DeepEqual(bigipConfig.gtmConfig, GTMConfig{})) && ctlr.resources.isConfigUpdated(bigip) {
+			if (!reflect.DeepEqual(bigipConfig.ltmConfig, LTMConfig{}) || !reflect.DeepEqual(bigipConfig.gtmConfig, GTMConfig{})) &&
+				(ctlr.resources.isConfigUpdated(bigip) || vxlanUpdated) {
 				for _, bigIpKey := range getBigIpList(bigip) {
 					config := ResourceConfigRequest{
 						bigIpKey:            bigIpKey,
 						bigIpResourceConfig: bigipConfig,
 					}
 					config.reqMeta = ctlr.enqueueReq(bigipConfig, bigIpKey)
-					ctlr.RequestHandler.EnqueueRequestConfig(config)
+					config.reqMeta.eventTime = eventTime
+					configs = append(configs, config)
 				}
 			}
 		}
+		ctlr.RequestHandler.EnqueueRequestConfigs(configs)
 		ctlr.initState = false
 		ctlr.resources.updateCaches()
 
@@ -679,23 +803,27 @@ func getBigIpList(config cisapiv1.BigIpConfig) []BigIpKey {
 	return bigIpList
 }
 
-// getServiceForEndpoints returns the service associated with endpoints.
-func (ctlr *Controller) getServiceForEndpoints(ep *v1.Endpoints, clusterName string) *v1.Service {
+// getServiceForEndpointSlice returns the service owning the EndpointSlice.
+func (ctlr *Controller) getServiceForEndpointSlice(slice *discoveryv1.EndpointSlice, clusterName string) *v1.Service {
 	var svc interface{}
 	var exists bool
 	var err error
-	svcKey := fmt.Sprintf("%s/%s", ep.Namespace, ep.Name)
+	svcName := slice.Labels[discoveryv1.LabelServiceName]
+	if svcName == "" {
+		return nil
+	}
+	svcKey := fmt.Sprintf("%s/%s", slice.Namespace, svcName)
 	if clusterName == "" {
-		comInf, ok := ctlr.getNamespacedCommonInformer(ep.Namespace)
+		comInf, ok := ctlr.getNamespacedCommonInformer(slice.Namespace)
 		if !ok {
-			log.Errorf("Informer not found for namespace: %v", ep.Namespace)
+			log.Errorf("Informer not found for namespace: %v", slice.Namespace)
 			return nil
 		}
 		svc, exists, err = comInf.svcInformer.GetIndexer().GetByKey(svcKey)
 	} else {
-		poolInf, ok := ctlr.getNamespaceMultiClusterPoolInformer(ep.Namespace, clusterName)
+		poolInf, ok := ctlr.getNamespaceMultiClusterPoolInformer(slice.Namespace, clusterName)
 		if !ok {
-			log.Errorf("[MultiCluster] Informer not found for namespace %v and cluster %v", ep.Namespace, clusterName)
+			log.Errorf("[MultiCluster] Informer not found for namespace %v and cluster %v", slice.Namespace, clusterName)
 			return nil
 		}
 		svc, exists, err = poolInf.svcInformer.GetIndexer().GetByKey(svcKey)
@@ -917,12 +1045,14 @@ func (ctlr *Controller) getTLSProfileForVirtualServer(vs *cisapiv1.VirtualServer
 	tlsProfile, err := ctlr.getTLSProfile(tlsName, namespace)
 	if err != nil {
 		log.Errorf("Error fetching TLSProfile %s: %v", tlsName, err)
+		ctlr.recordEventf(vs, v1.EventTypeWarning, MissingTLSProfile, "TLSProfile %v not found", tlsName)
 		return nil
 	}
 
 	// validate TLSProfile
 	validation := validateTLSProfile(tlsProfile)
 	if validation == false {
+		ctlr.recordEventf(vs, v1.EventTypeWarning, InvalidSpec, "TLSProfile %v is invalid", tlsName)
 		return nil
 	}
 
@@ -933,6 +1063,7 @@ func (ctlr *Controller) getTLSProfileForVirtualServer(vs *cisapiv1.VirtualServer
 				secretKey := namespace + "/" + secret
 				clientSecretobj, found, err := comInf.secretsInformer.GetIndexer().GetByKey(secretKey)
 				if err != nil || !found {
+					ctlr.recordEventf(vs, v1.EventTypeWarning, MissingSecret, "Secret %v referred in TLSProfile %v not found", secretKey, tlsName)
 					return nil
 				}
 				clientSecret := clientSecretobj.(*v1.Secret)
@@ -947,6 +1078,7 @@ func (ctlr *Controller) getTLSProfileForVirtualServer(vs *cisapiv1.VirtualServer
 			secretKey := namespace + "/" + tlsProfile.Spec.TLS.ClientSSL
 			clientSecretobj, found, err := comInf.secretsInformer.GetIndexer().GetByKey(secretKey)
 			if err != nil || !found {
+				ctlr.recordEventf(vs, v1.EventTypeWarning, MissingSecret, "Secret %v referred in TLSProfile %v not found", secretKey, tlsName)
 				return nil
 			}
 			clientSecret := clientSecretobj.(*v1.Secret)
@@ -980,6 +1112,7 @@ func (ctlr *Controller) getTLSProfileForVirtualServer(vs *cisapiv1.VirtualServer
 		}
 	}
 	log.Errorf("TLSProfile %s with host %s does not match with virtual server %s host.", tlsName, vs.Spec.Host, vs.ObjectMeta.Name)
+	ctlr.recordEventf(vs, v1.EventTypeWarning, InvalidSpec, "TLSProfile %v does not match the host %v", tlsName, vs.Spec.Host)
 	return nil
 
 }
@@ -1051,40 +1184,53 @@ func (ctlr *Controller) processVirtualServers(
 	// Prepare list of associated VirtualServers to be processed
 	// In the event of deletion, exclude the deleted VirtualServer
 	log.Debugf("Process all the Virtual Servers which share same VirtualServerAddress")
-	// TODO: phase2 get bigipLabel from cr resource or service address cr
-	// Phase1 setting bigipLabel to empty string
-	bigipLabel := BigIPLabel
-	bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
+	bigipConfig, err := ctlr.getResourceBIGIPConfig(virtual.Spec.BigIpLabel)
+	if err != nil {
+		if isVSDeleted {
+			// nothing is posted for the VirtualServer
+			return nil
+		}
+		log.Errorf("VirtualServer %s/%s: %v", virtual.Namespace, virtual.Name, err)
+		ctlr.recordEventf(virtual, v1.EventTypeWarning, BIGIPNotFound, "%v", err)
+		return err
+	}
 	VSSpecProps := &VSSpecProperties{}
 	virtuals := ctlr.getAssociatedVirtualServers(virtual, allVirtuals, isVSDeleted, VSSpecProps)
 	//ctlr.getAssociatedSpecVirtuals(virtuals,VSSpecProps)
 
 	var ip string
 	var status int
-	partition := ctlr.getCRPartition(virtual.Spec.Partition)
-	if ctlr.ipamCli != nil {
+	partition := ctlr.getCRPartition(virtual.Spec.Partition, virtual.Spec.BigIpLabel)
+	if ipamProvider := ctlr.getIPAMProvider(); ipamProvider != nil {
 		if isVSDeleted && len(virtuals) == 0 && virtual.Spec.VirtualServerAddress == "" {
 			if virtual.Spec.HostGroup != "" {
 				//hg is unique across namespaces
 				//all virtuals with same hg are grouped together across namespaces
 				key := virtual.Spec.HostGroup + "_hg"
-				ip = ctlr.releaseIP(virtual.Spec.IPAMLabel, "", key)
+				ip = ipamProvider.ReleaseIP(virtual.Spec.IPAMLabel, "", key)
 			} else {
 				key := virtual.Namespace + "/" + virtual.Spec.Host + "_host"
-				ip = ctlr.releaseIP(virtual.Spec.IPAMLabel, virtual.Spec.Host, key)
+				ip = ipamProvider.ReleaseIP(virtual.Spec.IPAMLabel, virtual.Spec.Host, key)
 			}
 		} else if virtual.Spec.VirtualServerAddress != "" {
 			// Prioritise VirtualServerAddress specified over IPAMLabel
 			ip = virtual.Spec.VirtualServerAddress
+			key := virtual.Namespace + "/" + virtual.Spec.Host + "_host"
+			if virtual.Spec.HostGroup != "" {
+				key = virtual.Spec.HostGroup + "_hg"
+			}
+			if !isVSDeleted && ctlr.hasIPAMAddressConflict(virtual, ip, virtual.Spec.Host, key) {
+				return nil
+			}
 		} else {
 			ipamLabel := getIPAMLabel(virtuals)
 			if virtual.Spec.HostGroup != "" {
 				//hg is unique across namepsaces
 				key := virtual.Spec.HostGroup + "_hg"
-				ip, status = ctlr.requestIP(ipamLabel, "", key)
+				ip, status = ipamProvider.RequestIP(ipamLabel, "", key)
 			} else {
 				key := virtual.Namespace + "/" + virtual.Spec.Host + "_host"
-				ip, status = ctlr.requestIP(ipamLabel, virtual.Spec.Host, key)
+				ip, status = ipamProvider.RequestIP(ipamLabel, virtual.Spec.Host, key)
 			}
 
 			switch status {
@@ -1093,8 +1239,10 @@ func (ctlr *Controller) processVirtualServers(
 				return nil
 			case InvalidInput:
 				log.Debugf("IPAM Invalid IPAM Label: %v for Virtual Server: %s/%s", ipamLabel, virtual.Namespace, virtual.Name)
+				ctlr.recordEventf(virtual, v1.EventTypeWarning, IPAMAllocationFailed, "Invalid IPAM label %v", ipamLabel)
 				return nil
 			case NotRequested:
+				ctlr.recordEvent(virtual, v1.EventTypeWarning, IPAMAllocationFailed, "Unable to request IP address from IPAM, will be re-requested")
 				return fmt.Errorf("unable make do IPAM Request, will be re-requested soon")
 			case Requested:
 				log.Debugf("IP address requested for service: %s/%s", virtual.Namespace, virtual.Name)
@@ -1349,7 +1497,8 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 	var virtuals []*cisapiv1.VirtualServer
 	// {hostname: {path: <empty_struct>}}
 	uniqueHostPathMap := make(map[string]map[string]struct{})
-	currentVSPartition := ctlr.getCRPartition(currentVS.Spec.Partition)
+	currentVSPartition := ctlr.getCRPartition(currentVS.Spec.Partition, currentVS.Spec.BigIpLabel)
+	currentVSBigIpConfig := ctlr.getBIGIPConfig(currentVS.Spec.BigIpLabel)
 
 	for _, vrt := range allVirtuals {
 		// skip the deleted virtual in the event of deletion
@@ -1357,12 +1506,19 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 			continue
 		}
 
+		// skip the virtuals which are posted to other BIG-IPs
+		if ctlr.getBIGIPConfig(vrt.Spec.BigIpLabel) != currentVSBigIpConfig {
+			continue
+		}
+
 		// Multiple VS sharing same VS address with different partition is invalid
 		// This also handles for host group/VS with same hosts
 		if currentVS.Spec.VirtualServerAddress != "" &&
 			currentVS.Spec.VirtualServerAddress == vrt.Spec.VirtualServerAddress &&
-			currentVSPartition != ctlr.getCRPartition(vrt.Spec.Partition) {
+			currentVSPartition != ctlr.getCRPartition(vrt.Spec.Partition, vrt.Spec.BigIpLabel) {
 			log.Errorf("Multiple Virtual Servers %v,%v are configured with same VirtualServerAddress : %v with different partitions", currentVS.Name, vrt.Name, vrt.Spec.VirtualServerAddress)
+			ctlr.recordEventf(currentVS, v1.EventTypeWarning, AddressConflict,
+				"VirtualServerAddress %v is used by VirtualServer %v/%v in a different partition", vrt.Spec.VirtualServerAddress, vrt.Namespace, vrt.Name)
 			return nil
 		}
 
@@ -1392,6 +1548,8 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 			if vrt.Spec.VirtualServerAddress != currentVS.Spec.VirtualServerAddress {
 				if vrt.Spec.Host != "" && vrt.Spec.Host == currentVS.Spec.Host {
 					log.Errorf("Same host %v is configured with different VirtualServerAddress : %v ", vrt.Spec.Host, vrt.Spec.VirtualServerName)
+					ctlr.recordEventf(currentVS, v1.EventTypeWarning, AddressConflict,
+						"Host %v is configured with a different VirtualServerAddress in VirtualServer %v/%v", vrt.Spec.Host, vrt.Namespace, vrt.Name)
 					return nil
 				}
 				// In case of empty host name or host names not matching, skip the virtual with other VirtualServerAddress
@@ -1408,7 +1566,7 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 			}
 		}
 
-		if ctlr.ipamCli != nil {
+		if ctlr.getIPAMProvider() != nil {
 			if currentVS.Spec.HostGroup == "" && vrt.Spec.IPAMLabel != currentVS.Spec.IPAMLabel {
 				log.Errorf("Same host %v is configured with different IPAM labels: %v, %v. Unable to process %v", vrt.Spec.Host, vrt.Spec.IPAMLabel, currentVS.Spec.IPAMLabel, currentVS.Name)
 				return nil
@@ -1474,20 +1632,28 @@ func (ctlr *Controller) validateTSWithSameVSAddress(
 	currentTS *cisapiv1.TransportServer,
 	allVirtuals []*cisapiv1.TransportServer,
 	isVSDeleted bool) bool {
-	currentTSPartition := ctlr.getCRPartition(currentTS.Spec.Partition)
+	currentTSPartition := ctlr.getCRPartition(currentTS.Spec.Partition, currentTS.Spec.BigIpLabel)
+	currentTSBigIpConfig := ctlr.getBIGIPConfig(currentTS.Spec.BigIpLabel)
 	for _, vrt := range allVirtuals {
 		// skip the deleted virtual in the event of deletion
 		if isVSDeleted && vrt.Name == currentTS.Name {
 			continue
 		}
 
+		// skip the virtuals which are posted to other BIG-IPs
+		if ctlr.getBIGIPConfig(vrt.Spec.BigIpLabel) != currentTSBigIpConfig {
+			continue
+		}
+
 		// Multiple TS sharing same VS address with different partition is invalid
 		// This also handles for host group/ vs with same hosts
 		if currentTS.Spec.VirtualServerAddress != "" &&
 			currentTS.Spec.VirtualServerAddress == vrt.Spec.VirtualServerAddress &&
-			currentTSPartition != ctlr.getCRPartition(vrt.Spec.Partition) {
+			currentTSPartition != ctlr.getCRPartition(vrt.Spec.Partition, vrt.Spec.BigIpLabel) {
 			log.Errorf("Multiple Transport Servers %v,%v are configured with same VirtualServerAddress : %v "+
 				"with different partitions", currentTS.Name, vrt.Name, vrt.Spec.VirtualServerAddress)
+			ctlr.recordEventf(currentTS, v1.EventTypeWarning, AddressConflict,
+				"VirtualServerAddress %v is used by TransportServer %v/%v in a different partition", vrt.Spec.VirtualServerAddress, vrt.Namespace, vrt.Name)
 			return false
 		}
 	}
@@ -1497,27 +1663,37 @@ func (ctlr *Controller) validateILsWithSameVSAddress(
 	currentIL *cisapiv1.IngressLink,
 	allILs []*cisapiv1.IngressLink,
 	isILDeleted bool) bool {
-	currentILPartition := ctlr.getCRPartition(currentIL.Spec.Partition)
+	currentILPartition := ctlr.getCRPartition(currentIL.Spec.Partition, currentIL.Spec.BigIpLabel)
+	currentILBigIpConfig := ctlr.getBIGIPConfig(currentIL.Spec.BigIpLabel)
 	for _, vrt := range allILs {
 		// skip the deleted virtual in the event of deletion
 		if isILDeleted && vrt.Name == currentIL.Name {
 			continue
 		}
 
+		// skip the ingress links which are posted to other BIG-IPs
+		if ctlr.getBIGIPConfig(vrt.Spec.BigIpLabel) != currentILBigIpConfig {
+			continue
+		}
+
 		// Multiple IL sharing same VS address with different partition is invalid
 		if currentIL.Spec.VirtualServerAddress != "" &&
 			currentIL.Spec.VirtualServerAddress == vrt.Spec.VirtualServerAddress &&
-			currentILPartition != ctlr.getCRPartition(vrt.Spec.Partition) {
+			currentILPartition != ctlr.getCRPartition(vrt.Spec.Partition, vrt.Spec.BigIpLabel) {
 			log.Errorf("Multiple Ingress Links %v,%v are configured with same VirtualServerAddress : %v "+
 				"with different partitions", currentIL.Name, vrt.Name, vrt.Spec.VirtualServerAddress)
+			ctlr.recordEventf(currentIL, v1.EventTypeWarning, AddressConflict,
+				"VirtualServerAddress %v is used by IngressLink %v/%v in a different partition", vrt.Spec.VirtualServerAddress, vrt.Namespace, vrt.Name)
 			return false
 		}
 	}
 	return true
 }
-func (ctlr *Controller) getCRPartition(partition string) string {
+
+// getCRPartition returns the partition of the resource, the default partition of its BIG-IP is used if not set
+func (ctlr *Controller) getCRPartition(partition, bigipLabel string) string {
 	if partition == "" {
-		return ctlr.getPartitionForBIGIP("")
+		return ctlr.getPartitionForBIGIP(bigipLabel)
 	}
 	return partition
 }
@@ -1974,6 +2150,20 @@ func (ctlr *Controller) updatePoolMembersForService(svcKey MultiClusterServiceKe
 										_ = ctlr.processTransportServers(virtual, false)
 									}
 									return
+								case Gateway:
+									gw := ctlr.getGateway(poolId.rsKey.namespace, poolId.rsKey.name)
+									if gw == nil {
+										continue
+									}
+									_ = ctlr.processGateway(gw, false)
+									return
+								case Ingress:
+									ing := ctlr.getIngress(poolId.rsKey.namespace, poolId.rsKey.name)
+									if ing == nil {
+										continue
+									}
+									_ = ctlr.processIngress(ing, false)
+									return
 								}
 							}
 							ctlr.updatePoolMembersForResources(&pool)
@@ -1985,7 +2175,7 @@ func (ctlr *Controller) updatePoolMembersForService(svcKey MultiClusterServiceKe
 							freshRsCfg.Pools[index] = pool
 						}
 					}
-					bigipConfig := ctlr.getBIGIPConfig(BigIPLabel)
+					bigipConfig := ctlr.getBIGIPConfig(poolId.bigIpLabel)
 					_ = ctlr.resources.setResourceConfig(poolId.partition, poolId.rsName, freshRsCfg, bigipConfig)
 				}
 			}
@@ -2306,10 +2496,16 @@ func (ctlr *Controller) processTransportServers(
 	}
 
 	var allVirtuals []*cisapiv1.TransportServer
-	// TODO: phase2 get bigipLabel from cr resource or service address cr
-	// Phase1 setting bigipLabel to empty string
-	bigipLabel := BigIPLabel
-	bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
+	bigipConfig, err := ctlr.getResourceBIGIPConfig(virtual.Spec.BigIpLabel)
+	if err != nil {
+		if isTSDeleted {
+			// nothing is posted for the TransportServer
+			return nil
+		}
+		log.Errorf("TransportServer %s/%s: %v", virtual.Namespace, virtual.Name, err)
+		ctlr.recordEventf(virtual, v1.EventTypeWarning, BIGIPNotFound, "%v", err)
+		return err
+	}
 	if virtual.Spec.HostGroup != "" {
 		// grouping by hg across all namespaces
 		allVirtuals = ctlr.getAllTSFromMonitoredNamespaces()
@@ -2324,18 +2520,21 @@ func (ctlr *Controller) processTransportServers(
 	var ip string
 	var key string
 	var status int
-	partition := ctlr.getCRPartition(virtual.Spec.Partition)
+	partition := ctlr.getCRPartition(virtual.Spec.Partition, virtual.Spec.BigIpLabel)
 	key = virtual.ObjectMeta.Namespace + "/" + virtual.ObjectMeta.Name + "_ts"
-	if ctlr.ipamCli != nil {
+	if ipamProvider := ctlr.getIPAMProvider(); ipamProvider != nil {
 		if virtual.Spec.HostGroup != "" {
 			key = virtual.Spec.HostGroup + "_hg"
 		}
 		if isTSDeleted && virtual.Spec.VirtualServerAddress == "" {
-			ip = ctlr.releaseIP(virtual.Spec.IPAMLabel, "", key)
+			ip = ipamProvider.ReleaseIP(virtual.Spec.IPAMLabel, "", key)
 		} else if virtual.Spec.VirtualServerAddress != "" {
 			ip = virtual.Spec.VirtualServerAddress
+			if !isTSDeleted && ctlr.hasIPAMAddressConflict(virtual, ip, "", key) {
+				return nil
+			}
 		} else {
-			ip, status = ctlr.requestIP(virtual.Spec.IPAMLabel, "", key)
+			ip, status = ipamProvider.RequestIP(virtual.Spec.IPAMLabel, "", key)
 
 			switch status {
 			case NotEnabled:
@@ -2344,8 +2543,10 @@ func (ctlr *Controller) processTransportServers(
 			case InvalidInput:
 				log.Debugf("[IPAM] IPAM Invalid IPAM Label: %v for Transport Server: %s/%s",
 					virtual.Spec.IPAMLabel, virtual.Namespace, virtual.Name)
+				ctlr.recordEventf(virtual, v1.EventTypeWarning, IPAMAllocationFailed, "Invalid IPAM label %v", virtual.Spec.IPAMLabel)
 				return nil
 			case NotRequested:
+				ctlr.recordEvent(virtual, v1.EventTypeWarning, IPAMAllocationFailed, "Unable to request IP address from IPAM, will be re-requested")
 				return fmt.Errorf("[IPAM] unable to make IPAM Request, will be re-requested soon")
 			case Requested:
 				log.Debugf("[IPAM] IP address requested for Transport Server: %s/%s", virtual.Namespace, virtual.Name)
@@ -2532,62 +2733,155 @@ func (ctlr *Controller) processLBServices(
 	isSVCDeleted bool,
 ) error {
 
+	if !ctlr.isLBServiceClassManaged(svc) {
+		log.Debugf("Service %v/%v is not of the loadBalancerClass %v, continuing.",
+			svc.Namespace,
+			svc.Name,
+			ctlr.loadBalancerClass,
+		)
+		return nil
+	}
+	staticAddresses, err := getLBServiceAddresses(svc)
+	if err != nil {
+		log.Errorf("Service %s/%s: %v", svc.Namespace, svc.Name, err)
+		ctlr.recordEventf(svc, v1.EventTypeWarning, InvalidAddress, "%v", err)
+		return nil
+	}
 	ipamLabel, ok := svc.Annotations[LBServiceIPAMLabelAnnotation]
-	if !ok {
-		log.Debugf("Service %v/%v does not have annotation %v, continuing.",
+	if !ok && len(staticAddresses) == 0 {
+		log.Debugf("Service %v/%v does not have annotation %v, %v or spec.loadBalancerIP, continuing.",
 			svc.Namespace,
 			svc.Name,
 			LBServiceIPAMLabelAnnotation,
+			LBServiceIPAnnotation,
 		)
 		return nil
 	}
-	if ctlr.ipamCli == nil {
+	ipamProvider := ctlr.getIPAMProvider()
+	if ipamProvider == nil && len(staticAddresses) == 0 {
 		warning := "[IPAM] IPAM is not enabled, Unable to process Services of Type LoadBalancer"
 		log.Warningf(warning)
 		prometheus.ConfigurationWarnings.WithLabelValues(Service, svc.ObjectMeta.Namespace, svc.ObjectMeta.Name, warning).Set(1)
 		return nil
 	}
 	prometheus.ConfigurationWarnings.WithLabelValues(Service, svc.ObjectMeta.Namespace, svc.ObjectMeta.Name, "").Set(0)
+	bigipConfig, err := ctlr.getResourceBIGIPConfig(svc.Annotations[LBServiceBigIpLabelAnnotation])
+	if err != nil {
+		if isSVCDeleted {
+			// nothing is posted for the service
+			return nil
+		}
+		log.Errorf("Service %s/%s: %v", svc.Namespace, svc.Name, err)
+		ctlr.recordEventf(svc, v1.EventTypeWarning, BIGIPNotFound, "%v", err)
+		return err
+	}
+	partition := bigipConfig.DefaultPartition
 	svcKey := svc.Namespace + "/" + svc.Name + "_svc"
-	var ip string
+	var ips []string
 	var status int
-	if isSVCDeleted {
-		ip = ctlr.releaseIP(ipamLabel, "", svcKey)
-	} else {
-		ip, status = ctlr.requestIP(ipamLabel, "", svcKey)
-
-		switch status {
-		case NotEnabled:
-			log.Debug("[IPAM] IPAM Custom Resource Not Available")
-			return nil
-		case InvalidInput:
-			log.Debugf("[IPAM] IPAM Invalid IPAM Label: %v for service: %s/%s", ipamLabel, svc.Namespace, svc.Name)
-			return nil
-		case NotRequested:
-			return fmt.Errorf("[IPAM] unable to make IPAM Request, will be re-requested soon")
-		case Requested:
-			log.Debugf("[IPAM] IP address requested for service: %s/%s", svc.Namespace, svc.Name)
+	if len(staticAddresses) > 0 {
+		// Prioritise the static addresses over IPAMLabel, one virtual is created for each IP family of the service
+		ips = getLBServiceFamilyAddresses(svc, staticAddresses)
+		if len(ips) == 0 {
+			log.Errorf("Service %s/%s: none of the addresses %v match the ipFamilies %v", svc.Namespace, svc.Name,
+				staticAddresses, svc.Spec.IPFamilies)
+			ctlr.recordEventf(svc, v1.EventTypeWarning, InvalidAddress, "None of the addresses %v match the ipFamilies %v",
+				staticAddresses, svc.Spec.IPFamilies)
 			return nil
 		}
+		if !isSVCDeleted {
+			for _, ip := range ips {
+				if ctlr.hasIPAMAddressConflict(svc, ip, "", svcKey) {
+					return nil
+				}
+			}
+		}
+	} else if familyIPAM, ok := ipamProvider.(FamilyIPAMProvider); ok && len(svc.Spec.IPFamilies) > 0 {
+		// an IP address of each IP family of the Service is allocated from the ranges of the IPAM label,
+		// the IP addresses of the families removed from the Service are released
+		for _, family := range []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol} {
+			if isSVCDeleted || !slices.Contains(svc.Spec.IPFamilies, family) {
+				if ip := familyIPAM.ReleaseIP(ipamLabel, "", getLBServiceFamilyKey(svcKey, family)); ip != "" && isSVCDeleted {
+					ips = append(ips, ip)
+				}
+			}
+		}
+		for i := 0; i < len(svc.Spec.IPFamilies) && !isSVCDeleted; i++ {
+			family := svc.Spec.IPFamilies[i]
+			var ip string
+			ip, status = familyIPAM.RequestFamilyIP(ipamLabel, family, getLBServiceFamilyKey(svcKey, family))
+			// the secondary IP family is optional unless the Service requires dual-stack
+			if i > 0 && status == InvalidInput && (svc.Spec.IPFamilyPolicy == nil ||
+				*svc.Spec.IPFamilyPolicy != v1.IPFamilyPolicyRequireDualStack) {
+				log.Debugf("[IPAM] No %v address is allocated for service: %s/%s", family, svc.Namespace, svc.Name)
+				continue
+			}
+			if processed, err := ctlr.handleLBServiceIPAMStatus(svc, ipamLabel, status); !processed {
+				return err
+			}
+			ips = append(ips, ip)
+		}
+	} else if isSVCDeleted {
+		ips = []string{ipamProvider.ReleaseIP(ipamLabel, "", svcKey)}
+	} else {
+		var ip string
+		ip, status = ipamProvider.RequestIP(ipamLabel, "", svcKey)
+		if processed, err := ctlr.handleLBServiceIPAMStatus(svc, ipamLabel, status); !processed {
+			return err
+		}
+		ips = []string{ip}
 	}
 
 	if !isSVCDeleted {
-		ctlr.setLBServiceIngressStatus(svc, ip)
+		ctlr.setLBServiceIngressStatus(svc, ips...)
 	} else {
-		ctlr.unSetLBServiceIngressStatus(svc, ip)
+		ctlr.unSetLBServiceIngressStatus(svc, ips...)
 	}
 
+	for _, ip := range ips {
+		if err := ctlr.processLBServiceVirtuals(svc, ip, partition, bigipConfig, isSVCDeleted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleLBServiceIPAMStatus handles the status of the IPAM request of the Service of type LoadBalancer,
+// it returns false when the IP address is not allocated and the Service is not processed
+func (ctlr *Controller) handleLBServiceIPAMStatus(svc *v1.Service, ipamLabel string, status int) (bool, error) {
+	switch status {
+	case NotEnabled:
+		log.Debug("[IPAM] IPAM Custom Resource Not Available")
+		return false, nil
+	case InvalidInput:
+		log.Debugf("[IPAM] IPAM Invalid IPAM Label: %v for service: %s/%s", ipamLabel, svc.Namespace, svc.Name)
+		ctlr.recordEventf(svc, v1.EventTypeWarning, IPAMAllocationFailed, "Invalid IPAM label %v", ipamLabel)
+		return false, nil
+	case NotRequested:
+		ctlr.recordEvent(svc, v1.EventTypeWarning, IPAMAllocationFailed, "Unable to request IP address from IPAM, will be re-requested")
+		return false, fmt.Errorf("[IPAM] unable to make IPAM Request, will be re-requested soon")
+	case Requested:
+		log.Debugf("[IPAM] IP address requested for service: %s/%s", svc.Namespace, svc.Name)
+		return false, nil
+	}
+	return true, nil
+}
+
+// processLBServiceVirtuals creates or deletes the virtuals of the ports of the Service of type LoadBalancer on the ip
+func (ctlr *Controller) processLBServiceVirtuals(
+	svc *v1.Service,
+	ip string,
+	partition string,
+	bigipConfig cisapiv1.BigIpConfig,
+	isSVCDeleted bool,
+) error {
 	for _, portSpec := range svc.Spec.Ports {
 
 		log.Debugf("Processing Service Type LB %s for port %v",
 			svc.ObjectMeta.Name, portSpec)
 
 		rsName := AS3NameFormatter(fmt.Sprintf("vs_lb_svc_%s_%s_%s_%v", svc.Namespace, svc.Name, ip, portSpec.Port))
-		//TODO: get bigipLabel from route resource or service address cr and get parition from specific bigip agent
-		//Phase1 getting partition from bigipconfig index 0
-		bigipLabel := BigIPLabel
-		partition := ctlr.getPartitionForBIGIP(bigipLabel)
-		bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
 		if isSVCDeleted {
 			rsMap := ctlr.resources.getPartitionResourceMap(partition, bigipConfig)
 			var hostnames []string
@@ -2649,6 +2943,57 @@ func (ctlr *Controller) processLBServices(
 	return nil
 }
 
+// isLBServiceClassManaged checks if the loadBalancerClass of the Service of type LoadBalancer is managed by CIS
+func (ctlr *Controller) isLBServiceClassManaged(svc *v1.Service) bool {
+	if svc.Spec.LoadBalancerClass == nil {
+		return !ctlr.manageLoadBalancerClassOnly
+	}
+	return *svc.Spec.LoadBalancerClass == ctlr.loadBalancerClass
+}
+
+// getLBServiceAddresses returns the static addresses of the Service of type LoadBalancer,
+// the comma separated addresses of the cis.f5.com/ip annotation take precedence over spec.loadBalancerIP
+func getLBServiceAddresses(svc *v1.Service) ([]string, error) {
+	var addresses []string
+	if annotation := svc.Annotations[LBServiceIPAnnotation]; annotation != "" {
+		addresses = strings.Split(annotation, ",")
+	} else if svc.Spec.LoadBalancerIP != "" {
+		addresses = []string{svc.Spec.LoadBalancerIP}
+	}
+	for i, address := range addresses {
+		addresses[i] = strings.TrimSpace(address)
+		if net.ParseIP(addresses[i]) == nil {
+			return nil, fmt.Errorf("invalid LoadBalancer IP address %v", addresses[i])
+		}
+	}
+	return addresses, nil
+}
+
+// getLBServiceFamilyKey returns the IPAM key of the IP address of the IP family of the Service of type LoadBalancer
+func getLBServiceFamilyKey(svcKey string, family v1.IPFamily) string {
+	return svcKey + "_" + strings.ToLower(string(family))
+}
+
+// getLBServiceFamilyAddresses returns the first address of each IP family of the Service of type LoadBalancer,
+// both the IP families are served when the ipFamilies are not set
+func getLBServiceFamilyAddresses(svc *v1.Service, addresses []string) []string {
+	families := svc.Spec.IPFamilies
+	if len(families) == 0 {
+		families = []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol}
+	}
+	var familyAddresses []string
+	for _, family := range families {
+		for _, address := range addresses {
+			isIPv4 := net.ParseIP(address).To4() != nil
+			if (family == v1.IPv4Protocol) == isIPv4 {
+				familyAddresses = append(familyAddresses, address)
+				break
+			}
+		}
+	}
+	return familyAddresses
+}
+
 func (ctlr *Controller) processService(
 	svc *v1.Service,
 	clusterName string,
@@ -2664,20 +3009,14 @@ func (ctlr *Controller) processService(
 	pmi.portSpec = svc.Spec.Ports
 	pmi.svcType = svc.Spec.Type
 	nodes := ctlr.getNodesFromCache(svcKey.clusterName)
-	var eps *v1.Endpoints
+	var epsInformer cache.SharedIndexInformer
 	if clusterName == "" {
 		comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
 		if !ok {
 			log.Errorf("Informer not found for namespace: %v %v", namespace, getClusterLog(clusterName))
 			return fmt.Errorf("unable to process Service: %v %v", svcKey, getClusterLog(clusterName))
 		}
-		if comInf.epsInformer != nil {
-			item, found, _ := comInf.epsInformer.GetIndexer().GetByKey(svc.Namespace + "/" + svc.Name)
-			if !found {
-				return fmt.Errorf("Endpoints for service %v %v not found!", svcKey, getClusterLog(clusterName))
-			}
-			eps, _ = item.(*v1.Endpoints)
-		}
+		epsInformer = comInf.epsInformer
 	} else {
 		if _, ok := ctlr.multiClusterPoolInformers[svcKey.clusterName]; ok {
 			var poolInf *MultiClusterPoolInformer
@@ -2688,42 +3027,29 @@ func (ctlr *Controller) processService(
 			if !found {
 				return fmt.Errorf("[MultiCluster] Informer not found for namespace: %v in cluster: %s", svcKey.namespace, clusterName)
 			}
-
-			if poolInf.epsInformer != nil {
-				mItem, mFound, _ := poolInf.epsInformer.GetIndexer().GetByKey(svcKey.namespace + "/" + svcKey.serviceName)
-				if !mFound {
-					return fmt.Errorf("[MultiCluster] Endpoints for service %v %v not found!", svcKey, getClusterLog(clusterName))
-				}
-				eps, _ = mItem.(*v1.Endpoints)
-			}
+			epsInformer = poolInf.epsInformer
 		}
 	}
 
-	if eps != nil {
-		if len(eps.Subsets) == 0 {
+	if epsInformer != nil {
+		objs, _ := epsInformer.GetIndexer().ByIndex(EndpointSliceServiceIndex, svc.Namespace+"/"+svc.Name)
+		if len(objs) == 0 {
+			return fmt.Errorf("EndpointSlices for service %v %v not found!", svcKey, getClusterLog(clusterName))
+		}
+		var slices []*discoveryv1.EndpointSlice
+		for _, obj := range objs {
+			slices = append(slices, obj.(*discoveryv1.EndpointSlice))
+		}
+		memberMap := getPoolMembersForEndpointSlices(slices, nodes, svc.Spec.ClusterIP == "None")
+		if len(memberMap) == 0 {
 			for _, port := range pmi.portSpec {
 				portKey := portRef{name: port.Name, port: port.TargetPort.IntVal}
 				var members []PoolMember
 				pmi.memberMap[portKey] = members
 			}
 		}
-		for _, subset := range eps.Subsets {
-			for _, p := range subset.Ports {
-				var members []PoolMember
-				for _, addr := range subset.Addresses {
-					// Checking for headless services
-					if svc.Spec.ClusterIP == "None" || (addr.NodeName != nil && containsNode(nodes, *addr.NodeName)) {
-						member := PoolMember{
-							Address: addr.IP,
-							Port:    p.Port,
-							Session: "user-enabled",
-						}
-						members = append(members, member)
-					}
-				}
-				portKey := portRef{name: p.Name, port: p.Port}
-				pmi.memberMap[portKey] = members
-			}
+		for portKey, members := range memberMap {
+			pmi.memberMap[portKey] = members
 		}
 	} else {
 		for _, port := range pmi.portSpec {
@@ -2738,14 +3064,71 @@ func (ctlr *Controller) processService(
 	return nil
 }
 
+// getPoolMembersForEndpointSlices merges the endpoints of the Service's EndpointSlices per port, the ready endpoints
+// are enabled pool members while the terminating endpoints which are still serving are disabled to drain connections
+func getPoolMembersForEndpointSlices(
+	slices []*discoveryv1.EndpointSlice,
+	nodes []Node,
+	headless bool,
+) map[portRef][]PoolMember {
+	memberMap := make(map[portRef][]PoolMember)
+	seen := make(map[portRef]map[string]bool)
+	for _, slice := range slices {
+		if slice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+		for _, p := range slice.Ports {
+			if p.Port == nil {
+				continue
+			}
+			portKey := portRef{port: *p.Port}
+			if p.Name != nil {
+				portKey.name = *p.Name
+			}
+			if _, ok := memberMap[portKey]; !ok {
+				memberMap[portKey] = nil
+				seen[portKey] = make(map[string]bool)
+			}
+			for _, ep := range slice.Endpoints {
+				if len(ep.Addresses) == 0 || seen[portKey][ep.Addresses[0]] {
+					continue
+				}
+				// Checking for headless services
+				if !headless && (ep.NodeName == nil || !containsNode(nodes, *ep.NodeName)) {
+					continue
+				}
+				ready := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+				serving := ready
+				if ep.Conditions.Serving != nil {
+					serving = *ep.Conditions.Serving
+				}
+				terminating := ep.Conditions.Terminating != nil && *ep.Conditions.Terminating
+				member := PoolMember{
+					Address: ep.Addresses[0],
+					Port:    *p.Port,
+					Session: "user-enabled",
+				}
+				if !ready {
+					if !serving || !terminating {
+						continue
+					}
+					member.Session = "user-disabled"
+					member.AdminState = string(clustermanager.Disable)
+				}
+				seen[portKey][member.Address] = true
+				memberMap[portKey] = append(memberMap[portKey], member)
+			}
+		}
+	}
+	return memberMap
+}
+
 func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete bool) {
 	if ctlr.managedResources.ManageEDNS == false {
 		return
 	}
-	//TODO: get bigipLabel from route resource or service address cr
-	// Phase1 setting bigipLabel to empty string
-	bigipLabel := BigIPLabel
-	bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
+	// ExternalDNS are posted to the default BIG-IP
+	bigipConfig := ctlr.getBIGIPConfig(BigIPLabel)
 	if bigipConfig != (cisapiv1.BigIpConfig{}) {
 		if _, ok := ctlr.resources.bigIpMap[bigipConfig]; ok {
 			if gtmPartitionConfig, ok := ctlr.resources.bigIpMap[bigipConfig].gtmConfig[DEFAULT_GTM_PARTITION]; ok {
@@ -2809,7 +3192,7 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 
 	log.Debugf("Processing WideIP: %v", edns.Spec.DomainName)
 
-	partitions := ctlr.resources.getLTMPartitions(bigipLabel)
+	partitions := ctlr.resources.getLTMPartitions(bigipConfig.BigIpLabel)
 	for _, pl := range edns.Spec.Pools {
 		UniquePoolName := strings.Replace(edns.Spec.DomainName, "*", "wildcard", -1) + "_" +
 			AS3NameFormatter(strings.TrimPrefix(bigipConfig.BigIpAddress, "https://")) + "_" + DEFAULT_GTM_PARTITION
@@ -3279,19 +3662,28 @@ func (ctlr *Controller) processIngressLink(
 	var ip string
 	var key string
 	var status int
-	partition := ctlr.getCRPartition(ingLink.Spec.Partition)
+	partition := ctlr.getCRPartition(ingLink.Spec.Partition, ingLink.Spec.BigIpLabel)
 	key = ingLink.ObjectMeta.Namespace + "/" + ingLink.ObjectMeta.Name + "_il"
-	//TODO: get bigipLabel from route resource or service address cr
-	// Phase1 setting bigipLabel to empty string
-	bigipLabel := BigIPLabel
-	bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
-	if ctlr.ipamCli != nil {
+	bigipConfig, err := ctlr.getResourceBIGIPConfig(ingLink.Spec.BigIpLabel)
+	if err != nil {
+		if isILDeleted {
+			// nothing is posted for the IngressLink
+			return nil
+		}
+		log.Errorf("IngressLink %s/%s: %v", ingLink.Namespace, ingLink.Name, err)
+		ctlr.recordEventf(ingLink, v1.EventTypeWarning, BIGIPNotFound, "%v", err)
+		return err
+	}
+	if ipamProvider := ctlr.getIPAMProvider(); ipamProvider != nil {
 		if isILDeleted && ingLink.Spec.VirtualServerAddress == "" {
-			ip = ctlr.releaseIP(ingLink.Spec.IPAMLabel, "", key)
+			ip = ipamProvider.ReleaseIP(ingLink.Spec.IPAMLabel, "", key)
 		} else if ingLink.Spec.VirtualServerAddress != "" {
 			ip = ingLink.Spec.VirtualServerAddress
+			if !isILDeleted && ctlr.hasIPAMAddressConflict(ingLink, ip, "", key) {
+				return nil
+			}
 		} else {
-			ip, status = ctlr.requestIP(ingLink.Spec.IPAMLabel, "", key)
+			ip, status = ipamProvider.RequestIP(ingLink.Spec.IPAMLabel, "", key)
 
 			switch status {
 			case NotEnabled:
@@ -3300,8 +3692,10 @@ func (ctlr *Controller) processIngressLink(
 			case InvalidInput:
 				log.Debugf("[IPAM] IPAM Invalid IPAM Label: %v for IngressLink: %s/%s",
 					ingLink.Spec.IPAMLabel, ingLink.Namespace, ingLink.Name)
+				ctlr.recordEventf(ingLink, v1.EventTypeWarning, IPAMAllocationFailed, "Invalid IPAM label %v", ingLink.Spec.IPAMLabel)
 				return nil
 			case NotRequested:
+				ctlr.recordEvent(ingLink, v1.EventTypeWarning, IPAMAllocationFailed, "Unable to request IP address from IPAM, will be re-requested")
 				return fmt.Errorf("[IPAM] unable to make IPAM Request, will be re-requested soon")
 			case Requested:
 				log.Debugf("[IPAM] IP address requested for IngressLink: %s/%s", ingLink.Namespace, ingLink.Name)
@@ -3345,7 +3739,7 @@ func (ctlr *Controller) processIngressLink(
 		for _, rsName := range delRes {
 			var hostnames []string
 			if rsMap[rsName] != nil {
-				rsCfg, err := ctlr.resources.getResourceConfig(partition, rsName, BigIPLabel)
+				rsCfg, err := ctlr.resources.getResourceConfig(partition, rsName, bigipConfig.BigIpLabel)
 				if err == nil {
 					hostnames = rsCfg.MetaData.hosts
 				}
@@ -3435,7 +3829,7 @@ func (ctlr *Controller) processIngressLink(
 			kind:      IngressLink,
 		}
 		// updating the service cache
-		ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, svc.ObjectMeta.Name, "", pool, svcPort, "", bigipLabel)
+		ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, svc.ObjectMeta.Name, "", pool, svcPort, "", bigipConfig.BigIpLabel)
 		// Update the pool Members
 		ctlr.updatePoolMembersForResources(&pool)
 		if len(pool.Members) > 0 {
@@ -3594,14 +3988,16 @@ func (ctlr *Controller) getKICServiceOfIngressLink(ingLink *cisapiv1.IngressLink
 
 func (ctlr *Controller) setLBServiceIngressStatus(
 	svc *v1.Service,
-	ip string,
+	ips ...string,
 ) {
-	// Set the ingress status to include the virtual IP
-	lbIngress := v1.LoadBalancerIngress{IP: ip}
-	if len(svc.Status.LoadBalancer.Ingress) == 0 {
-		svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, lbIngress)
-	} else if svc.Status.LoadBalancer.Ingress[0].IP != ip {
-		svc.Status.LoadBalancer.Ingress[0] = lbIngress
+	// Set the ingress status to include the virtual IPs
+	for i, ip := range ips {
+		lbIngress := v1.LoadBalancerIngress{IP: ip}
+		if len(svc.Status.LoadBalancer.Ingress) <= i {
+			svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, lbIngress)
+		} else if svc.Status.LoadBalancer.Ingress[i].IP != ip {
+			svc.Status.LoadBalancer.Ingress[i] = lbIngress
+		}
 	}
 
 	_, updateErr := ctlr.clientsets.kubeClient.CoreV1().Services(svc.ObjectMeta.Namespace).UpdateStatus(context.TODO(), svc, metav1.UpdateOptions{})
@@ -3616,14 +4012,14 @@ func (ctlr *Controller) setLBServiceIngressStatus(
 		log.Warning(warning)
 		ctlr.recordLBServiceIngressEvent(svc, v1.EventTypeWarning, "StatusIPError", warning)
 	} else {
-		message := fmt.Sprintf("F5 CIS assigned LoadBalancer IP: %v", ip)
+		message := fmt.Sprintf("F5 CIS assigned LoadBalancer IP: %v", strings.Join(ips, ","))
 		ctlr.recordLBServiceIngressEvent(svc, v1.EventTypeNormal, "ExternalIP", message)
 	}
 }
 
 func (ctlr *Controller) unSetLBServiceIngressStatus(
 	svc *v1.Service,
-	ip string,
+	ips ...string,
 ) {
 
 	svcName := svc.Namespace + "/" + svc.Name
@@ -3634,17 +4030,15 @@ func (ctlr *Controller) unSetLBServiceIngressStatus(
 		return
 	}
 	svc = service.(*v1.Service)
-	index := -1
-	for i, lbIng := range svc.Status.LoadBalancer.Ingress {
-		if lbIng.IP == ip {
-			index = i
-			break
+	var lbIngress []v1.LoadBalancerIngress
+	for _, lbIng := range svc.Status.LoadBalancer.Ingress {
+		if !slices.Contains(ips, lbIng.IP) {
+			lbIngress = append(lbIngress, lbIng)
 		}
 	}
 
-	if index != -1 {
-		svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress[:index],
-			svc.Status.LoadBalancer.Ingress[index+1:]...)
+	if len(lbIngress) != len(svc.Status.LoadBalancer.Ingress) {
+		svc.Status.LoadBalancer.Ingress = lbIngress
 
 		_, updateErr := ctlr.clientsets.kubeClient.CoreV1().Services(svc.ObjectMeta.Namespace).UpdateStatus(
 			context.TODO(), svc, metav1.UpdateOptions{})
@@ -3660,7 +4054,7 @@ func (ctlr *Controller) unSetLBServiceIngressStatus(
 			log.Warning(warning)
 			ctlr.recordLBServiceIngressEvent(svc, v1.EventTypeWarning, "StatusIPError", warning)
 		} else {
-			message := fmt.Sprintf("F5 CIS unassigned LoadBalancer IP: %v", ip)
+			message := fmt.Sprintf("F5 CIS unassigned LoadBalancer IP: %v", strings.Join(ips, ","))
 			ctlr.recordLBServiceIngressEvent(svc, v1.EventTypeNormal, "ExternalIP", message)
 		}
 	}
@@ -3696,11 +4090,7 @@ func (ctlr *Controller) recordLBServiceIngressEvent(
 	reason string,
 	message string,
 ) {
-	//namespace := svc.ObjectMeta.Namespace
-	//// Create the event
-	//evNotifier := ctlr.eventNotifier.CreateNotifierForNamespace(
-	//	namespace, ctlr.clientsets.kubeClient.CoreV1())
-	//evNotifier.RecordEvent(svc, eventType, reason, message)
+	ctlr.recordEvent(svc, eventType, reason, message)
 }
 
 // sort services by timestamp
@@ -3752,24 +4142,50 @@ func getNodeport(svc *v1.Service, servicePort int32) int32 {
 	return 0
 }
 
-// Update virtual server status with virtual server address
-/*func (ctlr *Controller) updateVirtualServerStatus(vs *cisapiv1.VirtualServer, ip string, statusOk string) {
-	// Set the vs status to include the virtual IP address
-	vsStatus := cisapiv1.VirtualServerStatus{VSAddress: ip, StatusOk: statusOk}
-	log.Debugf("Updating VirtualServer Status with %v for resource name:%v , namespace: %v", vsStatus, vs.Name, vs.Namespace)
-	vs.Status = vsStatus
-	vs.Status.VSAddress = ip
-	vs.Status.StatusOk = statusOk
+// Update virtual server status with virtual server address and the result of the post to BIG-IP
+func (ctlr *Controller) updateVirtualServerStatus(vs *cisapiv1.VirtualServer, ip string, statusOk string, errMsg string) {
+	// Skip the update if the status is already up-to-date with the latest generation
+	if vs.Status.VSAddress == ip && vs.Status.StatusOk == statusOk && vs.Status.Error == errMsg &&
+		vs.Status.ObservedGeneration == vs.Generation {
+		return
+	}
+	vsStatus := vs.Status.DeepCopy()
+	vsStatus.VSAddress = ip
+	vsStatus.StatusOk = statusOk
+	vsStatus.Error = errMsg
+	vsStatus.LastUpdated = metav1.Now()
+	vsStatus.ObservedGeneration = vs.Generation
+	condition := metav1.Condition{
+		Type:               DeployedCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: vs.Generation,
+		Reason:             DeploySucceeded,
+		Message:            fmt.Sprintf("VirtualServer deployed on BIG-IP with address %v", ip),
+	}
+	if statusOk != StatusOk {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = DeployFailed
+		condition.Message = errMsg
+	}
+	apimeta.SetStatusCondition(&vsStatus.Conditions, condition)
+	log.Debugf("Updating VirtualServer Status with %v for resource name:%v , namespace: %v", vsStatus.StatusOk, vs.Name, vs.Namespace)
+	vs.Status = *vsStatus
 	_, updateErr := ctlr.clientsets.kubeCRClient.CisV1().VirtualServers(vs.ObjectMeta.Namespace).UpdateStatus(context.TODO(), vs, metav1.UpdateOptions{})
 	if nil != updateErr {
 		log.Debugf("Error while updating virtual server status:%v", updateErr)
 		return
 	}
-}*/
+	if statusOk == StatusOk {
+		ctlr.recordEvent(vs, v1.EventTypeNormal, DeploySucceeded, condition.Message)
+	} else {
+		ctlr.recordEventf(vs, v1.EventTypeWarning, DeployFailed, "Failed to deploy VirtualServer on BIG-IP: %v", errMsg)
+	}
+}
 
 // Update Transport server status with virtual server address
 func (ctlr *Controller) updateTransportServerStatus(ts *cisapiv1.TransportServer, ip string, statusOk string) {
 	// Set the vs status to include the virtual IP address
+	changed := ts.Status.VSAddress != ip || ts.Status.StatusOk != statusOk
 	tsStatus := cisapiv1.TransportServerStatus{VSAddress: ip, StatusOk: statusOk}
 	log.Debugf("Updating VirtualServer Status with %v for resource name:%v , namespace: %v", tsStatus, ts.Name, ts.Namespace)
 	ts.Status = tsStatus
@@ -3780,6 +4196,9 @@ func (ctlr *Controller) updateTransportServerStatus(ts *cisapiv1.TransportServer
 		log.Debugf("Error while updating Transport server status:%v", updateErr)
 		return
 	}
+	if changed && statusOk == StatusOk {
+		ctlr.recordEventf(ts, v1.EventTypeNormal, DeploySucceeded, "TransportServer deployed on BIG-IP with address %v", ip)
+	}
 }
 
 // Update ingresslink status with virtual server address
@@ -3968,6 +4387,7 @@ func (ctlr *Controller) processCNIConfig(configCR *cisapiv1.DeployConfig) {
 				log.Errorf("tunnelName is not set in CIS Config CR")
 				os.Exit(1)
 			}
+			ctlr.TunnelName = configCR.Spec.NetworkConfig.MetaData.TunnelName
 		} else {
 			log.Errorf("invalid CNI: %v configured in Config CR", ctlr.OrchestrationCNI)
 			os.Exit(1)
@@ -3975,6 +4395,32 @@ func (ctlr *Controller) processCNIConfig(configCR *cisapiv1.DeployConfig) {
 	}
 }
 
+// processL3NetworkConfig updates the network config of the BIG-IPs and re-posts the config of the updated BIG-IPs
+func (ctlr *Controller) processL3NetworkConfig(l3Configs []cisapiv1.L3NetworkConfig) {
+	updatedBigIps := ctlr.RequestHandler.setL3NetworkConfigs(l3Configs)
+	// on startup the network config is posted along with the resources
+	if ctlr.initState || len(updatedBigIps) == 0 {
+		return
+	}
+	// the network config is deployed to the BIG-IPs without any resources as well
+	var configs []ResourceConfigRequest
+	for bigip := range ctlr.bigIpMap {
+		bigipConfig := ctlr.resources.bigIpMap[bigip]
+		for _, bigIpKey := range getBigIpList(bigip) {
+			if !slices.Contains(updatedBigIps, bigIpKey.BigIpAddress) {
+				continue
+			}
+			config := ResourceConfigRequest{
+				bigIpKey:            bigIpKey,
+				bigIpResourceConfig: bigipConfig,
+			}
+			config.reqMeta = ctlr.enqueueReq(bigipConfig, bigIpKey)
+			configs = append(configs, config)
+		}
+	}
+	ctlr.RequestHandler.EnqueueRequestConfigs(configs)
+}
+
 func (ctlr *Controller) processConfigCR(configCR *cisapiv1.DeployConfig, isDelete bool) (error, bool) {
 	startTime := time.Now()
 	defer func() {
@@ -3999,6 +4445,10 @@ func (ctlr *Controller) processConfigCR(configCR *cisapiv1.DeployConfig, isDelet
 			os.Exit(1)
 		}
 	}
+	if ctlr.isGlobalExtendedCR(configCR) {
+		ctlr.processL3NetworkConfig(configCR.Spec.NetworkConfig.L3Config)
+		ctlr.processIPAMConfig(configCR.Spec.IPAMConfig, configCR.Namespace)
+	}
 	es := configCR.Spec.ExtendedSpec
 	// clusterConfigUpdated, oldClusterRatio and oldClusterAdminState are used for tracking cluster ratio and cluster Admin state updates
 	clusterConfigUpdated := false
@@ -4427,33 +4877,30 @@ func (ctlr *Controller) handleBigipConfigUpdates(config []cisapiv1.BigIpConfig)
 }
 
 func (ctlr *Controller) getPartitionForBIGIP(bigipLabel string) string {
-	//get partition from bigip
-	for bigipconfig, _ := range ctlr.bigIpMap {
-		//TODO: get bigipLabel from route resource or service address cr and get parition from specific bigip agent
-		//Phase1 getting partition from bigipconfig index 0
-		if bigipLabel == "" {
-			return bigipconfig.DefaultPartition
-		} else {
-			if bigipconfig.BigIpLabel == bigipLabel {
-				return bigipconfig.DefaultPartition
-			}
-		}
-	}
-	return ""
+	return ctlr.getBIGIPConfig(bigipLabel).DefaultPartition
 }
 
+// getBIGIPConfig returns the BIG-IP config of the bigipLabel,
+// resources without bigipLabel are posted to the BIG-IP which comes first in the order of the bigIpLabel
 func (ctlr *Controller) getBIGIPConfig(bigipLabel string) cisapiv1.BigIpConfig {
-	//get partition from bigip
+	var bigipConfigs BIGIPConfigs
 	for bigipconfig, _ := range ctlr.bigIpMap {
-		//TODO: get bigipLabel from route resource or service address cr and get parition from specific bigip agent
-		//Phase1 getting partition from bigipconfig index 0
-		if bigipLabel == "" {
-			return bigipconfig
-		} else {
-			if bigipconfig.BigIpLabel == bigipLabel {
-				return bigipconfig
-			}
+		if bigipLabel == "" || bigipconfig.BigIpLabel == bigipLabel {
+			bigipConfigs = append(bigipConfigs, bigipconfig)
 		}
 	}
-	return cisapiv1.BigIpConfig{}
+	if len(bigipConfigs) == 0 {
+		return cisapiv1.BigIpConfig{}
+	}
+	sort.Sort(bigipConfigs)
+	return bigipConfigs[0]
+}
+
+// getResourceBIGIPConfig returns the BIG-IP config of the bigipLabel set on the resource
+func (ctlr *Controller) getResourceBIGIPConfig(bigipLabel string) (cisapiv1.BigIpConfig, error) {
+	bigipConfig := ctlr.getBIGIPConfig(bigipLabel)
+	if bigipLabel != "" && bigipConfig.BigIpLabel != bigipLabel {
+		return bigipConfig, fmt.Errorf("BIG-IP with bigIpLabel %v is not found in the DeployConfig", bigipLabel)
+	}
+	return bigipConfig, nil
 }
@@ -24,12 +24,14 @@ import (
 	//apm "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/appmanager"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/test"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -193,6 +195,87 @@ var _ = Describe("Worker Tests", func() {
 		})
 	})
 
+	Describe("VirtualServer Status", func() {
+		It("Update VirtualServer status", func() {
+			recorder := record.NewFakeRecorder(10)
+			mockCtlr.eventRecorder = recorder
+			vrt1.Generation = 2
+			mockCtlr.updateVirtualServerStatus(vrt1, "1.2.3.4", StatusOk, "")
+			Expect(recorder.Events).To(Receive(Equal("Normal DeploySucceeded VirtualServer deployed on BIG-IP with address 1.2.3.4")))
+			vs, err := mockCtlr.clientsets.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(vs.Status.VSAddress).To(Equal("1.2.3.4"))
+			Expect(vs.Status.StatusOk).To(Equal(StatusOk))
+			Expect(vs.Status.ObservedGeneration).To(BeEquivalentTo(2))
+			Expect(vs.Status.Conditions).To(HaveLen(1))
+			Expect(vs.Status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+			Expect(vs.Status.Conditions[0].Reason).To(Equal(DeploySucceeded))
+
+			mockCtlr.updateVirtualServerStatus(vrt1, "1.2.3.4", StatusFailed, "invalid declaration")
+			vs, err = mockCtlr.clientsets.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(vs.Status.StatusOk).To(Equal(StatusFailed))
+			Expect(vs.Status.Error).To(Equal("invalid declaration"))
+			Expect(vs.Status.Conditions).To(HaveLen(1))
+			Expect(vs.Status.Conditions[0].Status).To(Equal(metav1.ConditionFalse))
+			Expect(vs.Status.Conditions[0].Reason).To(Equal(DeployFailed))
+			Expect(vs.Status.Conditions[0].Message).To(Equal("invalid declaration"))
+			Expect(recorder.Events).To(Receive(Equal("Warning DeployFailed Failed to deploy VirtualServer on BIG-IP: invalid declaration")))
+
+			// no event for an unchanged status
+			mockCtlr.updateVirtualServerStatus(vrt1, "1.2.3.4", StatusFailed, "invalid declaration")
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("Update VirtualServer and LB Service status from post response", func() {
+			bigIpKey := BigIpKey{BigIpAddress: "10.8.3.11", BigIpLabel: "bigip1"}
+			respChan := make(chan *agentConfig, 1)
+			go mockCtlr.responseHandler(respChan)
+			svc1.Spec.Type = v1.ServiceTypeLoadBalancer
+			mockCtlr.addService(svc1)
+			vrt1.Status.VSAddress = "1.2.3.4"
+			mockCtlr.addVirtualServer(vrt1)
+			rscKey := vrt1.Namespace + "/" + vrt1.Name
+			time.Sleep(10 * time.Millisecond)
+			mockCtlr.requestMap.Lock()
+			// failed tenants of an older request are not retried
+			mockCtlr.requestMap.requestMap[bigIpKey] = requestMeta{id: 2}
+			mockCtlr.requestMap.Unlock()
+
+			agentCfg := &agentConfig{
+				id: 1,
+				as3Config: as3Config{
+					failedTenants:     map[string]struct{}{"test": {}},
+					tenantResponseMap: map[string]tenantResponse{"test": {agentResponseCode: http.StatusUnprocessableEntity, message: "invalid pool"}},
+				},
+				BigIpKey: bigIpKey,
+				reqMeta:  requestMeta{id: 1, partitionMap: map[string]map[string]string{"test": {rscKey: VirtualServer}}},
+			}
+			respChan <- agentCfg
+			Eventually(func() string {
+				vs, _ := mockCtlr.clientsets.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+				return vs.Status.Error
+			}, 5*time.Second, 50*time.Millisecond).Should(Equal("invalid pool"))
+
+			agentCfg = &agentConfig{
+				id:        1,
+				as3Config: as3Config{failedTenants: make(map[string]struct{})},
+				BigIpKey:  bigIpKey,
+				reqMeta:   requestMeta{id: 1, partitionMap: map[string]map[string]string{"test": {rscKey: VirtualServer}}},
+			}
+			respChan <- agentCfg
+			Eventually(func() string {
+				vs, _ := mockCtlr.clientsets.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+				return vs.Status.StatusOk
+			}, 5*time.Second, 50*time.Millisecond).Should(Equal(StatusOk))
+			Eventually(func() []v1.LoadBalancerIngress {
+				svc, _ := mockCtlr.clientsets.kubeClient.CoreV1().Services(namespace).Get(context.TODO(), svc1.Name, metav1.GetOptions{})
+				return svc.Status.LoadBalancer.Ingress
+			}, 5*time.Second, 50*time.Millisecond).Should(Equal([]v1.LoadBalancerIngress{{IP: "1.2.3.4"}}))
+			close(respChan)
+		})
+	})
+
 	Describe("IPAM", func() {
 		DEFAULT_PARTITION = "test"
 		BeforeEach(func() {
@@ -843,6 +926,41 @@ var _ = Describe("Worker Tests", func() {
 			Expect(len(mems)).To(Equal(0), "Wrong set of Endpoints for NodePort")
 		})
 
+		It("EndpointSlices", func() {
+			ports := convertSvcPortsToEndpointPorts([]v1.ServicePort{{Name: "http", Port: 8080}})
+			slice1 := test.NewEndpointSlice("svc1", "1", "worker1", namespace,
+				[]string{"10.1.1.1", "10.1.1.2"}, []string{"10.1.1.3"}, ports)
+			slice1.Name = "svc1-abcde"
+			slice2 := test.NewEndpointSlice("svc1", "1", "worker2", namespace,
+				[]string{"2001:db8::1", "10.1.1.1"}, nil, ports)
+			slice2.Name = "svc1-fghij"
+			slice2.AddressType = discoveryv1.AddressTypeIPv6
+			// terminating endpoint which is still serving is drained
+			serving, terminating, ready := true, true, false
+			slice2.Endpoints[1].Addresses = []string{"2001:db8::2"}
+			slice2.Endpoints[1].Conditions = discoveryv1.EndpointConditions{
+				Ready: &ready, Serving: &serving, Terminating: &terminating,
+			}
+			// endpoint of an unknown node
+			slice3 := test.NewEndpointSlice("svc1", "1", "worker9", namespace, []string{"10.1.1.9"}, nil, ports)
+			slice3.Name = "svc1-klmno"
+
+			memberMap := getPoolMembersForEndpointSlices([]*discoveryv1.EndpointSlice{slice1, slice2, slice3},
+				mockCtlr.getNodesFromCache(""), false)
+			Expect(memberMap).To(Equal(map[portRef][]PoolMember{
+				{name: "http", port: 8080}: {
+					{Address: "10.1.1.1", Port: 8080, Session: "user-enabled"},
+					{Address: "10.1.1.2", Port: 8080, Session: "user-enabled"},
+					{Address: "2001:db8::1", Port: 8080, Session: "user-enabled"},
+					{Address: "2001:db8::2", Port: 8080, Session: "user-disabled", AdminState: "disable"},
+				},
+			}), "Wrong set of pool members for EndpointSlices")
+
+			memberMap = getPoolMembersForEndpointSlices([]*discoveryv1.EndpointSlice{slice3},
+				mockCtlr.getNodesFromCache(""), true)
+			Expect(memberMap[portRef{name: "http", port: 8080}]).To(HaveLen(1), "Headless service member not found")
+		})
+
 	})
 
 	Describe("Processing Resources", func() {
@@ -907,6 +1025,81 @@ var _ = Describe("Worker Tests", func() {
 			Expect(len(svc1.Status.LoadBalancer.Ingress)).To(Equal(1))
 		})
 
+		It("Processing ServiceTypeLoadBalancer with static addresses", func() {
+			mockCtlr.resources.Init()
+			svc1.Spec.Type = v1.ServiceTypeLoadBalancer
+			svc1.Spec.LoadBalancerIP = "10.10.10.1"
+			rsName := AS3NameFormatter("vs_lb_svc_default_svc1_10.10.10.1_80")
+
+			// Service of another loadBalancerClass
+			lbClass := "metallb"
+			svc1.Spec.LoadBalancerClass = &lbClass
+			_ = mockCtlr.processLBServices(svc1, false)
+			Expect(len(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig)).To(Equal(0), "Resource Config should be empty")
+
+			mockCtlr.loadBalancerClass = lbClass
+			Expect(mockCtlr.processLBServices(svc1, false)).To(BeNil())
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap).To(HaveKey(rsName))
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap[rsName].Virtual.Destination).
+				To(Equal("/test/10.10.10.1:80"))
+			_ = mockCtlr.processLBServices(svc1, true)
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap).To(BeEmpty())
+
+			// Service without loadBalancerClass when only the loadBalancerClass is managed
+			svc1.Spec.LoadBalancerClass = nil
+			mockCtlr.manageLoadBalancerClassOnly = true
+			_ = mockCtlr.processLBServices(svc1, false)
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap).To(BeEmpty())
+			mockCtlr.manageLoadBalancerClassOnly = false
+
+			// dual-stack Service with the annotation
+			svc1.Annotations = map[string]string{LBServiceIPAnnotation: "2001:db8::10, 10.10.10.2, 10.10.10.3"}
+			svc1.Spec.IPFamilies = []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol}
+			Expect(mockCtlr.processLBServices(svc1, false)).To(BeNil())
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap).To(HaveLen(2))
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap).
+				To(HaveKey(AS3NameFormatter("vs_lb_svc_default_svc1_10.10.10.2_80")))
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap).
+				To(HaveKey(AS3NameFormatter("vs_lb_svc_default_svc1_2001:db8::10_80")))
+			Expect(svc1.Status.LoadBalancer.Ingress).To(Equal([]v1.LoadBalancerIngress{{IP: "10.10.10.2"}, {IP: "2001:db8::10"}}))
+
+			// invalid address
+			mockCtlr.resources.Init()
+			svc1.Annotations[LBServiceIPAnnotation] = "10.10.10.300"
+			_ = mockCtlr.processLBServices(svc1, false)
+			Expect(len(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig)).To(Equal(0), "Resource Config should be empty")
+		})
+
+		It("Processing dual-stack ServiceTypeLoadBalancer with the embedded IPAM", func() {
+			mockCtlr.resources.Init()
+			mockCtlr.builtinIPAM = NewBuiltinIPAM(nil, "kube-system", nil)
+			mockCtlr.builtinIPAM.updateRanges(cisapiv1.IPAMConfig{Ranges: []cisapiv1.IPAMRange{
+				{IPAMLabel: "Dev", Range: "10.8.3.10-10.8.3.12"},
+				{IPAMLabel: "Dev", Range: "2001:db8::/126"},
+			}})
+			svc1.Spec.Type = v1.ServiceTypeLoadBalancer
+			svc1.Annotations = map[string]string{LBServiceIPAMLabelAnnotation: "Dev"}
+			svc1.Spec.IPFamilies = []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol}
+			Expect(mockCtlr.processLBServices(svc1, false)).To(BeNil())
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap).To(HaveLen(2))
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap).
+				To(HaveKey(AS3NameFormatter("vs_lb_svc_default_svc1_2001:db8::1_80")))
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap).
+				To(HaveKey(AS3NameFormatter("vs_lb_svc_default_svc1_10.8.3.10_80")))
+			Expect(mockCtlr.builtinIPAM.allocations).To(HaveLen(2))
+
+			// the IP address of the removed IP family is released
+			svc1.Spec.IPFamilies = []v1.IPFamily{v1.IPv4Protocol}
+			Expect(mockCtlr.processLBServices(svc1, false)).To(BeNil())
+			Expect(mockCtlr.builtinIPAM.allocations).To(HaveLen(1))
+
+			svc1.Spec.IPFamilies = []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol}
+			Expect(mockCtlr.processLBServices(svc1, false)).To(BeNil())
+			Expect(mockCtlr.processLBServices(svc1, true)).To(BeNil())
+			Expect(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig["test"].ResourceMap).To(BeEmpty())
+			Expect(mockCtlr.builtinIPAM.allocations).To(BeEmpty())
+		})
+
 		It("Processing External DNS", func() {
 			mockCtlr.resources.Init()
 			mockCtlr.resources.bigIpMap[bigipConfig] = BigIpResourceConfig{
@@ -1371,6 +1564,46 @@ var _ = Describe("Worker Tests", func() {
 
 		})
 	})
+	Describe("Targeting BIG-IPs by bigIpLabel", func() {
+		var bigipConfig2 cisapiv1.BigIpConfig
+		BeforeEach(func() {
+			bigipConfig2 = cisapiv1.BigIpConfig{
+				BigIpLabel:       "bigip2",
+				DefaultPartition: "test2",
+				BigIpAddress:     "10.8.3.12",
+			}
+			mockCtlr.bigIpMap[bigipConfig2] = BigIpResourceConfig{ltmConfig: make(LTMConfig), gtmConfig: make(GTMConfig)}
+		})
+		It("Gets the BIG-IP config of the bigIpLabel", func() {
+			Expect(mockCtlr.getBIGIPConfig("")).To(Equal(bigipConfig), "Resources without bigIpLabel should be posted to the first BIG-IP")
+			Expect(mockCtlr.getBIGIPConfig("bigip2")).To(Equal(bigipConfig2))
+			Expect(mockCtlr.getCRPartition("", "bigip2")).To(Equal("test2"))
+			Expect(mockCtlr.getCRPartition("prtn", "bigip2")).To(Equal("prtn"))
+			config, err := mockCtlr.getResourceBIGIPConfig("bigip2")
+			Expect(err).To(BeNil())
+			Expect(config).To(Equal(bigipConfig2))
+			_, err = mockCtlr.getResourceBIGIPConfig("bigip3")
+			Expect(err).NotTo(BeNil(), "BIG-IP with bigIpLabel bigip3 is not configured")
+		})
+		It("Skips the virtuals posted to other BIG-IPs", func() {
+			vrt2 := test.NewVirtualServer(
+				"vrt2",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host:                 "test.com",
+					VirtualServerAddress: "1.2.3.5",
+					BigIpLabel:           "bigip2",
+				},
+			)
+			vrt1.Spec.VirtualServerAddress = "1.2.3.5"
+			vrt1.Spec.Partition = "test1"
+			virtuals := mockCtlr.getAssociatedVirtualServers(vrt1, []*cisapiv1.VirtualServer{vrt1, vrt2}, false, &VSSpecProperties{})
+			Expect(virtuals).To(Equal([]*cisapiv1.VirtualServer{vrt1}), "Virtual on the other BIG-IP should be skipped")
+			vrt1.Spec.BigIpLabel = "bigip2"
+			virtuals = mockCtlr.getAssociatedVirtualServers(vrt1, []*cisapiv1.VirtualServer{vrt1, vrt2}, false, &VSSpecProperties{})
+			Expect(virtuals).To(BeNil(), "Virtuals with same address in different partitions are invalid on the same BIG-IP")
+		})
+	})
 	//Describe("Update Pool Members for nodeport", func() {
 	//	BeforeEach(func() {
 	//		mockCtlr.crInformers = make(map[string]*CRInformer)
@@ -1554,7 +1787,7 @@ var _ = Describe("Worker Tests", func() {
 			var tlsProf *cisapiv1.TLSProfile
 			var secret *v1.Secret
 			var tlsSecretProf *cisapiv1.TLSProfile
-			var fooEndpts *v1.Endpoints
+			var fooEndpts *discoveryv1.EndpointSlice
 			var fooPorts []v1.ServicePort
 
 			BeforeEach(func() {
@@ -1617,7 +1850,7 @@ var _ = Describe("Worker Tests", func() {
 					{Port: 9090, NodePort: 39001}}
 				fooIps := []string{"10.1.1.1"}
 
-				fooEndpts = test.NewEndpoints(
+				fooEndpts = test.NewEndpointSlice(
 					"svc1", "1", "node0", namespace, fooIps, []string{},
 					convertSvcPortsToEndpointPorts(fooPorts))
 
@@ -1662,7 +1895,7 @@ var _ = Describe("Worker Tests", func() {
 				crInf.start()
 				nrInf.start()
 
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				svc := test.NewService("svc1", "1", namespace, "NodePort", fooPorts)
@@ -1798,7 +2031,7 @@ var _ = Describe("Worker Tests", func() {
 			//	crInf.start()
 			//	nrInf.start()
 			//
-			//	mockCtlr.addEndpoints(fooEndpts)
+			//	mockCtlr.addEndpointSlice(fooEndpts)
 			//	mockCtlr.processResources()
 			//
 			//	svc := test.NewService("svc1", "1", namespace, "NodePort", fooPorts)
@@ -1894,7 +2127,7 @@ var _ = Describe("Worker Tests", func() {
 				//	Add Service
 				vs.Spec.IPAMLabel = "test"
 
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				svc := test.NewService("svc1", "1", namespace, "NodePort", fooPorts)
@@ -2032,7 +2265,7 @@ var _ = Describe("Worker Tests", func() {
 				crInf.start()
 				nrInf.start()
 				vs.Spec.TLSProfileName = ""
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				svc := test.NewService("svc1", "1", namespace, "NodePort", fooPorts)
@@ -2116,7 +2349,7 @@ var _ = Describe("Worker Tests", func() {
 
 		Describe("Processing Transport Server", func() {
 			var ts *cisapiv1.TransportServer
-			var fooEndpts *v1.Endpoints
+			var fooEndpts *discoveryv1.EndpointSlice
 			var fooPorts []v1.ServicePort
 
 			BeforeEach(func() {
@@ -2126,7 +2359,7 @@ var _ = Describe("Worker Tests", func() {
 					{Port: 9090, NodePort: 39001}}
 				fooIps := []string{"10.1.1.1"}
 
-				fooEndpts = test.NewEndpoints(
+				fooEndpts = test.NewEndpointSlice(
 					"svc1", "1", "node0", namespace, fooIps, []string{},
 					convertSvcPortsToEndpointPorts(fooPorts))
 
@@ -2167,7 +2400,7 @@ var _ = Describe("Worker Tests", func() {
 				go mockCtlr.RequestHandler.startRequestHandler()
 				go mockCtlr.responseHandler(mockCtlr.RequestHandler.PostManagers.PostManagerMap[bigIpKey].respChan)
 
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				svc := test.NewService("svc1", "1", namespace, "NodePort", fooPorts)
@@ -2232,7 +2465,7 @@ var _ = Describe("Worker Tests", func() {
 				go mockCtlr.RequestHandler.startRequestHandler()
 				mockCtlr.TeemData.ResourceType.IPAMTS = make(map[string]int)
 				//Add Service
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				svc := test.NewService("svc1", "1", namespace, "NodePort", fooPorts)
@@ -2346,7 +2579,7 @@ var _ = Describe("Worker Tests", func() {
 				go mockCtlr.RequestHandler.startRequestHandler()
 				mockCtlr.TeemData.ResourceType.IPAMTS = make(map[string]int)
 				//Add Service
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				svc := test.NewService("svc1", "1", namespace, "NodePort", fooPorts)
@@ -2448,7 +2681,7 @@ var _ = Describe("Worker Tests", func() {
 		})
 
 		Describe("Processing EDNS", func() {
-			var fooEndpts *v1.Endpoints
+			var fooEndpts *discoveryv1.EndpointSlice
 			var fooPorts []v1.ServicePort
 			var newEDNS *cisapiv1.ExternalDNS
 			//var ts *cisapiv1.TransportServer
@@ -2460,7 +2693,7 @@ var _ = Describe("Worker Tests", func() {
 					{Port: 9090, NodePort: 39001}}
 				fooIps := []string{"10.1.1.1"}
 
-				fooEndpts = test.NewEndpoints(
+				fooEndpts = test.NewEndpointSlice(
 					"svc1", "1", "node0", namespace, fooIps, []string{},
 					convertSvcPortsToEndpointPorts(fooPorts))
 
@@ -2513,7 +2746,7 @@ var _ = Describe("Worker Tests", func() {
 			})
 
 			It("EDNS", func() {
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				svc := test.NewService("svc1", "1", namespace, "NodePort", fooPorts)
@@ -2531,7 +2764,7 @@ var _ = Describe("Worker Tests", func() {
 			})
 
 			//It("Process Transport server with EDNS", func() {
-			//	mockCtlr.addEndpoints(fooEndpts)
+			//	mockCtlr.addEndpointSlice(fooEndpts)
 			//	mockCtlr.processResources()
 			//
 			//	svc := test.NewService("svc1", "1", namespace, "NodePort", fooPorts)
@@ -2995,7 +3228,7 @@ var _ = Describe("Worker Tests", func() {
 				mockCtlr.shutdown()
 			})
 
-			var fooEndpts *v1.Endpoints
+			var fooEndpts *discoveryv1.EndpointSlice
 			var fooPorts []v1.ServicePort
 			var spec1 routeapi.RouteSpec
 			var routeGroup = "default"
@@ -3015,7 +3248,7 @@ var _ = Describe("Worker Tests", func() {
 				svc = test.NewService("foo", "1", routeGroup, "ClusterIP", fooPorts)
 
 				fooIps := []string{"10.1.1.1"}
-				fooEndpts = test.NewEndpoints(
+				fooEndpts = test.NewEndpointSlice(
 					"foo", "1", "node0", routeGroup, fooIps, []string{},
 					convertSvcPortsToEndpointPorts(fooPorts))
 
@@ -3256,7 +3489,7 @@ var _ = Describe("Worker Tests", func() {
 				mockCtlr.addPod(pod)
 				mockCtlr.processResources()
 
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				mockCtlr.addService(svc)
@@ -3361,7 +3594,7 @@ var _ = Describe("Worker Tests", func() {
 				mockCtlr.addPod(pod)
 				mockCtlr.processResources()
 
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				mockCtlr.addService(svc)
@@ -3528,13 +3761,13 @@ var _ = Describe("Worker Tests", func() {
 				mockCtlr.addPod(pod)
 				mockCtlr.processResources()
 
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
-				mockCtlr.deleteEndpoints(fooEndpts)
+				mockCtlr.deleteEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				mockCtlr.deleteService(svc)
@@ -3604,15 +3837,15 @@ var _ = Describe("Worker Tests", func() {
 				mockCtlr.resources.invertedNamespaceLabelMap[routeGroup] = routeGroup
 				mockCtlr.processResources()
 
-				mockCtlr.deleteEndpoints(fooEndpts)
+				mockCtlr.deleteEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				pod.Spec.Containers[0].ReadinessProbe.TimeoutSeconds = 1
 				mockCtlr.clientsets.kubeClient.CoreV1().Pods(svc.ObjectMeta.Namespace).Update(context.TODO(), pod, metav1.UpdateOptions{})
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
-				mockCtlr.deleteEndpoints(fooEndpts)
+				mockCtlr.deleteEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				pod.Spec.Containers[0].ReadinessProbe = &v1.Probe{
@@ -3627,13 +3860,13 @@ var _ = Describe("Worker Tests", func() {
 					},
 				}
 				mockCtlr.clientsets.kubeClient.CoreV1().Pods(svc.ObjectMeta.Namespace).Update(context.TODO(), pod, metav1.UpdateOptions{})
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
-				mockCtlr.deleteEndpoints(fooEndpts)
+				mockCtlr.deleteEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				//length should be 1
@@ -3668,7 +3901,7 @@ var _ = Describe("Worker Tests", func() {
 				mockCtlr.addService(svc)
 				mockCtlr.processResources()
 
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				delete(annotation1, F5ClientSslProfileAnnotation)
@@ -3718,7 +3951,7 @@ var _ = Describe("Worker Tests", func() {
 				mockCtlr.addService(svc)
 				mockCtlr.processResources()
 
-				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.addEndpointSlice(fooEndpts)
 				mockCtlr.processResources()
 
 				// Invalid Service
@@ -3836,10 +4069,10 @@ var _ = Describe("Worker Tests", func() {
 				},
 			}
 			fooIps := []string{"10.1.1.1"}
-			fooEndpts := test.NewEndpoints(
+			fooEndpts := test.NewEndpointSlice(
 				"svc1", "1", "node0", namespace, fooIps, []string{},
 				convertSvcPortsToEndpointPorts(fooPorts))
-			mockCtlr.addEndpoints(fooEndpts)
+			mockCtlr.addEndpointSlice(fooEndpts)
 			mockCtlr.processResources()
 			bigipConfig = cisapiv1.BigIpConfig{
 				BigIpLabel:       "bigip1",
@@ -3953,7 +4186,7 @@ var _ = Describe("Worker Tests", func() {
 			mockCtlr.processResources()
 
 			// Update Endpoints
-			mockCtlr.addEndpoints(fooEndpts)
+			mockCtlr.addEndpointSlice(fooEndpts)
 			mockCtlr.processResources()
 			Expect(len(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig)).To(Equal(1), "Invalid Virtual Server")
 
@@ -3963,7 +4196,7 @@ var _ = Describe("Worker Tests", func() {
 			mockCtlr.processResources()
 
 			// Update Endpoints
-			mockCtlr.addEndpoints(fooEndpts)
+			mockCtlr.addEndpointSlice(fooEndpts)
 			mockCtlr.processResources()
 			Expect(len(mockCtlr.resources.bigIpMap[bigipConfig].ltmConfig)).To(Equal(1), "Invalid Virtual Server")
 
@@ -2,11 +2,10 @@ package networkmanager
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v3/config/apis/cis/v1"
+	bigIPPrometheus "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
 	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	"net/http"
@@ -19,6 +18,12 @@ const (
 	InstancesURI         = "/api/v1/spaces/default/instances/"
 	InventoryURI         = "/api/device/v1/inventory"
 	L3Forwards           = "/l3forwards"
+	RouteDomains         = "/route-domains"
+	VLANs                = "/vlans"
+	SelfIPs              = "/self-ips"
+	Tunnels              = "/tunnels"
+	FDBRecords           = "/fdb-records"
+	ARPs                 = "/arps"
 	TaskBaseURI          = "/api/task-manager"
 	L3RouteGateway       = "L3RouteGateway"
 	Completed            = "COMPLETED"
@@ -68,6 +73,13 @@ type (
 		L3ForwardType string `json:"l3ForwardType"`
 	}
 
+	// NetworkObject represents a route domain, VLAN, self IP or tunnel of the instance
+	NetworkObject struct {
+		ID      string
+		Name    string
+		Payload interface{}
+	}
+
 	// NetworkConfigRequest represents the network config request
 	NetworkConfigRequest struct {
 		NetworkConfig   interface{}
@@ -78,25 +90,8 @@ type (
 )
 
 func NewNetworkManager(tm *tokenmanager.TokenManager, clusterName string) *NetworkManager {
-	// Get the SystemCertPool, continue with an empty pool on error
-	rootCAs, _ := x509.SystemCertPool()
-	if rootCAs == nil {
-		rootCAs = x509.NewCertPool()
-	}
-	// TODO: Make sure appMgr sets certificates in bigipInfo
-	certs := []byte(tm.TrustedCerts)
-
-	// Append our certs to the system pool
-	if ok := rootCAs.AppendCertsFromPEM(certs); !ok {
-		log.Debugf("%v No certs appended, using only system certs", networkManagerPrefix)
-	}
-
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: tm.SslInsecure,
-			RootCAs:            rootCAs,
-		},
-	}
+	// transport of the token manager always uses the latest trusted certs
+	tr := tm.Transport()
 	httpClient := &http.Client{
 		Transport: tr,
 		Timeout:   timeoutLarge,
@@ -137,7 +132,7 @@ func (nm *NetworkManager) SetInstanceIds(bigIpConfigs []cisapiv1.BigIpConfig, co
 	nm.L3ForwardStore.Unlock()
 
 	// Create request
-	req, err := http.NewRequest("GET", nm.CMTokenManager.ServerURL+InventoryURI, nil)
+	req, err := http.NewRequest("GET", nm.CMTokenManager.GetServerURL()+InventoryURI, nil)
 	if err != nil {
 		return err
 	}
@@ -146,7 +141,7 @@ func (nm *NetworkManager) SetInstanceIds(bigIpConfigs []cisapiv1.BigIpConfig, co
 	req.Header.Set("Authorization", "Bearer "+nm.CMTokenManager.GetToken())
 
 	// Perform request
-	resp, err := nm.httpClient.Do(req)
+	resp, err := nm.doRequest(req)
 	if err != nil {
 		return err
 	}
@@ -194,11 +189,20 @@ func (nm *NetworkManager) SetInstanceIds(bigIpConfigs []cisapiv1.BigIpConfig, co
 	return nil
 }
 
+// doRequest performs the request and requests a re-login when Central Manager rejects the token
+func (nm *NetworkManager) doRequest(req *http.Request) (*http.Response, error) {
+	resp, err := nm.httpClient.Do(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && nm.CMTokenManager != nil {
+		nm.CMTokenManager.ForceRelogin()
+	}
+	return resp, err
+}
+
 // GetL3ForwardsFromInstance performs an HTTP GET request to the API, extracts name and route information, and stores them
 func (nm *NetworkManager) GetL3ForwardsFromInstance(instanceId string, controllerID string) (StaticRouteMap, error) {
 
 	// Create request
-	req, err := http.NewRequest("GET", nm.CMTokenManager.ServerURL+InstancesURI+instanceId+L3Forwards, nil)
+	req, err := http.NewRequest("GET", nm.CMTokenManager.GetServerURL()+InstancesURI+instanceId+L3Forwards, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -207,7 +211,7 @@ func (nm *NetworkManager) GetL3ForwardsFromInstance(instanceId string, controlle
 	req.Header.Set("Authorization", "Bearer "+nm.CMTokenManager.GetToken())
 
 	// Perform request
-	resp, err := nm.httpClient.Do(req)
+	resp, err := nm.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -266,7 +270,7 @@ func (nm *NetworkManager) GetL3ForwardsFromInstance(instanceId string, controlle
 func (nm *NetworkManager) DeleteL3Forward(instanceId, l3ForwardID string) error {
 
 	// Create request URL
-	url := fmt.Sprintf("%s/%s", nm.CMTokenManager.ServerURL+InstancesURI+instanceId+L3Forwards, l3ForwardID)
+	url := fmt.Sprintf("%s/%s", nm.CMTokenManager.GetServerURL()+InstancesURI+instanceId+L3Forwards, l3ForwardID)
 
 	// Create request
 	req, err := http.NewRequest("DELETE", url, nil)
@@ -278,7 +282,7 @@ func (nm *NetworkManager) DeleteL3Forward(instanceId, l3ForwardID string) error
 	req.Header.Set("Authorization", "Bearer "+nm.CMTokenManager.GetToken())
 
 	// Perform request
-	resp, err := nm.httpClient.Do(req)
+	resp, err := nm.doRequest(req)
 	if err != nil {
 		return err
 	}
@@ -298,8 +302,17 @@ func (nm *NetworkManager) DeleteL3Forward(instanceId, l3ForwardID string) error
 	if taskRef == "" {
 		return fmt.Errorf("task URI not found in response")
 	}
-	// Get the task status
+	// Wait for the task to complete
+	if err = nm.waitForTask(taskRef); err != nil {
+		return err
+	}
+	return nil
+}
+
+// waitForTask polls the task until it is completed or failed
+func (nm *NetworkManager) waitForTask(taskRef string) error {
 	var taskStatus, failureReason string
+	var err error
 	for {
 		time.Sleep(timeoutSmall)
 		taskStatus, failureReason, err = nm.GetTaskStatus(taskRef)
@@ -320,7 +333,7 @@ func (nm *NetworkManager) DeleteL3Forward(instanceId, l3ForwardID string) error
 func (nm *NetworkManager) GetTaskStatus(taskRef string) (string, string, error) {
 
 	// Create request
-	req, err := http.NewRequest("GET", nm.CMTokenManager.ServerURL+TaskBaseURI+taskRef, nil)
+	req, err := http.NewRequest("GET", nm.CMTokenManager.GetServerURL()+TaskBaseURI+taskRef, nil)
 	if err != nil {
 		return "", "", err
 	}
@@ -329,7 +342,7 @@ func (nm *NetworkManager) GetTaskStatus(taskRef string) (string, string, error)
 	req.Header.Set("Authorization", "Bearer "+nm.CMTokenManager.GetToken())
 
 	// Perform request
-	resp, err := nm.httpClient.Do(req)
+	resp, err := nm.doRequest(req)
 	if err != nil {
 		return "", "", err
 	}
@@ -378,7 +391,7 @@ func (nm *NetworkManager) PostL3Forward(apiURL, authToken string, l3ForwardReq *
 	req.Header.Set("Authorization", "Bearer "+authToken)
 
 	// Perform request
-	resp, err := nm.httpClient.Do(req)
+	resp, err := nm.doRequest(req)
 	if err != nil {
 		return err
 	}
@@ -400,20 +413,9 @@ func (nm *NetworkManager) PostL3Forward(apiURL, authToken string, l3ForwardReq *
 		return fmt.Errorf("task URI not found in response")
 	}
 
-	// Get the task status
-	var taskStatus, failureReason string
-	for {
-		time.Sleep(timeoutSmall)
-		taskStatus, failureReason, err = nm.GetTaskStatus(taskRef)
-		if err != nil || taskStatus == Completed || taskStatus == Failed {
-			break
-		}
-	}
-	if taskStatus != Completed {
-		if err != nil {
-			return fmt.Errorf("task did not completed with error: %s", err)
-		}
-		return fmt.Errorf("task did not completed with status: %s and failure reason: %s", taskStatus, failureReason)
+	// Wait for the task to complete
+	if err = nm.waitForTask(taskRef); err != nil {
+		return err
 	}
 
 	// set the task id for the l3 forward
@@ -510,10 +512,11 @@ func (nm *NetworkManager) HandleL3ForwardRequest(req *NetworkConfigRequest, l3Fo
 			return
 		}
 		// create the l3 forward
-		err := nm.PostL3Forward(nm.CMTokenManager.ServerURL+InstancesURI+req.BigIpInstanceId+L3Forwards, nm.CMTokenManager.GetToken(), l3Forward)
+		err := nm.PostL3Forward(nm.CMTokenManager.GetServerURL()+InstancesURI+req.BigIpInstanceId+L3Forwards, nm.CMTokenManager.GetToken(), l3Forward)
 		if err != nil {
 			// as the request is failed retrying the request
 			log.Errorf("%v error while creating l3 forward %v", networkManagerPrefix, err)
+			bigIPPrometheus.L3ForwardFailures.WithLabelValues(req.BigIpInstanceId, Create).Inc()
 			req.retryTimeout = getRetryTimeout(req.retryTimeout)
 			nm.NetworkChan <- req
 			return
@@ -533,6 +536,7 @@ func (nm *NetworkManager) HandleL3ForwardRequest(req *NetworkConfigRequest, l3Fo
 		err := nm.DeleteL3Forward(req.BigIpInstanceId, l3Forward.ID)
 		if err != nil {
 			log.Errorf("%v error while deleting l3 forward %v", networkManagerPrefix, err)
+			bigIPPrometheus.L3ForwardFailures.WithLabelValues(req.BigIpInstanceId, Delete).Inc()
 			req.retryTimeout = getRetryTimeout(req.retryTimeout)
 			// as the request is failed retrying the request
 			nm.NetworkChan <- req
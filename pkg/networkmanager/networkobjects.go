@@ -0,0 +1,148 @@
+package networkmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GetInstanceId returns the id of the instance with the given address from the Central Manager inventory
+func (nm *NetworkManager) GetInstanceId(address string) (string, error) {
+	response, err := nm.getResource(nm.CMTokenManager.GetServerURL() + InventoryURI)
+	if err != nil {
+		return "", err
+	}
+	if embedded, ok := response["_embedded"].(map[string]interface{}); ok {
+		if devicesArray, ok := embedded["devices"].([]interface{}); ok {
+			for _, deviceData := range devicesArray {
+				if device, ok := deviceData.(map[string]interface{}); ok {
+					if device["address"] == address {
+						if id, ok := device["id"].(string); ok {
+							return id, nil
+						}
+					}
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("instance %v not found in the Central Manager inventory", address)
+}
+
+// GetNetworkObjects returns the network objects of the given kind on the instance, keyed by name
+func (nm *NetworkManager) GetNetworkObjects(instanceId, kind string) (map[string]NetworkObject, error) {
+	response, err := nm.getResource(nm.CMTokenManager.GetServerURL() + InstancesURI + instanceId + kind)
+	if err != nil {
+		return nil, err
+	}
+	objects := make(map[string]NetworkObject)
+	if embedded, ok := response["_embedded"].(map[string]interface{}); ok {
+		if objectsArray, ok := embedded[strings.TrimPrefix(kind, "/")].([]interface{}); ok {
+			for _, objectData := range objectsArray {
+				object, ok := objectData.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				id, idOk := object["id"].(string)
+				payload, payloadOk := object["payload"].(map[string]interface{})
+				if !payloadOk {
+					payload = object
+				}
+				name, nameOk := payload["name"].(string)
+				if idOk && nameOk {
+					objects[name] = NetworkObject{
+						ID:      id,
+						Name:    name,
+						Payload: payload,
+					}
+				}
+			}
+		}
+	}
+	return objects, nil
+}
+
+// PostNetworkObject creates the network object of the given kind on the instance and returns its id
+func (nm *NetworkManager) PostNetworkObject(instanceId, kind string, payload interface{}) (string, error) {
+	return nm.deployNetworkObject("POST", nm.CMTokenManager.GetServerURL()+InstancesURI+instanceId+kind, payload)
+}
+
+// PutNetworkObject updates the network object of the given kind on the instance
+func (nm *NetworkManager) PutNetworkObject(instanceId, kind, id string, payload interface{}) error {
+	url := fmt.Sprintf("%s/%s", nm.CMTokenManager.GetServerURL()+InstancesURI+instanceId+kind, id)
+	_, err := nm.deployNetworkObject("PUT", url, payload)
+	return err
+}
+
+// DeleteNetworkObject deletes the network object of the given kind from the instance
+func (nm *NetworkManager) DeleteNetworkObject(instanceId, kind, id string) error {
+	url := fmt.Sprintf("%s/%s", nm.CMTokenManager.GetServerURL()+InstancesURI+instanceId+kind, id)
+	_, err := nm.deployNetworkObject("DELETE", url, nil)
+	return err
+}
+
+// deployNetworkObject sends the request and waits for the task to complete, it returns the id of the object
+func (nm *NetworkManager) deployNetworkObject(method, url string, payload interface{}) (string, error) {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return "", err
+		}
+	}
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+nm.CMTokenManager.GetToken())
+
+	resp, err := nm.doRequest(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
+	}
+	var response map[string]interface{}
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", err
+	}
+	taskRef, objectId := GetTaskURIAndObjectIdFromResponse(response)
+	if taskRef == "" {
+		return "", fmt.Errorf("task URI not found in response")
+	}
+	if err = nm.waitForTask(taskRef); err != nil {
+		return "", err
+	}
+	return objectId, nil
+}
+
+// getResource performs an HTTP GET request to the API and decodes the JSON response
+func (nm *NetworkManager) getResource(url string) (map[string]interface{}, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+nm.CMTokenManager.GetToken())
+
+	resp, err := nm.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
+	}
+	var response map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,101 @@
+package networkmanager
+
+import (
+	"fmt"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/tokenmanager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Network Object Tests", func() {
+	var server *ghttp.Server
+	var networkManager *NetworkManager
+	const (
+		BigIPAddress       = "10.218.130.73"
+		BigIpId            = "41073280-8f16-4b1f-9808-8908910e8fc2"
+		TaskRef            = "/v1/tasks/9bb9a35e-83f0-4998-af41-95f3fcc4ac09"
+		statusCodeOk       = 200
+		statusCodeAccepted = 202
+		statusCodeError    = 500
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		tokenManager := tokenmanager.NewTokenManager(server.URL(), tokenmanager.Credentials{
+			Username: "admin",
+			Password: "admin",
+		}, "", true)
+		networkManager = NewNetworkManager(tokenManager, "")
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Get the instance id", func() {
+		inventoryResponse := fmt.Sprintf(`{"_embedded": {"devices": [{"address": "%s", "id": "%s"}]}}`, BigIPAddress, BigIpId)
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", InventoryURI),
+				ghttp.RespondWithJSONEncoded(statusCodeOk, stringToJson(inventoryResponse))),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", InventoryURI),
+				ghttp.RespondWithJSONEncoded(statusCodeOk, stringToJson(inventoryResponse))),
+		)
+		id, err := networkManager.GetInstanceId(BigIPAddress)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(Equal(BigIpId))
+		_, err = networkManager.GetInstanceId("10.218.130.74")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Get the network objects", func() {
+		vlansResponse := `{"_embedded": {"vlans": [
+    {"id": "vlan-1", "payload": {"name": "external", "tag": 100}},
+    {"id": "vlan-2", "name": "internal", "tag": 200},
+    {"payload": {"name": "invalid"}}
+]}}`
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", InstancesURI+BigIpId+VLANs),
+				ghttp.RespondWithJSONEncoded(statusCodeOk, stringToJson(vlansResponse))),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", InstancesURI+BigIpId+SelfIPs),
+				ghttp.RespondWithJSONEncoded(statusCodeError, nil)),
+		)
+		objects, err := networkManager.GetNetworkObjects(BigIpId, VLANs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objects).To(HaveLen(2))
+		Expect(objects["external"].ID).To(Equal("vlan-1"))
+		Expect(objects["internal"].ID).To(Equal("vlan-2"))
+		_, err = networkManager.GetNetworkObjects(BigIpId, SelfIPs)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Create the network object", func() {
+		postResponse := fmt.Sprintf(`{"_links": {"task": {"href": "%s"}}, "path": "%s"}`, TaskRef,
+			InstancesURI+BigIpId+Tunnels+"/tunnel-1")
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", InstancesURI+BigIpId+Tunnels),
+				ghttp.VerifyJSON(`{"name": "vxlan-tunnel", "key": 4096}`),
+				ghttp.RespondWithJSONEncoded(statusCodeAccepted, stringToJson(postResponse))),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", TaskBaseURI+TaskRef),
+				ghttp.RespondWithJSONEncoded(statusCodeOk, stringToJson(fmt.Sprintf(`{"status": "%s"}`, Completed)))),
+		)
+		id, err := networkManager.PostNetworkObject(BigIpId, Tunnels, map[string]interface{}{"name": "vxlan-tunnel", "key": 4096})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(Equal("tunnel-1"))
+	})
+
+	It("Delete the network object failure", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", InstancesURI+BigIpId+Tunnels+"/tunnel-1"),
+				ghttp.RespondWithJSONEncoded(statusCodeError, nil)),
+		)
+		Expect(networkManager.DeleteNetworkObject(BigIpId, Tunnels, "tunnel-1")).ToNot(Succeed())
+	})
+})
@@ -37,6 +37,123 @@ var MonitoredNodes = prometheus.NewGaugeVec(
 	[]string{"nodeselector"},
 )
 
+var CMTokenValid = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "k8s_bigip_ctlr_cm_token_valid",
+	Help: "Whether the CIS Controller holds a valid access token of the central manager.",
+})
+
+var CMTokenExpiry = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "k8s_bigip_ctlr_cm_token_expiry_timestamp_seconds",
+	Help: "The expiry time of the central manager access token in seconds since epoch.",
+})
+
+var CMTokenRenewals = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "k8s_bigip_ctlr_cm_token_renewals_total",
+		Help: "The total number of central manager access token renewals by type and result.",
+	},
+	[]string{"type", "result"},
+)
+
+var AS3ValidationFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "k8s_bigip_ctlr_as3_validation_failures_total",
+		Help: "The total number of tenant declarations rejected by the AS3 schema validation before posting.",
+	},
+	[]string{"tenant"},
+)
+
+var TenantDrift = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "k8s_bigip_ctlr_tenant_drift",
+		Help: "Whether the tenant deployed on the central manager differs from the tenant posted by the CIS Controller.",
+	},
+	[]string{"bigip", "tenant"},
+)
+
+var DriftHeals = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "k8s_bigip_ctlr_tenant_drift_heals_total",
+		Help: "The total number of drifted tenants re-posted to the central manager by result.",
+	},
+	[]string{"bigip", "tenant", "result"},
+)
+
+var PostDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "k8s_bigip_ctlr_declaration_post_duration_seconds",
+		Help:    "The duration of the declaration posts to the central manager by API.",
+		Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	},
+	[]string{"bigip", "api"},
+)
+
+var TenantResponses = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "k8s_bigip_ctlr_tenant_responses_total",
+		Help: "The total number of tenant deployments by the response code of the central manager.",
+	},
+	[]string{"bigip", "tenant", "code"},
+)
+
+var TenantRetries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "k8s_bigip_ctlr_tenant_retries_total",
+		Help: "The total number of retries of the failed tenants.",
+	},
+	[]string{"bigip", "tenant"},
+)
+
+var TaskPollDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "k8s_bigip_ctlr_task_poll_duration_seconds",
+		Help:    "The duration of polling the accepted tenant deployments until they are completed.",
+		Buckets: []float64{1, 2.5, 5, 10, 30, 60, 120, 300},
+	},
+	[]string{"bigip"},
+)
+
+var QueueDepth = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "k8s_bigip_ctlr_queue_depth",
+		Help: "The number of items waiting in the resource queue, request channel and post channels.",
+	},
+	[]string{"queue", "bigip"},
+)
+
+var DeployLatency = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "k8s_bigip_ctlr_deploy_latency_seconds",
+		Help:    "The time from the oldest kubernetes event of a declaration to its successful deployment.",
+		Buckets: []float64{.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	},
+	[]string{"bigip"},
+)
+
+var L3ForwardFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "k8s_bigip_ctlr_l3_forward_failures_total",
+		Help: "The total number of failed static route create and delete requests.",
+	},
+	[]string{"instance", "action"},
+)
+
+var Rollouts = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "k8s_bigip_ctlr_rollouts_total",
+		Help: "The total number of staged rollouts by the canary BIG-IP and result, promoted or halted.",
+	},
+	[]string{"bigip", "result"},
+)
+
+var TenantRollbacks = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "k8s_bigip_ctlr_tenant_rollbacks_total",
+		Help: "The total number of tenants rolled back to an earlier revision by trigger, manual or auto, and result.",
+	},
+	[]string{"bigip", "tenant", "trigger", "result"},
+)
+
 var ClientInFlightGauge = prometheus.NewGauge(prometheus.GaugeOpts{
 	Name: "k8s_bigip_ctlr_http_client_in_flight_requests",
 	Help: "Total count of in-flight requests for the wrapped http client.",
@@ -103,6 +220,21 @@ func RegisterMetrics(httpClientMetrics bool, cmAddress string) {
 			ConfigurationWarnings,
 			AgentCount,
 			MonitoredNodes,
+			CMTokenValid,
+			CMTokenExpiry,
+			CMTokenRenewals,
+			AS3ValidationFailures,
+			TenantDrift,
+			DriftHeals,
+			PostDuration,
+			TenantResponses,
+			TenantRetries,
+			TaskPollDuration,
+			QueueDepth,
+			DeployLatency,
+			L3ForwardFailures,
+			Rollouts,
+			TenantRollbacks,
 			ClientInFlightGauge,
 			ClientAPIRequestsCounter,
 			ClientDNSLatencyVec,
@@ -116,6 +248,21 @@ func RegisterMetrics(httpClientMetrics bool, cmAddress string) {
 			ConfigurationWarnings,
 			AgentCount,
 			MonitoredNodes,
+			CMTokenValid,
+			CMTokenExpiry,
+			CMTokenRenewals,
+			AS3ValidationFailures,
+			TenantDrift,
+			DriftHeals,
+			PostDuration,
+			TenantResponses,
+			TenantRetries,
+			TaskPollDuration,
+			QueueDepth,
+			DeployLatency,
+			L3ForwardFailures,
+			Rollouts,
+			TenantRollbacks,
 		)
 	}
 }
@@ -19,6 +19,7 @@ package test
 import (
 	routeapi "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -95,48 +96,45 @@ func NewService(id, rv, namespace string, serviceType v1.ServiceType,
 	}
 }
 
-// NewEndpoints returns an endpoints objects
-func NewEndpoints(
+// NewEndpointSlice returns an EndpointSlice object of the service
+func NewEndpointSlice(
 	svcName,
 	rv,
 	node,
 	namespace string,
 	readyIps,
 	notReadyIps []string,
-	ports []v1.EndpointPort,
-) *v1.Endpoints {
-	ep := &v1.Endpoints{
+	ports []discoveryv1.EndpointPort,
+) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
 		TypeMeta: metav1.TypeMeta{
-			Kind:       "Endpoints",
-			APIVersion: "v1",
+			Kind:       "EndpointSlice",
+			APIVersion: "discovery.k8s.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            svcName,
 			Namespace:       namespace,
 			ResourceVersion: rv,
+			Labels:          map[string]string{discoveryv1.LabelServiceName: svcName},
 		},
-		Subsets: []v1.EndpointSubset{},
+		AddressType: discoveryv1.AddressTypeIPv4,
 	}
 
 	if 0 < len(readyIps) {
-		ep.Subsets = append(
-			ep.Subsets,
-			v1.EndpointSubset{
-				Addresses:         newEndpointAddress(readyIps, node),
-				NotReadyAddresses: newEndpointAddress(notReadyIps, node),
-				Ports:             ports,
-			},
-		)
+		slice.Endpoints = append(slice.Endpoints, newEndpoints(readyIps, node, true)...)
+		slice.Endpoints = append(slice.Endpoints, newEndpoints(notReadyIps, node, false)...)
+		slice.Ports = ports
 	}
 
-	return ep
+	return slice
 }
 
-func newEndpointAddress(ips []string, node string) []v1.EndpointAddress {
-	eps := make([]v1.EndpointAddress, len(ips))
+func newEndpoints(ips []string, node string, ready bool) []discoveryv1.Endpoint {
+	eps := make([]discoveryv1.Endpoint, len(ips))
 	for i, v := range ips {
-		eps[i].IP = v
+		eps[i].Addresses = []string{v}
 		eps[i].NodeName = &node
+		eps[i].Conditions = discoveryv1.EndpointConditions{Ready: &ready, Serving: &ready}
 	}
 	return eps
 }
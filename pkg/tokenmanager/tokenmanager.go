@@ -4,30 +4,58 @@ import (
 	"bytes"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 	"io"
+	"math/rand"
 	"net/http"
-	"os"
+	"strings"
 	"sync"
 	"time"
+
+	bigIPPrometheus "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/prometheus"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v3/pkg/vlogger"
 )
 
 const (
 	//CM login url
 	CMLoginURL              = "/api/login"
+	CMRefreshTokenURL       = "/api/token-refresh"
 	CMAccessTokenExpiration = 5 * time.Minute
+	// CMTokenRefreshBuffer is the time before the token expiry at which the token is renewed
+	CMTokenRefreshBuffer = 1 * time.Minute
+	// minRetryInterval and maxRetryInterval are the bounds of the backoff between the failed token renewals
+	minRetryInterval = 1 * time.Second
+	maxRetryInterval = 2 * time.Minute
+)
+
+// token renewal types reported in the metrics
+const (
+	loginRenewal   = "login"
+	refreshRenewal = "refresh"
 )
 
 // TokenManager is responsible for managing the authentication token.
 type TokenManager struct {
 	mu           sync.Mutex
 	token        string
-	ServerURL    string
+	refreshToken string
+	expiry       time.Time
+	lastRenewal  time.Time
+	lastError    error
+	reloginCh    chan struct{}
+	serverURL    string
 	credentials  Credentials
 	SslInsecure  bool
-	TrustedCerts string
+	trustedCerts string
+	// transport is swapped when the trusted certs are updated, the in-flight requests complete on the old transport
+	transport *http.Transport
+}
+
+// cmTransport is the round tripper which uses the latest transport of the token manager
+type cmTransport struct {
+	tm *TokenManager
 }
 
 // Credentials represent the username and password used for authentication.
@@ -41,15 +69,32 @@ type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	UserID       string `json:"user_id"`
+	// ExpiresIn is the lifetime of the access token in seconds
+	ExpiresIn int64 `json:"expires_in,omitempty"`
+}
+
+// TokenStatus is the health of the access token
+type TokenStatus struct {
+	Valid       bool
+	Expiry      time.Time
+	LastRenewal time.Time
+	LastError   error
+}
+
+// Healthy reports false only when the token is not usable and the last renewal failed
+func (status TokenStatus) Healthy() bool {
+	return status.Valid || status.LastError == nil
 }
 
 // NewTokenManager creates a new instance of TokenManager.
 func NewTokenManager(serverURL string, credentials Credentials, trustedCerts string, sslInsecure bool) *TokenManager {
 	return &TokenManager{
-		ServerURL:    serverURL,
+		serverURL:    serverURL,
 		credentials:  credentials,
-		TrustedCerts: trustedCerts,
+		trustedCerts: trustedCerts,
 		SslInsecure:  sslInsecure,
+		reloginCh:    make(chan struct{}, 1),
+		transport:    newTransport(trustedCerts, sslInsecure),
 	}
 }
 
@@ -61,112 +106,301 @@ func (tm *TokenManager) GetToken() string {
 	return token
 }
 
+// GetServerURL returns the URL of Central Manager
+func (tm *TokenManager) GetServerURL() string {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.serverURL
+}
+
+// GetTrustedCerts returns the certificates trusted in addition to the system certs
+func (tm *TokenManager) GetTrustedCerts() string {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.trustedCerts
+}
+
+// Transport returns the round tripper for the requests to Central Manager, it always uses the latest trusted certs
+func (tm *TokenManager) Transport() http.RoundTripper {
+	return &cmTransport{tm: tm}
+}
+
+func (t *cmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.tm.getTransport().RoundTrip(req)
+}
+
+func (tm *TokenManager) getTransport() *http.Transport {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.transport == nil {
+		tm.transport = newTransport(tm.trustedCerts, tm.SslInsecure)
+	}
+	return tm.transport
+}
+
+// UpdateConfig atomically swaps the URL, credentials and trusted certs of Central Manager, a re-login is requested
+// when the URL or the credentials are updated. It returns whether the config is updated.
+func (tm *TokenManager) UpdateConfig(serverURL string, credentials Credentials, trustedCerts string) bool {
+	tm.mu.Lock()
+	loginUpdated := serverURL != tm.serverURL || credentials != tm.credentials
+	certsUpdated := trustedCerts != tm.trustedCerts
+	if !loginUpdated && !certsUpdated {
+		tm.mu.Unlock()
+		return false
+	}
+	tm.serverURL = serverURL
+	tm.credentials = credentials
+	var oldTransport *http.Transport
+	if certsUpdated {
+		tm.trustedCerts = trustedCerts
+		oldTransport = tm.transport
+		tm.transport = newTransport(trustedCerts, tm.SslInsecure)
+	}
+	if loginUpdated {
+		// refresh token is issued for the old login
+		tm.refreshToken = ""
+	}
+	tm.mu.Unlock()
+
+	if oldTransport != nil {
+		oldTransport.CloseIdleConnections()
+	}
+	if loginUpdated {
+		tm.ForceRelogin()
+	}
+	return true
+}
+
+// Status returns the health of the access token
+func (tm *TokenManager) Status() TokenStatus {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return TokenStatus{
+		Valid:       tm.token != "" && time.Now().Before(tm.expiry),
+		Expiry:      tm.expiry,
+		LastRenewal: tm.lastRenewal,
+		LastError:   tm.lastError,
+	}
+}
+
+// ForceRelogin requests an immediate re-login, callers invoke it when Central Manager rejects the token with 401
+func (tm *TokenManager) ForceRelogin() {
+	select {
+	case tm.reloginCh <- struct{}{}:
+		log.Debug("[Token Manager] Re-login requested as the token is unauthorized")
+	default:
+	}
+}
+
 // FetchToken retrieves a new token from the CM.
 func (tm *TokenManager) FetchToken() error {
-	// Prepare the request payload
-	payload, err := json.Marshal(tm.credentials)
+	tm.mu.Lock()
+	credentials := tm.credentials
+	tm.mu.Unlock()
+	payload, err := json.Marshal(credentials)
 	if err != nil {
 		return err
 	}
+	return tm.requestToken(loginRenewal, CMLoginURL, payload)
+}
 
-	// Configure CA certificates
-	rootCAs, _ := x509.SystemCertPool()
-	if rootCAs == nil {
-		rootCAs = x509.NewCertPool()
+// RefreshToken renews the access token with the refresh token received in the last login
+func (tm *TokenManager) RefreshToken() error {
+	tm.mu.Lock()
+	refreshToken := tm.refreshToken
+	tm.mu.Unlock()
+	if refreshToken == "" {
+		return fmt.Errorf("refresh token not available")
 	}
-
-	certs := []byte(tm.TrustedCerts)
-
-	// Append our certs to the system pool
-	if ok := rootCAs.AppendCertsFromPEM(certs); !ok {
-		log.Debug("[Token Manager] No certs appended, using only system certs")
+	payload, err := json.Marshal(map[string]string{"refresh_token": refreshToken})
+	if err != nil {
+		return err
 	}
+	return tm.requestToken(refreshRenewal, CMRefreshTokenURL, payload)
+}
 
-	// Create an insecure/secure client based on the SslInsecure flag
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: tm.SslInsecure,
-			RootCAs:            rootCAs,
-		},
+// renewToken uses the refresh token flow and falls back to the login when the refresh fails
+func (tm *TokenManager) renewToken() error {
+	err := tm.RefreshToken()
+	if err == nil {
+		return nil
 	}
+	log.Debugf("[Token Manager] Unable to refresh token, logging in again: %v", err)
+	return tm.FetchToken()
+}
 
-	client := &http.Client{Transport: tr}
+func (tm *TokenManager) requestToken(renewalType, uri string, payload []byte) error {
+	err := tm.postTokenRequest(uri, payload)
+	tm.mu.Lock()
+	tm.lastError = err
+	valid := tm.token != "" && time.Now().Before(tm.expiry)
+	expiry := tm.expiry
+	tm.mu.Unlock()
 
-	// Send POST request for token
-	resp, err := client.Post(tm.ServerURL+CMLoginURL, "application/json", bytes.NewBuffer(payload))
+	result := "success"
 	if err != nil {
-		log.Errorf("[Token Manager] Unable to establish connection with Central Manager, Probable reasons might be: invalid custom-certs (or) custom-certs not provided using --trusted-certs-cfgmap flag")
-		os.Exit(1)
+		result = "failure"
+	}
+	bigIPPrometheus.CMTokenRenewals.WithLabelValues(renewalType, result).Inc()
+	if valid {
+		bigIPPrometheus.CMTokenValid.Set(1)
+	} else {
+		bigIPPrometheus.CMTokenValid.Set(0)
+	}
+	bigIPPrometheus.CMTokenExpiry.Set(float64(expiry.Unix()))
+	return err
+}
+
+func (tm *TokenManager) postTokenRequest(uri string, payload []byte) error {
+	client := &http.Client{Transport: tm.getTransport(), Timeout: 30 * time.Second}
+	resp, err := client.Post(tm.GetServerURL()+uri, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("unable to establish connection with Central Manager, probable reasons might be: "+
+			"invalid custom-certs (or) custom-certs not provided using --trusted-certs-cfgmap flag: %v", err)
 	}
 	defer resp.Body.Close()
 
-	// Read the response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
 
-	// Check for successful response
 	if resp.StatusCode != http.StatusOK {
 		switch resp.StatusCode {
 		case http.StatusUnauthorized:
-			log.Errorf("[Token Manager] Unauthorized to fetch token from Central Manager. "+
-				"Please check the credentials, status code: %d, response: %s", resp.StatusCode, body)
-			os.Exit(1)
+			return fmt.Errorf("unauthorized to fetch token from Central Manager, please check the credentials, "+
+				"status code: %d, response: %s", resp.StatusCode, body)
 		case http.StatusServiceUnavailable:
-			return fmt.Errorf("[Token Manager] failed to get token due to service unavailability, "+
+			return fmt.Errorf("failed to get token due to service unavailability, "+
 				"status code: %d, response: %s", resp.StatusCode, body)
 		case http.StatusNotFound, http.StatusMovedPermanently:
-			log.Infof("[Token Manager] requested page/api not found, status code: %d, response: %s", resp.StatusCode, body)
-			os.Exit(1)
+			return fmt.Errorf("requested page/api not found, status code: %d, response: %s", resp.StatusCode, body)
 		default:
 			return fmt.Errorf("failed to get token, status code: %d, response: %s", resp.StatusCode, body)
 		}
 	}
 
-	// Parse the token and its expiration time from the response
 	tokenResponse := TokenResponse{}
 	err = json.Unmarshal(body, &tokenResponse)
 	if err != nil {
 		return err
 	}
+	if tokenResponse.AccessToken == "" {
+		return fmt.Errorf("access token missing in the response")
+	}
 
-	// Keep the token updated in the TokenManager
+	now := time.Now()
 	tm.mu.Lock()
 	tm.token = tokenResponse.AccessToken
+	if tokenResponse.RefreshToken != "" {
+		tm.refreshToken = tokenResponse.RefreshToken
+	}
+	tm.expiry = now.Add(getTokenExpiration(tokenResponse))
+	tm.lastRenewal = now
 	tm.mu.Unlock()
 
 	return nil
 }
 
-// SyncToken maintains valid token. It fetches a new token before expiry.
+// newTransport returns the transport trusting the system certs and the trusted certs
+func newTransport(trustedCerts string, sslInsecure bool) *http.Transport {
+	rootCAs, _ := x509.SystemCertPool()
+	if rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	certs := []byte(trustedCerts)
+
+	if ok := rootCAs.AppendCertsFromPEM(certs); !ok {
+		log.Debug("[Token Manager] No certs appended, using only system certs")
+	}
+
+	return &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: sslInsecure,
+			RootCAs:            rootCAs,
+		},
+	}
+}
+
+// getTokenExpiration returns the lifetime of the token reported by Central Manager in the response or the token claims
+func getTokenExpiration(tokenResponse TokenResponse) time.Duration {
+	if tokenResponse.ExpiresIn > 0 {
+		return time.Duration(tokenResponse.ExpiresIn) * time.Second
+	}
+	// access token is a JWT, exp claim is the expiry time in seconds since epoch
+	if parts := strings.Split(tokenResponse.AccessToken, "."); len(parts) == 3 {
+		if claims, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "=")); err == nil {
+			var payload struct {
+				Exp int64 `json:"exp"`
+			}
+			if json.Unmarshal(claims, &payload) == nil && payload.Exp > 0 {
+				if expiration := time.Until(time.Unix(payload.Exp, 0)); expiration > 0 {
+					return expiration
+				}
+			}
+		}
+	}
+	return CMAccessTokenExpiration
+}
+
+// SyncToken renews the token before it expires and retries the failed renewals with backoff until stopped
 func (tm *TokenManager) SyncToken(stopCh chan struct{}) {
-	// retryInterval is the time to wait before retrying to fetch token on the event of failure
-	retryInterval := time.Duration(10)
-	// Set ticker to 1 minute less than token expiry time to ensure token is refreshed on time
-	tokenUpdateTicker := time.Tick(CMAccessTokenExpiration - 1*time.Minute)
+	attempt := 0
 	for {
+		timer := time.NewTimer(tm.nextSyncInterval(attempt))
+		relogin := false
 		select {
-		case <-tokenUpdateTicker:
-			tm.syncTokenHelper(retryInterval)
+		case <-timer.C:
+		case <-tm.reloginCh:
+			timer.Stop()
+			relogin = true
 		case <-stopCh:
+			timer.Stop()
 			log.Debug("[Token Manager] Stopping synchronizing token")
 			return
 		}
+		var err error
+		if relogin {
+			err = tm.FetchToken()
+		} else {
+			err = tm.renewToken()
+		}
+		if err != nil {
+			attempt++
+			log.Errorf("[Token Manager] Error fetching token from Central Manager: %v", err)
+			continue
+		}
+		attempt = 0
+		log.Debugf("[Token Manager] Successfully fetched token from Central Manager")
 	}
 }
 
-// syncTokenHelper is a helper function to fetch token and retry on failure
-func (tm *TokenManager) syncTokenHelper(retryInterval time.Duration) {
-	for {
-		time.Sleep(retryInterval * time.Second)
-		err := tm.FetchToken()
-		if err != nil {
-			log.Errorf("[Token Manager] Error fetching token from Central Manager: %s", err)
-			log.Debugf("[Token Manager] Retrying to fetch token in %d seconds", retryInterval)
-		} else {
-			log.Debugf("[Token Manager] Successfully fetched token from Central Manager")
-			break
+// nextSyncInterval returns the wait before the next renewal, failed renewals are retried with the backoff
+func (tm *TokenManager) nextSyncInterval(attempt int) time.Duration {
+	tm.mu.Lock()
+	token, expiry := tm.token, tm.expiry
+	tm.mu.Unlock()
+	if attempt > 0 || token == "" {
+		interval := getRetryInterval(attempt)
+		log.Debugf("[Token Manager] Retrying to fetch token in %v", interval)
+		return interval
+	}
+	interval := time.Until(expiry) - CMTokenRefreshBuffer
+	if interval < minRetryInterval {
+		interval = minRetryInterval
+	}
+	return interval
+}
+
+// getRetryInterval returns the exponential backoff with jitter for the attempt
+func getRetryInterval(attempt int) time.Duration {
+	interval := maxRetryInterval
+	if attempt < 8 {
+		interval = minRetryInterval << attempt
+		if interval > maxRetryInterval {
+			interval = maxRetryInterval
 		}
 	}
+	return interval/2 + time.Duration(rand.Int63n(int64(interval/2)+1))
 }
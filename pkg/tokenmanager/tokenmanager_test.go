@@ -1,6 +1,11 @@
 package tokenmanager
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
@@ -58,6 +63,164 @@ var _ = Describe("Token Manager Tests", func() {
 				token := tokenManager.GetToken()
 				Expect(token).To(Equal(response.AccessToken), "Token should not be nil")
 			})
+
+			It("should return error without exiting when unauthorized", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/login"),
+						ghttp.RespondWithJSONEncoded(http.StatusUnauthorized, nil),
+					))
+				err := tokenManager.FetchToken()
+				Expect(err).NotTo(BeNil(), "Error should not be nil")
+				status := tokenManager.Status()
+				Expect(status.Valid).To(BeFalse())
+				Expect(status.Healthy()).To(BeFalse(), "Token should be unhealthy")
+			})
+
+			It("should honor the expiry reported by central manager", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/login"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, TokenResponse{
+							AccessToken:  "test.token",
+							RefreshToken: "refresh.token",
+							ExpiresIn:    600,
+						}),
+					))
+				Expect(tokenManager.FetchToken()).To(BeNil())
+				status := tokenManager.Status()
+				Expect(status.Valid).To(BeTrue())
+				Expect(status.Healthy()).To(BeTrue())
+				Expect(time.Until(status.Expiry)).To(BeNumerically("~", 10*time.Minute, 5*time.Second))
+			})
+
+			It("should refresh the token with the refresh token", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/login"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, TokenResponse{
+							AccessToken:  "test.token",
+							RefreshToken: "refresh.token",
+						}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/token-refresh"),
+						ghttp.VerifyJSONRepresenting(map[string]string{"refresh_token": "refresh.token"}),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, TokenResponse{AccessToken: "refreshed.token"}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/token-refresh"),
+						ghttp.RespondWithJSONEncoded(http.StatusUnauthorized, nil),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/login"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, TokenResponse{AccessToken: "new.token"}),
+					),
+				)
+				Expect(tokenManager.FetchToken()).To(BeNil())
+				Expect(tokenManager.renewToken()).To(BeNil())
+				Expect(tokenManager.GetToken()).To(Equal("refreshed.token"))
+				// login when the refresh token is rejected
+				Expect(tokenManager.renewToken()).To(BeNil())
+				Expect(tokenManager.GetToken()).To(Equal("new.token"))
+			})
+
+			It("should re-login when requested", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/login"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, TokenResponse{AccessToken: "test.token", ExpiresIn: 3600}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/api/login"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, TokenResponse{AccessToken: "new.token", ExpiresIn: 3600}),
+					),
+				)
+				Expect(tokenManager.FetchToken()).To(BeNil())
+				stopCh := make(chan struct{})
+				defer close(stopCh)
+				go tokenManager.SyncToken(stopCh)
+				tokenManager.ForceRelogin()
+				Eventually(tokenManager.GetToken, 5*time.Second).Should(Equal("new.token"))
+			})
+		})
+	})
+
+	Describe("Update config", func() {
+		var newServer *ghttp.Server
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			newServer = ghttp.NewServer()
+			tokenManager = NewTokenManager(server.URL(), Credentials{
+				Username: "admin",
+				Password: "admin",
+			}, "", true)
+		})
+		AfterEach(func() {
+			server.Close()
+			newServer.Close()
+		})
+
+		It("should login to the new url with the new credentials", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/login"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, TokenResponse{
+						AccessToken: "test.token", RefreshToken: "refresh.token", ExpiresIn: 3600}),
+				),
+			)
+			newServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/login"),
+					ghttp.VerifyJSONRepresenting(Credentials{Username: "user", Password: "pass"}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, TokenResponse{AccessToken: "new.token", ExpiresIn: 3600}),
+				),
+			)
+			Expect(tokenManager.FetchToken()).To(BeNil())
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			go tokenManager.SyncToken(stopCh)
+
+			Expect(tokenManager.UpdateConfig(server.URL(), Credentials{Username: "admin", Password: "admin"}, "")).To(BeFalse())
+			Expect(tokenManager.UpdateConfig(newServer.URL(), Credentials{Username: "user", Password: "pass"}, "")).To(BeTrue())
+			Expect(tokenManager.GetServerURL()).To(Equal(newServer.URL()))
+			Eventually(tokenManager.GetToken, 5*time.Second).Should(Equal("new.token"))
+		})
+
+		It("should swap the transport when the trusted certs are updated", func() {
+			transport := tokenManager.getTransport()
+			roundTripper := tokenManager.Transport()
+			Expect(tokenManager.UpdateConfig(server.URL(), Credentials{Username: "admin", Password: "admin"}, "certs")).To(BeTrue())
+			Expect(tokenManager.GetTrustedCerts()).To(Equal("certs"))
+			Expect(tokenManager.getTransport()).NotTo(BeIdenticalTo(transport))
+			// requests made with the existing round tripper use the new transport
+			server.AppendHandlers(ghttp.RespondWith(http.StatusOK, ""))
+			req, _ := http.NewRequest("GET", server.URL(), nil)
+			resp, err := roundTripper.RoundTrip(req)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			// re-login is not requested when only the certs are updated
+			Expect(len(tokenManager.reloginCh)).To(Equal(0))
+		})
+	})
+
+	Describe("Token expiration and retries", func() {
+		It("should read the expiry from the token claims", func() {
+			claims, _ := json.Marshal(map[string]int64{"exp": time.Now().Add(20 * time.Minute).Unix()})
+			token := "header." + base64.RawURLEncoding.EncodeToString(claims) + ".signature"
+			Expect(getTokenExpiration(TokenResponse{AccessToken: token})).To(
+				BeNumerically("~", 20*time.Minute, 5*time.Second))
+			Expect(getTokenExpiration(TokenResponse{AccessToken: "test.token"})).To(Equal(CMAccessTokenExpiration))
+		})
+
+		It("should backoff exponentially with jitter", func() {
+			for attempt := 0; attempt < 20; attempt++ {
+				interval := getRetryInterval(attempt)
+				Expect(interval).To(BeNumerically(">=", minRetryInterval/2))
+				Expect(interval).To(BeNumerically("<=", maxRetryInterval))
+			}
+			Expect(getRetryInterval(3)).To(BeNumerically(">=", 4*time.Second))
+			Expect(getRetryInterval(30)).To(BeNumerically(">=", maxRetryInterval/2))
 		})
 	})
 })